@@ -0,0 +1,115 @@
+// Command book_server serves the book API from a single bolt file, without
+// AWS or a database process, for kiosks and offline demos:
+//
+//	go run ./cmd/book_server -addr :8080 -db books.bolt
+//
+// The routes are those of API Gateway, served by the same Lambda handlers.
+// Book images are read back from /files/{key} and a consistent copy of the
+// whole store is downloaded from /backup while it keeps serving.
+package main
+
+import (
+	"flag"
+	"log"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	batchBooks "main/lambdas/batch_books/lambda_handler"
+	batchGetBooks "main/lambdas/batch_get_books/lambda_handler"
+	booksFeed "main/lambdas/books_feed/lambda_handler"
+	createBook "main/lambdas/create_book/lambda_handler"
+	deleteBook "main/lambdas/delete_book/lambda_handler"
+	getAllBooks "main/lambdas/get_all_books/lambda_handler"
+	getBookByID "main/lambdas/get_book_by_id/lambda_handler"
+	getTrashedBooks "main/lambdas/get_trashed_books/lambda_handler"
+	opdsCatalog "main/lambdas/opds_catalog/lambda_handler"
+	purgeBook "main/lambdas/purge_book/lambda_handler"
+	restoreTrashedBook "main/lambdas/restore_trashed_book/lambda_handler"
+	searchBooks "main/lambdas/search_books/lambda_handler"
+	updateBook "main/lambdas/update_book/lambda_handler"
+	book "main/src/books/application/handler"
+	"main/src/books/infrastructure/configuration"
+	"main/utils/apigateway"
+)
+
+// routes maps the API Gateway routes, as METHOD and resource, to their
+// Lambda handlers.
+var routes = []struct {
+	method, resource string
+	handler          apigateway.LambdaHandler
+}{
+	{http.MethodGet, "/books", getAllBooks.Handler},
+	{http.MethodPost, "/books", createBook.Handler},
+	{http.MethodGet, "/books/search", searchBooks.Handler},
+	{http.MethodGet, "/books/{bookId}", getBookByID.Handler},
+	{http.MethodPut, "/books/{bookId}", updateBook.Handler},
+	{http.MethodDelete, "/books/{bookId}", deleteBook.Handler},
+	{http.MethodPost, "/books:batchGet", batchGetBooks.Handler},
+	{http.MethodPost, "/books:batchDelete", batchBooks.Handler},
+	{http.MethodPost, "/books:batchUpdate", batchBooks.Handler},
+	{http.MethodGet, "/feeds/recent.rss", booksFeed.Handler},
+	{http.MethodGet, "/feeds/recent.atom", booksFeed.Handler},
+	{http.MethodGet, "/trash", getTrashedBooks.Handler},
+	{http.MethodPost, "/trash/{bookId}/restore", restoreTrashedBook.Handler},
+	{http.MethodDelete, "/trash/{bookId}", purgeBook.Handler},
+	{http.MethodGet, "/opds", opdsCatalog.Handler},
+	{http.MethodGet, "/opds/books", opdsCatalog.Handler},
+	{http.MethodGet, "/opds/search", opdsCatalog.Handler},
+	{http.MethodGet, "/opds/opensearch.xml", opdsCatalog.Handler},
+	{http.MethodGet, "/opds/v2", opdsCatalog.Handler},
+	{http.MethodGet, "/opds/v2/books", opdsCatalog.Handler},
+	{http.MethodGet, "/opds/v2/search", opdsCatalog.Handler},
+}
+
+func main() {
+	addr := flag.String("addr", ":8080", "address to listen on")
+	dbPath := flag.String("db", configuration.GetBoltBookPath(), "bolt file of the store")
+	flag.Parse()
+
+	os.Setenv("BOOKS_STORE", configuration.BookStoreBolt)
+	os.Setenv("BOOKS_BOLT_PATH", *dbPath)
+	if _, err := configuration.GetBoltDB(); err != nil {
+		log.Fatalf("Error opening bolt database: %v", err)
+	}
+
+	mux := http.NewServeMux()
+	for _, route := range routes {
+		mux.Handle(route.method+" "+route.resource, apigateway.HTTPHandler(route.resource, route.handler))
+	}
+	mux.HandleFunc("GET /files/{key...}", serveFile)
+	mux.HandleFunc("GET /backup", serveBackup)
+
+	log.Printf("Serving books from %s on %s", *dbPath, *addr)
+	log.Fatal(http.ListenAndServe(*addr, mux))
+}
+
+// serveFile returns a stored book file, such as the image of a book.
+func serveFile(w http.ResponseWriter, r *http.Request) {
+	bookMicro := book.MicroAWSBookDynamoDB{Ctx: r.Context()}
+	key := r.PathValue("key")
+	content, errFile := bookMicro.GetBookFile(key)
+	if errFile != nil {
+		http.Error(w, errFile.Message, errFile.Code)
+		return
+	}
+	if contentType := mime.TypeByExtension(filepath.Ext(key)); contentType != "" {
+		w.Header().Set("Content-Type", contentType)
+	}
+	w.Write(content)
+}
+
+// serveBackup streams a copy of the bolt file, which can replace the store
+// file to restore it.
+func serveBackup(w http.ResponseWriter, r *http.Request) {
+	bookMicro := book.MicroAWSBookDynamoDB{Ctx: r.Context()}
+	name := "books-" + time.Now().UTC().Format("20060102T150405Z") + ".bolt"
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set("Content-Disposition", `attachment; filename="`+name+`"`)
+	if _, errBackup := bookMicro.BackupBooks(w); errBackup != nil {
+		// The status is already sent once the copy started.
+		log.Printf("Error while writing backup, %s", errBackup.ToString())
+	}
+}
@@ -0,0 +1,58 @@
+// Command export_books dumps the books of the store chosen by BOOKS_STORE to
+// a local file or stdout. Without BOOKS_STORE and BOOKS_TABLE set it reads
+// from DynamoDB Local:
+//
+//	go run ./cmd/export_books -format columnar -gzip -out books.bkcol.gz
+//	go run ./cmd/export_books -format marc -out books.mrc
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+
+	"main/src/books/application/exporter"
+	book "main/src/books/application/handler"
+	"main/src/books/infrastructure/configuration"
+)
+
+func main() {
+	format := flag.String("format", string(exporter.FormatCSV), "csv, ndjson, columnar, marcxml, marc or dc")
+	gzip := flag.Bool("gzip", false, "gzip the output")
+	segments := flag.Int("segments", exporter.DefaultSegments, "parallel scan segments")
+	table := flag.String("table", configuration.GetDynamoDBBookTable(), "books table name")
+	outFile := flag.String("out", "", "write the export to this file instead of stdout")
+	flag.Parse()
+
+	exportFormat, errFormat := exporter.ParseFormat(*format)
+	if errFormat != nil {
+		log.Fatal(errFormat.ToString())
+	}
+
+	output := os.Stdout
+	if *outFile != "" {
+		file, err := os.Create(*outFile)
+		if err != nil {
+			log.Fatalf("Error creating export file: %v", err)
+		}
+		defer file.Close()
+		output = file
+	}
+
+	bookMicro := &book.MicroAWSBookDynamoDB{Ctx: context.Background(), TableName: *table}
+	bookInfrastructure, errRepository := book.DefaultBookContainer().BookRepository(bookMicro, false)
+	if errRepository != nil {
+		log.Fatal(errRepository.ToString())
+	}
+
+	rows, errExport := exporter.NewExporter(bookInfrastructure).Export(output, exporter.Options{
+		Format:   exportFormat,
+		Gzip:     *gzip,
+		Segments: *segments,
+	})
+	if errExport != nil {
+		log.Fatal(errExport.ToString())
+	}
+	log.Printf("Exported %d books", rows)
+}
@@ -0,0 +1,102 @@
+// Command import_books loads a CSV, JSON or NDJSON catalog file, or an ONIX
+// 3.0 message (.xml), into the store chosen by BOOKS_STORE. Without
+// BOOKS_STORE and BOOKS_TABLE set it targets DynamoDB Local:
+//
+//	go run ./cmd/import_books -file catalog.csv -mapping catalog.mapping.json
+//	go run ./cmd/import_books -file json/onix_feeds/new_titles.xml
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	book "main/src/books/application/handler"
+	"main/src/books/application/importer"
+	"main/src/books/application/onix"
+	"main/src/books/application/service"
+	"main/src/books/infrastructure/configuration"
+)
+
+func main() {
+	file := flag.String("file", "", "catalog file to import")
+	format := flag.String("format", "", "csv, json, ndjson or onix; taken from the file extension when empty")
+	mappingFile := flag.String("mapping", "", "JSON file mapping CSV headers to book fields")
+	table := flag.String("table", configuration.GetDynamoDBBookTable(), "books table name")
+	reportFile := flag.String("report", "", "write the report to this file instead of stdout")
+	flag.Parse()
+
+	if *file == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	isONIX := *format == "onix" || (*format == "" && strings.EqualFold(filepath.Ext(*file), ".xml"))
+	importFormat := importer.Format(*format)
+	if importFormat == "" && !isONIX {
+		detected, errFormat := importer.FormatFromName(*file)
+		if errFormat != nil {
+			log.Fatal(errFormat.ToString())
+		}
+		importFormat = detected
+	}
+
+	var mapping importer.Mapping
+	if *mappingFile != "" {
+		content, err := os.ReadFile(*mappingFile)
+		if err != nil {
+			log.Fatalf("Error reading mapping file: %v", err)
+		}
+		parsed, errParse := importer.ParseMapping(content)
+		if errParse != nil {
+			log.Fatal(errParse.ToString())
+		}
+		mapping = parsed
+	}
+
+	content, err := os.Open(*file)
+	if err != nil {
+		log.Fatalf("Error opening import file: %v", err)
+	}
+	defer content.Close()
+
+	bookMicro := &book.MicroAWSBookDynamoDB{Ctx: context.Background(), TableName: *table}
+	bookInfrastructure, errRepository := book.DefaultBookContainer().BookRepository(bookMicro, false)
+	if errRepository != nil {
+		log.Fatal(errRepository.ToString())
+	}
+	bookService := service.NewBookServiceDynamoDB(bookInfrastructure)
+
+	var report interface{}
+	if isONIX {
+		importLog, errImport := onix.NewImporter(bookService).Import(*file, content)
+		if errImport != nil {
+			log.Fatal(errImport.ToString())
+		}
+		report = importLog
+	} else {
+		importReport, errImport := importer.NewImporter(bookService).Import(*file, content, importFormat, mapping)
+		if errImport != nil {
+			log.Fatal(errImport.ToString())
+		}
+		report = importReport
+	}
+
+	output := os.Stdout
+	if *reportFile != "" {
+		output, err = os.Create(*reportFile)
+		if err != nil {
+			log.Fatalf("Error creating report file: %v", err)
+		}
+		defer output.Close()
+	}
+	encoder := json.NewEncoder(output)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		log.Fatalf("Error writing report: %v", err)
+	}
+}
@@ -0,0 +1,57 @@
+package lambdahandler
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"os"
+
+	list "main/src/lists/application/handler"
+	"main/utils/apigateway"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+var (
+	LISTS_TABLE = os.Getenv("LISTS_TABLE")
+	BOOKS_TABLE = os.Getenv("BOOKS_TABLE")
+)
+
+type addBookRequest struct {
+	BookID string `json:"book_id"`
+}
+
+func Handler(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+
+	listMicro := list.MicroAWSListDynamoDB{
+		Ctx:        ctx,
+		ListsTable: LISTS_TABLE,
+		BooksTable: BOOKS_TABLE,
+	}
+
+	listId, errApi := apigateway.ParseAPIGatewayRequestParameters(request, "listId")
+	if errApi != nil {
+		log.Printf("Error parsing request parameters: %v", errApi)
+		return apigateway.APIGatewayError(http.StatusBadRequest, "Error parsing request parameters.")
+	}
+
+	userId, errIdentity := apigateway.ParseAPIGatewayRequestIdentity(request)
+	if errIdentity != nil {
+		log.Printf("Error reading the caller: %s", errIdentity.ToString())
+		return apigateway.APIGatewayError(errIdentity.Code, errIdentity.ToString())
+	}
+
+	var body addBookRequest
+	if errBody := apigateway.ParseAPIGatewayRequestBody(request, &body); errBody != nil {
+		log.Printf("Error parsing request body: %s", errBody.ToString())
+		return apigateway.APIGatewayError(errBody.Code, errBody.ToString())
+	}
+
+	listRecord, errListMicro := listMicro.AddBook(listId, userId, body.BookID)
+	if errListMicro != nil {
+		log.Printf("Error while adding book to list, %s", errListMicro.ToString())
+		return apigateway.APIGatewayError(errListMicro.Code, errListMicro.ToString())
+	}
+
+	return apigateway.APIGatewayDataResponse(http.StatusOK, listRecord)
+}
@@ -0,0 +1,106 @@
+package lambdahandler
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	book "main/src/books/application/handler"
+	"main/src/books/domain/model"
+	"main/utils/apigateway"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+const defaultMaxBatchSize = 100
+
+var (
+	BOOKS_TABLE         = os.Getenv("BOOKS_TABLE")
+	BOOKS_OUTBOX_TABLE  = os.Getenv("BOOKS_OUTBOX_TABLE")
+	BOOKS_HISTORY_TABLE = os.Getenv("BOOKS_HISTORY_TABLE")
+	BUCKET_NAME         = os.Getenv("BUCKET_NAME")
+	BUCKET_KEY          = os.Getenv("BUCKET_KEY")
+	MAX_BATCH_SIZE      = maxBatchSize()
+	TRASH_RETENTION     = trashRetention()
+)
+
+type batchRequest struct {
+	IDs   []string     `json:"ids"`
+	Books []model.Book `json:"books"`
+}
+
+func maxBatchSize() int {
+	size, err := strconv.Atoi(os.Getenv("MAX_BATCH_SIZE"))
+	if err != nil || size <= 0 {
+		return defaultMaxBatchSize
+	}
+	return size
+}
+
+// trashRetention is how long deleted books stay in the trash, zero for the
+// repository default.
+func trashRetention() time.Duration {
+	days, err := strconv.Atoi(os.Getenv("TRASH_RETENTION_DAYS"))
+	if err != nil || days <= 0 {
+		return 0
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// Handler serves both batch routes: POST /books:batchDelete with {"ids": [...]}
+// and POST /books:batchUpdate with {"books": [...]}.
+func Handler(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+
+	actor, errIdentity := apigateway.ParseAPIGatewayRequestIdentity(request)
+	if errIdentity != nil {
+		log.Printf("Error reading the caller: %s", errIdentity.ToString())
+		return apigateway.APIGatewayError(errIdentity.Code, errIdentity.ToString())
+	}
+	bookMicro := book.MicroAWSBookDynamoDB{
+		Ctx:              ctx,
+		TableName:        BOOKS_TABLE,
+		BucketName:       BUCKET_NAME,
+		BucketKey:        BUCKET_KEY,
+		OutboxTableName:  BOOKS_OUTBOX_TABLE,
+		HistoryTableName: BOOKS_HISTORY_TABLE,
+		Actor:            actor,
+		TrashRetention:   TRASH_RETENTION,
+	}
+
+	var body batchRequest
+	if errBody := apigateway.ParseAPIGatewayRequestBody(request, &body); errBody != nil {
+		log.Printf("Error parsing request body: %s", errBody.ToString())
+		return apigateway.APIGatewayError(errBody.Code, errBody.ToString())
+	}
+
+	if len(body.IDs) > MAX_BATCH_SIZE || len(body.Books) > MAX_BATCH_SIZE {
+		message := fmt.Sprintf("Batch cannot exceed %d books.", MAX_BATCH_SIZE)
+		return apigateway.APIGatewayError(http.StatusRequestEntityTooLarge, message)
+	}
+
+	var report *model.BatchReport
+	switch request.Resource {
+	case "/books:batchDelete":
+		result, errBookMicro := bookMicro.DeleteBooksByIDs(body.IDs)
+		if errBookMicro != nil {
+			log.Printf("Error while deleting books, %s", errBookMicro.ToString())
+			return apigateway.APIGatewayError(errBookMicro.Code, errBookMicro.ToString())
+		}
+		report = result
+	case "/books:batchUpdate":
+		result, errBookMicro := bookMicro.UpdateBooks(body.Books)
+		if errBookMicro != nil {
+			log.Printf("Error while updating books, %s", errBookMicro.ToString())
+			return apigateway.APIGatewayError(errBookMicro.Code, errBookMicro.ToString())
+		}
+		report = result
+	default:
+		return apigateway.APIGatewayError(http.StatusNotFound, "Unknown batch operation "+request.Resource+".")
+	}
+
+	return apigateway.APIGatewayDataResponse(http.StatusOK, report)
+}
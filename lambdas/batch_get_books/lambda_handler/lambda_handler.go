@@ -0,0 +1,43 @@
+package lambdahandler
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"os"
+
+	book "main/src/books/application/handler"
+	"main/utils/apigateway"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+var (
+	BOOKS_TABLE = os.Getenv("BOOKS_TABLE")
+)
+
+type batchGetRequest struct {
+	IDs []string `json:"ids"`
+}
+
+func Handler(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+
+	bookMicro := book.MicroAWSBookDynamoDB{
+		Ctx:       ctx,
+		TableName: BOOKS_TABLE,
+	}
+
+	var body batchGetRequest
+	if errBody := apigateway.ParseAPIGatewayRequestBody(request, &body); errBody != nil {
+		log.Printf("Error parsing request body: %s", errBody.ToString())
+		return apigateway.APIGatewayError(errBody.Code, errBody.ToString())
+	}
+
+	books, errBookMicro := bookMicro.GetBooksByIDs(body.IDs)
+	if errBookMicro != nil {
+		log.Printf("Error while getting books, %s", errBookMicro.ToString())
+		return apigateway.APIGatewayError(errBookMicro.Code, errBookMicro.ToString())
+	}
+
+	return apigateway.APIGatewayDataResponse(http.StatusOK, books)
+}
@@ -0,0 +1 @@
+package lambdahandler_test
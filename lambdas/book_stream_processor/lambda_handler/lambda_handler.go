@@ -0,0 +1,36 @@
+package lambdahandler
+
+import (
+	"context"
+	"log"
+	"os"
+
+	book "main/src/books/application/handler"
+	"main/src/books/application/stream"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+var (
+	BUCKET_NAME = os.Getenv("BUCKET_NAME")
+	BUCKET_KEY  = os.Getenv("BUCKET_KEY")
+)
+
+// Handler consumes the stream of the books table and passes every change to
+// the systems that follow the catalog. Failed records are reported back so
+// only they and the records after them are retried.
+func Handler(ctx context.Context, event events.DynamoDBEvent) (events.DynamoDBEventResponse, error) {
+	bookMicro := book.MicroAWSBookDynamoDB{
+		Ctx:        ctx,
+		BucketName: BUCKET_NAME,
+		BucketKey:  BUCKET_KEY,
+	}
+
+	handlers, errBookMicro := bookMicro.BookChangeHandlers()
+	if errBookMicro != nil {
+		log.Printf("Error while preparing stream handlers, %s", errBookMicro.ToString())
+		return events.DynamoDBEventResponse{}, errBookMicro.ToError()
+	}
+
+	return stream.NewDispatcher(handlers...).Process(event), nil
+}
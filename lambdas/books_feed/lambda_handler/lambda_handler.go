@@ -0,0 +1,98 @@
+package lambdahandler
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+
+	"main/src/books/application/feed"
+	book "main/src/books/application/handler"
+	"main/src/books/application/webpage"
+	"main/utils/apigateway"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+var (
+	BOOKS_TABLE = os.Getenv("BOOKS_TABLE")
+	FEED_SIZE   = os.Getenv("FEED_SIZE")
+	SITE_NAME   = os.Getenv("SITE_NAME")
+	SITE_URL    = os.Getenv("SITE_URL")
+)
+
+// Handler serves the newest added and updated books on /feeds/recent.rss and
+// /feeds/recent.atom. Feed readers poll, so ETag and Last-Modified are sent
+// and a matching conditional GET is answered with an empty 304.
+func Handler(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+
+	format := feed.FormatRSS
+	if strings.HasSuffix(request.Resource, ".atom") {
+		format = feed.FormatAtom
+	}
+
+	limit := feed.DefaultLimit
+	if FEED_SIZE != "" {
+		size, err := strconv.Atoi(FEED_SIZE)
+		if err != nil {
+			log.Printf("Invalid FEED_SIZE %q, using %d", FEED_SIZE, limit)
+		} else {
+			limit = size
+		}
+	}
+
+	bookMicro := book.MicroAWSBookDynamoDB{
+		Ctx:       ctx,
+		TableName: BOOKS_TABLE,
+	}
+
+	books, errBookMicro := bookMicro.GetRecentBooks(limit)
+	if errBookMicro != nil {
+		log.Printf("Error while getting recent books, %s", errBookMicro.ToString())
+		return apigateway.APIGatewayError(errBookMicro.Code, errBookMicro.ToString())
+	}
+
+	baseURL := apigateway.APIGatewayBaseURL(request)
+	recent := &feed.Feed{
+		Site:        &webpage.Site{Name: SITE_NAME, BaseURL: SITE_URL},
+		Title:       "New and updated books",
+		Description: "The most recently added and updated books.",
+		SelfURL:     baseURL + request.Resource,
+		Books:       books,
+	}
+	if recent.Site.BaseURL == "" {
+		recent.Site.BaseURL = baseURL
+	}
+	if SITE_NAME != "" {
+		recent.Title = SITE_NAME + ": " + recent.Title
+	}
+
+	etag := recent.ETag(format)
+	lastModified := recent.LastModified()
+	ifNoneMatch, _ := apigateway.ParseAPIGatewayRequestHeader(request, "If-None-Match")
+	ifModifiedSince, _ := apigateway.ParseAPIGatewayRequestHeader(request, "If-Modified-Since")
+
+	var response events.APIGatewayProxyResponse
+	if feed.NotModified(ifNoneMatch, ifModifiedSince, etag, lastModified) {
+		response, _ = apigateway.APIGatewayContentResponse(http.StatusNotModified, format.ContentType(), nil)
+	} else {
+		render := recent.RSS
+		if format == feed.FormatAtom {
+			render = recent.Atom
+		}
+		body, errRender := render()
+		if errRender != nil {
+			return apigateway.APIGatewayError(errRender.Code, errRender.ToString())
+		}
+		response, _ = apigateway.APIGatewayContentResponse(http.StatusOK, format.ContentType(), body)
+	}
+
+	response.Headers["ETag"] = etag
+	response.Headers["Cache-Control"] = "public, max-age=" + strconv.Itoa(int(feed.MaxAge.Seconds()))
+	if !lastModified.IsZero() {
+		response.Headers["Last-Modified"] = lastModified.Format(http.TimeFormat)
+	}
+	return response, nil
+}
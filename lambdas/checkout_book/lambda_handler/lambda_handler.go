@@ -0,0 +1,53 @@
+package lambdahandler
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"os"
+
+	loan "main/src/loans/application/handler"
+	"main/utils/apigateway"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+var (
+	COPIES_TABLE = os.Getenv("COPIES_TABLE")
+	LOANS_TABLE  = os.Getenv("LOANS_TABLE")
+	HOLDS_TABLE  = os.Getenv("HOLDS_TABLE")
+)
+
+type patronRequest struct {
+	PatronID string `json:"patron_id"`
+}
+
+func Handler(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+
+	loanMicro := loan.MicroAWSLoanDynamoDB{
+		Ctx:         ctx,
+		CopiesTable: COPIES_TABLE,
+		LoansTable:  LOANS_TABLE,
+		HoldsTable:  HOLDS_TABLE,
+	}
+
+	bookId, errApi := apigateway.ParseAPIGatewayRequestParameters(request, "bookId")
+	if errApi != nil {
+		log.Printf("Error parsing request parameters: %v", errApi)
+		return apigateway.APIGatewayError(http.StatusBadRequest, "Error parsing request parameters.")
+	}
+
+	var body patronRequest
+	if errBody := apigateway.ParseAPIGatewayRequestBody(request, &body); errBody != nil {
+		log.Printf("Error parsing request body: %s", errBody.ToString())
+		return apigateway.APIGatewayError(errBody.Code, errBody.ToString())
+	}
+
+	record, errLoanMicro := loanMicro.Checkout(bookId, body.PatronID)
+	if errLoanMicro != nil {
+		log.Printf("Error while checking out book, %s", errLoanMicro.ToString())
+		return apigateway.APIGatewayError(errLoanMicro.Code, errLoanMicro.ToString())
+	}
+
+	return apigateway.APIGatewayDataResponse(http.StatusCreated, record)
+}
@@ -20,18 +20,28 @@ import (
 )
 
 var (
-	BOOKS_TABLE = os.Getenv("BOOKS_TABLE")
-	BUCKET_NAME = os.Getenv("BUCKET_NAME")
-	BUCKET_KEY  = os.Getenv("BUCKET_KEY")
+	BOOKS_TABLE         = os.Getenv("BOOKS_TABLE")
+	BOOKS_OUTBOX_TABLE  = os.Getenv("BOOKS_OUTBOX_TABLE")
+	BOOKS_HISTORY_TABLE = os.Getenv("BOOKS_HISTORY_TABLE")
+	BUCKET_NAME         = os.Getenv("BUCKET_NAME")
+	BUCKET_KEY          = os.Getenv("BUCKET_KEY")
 )
 
 func Handler(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
 
+	actor, errIdentity := apigateway.ParseAPIGatewayRequestIdentity(request)
+	if errIdentity != nil {
+		log.Printf("Error reading the caller: %s", errIdentity.ToString())
+		return apigateway.APIGatewayError(errIdentity.Code, errIdentity.ToString())
+	}
 	bookMicro := book.MicroAWSBookDynamoDB{
-		Ctx:        ctx,
-		TableName:  BOOKS_TABLE,
-		BucketName: BUCKET_NAME,
-		BucketKey:  BUCKET_KEY,
+		Ctx:              ctx,
+		TableName:        BOOKS_TABLE,
+		BucketName:       BUCKET_NAME,
+		BucketKey:        BUCKET_KEY,
+		OutboxTableName:  BOOKS_OUTBOX_TABLE,
+		HistoryTableName: BOOKS_HISTORY_TABLE,
+		Actor:            actor,
 	}
 
 	decodedBody, err := base64.StdEncoding.DecodeString(request.Body)
@@ -0,0 +1,49 @@
+package lambdahandler
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"os"
+
+	list "main/src/lists/application/handler"
+	"main/src/lists/domain/model"
+	"main/utils/apigateway"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+var (
+	LISTS_TABLE = os.Getenv("LISTS_TABLE")
+	BOOKS_TABLE = os.Getenv("BOOKS_TABLE")
+)
+
+func Handler(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+
+	listMicro := list.MicroAWSListDynamoDB{
+		Ctx:        ctx,
+		ListsTable: LISTS_TABLE,
+		BooksTable: BOOKS_TABLE,
+	}
+
+	userId, errIdentity := apigateway.ParseAPIGatewayRequestIdentity(request)
+	if errIdentity != nil {
+		log.Printf("Error reading the caller: %s", errIdentity.ToString())
+		return apigateway.APIGatewayError(errIdentity.Code, errIdentity.ToString())
+	}
+
+	var body model.ReadingList
+	if errBody := apigateway.ParseAPIGatewayRequestBody(request, &body); errBody != nil {
+		log.Printf("Error parsing request body: %s", errBody.ToString())
+		return apigateway.APIGatewayError(errBody.Code, errBody.ToString())
+	}
+
+	body.OwnerID = userId
+	listRecord, errListMicro := listMicro.CreateList(&body)
+	if errListMicro != nil {
+		log.Printf("Error while creating list, %s", errListMicro.ToString())
+		return apigateway.APIGatewayError(errListMicro.Code, errListMicro.ToString())
+	}
+
+	return apigateway.APIGatewayDataResponse(http.StatusCreated, listRecord)
+}
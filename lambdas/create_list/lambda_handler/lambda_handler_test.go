@@ -0,0 +1,29 @@
+package lambdahandler_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	index "main/lambdas/create_list/lambda_handler"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/suite"
+)
+
+type CreateListHandlerSuite struct {
+	suite.Suite
+}
+
+func (suite *CreateListHandlerSuite) TestTheOwnerIsNotTakenFromAHeader() {
+	response, err := index.Handler(context.TODO(), events.APIGatewayProxyRequest{
+		Headers: map[string]string{"X-User-Id": "someone-else"},
+		Body:    `{"name": "Favorites"}`,
+	})
+	suite.NoError(err)
+	suite.Equal(http.StatusUnauthorized, response.StatusCode)
+}
+
+func TestCreateListHandlerSuite(t *testing.T) {
+	suite.Run(t, new(CreateListHandlerSuite))
+}
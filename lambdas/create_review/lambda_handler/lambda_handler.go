@@ -0,0 +1,49 @@
+package lambdahandler
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"os"
+
+	review "main/src/reviews/application/handler"
+	"main/src/reviews/domain/model"
+	"main/utils/apigateway"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+var (
+	REVIEWS_TABLE = os.Getenv("REVIEWS_TABLE")
+	BOOKS_TABLE   = os.Getenv("BOOKS_TABLE")
+)
+
+func Handler(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+
+	reviewMicro := review.MicroAWSReviewDynamoDB{
+		Ctx:          ctx,
+		ReviewsTable: REVIEWS_TABLE,
+		BooksTable:   BOOKS_TABLE,
+	}
+
+	bookId, errApi := apigateway.ParseAPIGatewayRequestParameters(request, "bookId")
+	if errApi != nil {
+		log.Printf("Error parsing request parameters: %v", errApi)
+		return apigateway.APIGatewayError(http.StatusBadRequest, "Error parsing request parameters.")
+	}
+
+	var body model.Review
+	if errBody := apigateway.ParseAPIGatewayRequestBody(request, &body); errBody != nil {
+		log.Printf("Error parsing request body: %s", errBody.ToString())
+		return apigateway.APIGatewayError(errBody.Code, errBody.ToString())
+	}
+
+	body.BookID = bookId
+	newReview, errReviewMicro := reviewMicro.CreateReview(&body)
+	if errReviewMicro != nil {
+		log.Printf("Error while creating review, %s", errReviewMicro.ToString())
+		return apigateway.APIGatewayError(errReviewMicro.Code, errReviewMicro.ToString())
+	}
+
+	return apigateway.APIGatewayDataResponse(http.StatusCreated, newReview)
+}
@@ -0,0 +1,33 @@
+package lambdahandler_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	index "main/lambdas/create_review/lambda_handler"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+)
+
+type CreateReviewHandlerSuite struct {
+	suite.Suite
+}
+
+func (suite *CreateReviewHandlerSuite) TestRejectedOutsideDynamoDB() {
+	for _, store := range []string{"sql", "bolt", "eventsourced"} {
+		suite.T().Setenv("BOOKS_STORE", store)
+		response, err := index.Handler(context.TODO(), events.APIGatewayProxyRequest{
+			PathParameters: map[string]string{"bookId": uuid.NewString()},
+			Body:           `{"user_id": "reader", "rating": 5}`,
+		})
+		suite.NoError(err)
+		suite.Equal(http.StatusNotImplemented, response.StatusCode, store)
+	}
+}
+
+func TestCreateReviewHandlerSuite(t *testing.T) {
+	suite.Run(t, new(CreateReviewHandlerSuite))
+}
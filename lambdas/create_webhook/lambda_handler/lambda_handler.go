@@ -0,0 +1,42 @@
+package lambdahandler
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"os"
+
+	webhook "main/src/webhooks/application/handler"
+	"main/src/webhooks/domain/model"
+	"main/utils/apigateway"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+var (
+	WEBHOOKS_TABLE   = os.Getenv("WEBHOOKS_TABLE")
+	DELIVERIES_TABLE = os.Getenv("DELIVERIES_TABLE")
+)
+
+func Handler(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+
+	webhookMicro := webhook.MicroAWSWebhookDynamoDB{
+		Ctx:             ctx,
+		WebhooksTable:   WEBHOOKS_TABLE,
+		DeliveriesTable: DELIVERIES_TABLE,
+	}
+
+	var body model.Subscription
+	if errBody := apigateway.ParseAPIGatewayRequestBody(request, &body); errBody != nil {
+		log.Printf("Error parsing request body: %s", errBody.ToString())
+		return apigateway.APIGatewayError(errBody.Code, errBody.ToString())
+	}
+
+	newWebhook, errWebhookMicro := webhookMicro.CreateWebhook(&body)
+	if errWebhookMicro != nil {
+		log.Printf("Error while creating webhook, %s", errWebhookMicro.ToString())
+		return apigateway.APIGatewayError(errWebhookMicro.Code, errWebhookMicro.ToString())
+	}
+
+	return apigateway.APIGatewayDataResponse(http.StatusCreated, newWebhook)
+}
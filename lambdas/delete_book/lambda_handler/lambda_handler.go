@@ -6,7 +6,8 @@ import (
 	"main/utils/apigateway"
 	"net/http"
 	"os"
-	"strings"
+	"strconv"
+	"time"
 
 	book "main/src/books/application/handler"
 
@@ -14,18 +15,36 @@ import (
 )
 
 var (
-	BOOKS_TABLE = os.Getenv("BOOKS_TABLE")
-	BUCKET_NAME = os.Getenv("BUCKET_NAME")
-	BUCKET_KEY  = os.Getenv("BUCKET_KEY")
+	BOOKS_TABLE         = os.Getenv("BOOKS_TABLE")
+	BOOKS_OUTBOX_TABLE  = os.Getenv("BOOKS_OUTBOX_TABLE")
+	BOOKS_HISTORY_TABLE = os.Getenv("BOOKS_HISTORY_TABLE")
+	BUCKET_NAME         = os.Getenv("BUCKET_NAME")
+	BUCKET_KEY          = os.Getenv("BUCKET_KEY")
+	// TRASH_RETENTION_DAYS is how long deleted books can be restored before
+	// they are purged with their cover.
+	TRASH_RETENTION_DAYS = os.Getenv("TRASH_RETENTION_DAYS")
 )
 
 func Handler(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
 
+	actor, errIdentity := apigateway.ParseAPIGatewayRequestIdentity(request)
+	if errIdentity != nil {
+		log.Printf("Error reading the caller: %s", errIdentity.ToString())
+		return apigateway.APIGatewayError(errIdentity.Code, errIdentity.ToString())
+	}
+	var retention time.Duration
+	if days, err := strconv.Atoi(TRASH_RETENTION_DAYS); err == nil && days > 0 {
+		retention = time.Duration(days) * 24 * time.Hour
+	}
 	bookMicro := book.MicroAWSBookDynamoDB{
-		Ctx:        ctx,
-		TableName:  BOOKS_TABLE,
-		BucketName: BUCKET_NAME,
-		BucketKey:  BUCKET_KEY,
+		Ctx:              ctx,
+		TableName:        BOOKS_TABLE,
+		BucketName:       BUCKET_NAME,
+		BucketKey:        BUCKET_KEY,
+		OutboxTableName:  BOOKS_OUTBOX_TABLE,
+		HistoryTableName: BOOKS_HISTORY_TABLE,
+		Actor:            actor,
+		TrashRetention:   retention,
 	}
 
 	bookId, errApi := apigateway.ParseAPIGatewayRequestParameters(request, "bookId")
@@ -34,28 +53,14 @@ func Handler(ctx context.Context, request events.APIGatewayProxyRequest) (events
 		return apigateway.APIGatewayError(http.StatusBadRequest, "Error parsing request parameters.")
 	}
 
-	book_record, errBookMicro := bookMicro.GetBookByID(bookId) 
-	if errBookMicro != nil {
-		log.Printf("Error while saving book file, %s", errBookMicro.ToString())
-		return apigateway.APIGatewayError(errBookMicro.Code, errBookMicro.ToString())
-	}
-
-	url_parts := strings.Split(book_record.ImgURL, BUCKET_KEY)
-	fileName := url_parts[1]
-	customKey := BUCKET_KEY + fileName
-
-	errBookMicro = bookMicro.DeleteBookFile(customKey)
-	if errBookMicro != nil {
-		log.Printf("Error while saving book file, %s", errBookMicro.ToString())
-		return apigateway.APIGatewayError(errBookMicro.Code, errBookMicro.ToString())
-	}
-
-	errBookMicro = bookMicro.DeleteBookByID(bookId)
+	// The cover stays with the book in the trash and is removed when the
+	// book is purged.
+	errBookMicro := bookMicro.DeleteBookByID(bookId)
 	if errBookMicro != nil {
 		log.Printf("Error while creating book, %s", errBookMicro.ToString())
 		return apigateway.APIGatewayError(errBookMicro.Code, errBookMicro.ToString())
 	}
 
-	message := "Book " + bookId + " deleted"
+	message := "Book " + bookId + " moved to the trash"
 	return apigateway.APIGatewayMessageResponse(http.StatusOK, message)
 }
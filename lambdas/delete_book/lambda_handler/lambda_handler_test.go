@@ -1 +1,80 @@
-package lambdahandler_test
\ No newline at end of file
+package lambdahandler_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	index "main/lambdas/delete_book/lambda_handler"
+	book "main/src/books/application/handler"
+	"main/src/books/domain/repository"
+	appError "main/utils/error"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	repoMock "main/mocks"
+)
+
+type DeleteBookHandlerSuite struct {
+	suite.Suite
+	bookRepository *repoMock.BookRepository
+	actor          string
+}
+
+func (suite *DeleteBookHandlerSuite) SetupTest() {
+	suite.bookRepository = new(repoMock.BookRepository)
+	book.UseBookContainer(&book.BookContainer{
+		BookRepository: func(micro *book.MicroAWSBookDynamoDB, tracked bool) (repository.BookRepository, *appError.Error) {
+			suite.True(tracked)
+			suite.actor = micro.Actor
+			return suite.bookRepository, nil
+		},
+	})
+}
+
+func (suite *DeleteBookHandlerSuite) TearDownTest() {
+	book.UseBookContainer(nil)
+}
+
+func (suite *DeleteBookHandlerSuite) TestMovesTheBookToTheTrash() {
+	bookID := uuid.NewString()
+	suite.bookRepository.On("DeleteBookByID", bookID).Return(nil)
+
+	response, err := index.Handler(context.TODO(), events.APIGatewayProxyRequest{
+		PathParameters: map[string]string{"bookId": bookID},
+		RequestContext: events.APIGatewayProxyRequestContext{
+			Authorizer: map[string]interface{}{"claims": map[string]interface{}{"sub": "librarian"}},
+		},
+	})
+	suite.NoError(err)
+	suite.Equal(http.StatusOK, response.StatusCode)
+	suite.Equal("librarian", suite.actor)
+	suite.bookRepository.AssertExpectations(suite.T())
+}
+
+func (suite *DeleteBookHandlerSuite) TestMissingBookID() {
+	response, err := index.Handler(context.TODO(), events.APIGatewayProxyRequest{
+		RequestContext: events.APIGatewayProxyRequestContext{
+			Authorizer: map[string]interface{}{"principalId": "librarian"},
+		},
+	})
+	suite.NoError(err)
+	suite.Equal(http.StatusBadRequest, response.StatusCode)
+}
+
+func (suite *DeleteBookHandlerSuite) TestTheActorIsNotTakenFromAHeader() {
+	response, err := index.Handler(context.TODO(), events.APIGatewayProxyRequest{
+		Headers:        map[string]string{"X-User-Id": "librarian"},
+		PathParameters: map[string]string{"bookId": uuid.NewString()},
+	})
+	suite.NoError(err)
+	suite.Equal(http.StatusUnauthorized, response.StatusCode)
+	suite.bookRepository.AssertNotCalled(suite.T(), "DeleteBookByID", mock.Anything)
+}
+
+func TestDeleteBookHandlerSuite(t *testing.T) {
+	suite.Run(t, new(DeleteBookHandlerSuite))
+}
@@ -0,0 +1,48 @@
+package lambdahandler
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"os"
+
+	list "main/src/lists/application/handler"
+	"main/utils/apigateway"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+var (
+	LISTS_TABLE = os.Getenv("LISTS_TABLE")
+	BOOKS_TABLE = os.Getenv("BOOKS_TABLE")
+)
+
+func Handler(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+
+	listMicro := list.MicroAWSListDynamoDB{
+		Ctx:        ctx,
+		ListsTable: LISTS_TABLE,
+		BooksTable: BOOKS_TABLE,
+	}
+
+	listId, errApi := apigateway.ParseAPIGatewayRequestParameters(request, "listId")
+	if errApi != nil {
+		log.Printf("Error parsing request parameters: %v", errApi)
+		return apigateway.APIGatewayError(http.StatusBadRequest, "Error parsing request parameters.")
+	}
+
+	userId, errIdentity := apigateway.ParseAPIGatewayRequestIdentity(request)
+	if errIdentity != nil {
+		log.Printf("Error reading the caller: %s", errIdentity.ToString())
+		return apigateway.APIGatewayError(errIdentity.Code, errIdentity.ToString())
+	}
+
+	errListMicro := listMicro.DeleteList(listId, userId)
+	if errListMicro != nil {
+		log.Printf("Error while deleting list, %s", errListMicro.ToString())
+		return apigateway.APIGatewayError(errListMicro.Code, errListMicro.ToString())
+	}
+
+	message := "List " + listId + " deleted"
+	return apigateway.APIGatewayMessageResponse(http.StatusOK, message)
+}
@@ -0,0 +1,48 @@
+package lambdahandler
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"os"
+
+	review "main/src/reviews/application/handler"
+	"main/utils/apigateway"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+var (
+	REVIEWS_TABLE = os.Getenv("REVIEWS_TABLE")
+	BOOKS_TABLE   = os.Getenv("BOOKS_TABLE")
+)
+
+func Handler(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+
+	reviewMicro := review.MicroAWSReviewDynamoDB{
+		Ctx:          ctx,
+		ReviewsTable: REVIEWS_TABLE,
+		BooksTable:   BOOKS_TABLE,
+	}
+
+	bookId, errApi := apigateway.ParseAPIGatewayRequestParameters(request, "bookId")
+	if errApi != nil {
+		log.Printf("Error parsing request parameters: %v", errApi)
+		return apigateway.APIGatewayError(http.StatusBadRequest, "Error parsing request parameters.")
+	}
+
+	userId, errApi := apigateway.ParseAPIGatewayRequestParameters(request, "userId")
+	if errApi != nil {
+		log.Printf("Error parsing request parameters: %v", errApi)
+		return apigateway.APIGatewayError(http.StatusBadRequest, "Error parsing request parameters.")
+	}
+
+	errReviewMicro := reviewMicro.DeleteReview(bookId, userId)
+	if errReviewMicro != nil {
+		log.Printf("Error while deleting review, %s", errReviewMicro.ToString())
+		return apigateway.APIGatewayError(errReviewMicro.Code, errReviewMicro.ToString())
+	}
+
+	message := "Review of book " + bookId + " by " + userId + " deleted"
+	return apigateway.APIGatewayMessageResponse(http.StatusOK, message)
+}
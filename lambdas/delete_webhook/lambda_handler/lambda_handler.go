@@ -0,0 +1,41 @@
+package lambdahandler
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"os"
+
+	webhook "main/src/webhooks/application/handler"
+	"main/utils/apigateway"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+var (
+	WEBHOOKS_TABLE   = os.Getenv("WEBHOOKS_TABLE")
+	DELIVERIES_TABLE = os.Getenv("DELIVERIES_TABLE")
+)
+
+func Handler(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+
+	webhookMicro := webhook.MicroAWSWebhookDynamoDB{
+		Ctx:             ctx,
+		WebhooksTable:   WEBHOOKS_TABLE,
+		DeliveriesTable: DELIVERIES_TABLE,
+	}
+
+	webhookId, errApi := apigateway.ParseAPIGatewayRequestParameters(request, "webhookId")
+	if errApi != nil {
+		log.Printf("Error parsing request parameters: %v", errApi)
+		return apigateway.APIGatewayError(http.StatusBadRequest, "Error parsing request parameters.")
+	}
+
+	errWebhookMicro := webhookMicro.DeleteWebhook(webhookId)
+	if errWebhookMicro != nil {
+		log.Printf("Error while deleting webhook, %s", errWebhookMicro.ToString())
+		return apigateway.APIGatewayError(errWebhookMicro.Code, errWebhookMicro.ToString())
+	}
+
+	return apigateway.APIGatewayMessageResponse(http.StatusOK, "Webhook "+webhookId+" deleted")
+}
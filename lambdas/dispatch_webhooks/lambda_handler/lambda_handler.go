@@ -0,0 +1,53 @@
+package lambdahandler
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"os"
+	"strconv"
+
+	bookModel "main/src/books/domain/model"
+	"main/src/webhooks/application/dispatcher"
+	webhook "main/src/webhooks/application/handler"
+	appError "main/utils/error"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+var (
+	WEBHOOKS_TABLE       = os.Getenv("WEBHOOKS_TABLE")
+	DELIVERIES_TABLE     = os.Getenv("DELIVERIES_TABLE")
+	WEBHOOK_MAX_ATTEMPTS = os.Getenv("WEBHOOK_MAX_ATTEMPTS")
+)
+
+// Handler receives the book events published on the event bus and delivers
+// them to the webhooks subscribed to them. Failed deliveries are retried by
+// retry_webhook_deliveries, so an error here only means the event could not
+// be dispatched at all and EventBridge should retry it.
+func Handler(ctx context.Context, event events.CloudWatchEvent) error {
+	var bookEvent bookModel.BookEvent
+	if err := json.Unmarshal(event.Detail, &bookEvent); err != nil {
+		log.Printf("Error unmarshaling book event: %v, ID: %s", err, event.ID)
+		return appError.NewBadRequestError(err.Error()).ToError()
+	}
+
+	options := dispatcher.DefaultOptions()
+	if attempts, err := strconv.Atoi(WEBHOOK_MAX_ATTEMPTS); err == nil && attempts > 0 {
+		options.MaxAttempts = attempts
+	}
+
+	webhookMicro := webhook.MicroAWSWebhookDynamoDB{
+		Ctx:             ctx,
+		WebhooksTable:   WEBHOOKS_TABLE,
+		DeliveriesTable: DELIVERIES_TABLE,
+	}
+	report, errWebhookMicro := webhookMicro.DispatchBookEvent(&bookEvent, options)
+	if errWebhookMicro != nil {
+		log.Printf("Error while dispatching book event, %s", errWebhookMicro.ToString())
+		return errWebhookMicro.ToError()
+	}
+
+	log.Printf("Dispatched book event %s: %+v", bookEvent.ID, report)
+	return nil
+}
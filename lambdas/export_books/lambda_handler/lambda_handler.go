@@ -0,0 +1,54 @@
+package lambdahandler
+
+import (
+	"context"
+	"log"
+	"os"
+	"strconv"
+
+	"main/src/books/application/exporter"
+	book "main/src/books/application/handler"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+var (
+	BOOKS_TABLE   = os.Getenv("BOOKS_TABLE")
+	BUCKET_NAME   = os.Getenv("BUCKET_NAME")
+	BUCKET_KEY    = os.Getenv("BUCKET_KEY")
+	EXPORT_FORMAT = os.Getenv("EXPORT_FORMAT")
+	EXPORT_GZIP   = os.Getenv("EXPORT_GZIP") == "true"
+	SCAN_SEGMENTS = os.Getenv("SCAN_SEGMENTS")
+)
+
+// Handler runs on a schedule and dumps the catalog to the bucket.
+func Handler(ctx context.Context, event events.CloudWatchEvent) error {
+	format, errFormat := exporter.ParseFormat(EXPORT_FORMAT)
+	if errFormat != nil {
+		log.Printf("Error reading export format, %s", errFormat.ToString())
+		return errFormat.ToError()
+	}
+	segments, err := strconv.Atoi(SCAN_SEGMENTS)
+	if err != nil {
+		segments = exporter.DefaultSegments
+	}
+
+	bookMicro := book.MicroAWSBookDynamoDB{
+		Ctx:        ctx,
+		TableName:  BOOKS_TABLE,
+		BucketName: BUCKET_NAME,
+		BucketKey:  BUCKET_KEY,
+	}
+	manifest, errBookMicro := bookMicro.ExportBooks(exporter.Options{
+		Format:   format,
+		Gzip:     EXPORT_GZIP,
+		Segments: segments,
+	})
+	if errBookMicro != nil {
+		log.Printf("Error while exporting books, %s", errBookMicro.ToString())
+		return errBookMicro.ToError()
+	}
+
+	log.Printf("Exported %d books to %s", manifest.Rows, manifest.Key)
+	return nil
+}
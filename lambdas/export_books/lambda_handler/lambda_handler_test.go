@@ -0,0 +1,88 @@
+package lambdahandler_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"io"
+	"testing"
+
+	index "main/lambdas/export_books/lambda_handler"
+	"main/src/books/application/exporter"
+	book "main/src/books/application/handler"
+	"main/src/books/domain/model"
+	"main/src/books/domain/repository"
+	appError "main/utils/error"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	repoMock "main/mocks"
+)
+
+type ExportBooksHandlerSuite struct {
+	suite.Suite
+	bookRepository     *repoMock.BookRepository
+	bookFileRepository *repoMock.BookFileRepository
+}
+
+func (suite *ExportBooksHandlerSuite) SetupTest() {
+	suite.bookRepository = new(repoMock.BookRepository)
+	suite.bookFileRepository = new(repoMock.BookFileRepository)
+	book.UseBookContainer(&book.BookContainer{
+		BookRepository: func(micro *book.MicroAWSBookDynamoDB, tracked bool) (repository.BookRepository, *appError.Error) {
+			return suite.bookRepository, nil
+		},
+		BookFileRepository: func(micro *book.MicroAWSBookDynamoDB) (repository.BookFileRepository, *appError.Error) {
+			return suite.bookFileRepository, nil
+		},
+	})
+}
+
+func (suite *ExportBooksHandlerSuite) TearDownTest() {
+	book.UseBookContainer(nil)
+}
+
+func (suite *ExportBooksHandlerSuite) TestStreamsTheExportToTheBucket() {
+	books := []model.Book{{ID: uuid.NewString(), Name: "Dune"}, {ID: uuid.NewString(), Name: "Hyperion"}}
+	suite.bookRepository.On("ScanBooks", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		visit := args.Get(1).(func([]model.Book) *appError.Error)
+		for _, scanned := range books {
+			visit([]model.Book{scanned})
+		}
+	}).Return(nil)
+	var uploaded bytes.Buffer
+	suite.bookFileRepository.On("UploadBookFile", mock.Anything, mock.Anything, ".csv").Run(func(args mock.Arguments) {
+		_, err := io.Copy(&uploaded, args.Get(0).(io.Reader))
+		suite.NoError(err)
+	}).Return(nil)
+	suite.bookFileRepository.On("SaveBookFile", mock.Anything, exporter.ManifestKey, ".json").Return(nil)
+
+	suite.NoError(index.Handler(context.TODO(), events.CloudWatchEvent{}))
+	records, err := csv.NewReader(&uploaded).ReadAll()
+	suite.NoError(err)
+	suite.Len(records, 3)
+	suite.bookFileRepository.AssertExpectations(suite.T())
+}
+
+func (suite *ExportBooksHandlerSuite) TestFailedUploadStopsTheExport() {
+	suite.bookRepository.On("ScanBooks", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		visit := args.Get(1).(func([]model.Book) *appError.Error)
+		for i := 0; i < 1000; i++ {
+			if visit([]model.Book{{ID: uuid.NewString(), Name: "Dune"}}) != nil {
+				return
+			}
+		}
+	}).Return(nil)
+	suite.bookFileRepository.On("UploadBookFile", mock.Anything, mock.Anything, ".csv").
+		Return(appError.NewUnexpectedError("Error while uploading object to S3"))
+
+	suite.Error(index.Handler(context.TODO(), events.CloudWatchEvent{}))
+	suite.bookFileRepository.AssertNotCalled(suite.T(), "SaveBookFile", mock.Anything, exporter.ManifestKey, ".json")
+}
+
+func TestExportBooksHandlerSuite(t *testing.T) {
+	suite.Run(t, new(ExportBooksHandlerSuite))
+}
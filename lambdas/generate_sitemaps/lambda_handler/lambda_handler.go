@@ -0,0 +1,55 @@
+package lambdahandler
+
+import (
+	"context"
+	"log"
+	"os"
+	"strconv"
+
+	book "main/src/books/application/handler"
+	"main/src/books/application/sitemap"
+	"main/src/books/application/webpage"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+var (
+	BOOKS_TABLE   = os.Getenv("BOOKS_TABLE")
+	BUCKET_NAME   = os.Getenv("BUCKET_NAME")
+	BUCKET_KEY    = os.Getenv("BUCKET_KEY")
+	SITE_URL      = os.Getenv("SITE_URL")
+	SITEMAP_URL   = os.Getenv("SITEMAP_URL")
+	SCAN_SEGMENTS = os.Getenv("SCAN_SEGMENTS")
+)
+
+// Handler runs on a schedule and rewrites the sitemaps of the catalog in the
+// bucket.
+func Handler(ctx context.Context, event events.CloudWatchEvent) error {
+	segments, err := strconv.Atoi(SCAN_SEGMENTS)
+	if err != nil {
+		segments = sitemap.DefaultSegments
+	}
+	sitemapURL := SITEMAP_URL
+	if sitemapURL == "" {
+		sitemapURL = SITE_URL + "/" + sitemap.Prefix
+	}
+
+	bookMicro := book.MicroAWSBookDynamoDB{
+		Ctx:        ctx,
+		TableName:  BOOKS_TABLE,
+		BucketName: BUCKET_NAME,
+		BucketKey:  BUCKET_KEY,
+	}
+	index, errBookMicro := bookMicro.GenerateSitemaps(sitemap.Options{
+		Site:     &webpage.Site{BaseURL: SITE_URL},
+		BaseURL:  sitemapURL,
+		Segments: segments,
+	})
+	if errBookMicro != nil {
+		log.Printf("Error while generating sitemaps, %s", errBookMicro.ToString())
+		return errBookMicro.ToError()
+	}
+
+	log.Printf("Generated %d sitemaps with %d URLs, index at %s", len(index.Sitemaps), index.URLs, index.Key)
+	return nil
+}
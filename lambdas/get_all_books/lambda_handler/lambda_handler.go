@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"os"
 
+	"main/src/books/application/catalog"
 	book "main/src/books/application/handler"
 	"main/utils/apigateway"
 
@@ -18,6 +19,13 @@ var (
 
 func Handler(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
 
+	// Library systems ask for MARC or Dublin Core records, everyone else gets JSON.
+	accept, _ := apigateway.ParseAPIGatewayRequestHeader(request, "Accept")
+	mediaType, ok := catalog.Negotiate(accept)
+	if !ok {
+		return apigateway.APIGatewayError(http.StatusNotAcceptable, "Supported formats are application/json, application/marcxml+xml, application/marc and application/oai_dc+xml.")
+	}
+
 	bookMicro := book.MicroAWSBookDynamoDB{
 		Ctx:        ctx,
 		TableName:  BOOKS_TABLE,
@@ -29,5 +37,12 @@ func Handler(ctx context.Context, request events.APIGatewayProxyRequest) (events
 		return apigateway.APIGatewayError(errBookMicro.Code, errBookMicro.ToString())
 	}
 
-	return apigateway.APIGatewayDataResponse(http.StatusOK, book_record)
+	if mediaType == catalog.MediaJSON {
+		return apigateway.APIGatewayDataResponse(http.StatusOK, book_record)
+	}
+	body, errCatalog := catalog.MarshalBooks(mediaType, book_record)
+	if errCatalog != nil {
+		return apigateway.APIGatewayError(errCatalog.Code, errCatalog.ToString())
+	}
+	return apigateway.APIGatewayContentResponse(http.StatusOK, string(mediaType), body)
 }
\ No newline at end of file
@@ -1,43 +1,10 @@
 package main
 
 import (
-	"context"
-	"encoding/json"
-	"net/http"
-
-	"github.com/aws/aws-lambda-go/events"
 	"github.com/aws/aws-lambda-go/lambda"
+	index "main/lambdas/get_all_books/lambda_handler"
 )
 
-var HeadersJSON = map[string]string{
-	"Access-Control-Allow-Origin":  "*",
-	"Access-Control-Allow-Methods": "DELETE,GET,HEAD,POST,PUT",
-	"Access-Control-Allow-Headers": "Content-Type,X-Amz-Date,Authorization,X-Api-Key,X-Amz-Security-Token",
-	"Content-Type": "application/json",
-}
-
-func Handler(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
-	requestBody, err := json.Marshal(request)
-	if err != nil {
-		return events.APIGatewayProxyResponse{
-			Body:       string(err.Error()),
-			StatusCode: http.StatusInternalServerError,
-			Headers: map[string]string{
-				"Content-Type": "application/json",
-			},
-		}, err
-	}
-
-	// Devolver el JSON como respuesta
-	return events.APIGatewayProxyResponse{
-		Body:       string(requestBody),
-		StatusCode: http.StatusOK,
-		Headers: map[string]string{
-			"Content-Type": "application/json",
-		},
-	}, nil
-}
-
 func main() {
-	lambda.Start(Handler)
-}
\ No newline at end of file
+	lambda.Start(index.Handler)
+}
@@ -0,0 +1,89 @@
+package lambdahandler
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"os"
+
+	"main/src/books/application/catalog"
+	book "main/src/books/application/handler"
+	"main/src/books/application/webpage"
+	"main/utils/apigateway"
+	appError "main/utils/error"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+var (
+	BOOKS_TABLE = os.Getenv("BOOKS_TABLE")
+	SITE_NAME   = os.Getenv("SITE_NAME")
+	SITE_URL    = os.Getenv("SITE_URL")
+)
+
+// offers are the catalog formats plus the public page of the book, which
+// browsers and link preview crawlers get through their text/html preference.
+var offers = []catalog.MediaType{
+	catalog.MediaJSON, catalog.MediaMARCXML, catalog.MediaMARC, catalog.MediaDublinCore,
+	webpage.MediaHTML, webpage.MediaJSONLD,
+}
+
+func Handler(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+
+	// Library systems ask for MARC or Dublin Core records, browsers for HTML,
+	// everyone else gets JSON.
+	accept, _ := apigateway.ParseAPIGatewayRequestHeader(request, "Accept")
+	mediaType, ok := catalog.Negotiate(accept, offers...)
+	if !ok {
+		return apigateway.APIGatewayError(http.StatusNotAcceptable, "Supported formats are application/json, application/marcxml+xml, application/marc, application/oai_dc+xml, text/html and application/ld+json.")
+	}
+
+	bookMicro := book.MicroAWSBookDynamoDB{
+		Ctx:       ctx,
+		TableName: BOOKS_TABLE,
+	}
+
+	bookId, errApi := apigateway.ParseAPIGatewayRequestParameters(request, "bookId")
+	if errApi != nil {
+		log.Printf("Error parsing request parameters: %v", errApi)
+		return apigateway.APIGatewayError(http.StatusBadRequest, "Error parsing request parameters.")
+	}
+
+	book_record, errBookMicro := bookMicro.GetBookByID(bookId)
+	if errBookMicro != nil {
+		log.Printf("Error while saving book file, %s", errBookMicro.ToString())
+		return apigateway.APIGatewayError(errBookMicro.Code, errBookMicro.ToString())
+	}
+
+	// JSON keeps answering an empty book for an unknown ID, but a record or
+	// page of nothing must not be served, let alone indexed.
+	if mediaType != catalog.MediaJSON && book_record.ID == "" {
+		return apigateway.APIGatewayError(http.StatusNotFound, "Book not found.")
+	}
+
+	site := &webpage.Site{Name: SITE_NAME, BaseURL: SITE_URL}
+	if site.BaseURL == "" {
+		site.BaseURL = apigateway.APIGatewayBaseURL(request)
+	}
+
+	var (
+		body        []byte
+		errRender   *appError.Error
+		contentType = string(mediaType)
+	)
+	switch mediaType {
+	case catalog.MediaJSON:
+		return apigateway.APIGatewayDataResponse(http.StatusOK, book_record)
+	case webpage.MediaHTML:
+		body, errRender = webpage.RenderHTML(site, book_record)
+		contentType = "text/html; charset=utf-8"
+	case webpage.MediaJSONLD:
+		body, errRender = webpage.MarshalJSONLD(site, book_record)
+	default:
+		body, errRender = catalog.MarshalBook(mediaType, book_record)
+	}
+	if errRender != nil {
+		return apigateway.APIGatewayError(errRender.Code, errRender.ToString())
+	}
+	return apigateway.APIGatewayContentResponse(http.StatusOK, contentType, body)
+}
@@ -0,0 +1,121 @@
+package lambdahandler_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	index "main/lambdas/get_book_by_id/lambda_handler"
+	"main/src/books/application/catalog"
+	book "main/src/books/application/handler"
+	"main/src/books/application/service"
+	"main/src/books/application/webpage"
+	"main/src/books/domain/model"
+	"main/src/books/domain/repository"
+	appError "main/utils/error"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+
+	repoMock "main/mocks"
+)
+
+type GetBookByIDHandlerSuite struct {
+	suite.Suite
+	bookRepository *repoMock.BookRepository
+	testBook       *model.Book
+}
+
+func (suite *GetBookByIDHandlerSuite) SetupTest() {
+	suite.bookRepository = new(repoMock.BookRepository)
+	book.UseBookContainer(&book.BookContainer{
+		BookRepository: func(*book.MicroAWSBookDynamoDB, bool) (repository.BookRepository, *appError.Error) {
+			return suite.bookRepository, nil
+		},
+		BookCache: service.NewBookCache(10, time.Minute, time.Minute),
+	})
+	suite.testBook = &model.Book{ID: uuid.NewString(), Name: "Test Book"}
+}
+
+func (suite *GetBookByIDHandlerSuite) TearDownTest() {
+	book.UseBookContainer(nil)
+}
+
+func (suite *GetBookByIDHandlerSuite) request(bookID string) events.APIGatewayProxyResponse {
+	return suite.requestAs(bookID, "")
+}
+
+func (suite *GetBookByIDHandlerSuite) requestAs(bookID string, accept catalog.MediaType) events.APIGatewayProxyResponse {
+	response, err := index.Handler(context.TODO(), events.APIGatewayProxyRequest{
+		Headers:        map[string]string{"Accept": string(accept)},
+		PathParameters: map[string]string{"bookId": bookID},
+	})
+	suite.Require().NoError(err)
+	return response
+}
+
+func (suite *GetBookByIDHandlerSuite) TestReturnsTheCachedBook() {
+	suite.bookRepository.On("GetBookByID", suite.testBook.ID).Return(suite.testBook, nil).Once()
+
+	for i := 0; i < 2; i++ {
+		response := suite.request(suite.testBook.ID)
+		suite.Equal(http.StatusOK, response.StatusCode)
+		var returned model.Book
+		suite.NoError(json.Unmarshal([]byte(response.Body), &returned))
+		suite.Equal(suite.testBook.Name, returned.Name)
+	}
+	suite.bookRepository.AssertExpectations(suite.T())
+}
+
+func (suite *GetBookByIDHandlerSuite) TestMissingBookIsEmptyJSON() {
+	suite.bookRepository.On("GetBookByID", suite.testBook.ID).Return(&model.Book{}, nil)
+
+	response := suite.requestAs(suite.testBook.ID, catalog.MediaJSON)
+	suite.Equal(http.StatusOK, response.StatusCode)
+	var returned model.Book
+	suite.NoError(json.Unmarshal([]byte(response.Body), &returned))
+	suite.Empty(returned.ID)
+}
+
+func (suite *GetBookByIDHandlerSuite) TestMissingBookIsNotFoundInOtherFormats() {
+	suite.bookRepository.On("GetBookByID", suite.testBook.ID).Return(&model.Book{}, nil)
+
+	for _, mediaType := range []catalog.MediaType{
+		catalog.MediaMARCXML, catalog.MediaMARC, catalog.MediaDublinCore, webpage.MediaHTML, webpage.MediaJSONLD,
+	} {
+		response := suite.requestAs(suite.testBook.ID, mediaType)
+		suite.Equal(http.StatusNotFound, response.StatusCode, mediaType)
+	}
+}
+
+func (suite *GetBookByIDHandlerSuite) TestRendersOtherFormats() {
+	suite.bookRepository.On("GetBookByID", suite.testBook.ID).Return(suite.testBook, nil)
+
+	for _, mediaType := range []catalog.MediaType{
+		catalog.MediaMARCXML, catalog.MediaMARC, catalog.MediaDublinCore, webpage.MediaHTML, webpage.MediaJSONLD,
+	} {
+		response := suite.requestAs(suite.testBook.ID, mediaType)
+		suite.Equal(http.StatusOK, response.StatusCode, mediaType)
+		suite.Contains(response.Body, suite.testBook.Name, mediaType)
+	}
+}
+
+func (suite *GetBookByIDHandlerSuite) TestRejectsInvalidID() {
+	response := suite.request("not-a-uuid")
+	suite.Equal(http.StatusUnprocessableEntity, response.StatusCode)
+	suite.bookRepository.AssertNotCalled(suite.T(), "GetBookByID", "not-a-uuid")
+}
+
+func (suite *GetBookByIDHandlerSuite) TestRepositoryErrors() {
+	suite.bookRepository.On("GetBookByID", suite.testBook.ID).Return(nil, appError.NewUnexpectedError("unavailable"))
+
+	response := suite.request(suite.testBook.ID)
+	suite.Equal(http.StatusInternalServerError, response.StatusCode)
+}
+
+func TestGetBookByIDHandlerSuite(t *testing.T) {
+	suite.Run(t, new(GetBookByIDHandlerSuite))
+}
@@ -0,0 +1,55 @@
+package lambdahandler
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+
+	book "main/src/books/application/handler"
+	"main/utils/apigateway"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+var (
+	BOOKS_TABLE         = os.Getenv("BOOKS_TABLE")
+	BOOKS_HISTORY_TABLE = os.Getenv("BOOKS_HISTORY_TABLE")
+)
+
+// Handler returns the revisions of a book, newest first. The limit query
+// parameter sizes the page and before continues below a revision number.
+func Handler(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+
+	bookMicro := book.MicroAWSBookDynamoDB{
+		Ctx:              ctx,
+		TableName:        BOOKS_TABLE,
+		HistoryTableName: BOOKS_HISTORY_TABLE,
+	}
+
+	bookId, errApi := apigateway.ParseAPIGatewayRequestParameters(request, "bookId")
+	if errApi != nil {
+		log.Printf("Error parsing request parameters: %v", errApi)
+		return apigateway.APIGatewayError(http.StatusBadRequest, "Error parsing request parameters.")
+	}
+
+	var limit, before int
+	for name, value := range map[string]*int{"limit": &limit, "before": &before} {
+		if raw := request.QueryStringParameters[name]; raw != "" {
+			number, err := strconv.Atoi(raw)
+			if err != nil {
+				return apigateway.APIGatewayError(http.StatusBadRequest, name+" must be a number.")
+			}
+			*value = number
+		}
+	}
+
+	revisions, errBookMicro := bookMicro.GetBookHistory(bookId, limit, before)
+	if errBookMicro != nil {
+		log.Printf("Error while getting book history, %s", errBookMicro.ToString())
+		return apigateway.APIGatewayError(errBookMicro.Code, errBookMicro.ToString())
+	}
+
+	return apigateway.APIGatewayDataResponse(http.StatusOK, revisions)
+}
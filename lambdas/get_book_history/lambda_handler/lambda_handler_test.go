@@ -0,0 +1,32 @@
+package lambdahandler_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	index "main/lambdas/get_book_history/lambda_handler"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+)
+
+type GetBookHistoryHandlerSuite struct {
+	suite.Suite
+}
+
+func (suite *GetBookHistoryHandlerSuite) TestRejectedOutsideDynamoDB() {
+	for _, store := range []string{"sql", "bolt", "eventsourced"} {
+		suite.T().Setenv("BOOKS_STORE", store)
+		response, err := index.Handler(context.TODO(), events.APIGatewayProxyRequest{
+			PathParameters: map[string]string{"bookId": uuid.NewString()},
+		})
+		suite.NoError(err)
+		suite.Equal(http.StatusNotImplemented, response.StatusCode, store)
+	}
+}
+
+func TestGetBookHistoryHandlerSuite(t *testing.T) {
+	suite.Run(t, new(GetBookHistoryHandlerSuite))
+}
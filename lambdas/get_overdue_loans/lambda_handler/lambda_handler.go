@@ -0,0 +1,37 @@
+package lambdahandler
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"os"
+
+	loan "main/src/loans/application/handler"
+	"main/utils/apigateway"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+var (
+	COPIES_TABLE = os.Getenv("COPIES_TABLE")
+	LOANS_TABLE  = os.Getenv("LOANS_TABLE")
+	HOLDS_TABLE  = os.Getenv("HOLDS_TABLE")
+)
+
+func Handler(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+
+	loanMicro := loan.MicroAWSLoanDynamoDB{
+		Ctx:         ctx,
+		CopiesTable: COPIES_TABLE,
+		LoansTable:  LOANS_TABLE,
+		HoldsTable:  HOLDS_TABLE,
+	}
+
+	loans, errLoanMicro := loanMicro.GetOverdueLoans()
+	if errLoanMicro != nil {
+		log.Printf("Error while getting overdue loans, %s", errLoanMicro.ToString())
+		return apigateway.APIGatewayError(errLoanMicro.Code, errLoanMicro.ToString())
+	}
+
+	return apigateway.APIGatewayDataResponse(http.StatusOK, loans)
+}
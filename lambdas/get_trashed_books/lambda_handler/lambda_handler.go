@@ -0,0 +1,47 @@
+package lambdahandler
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+
+	book "main/src/books/application/handler"
+	"main/utils/apigateway"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+const defaultTrashLimit = 50
+
+var (
+	BOOKS_TABLE = os.Getenv("BOOKS_TABLE")
+)
+
+// Handler lists the books in the trash, most recently deleted first. The
+// limit query parameter sizes the page.
+func Handler(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+
+	bookMicro := book.MicroAWSBookDynamoDB{
+		Ctx:       ctx,
+		TableName: BOOKS_TABLE,
+	}
+
+	limit := defaultTrashLimit
+	if raw := request.QueryStringParameters["limit"]; raw != "" {
+		number, err := strconv.Atoi(raw)
+		if err != nil {
+			return apigateway.APIGatewayError(http.StatusBadRequest, "limit must be a number.")
+		}
+		limit = number
+	}
+
+	books, errBookMicro := bookMicro.GetTrashedBooks(limit)
+	if errBookMicro != nil {
+		log.Printf("Error while getting trashed books, %s", errBookMicro.ToString())
+		return apigateway.APIGatewayError(errBookMicro.Code, errBookMicro.ToString())
+	}
+
+	return apigateway.APIGatewayDataResponse(http.StatusOK, books)
+}
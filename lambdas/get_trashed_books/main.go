@@ -0,0 +1,10 @@
+package main
+
+import (
+	"github.com/aws/aws-lambda-go/lambda"
+	index "main/lambdas/get_trashed_books/lambda_handler"
+)
+
+func main() {
+	lambda.Start(index.Handler)
+}
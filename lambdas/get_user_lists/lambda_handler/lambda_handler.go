@@ -0,0 +1,44 @@
+package lambdahandler
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"os"
+
+	list "main/src/lists/application/handler"
+	"main/utils/apigateway"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+var (
+	LISTS_TABLE = os.Getenv("LISTS_TABLE")
+	BOOKS_TABLE = os.Getenv("BOOKS_TABLE")
+)
+
+func Handler(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+
+	listMicro := list.MicroAWSListDynamoDB{
+		Ctx:        ctx,
+		ListsTable: LISTS_TABLE,
+		BooksTable: BOOKS_TABLE,
+	}
+
+	ownerId, errApi := apigateway.ParseAPIGatewayRequestParameters(request, "userId")
+	if errApi != nil {
+		log.Printf("Error parsing request parameters: %v", errApi)
+		return apigateway.APIGatewayError(http.StatusBadRequest, "Error parsing request parameters.")
+	}
+
+	// Callers the authorizer did not authenticate only see public lists.
+	viewerId, _ := apigateway.ParseAPIGatewayRequestIdentity(request)
+
+	lists, errListMicro := listMicro.GetListsByOwnerID(ownerId, viewerId)
+	if errListMicro != nil {
+		log.Printf("Error while getting user lists, %s", errListMicro.ToString())
+		return apigateway.APIGatewayError(errListMicro.Code, errListMicro.ToString())
+	}
+
+	return apigateway.APIGatewayDataResponse(http.StatusOK, lists)
+}
@@ -0,0 +1,54 @@
+package lambdahandler
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+
+	webhook "main/src/webhooks/application/handler"
+	"main/src/webhooks/domain/model"
+	"main/utils/apigateway"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+var (
+	WEBHOOKS_TABLE   = os.Getenv("WEBHOOKS_TABLE")
+	DELIVERIES_TABLE = os.Getenv("DELIVERIES_TABLE")
+)
+
+// Handler returns the delivery log of a webhook, optionally filtered with the
+// status query parameter; ?status=dead lists its dead letters.
+func Handler(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+
+	webhookMicro := webhook.MicroAWSWebhookDynamoDB{
+		Ctx:             ctx,
+		WebhooksTable:   WEBHOOKS_TABLE,
+		DeliveriesTable: DELIVERIES_TABLE,
+	}
+
+	webhookId, errApi := apigateway.ParseAPIGatewayRequestParameters(request, "webhookId")
+	if errApi != nil {
+		log.Printf("Error parsing request parameters: %v", errApi)
+		return apigateway.APIGatewayError(http.StatusBadRequest, "Error parsing request parameters.")
+	}
+
+	limit := 0
+	if value := request.QueryStringParameters["limit"]; value != "" {
+		var err error
+		if limit, err = strconv.Atoi(value); err != nil {
+			return apigateway.APIGatewayError(http.StatusBadRequest, "limit must be a number.")
+		}
+	}
+	status := model.DeliveryStatus(request.QueryStringParameters["status"])
+
+	deliveries, errWebhookMicro := webhookMicro.GetWebhookDeliveries(webhookId, status, limit)
+	if errWebhookMicro != nil {
+		log.Printf("Error while getting webhook deliveries, %s", errWebhookMicro.ToString())
+		return apigateway.APIGatewayError(errWebhookMicro.Code, errWebhookMicro.ToString())
+	}
+
+	return apigateway.APIGatewayDataResponse(http.StatusOK, deliveries)
+}
@@ -0,0 +1,35 @@
+package lambdahandler
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"os"
+
+	webhook "main/src/webhooks/application/handler"
+	"main/utils/apigateway"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+var (
+	WEBHOOKS_TABLE   = os.Getenv("WEBHOOKS_TABLE")
+	DELIVERIES_TABLE = os.Getenv("DELIVERIES_TABLE")
+)
+
+func Handler(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+
+	webhookMicro := webhook.MicroAWSWebhookDynamoDB{
+		Ctx:             ctx,
+		WebhooksTable:   WEBHOOKS_TABLE,
+		DeliveriesTable: DELIVERIES_TABLE,
+	}
+
+	webhooks, errWebhookMicro := webhookMicro.GetWebhooks()
+	if errWebhookMicro != nil {
+		log.Printf("Error while getting webhooks, %s", errWebhookMicro.ToString())
+		return apigateway.APIGatewayError(errWebhookMicro.Code, errWebhookMicro.ToString())
+	}
+
+	return apigateway.APIGatewayDataResponse(http.StatusOK, webhooks)
+}
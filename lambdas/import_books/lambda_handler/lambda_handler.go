@@ -0,0 +1,73 @@
+package lambdahandler
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	book "main/src/books/application/handler"
+	"main/src/books/application/importer"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+var (
+	BOOKS_TABLE         = os.Getenv("BOOKS_TABLE")
+	BOOKS_OUTBOX_TABLE  = os.Getenv("BOOKS_OUTBOX_TABLE")
+	BOOKS_HISTORY_TABLE = os.Getenv("BOOKS_HISTORY_TABLE")
+	BUCKET_KEY          = os.Getenv("BUCKET_KEY")
+)
+
+// Handler imports every catalog file created under the imports prefix. XML
+// files are ONIX messages; the rest are CSV, JSON or NDJSON catalogs.
+// Files that cannot be imported as a whole are logged and skipped; only
+// unexpected failures are returned so the invocation is retried.
+func Handler(ctx context.Context, event events.S3Event) error {
+	for _, record := range event.Records {
+		key, err := url.QueryUnescape(record.S3.Object.Key)
+		if err != nil {
+			log.Printf("Error decoding object key %s: %v", record.S3.Object.Key, err)
+			continue
+		}
+		if !strings.HasPrefix(key, importer.ImportPrefix) || strings.HasSuffix(key, ".mapping.json") {
+			continue
+		}
+
+		bookMicro := book.MicroAWSBookDynamoDB{
+			Ctx:              ctx,
+			TableName:        BOOKS_TABLE,
+			BucketName:       record.S3.Bucket.Name,
+			BucketKey:        BUCKET_KEY,
+			OutboxTableName:  BOOKS_OUTBOX_TABLE,
+			HistoryTableName: BOOKS_HISTORY_TABLE,
+			Actor:            "import:" + key,
+		}
+		if strings.EqualFold(filepath.Ext(key), ".xml") {
+			importLog, errBookMicro := bookMicro.ImportONIXFile(key)
+			if errBookMicro != nil {
+				log.Printf("Error while importing %s, %s", key, errBookMicro.ToString())
+				if errBookMicro.Code >= http.StatusInternalServerError {
+					return errBookMicro.ToError()
+				}
+				continue
+			}
+			log.Printf("Imported ONIX %s, created: %d, updated: %d, deleted: %d", key, importLog.Created, importLog.Updated, importLog.Deleted)
+			continue
+		}
+
+		report, errBookMicro := bookMicro.ImportBookFile(key)
+		if errBookMicro != nil {
+			log.Printf("Error while importing %s, %s", key, errBookMicro.ToString())
+			if errBookMicro.Code >= http.StatusInternalServerError {
+				return errBookMicro.ToError()
+			}
+			continue
+		}
+		log.Printf("Imported %s, created: %d, rejected: %d", key, report.Created, len(report.Errors))
+	}
+	return nil
+}
@@ -0,0 +1 @@
+package lambdahandler_test
\ No newline at end of file
@@ -0,0 +1,100 @@
+package lambdahandler
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"os"
+	"time"
+
+	book "main/src/books/application/handler"
+	"main/src/books/application/opds"
+	"main/utils/apigateway"
+	appError "main/utils/error"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+var (
+	BOOKS_TABLE   = os.Getenv("BOOKS_TABLE")
+	CATALOG_TITLE = os.Getenv("CATALOG_TITLE")
+)
+
+// Handler serves the OPDS catalog: /opds, /opds/books, /opds/search and
+// /opds/opensearch.xml as OPDS 1.2 Atom, and the same feeds under /opds/v2
+// as OPDS 2.0 JSON.
+func Handler(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+
+	// Absolute links, since some e-readers do not resolve relative ones.
+	catalog := &opds.Catalog{
+		BaseURL: apigateway.APIGatewayBaseURL(request),
+		Title:   CATALOG_TITLE,
+		Updated: time.Now(),
+	}
+	if catalog.Title == "" {
+		catalog.Title = "Books"
+	}
+
+	var (
+		body      []byte
+		mediaType string
+		errOPDS   *appError.Error
+	)
+	switch request.Resource {
+	case opds.PathRoot:
+		body, errOPDS = catalog.RootAtom()
+		mediaType = opds.MediaNavigation
+	case opds.PathOpenSearch:
+		body, errOPDS = catalog.OpenSearch()
+		mediaType = opds.MediaOpenSearch
+	case opds.PathRootV2:
+		body, errOPDS = catalog.RootJSON()
+		mediaType = opds.MediaOPDS2
+	case opds.PathBooks, opds.PathSearch, opds.PathBooksV2, opds.PathSearchV2:
+		page, errPage := bookPage(ctx, request)
+		if errPage != nil {
+			return apigateway.APIGatewayError(errPage.Code, errPage.ToString())
+		}
+		if request.Resource == opds.PathBooksV2 || request.Resource == opds.PathSearchV2 {
+			body, errOPDS = catalog.PublicationsJSON(page)
+			mediaType = opds.MediaOPDS2
+		} else {
+			body, errOPDS = catalog.AcquisitionAtom(page)
+			mediaType = opds.MediaAcquisition
+		}
+	default:
+		return apigateway.APIGatewayError(http.StatusNotFound, "Unknown OPDS feed "+request.Resource+".")
+	}
+	if errOPDS != nil {
+		return apigateway.APIGatewayError(errOPDS.Code, errOPDS.ToString())
+	}
+
+	return apigateway.APIGatewayContentResponse(http.StatusOK, mediaType, body)
+}
+
+// bookPage lists the books through the same service as GET /books and cuts
+// out the requested page, filtered by the q parameter on search routes.
+func bookPage(ctx context.Context, request events.APIGatewayProxyRequest) (*opds.Page, *appError.Error) {
+	number, size, errParams := opds.ParsePageParams(request.QueryStringParameters)
+	if errParams != nil {
+		return nil, errParams
+	}
+	query := ""
+	if request.Resource == opds.PathSearch || request.Resource == opds.PathSearchV2 {
+		query = request.QueryStringParameters["q"]
+		if query == "" {
+			return nil, appError.NewBadRequestError("query parameter q is required.")
+		}
+	}
+
+	bookMicro := book.MicroAWSBookDynamoDB{
+		Ctx:       ctx,
+		TableName: BOOKS_TABLE,
+	}
+	books, errBookMicro := bookMicro.GetAllBooks()
+	if errBookMicro != nil {
+		log.Printf("Error while listing books, %s", errBookMicro.ToString())
+		return nil, errBookMicro
+	}
+	return opds.NewPage(books, query, number, size)
+}
@@ -0,0 +1,42 @@
+package lambdahandler
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"os"
+
+	book "main/src/books/application/handler"
+	"main/utils/apigateway"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+var (
+	BOOKS_TABLE = os.Getenv("BOOKS_TABLE")
+)
+
+// Handler deletes a book of the trash for good, without waiting for the
+// retention period. The books table stream removes its cover.
+func Handler(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+
+	bookMicro := book.MicroAWSBookDynamoDB{
+		Ctx:       ctx,
+		TableName: BOOKS_TABLE,
+	}
+
+	bookId, errApi := apigateway.ParseAPIGatewayRequestParameters(request, "bookId")
+	if errApi != nil {
+		log.Printf("Error parsing request parameters: %v", errApi)
+		return apigateway.APIGatewayError(http.StatusBadRequest, "Error parsing request parameters.")
+	}
+
+	errBookMicro := bookMicro.PurgeBook(bookId)
+	if errBookMicro != nil {
+		log.Printf("Error while purging book, %s", errBookMicro.ToString())
+		return apigateway.APIGatewayError(errBookMicro.Code, errBookMicro.ToString())
+	}
+
+	message := "Book " + bookId + " purged"
+	return apigateway.APIGatewayMessageResponse(http.StatusOK, message)
+}
@@ -0,0 +1,44 @@
+package lambdahandler
+
+import (
+	"context"
+	"log"
+	"os"
+	"strconv"
+
+	book "main/src/books/application/handler"
+	"main/src/books/application/search"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+var (
+	BOOKS_TABLE   = os.Getenv("BOOKS_TABLE")
+	BUCKET_NAME   = os.Getenv("BUCKET_NAME")
+	BUCKET_KEY    = os.Getenv("BUCKET_KEY")
+	SCAN_SEGMENTS = os.Getenv("SCAN_SEGMENTS")
+)
+
+// Handler runs on a schedule and rebuilds the search index from the table,
+// undoing any drift left by failed index updates.
+func Handler(ctx context.Context, event events.CloudWatchEvent) error {
+	segments, err := strconv.Atoi(SCAN_SEGMENTS)
+	if err != nil {
+		segments = search.DefaultSegments
+	}
+
+	bookMicro := book.MicroAWSBookDynamoDB{
+		Ctx:        ctx,
+		TableName:  BOOKS_TABLE,
+		BucketName: BUCKET_NAME,
+		BucketKey:  BUCKET_KEY,
+	}
+	indexed, errBookMicro := bookMicro.RebuildSearchIndex(segments)
+	if errBookMicro != nil {
+		log.Printf("Error while rebuilding search index, %s", errBookMicro.ToString())
+		return errBookMicro.ToError()
+	}
+
+	log.Printf("Rebuilt search index with %d books", indexed)
+	return nil
+}
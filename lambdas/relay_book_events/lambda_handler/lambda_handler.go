@@ -0,0 +1,44 @@
+package lambdahandler
+
+import (
+	"context"
+	"log"
+	"os"
+	"strconv"
+
+	book "main/src/books/application/handler"
+	"main/src/books/application/outbox"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+var (
+	BOOKS_OUTBOX_TABLE = os.Getenv("BOOKS_OUTBOX_TABLE")
+	// EVENT_PUBLISHER is eventbridge, sns or sqs; EVENT_TARGET is the bus
+	// name, topic ARN or queue URL.
+	EVENT_PUBLISHER = os.Getenv("EVENT_PUBLISHER")
+	EVENT_TARGET    = os.Getenv("EVENT_TARGET")
+	RELAY_BATCH     = os.Getenv("RELAY_BATCH")
+)
+
+// Handler runs on a schedule and publishes the pending book events of the
+// outbox. Events that fail stay pending for the next run.
+func Handler(ctx context.Context, event events.CloudWatchEvent) error {
+	limit, err := strconv.Atoi(RELAY_BATCH)
+	if err != nil {
+		limit = outbox.DefaultBatchSize
+	}
+
+	bookMicro := book.MicroAWSBookDynamoDB{
+		Ctx:             ctx,
+		OutboxTableName: BOOKS_OUTBOX_TABLE,
+	}
+	report, errBookMicro := bookMicro.RelayBookEvents(EVENT_PUBLISHER, EVENT_TARGET, limit)
+	if errBookMicro != nil {
+		log.Printf("Error while relaying book events, %s", errBookMicro.ToString())
+		return errBookMicro.ToError()
+	}
+
+	log.Printf("Relayed book events: %+v", report)
+	return nil
+}
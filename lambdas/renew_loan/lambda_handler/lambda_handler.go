@@ -0,0 +1,43 @@
+package lambdahandler
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"os"
+
+	loan "main/src/loans/application/handler"
+	"main/utils/apigateway"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+var (
+	COPIES_TABLE = os.Getenv("COPIES_TABLE")
+	LOANS_TABLE  = os.Getenv("LOANS_TABLE")
+	HOLDS_TABLE  = os.Getenv("HOLDS_TABLE")
+)
+
+func Handler(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+
+	loanMicro := loan.MicroAWSLoanDynamoDB{
+		Ctx:         ctx,
+		CopiesTable: COPIES_TABLE,
+		LoansTable:  LOANS_TABLE,
+		HoldsTable:  HOLDS_TABLE,
+	}
+
+	loanId, errApi := apigateway.ParseAPIGatewayRequestParameters(request, "loanId")
+	if errApi != nil {
+		log.Printf("Error parsing request parameters: %v", errApi)
+		return apigateway.APIGatewayError(http.StatusBadRequest, "Error parsing request parameters.")
+	}
+
+	loanRecord, errLoanMicro := loanMicro.Renew(loanId)
+	if errLoanMicro != nil {
+		log.Printf("Error while renewing loan, %s", errLoanMicro.ToString())
+		return apigateway.APIGatewayError(errLoanMicro.Code, errLoanMicro.ToString())
+	}
+
+	return apigateway.APIGatewayDataResponse(http.StatusOK, loanRecord)
+}
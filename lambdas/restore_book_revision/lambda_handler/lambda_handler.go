@@ -0,0 +1,60 @@
+package lambdahandler
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+
+	book "main/src/books/application/handler"
+	"main/utils/apigateway"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+var (
+	BOOKS_TABLE         = os.Getenv("BOOKS_TABLE")
+	BOOKS_OUTBOX_TABLE  = os.Getenv("BOOKS_OUTBOX_TABLE")
+	BOOKS_HISTORY_TABLE = os.Getenv("BOOKS_HISTORY_TABLE")
+)
+
+func Handler(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+
+	actor, errIdentity := apigateway.ParseAPIGatewayRequestIdentity(request)
+	if errIdentity != nil {
+		log.Printf("Error reading the caller: %s", errIdentity.ToString())
+		return apigateway.APIGatewayError(errIdentity.Code, errIdentity.ToString())
+	}
+	bookMicro := book.MicroAWSBookDynamoDB{
+		Ctx:              ctx,
+		TableName:        BOOKS_TABLE,
+		OutboxTableName:  BOOKS_OUTBOX_TABLE,
+		HistoryTableName: BOOKS_HISTORY_TABLE,
+		Actor:            actor,
+	}
+
+	bookId, errApi := apigateway.ParseAPIGatewayRequestParameters(request, "bookId")
+	if errApi != nil {
+		log.Printf("Error parsing request parameters: %v", errApi)
+		return apigateway.APIGatewayError(http.StatusBadRequest, "Error parsing request parameters.")
+	}
+
+	revisionParam, errApi := apigateway.ParseAPIGatewayRequestParameters(request, "revision")
+	if errApi != nil {
+		log.Printf("Error parsing request parameters: %v", errApi)
+		return apigateway.APIGatewayError(http.StatusBadRequest, "Error parsing request parameters.")
+	}
+	revision, err := strconv.Atoi(revisionParam)
+	if err != nil {
+		return apigateway.APIGatewayError(http.StatusBadRequest, "revision must be a number.")
+	}
+
+	restored, errBookMicro := bookMicro.RestoreBookRevision(bookId, revision)
+	if errBookMicro != nil {
+		log.Printf("Error while restoring book revision, %s", errBookMicro.ToString())
+		return apigateway.APIGatewayError(errBookMicro.Code, errBookMicro.ToString())
+	}
+
+	return apigateway.APIGatewayDataResponse(http.StatusOK, restored)
+}
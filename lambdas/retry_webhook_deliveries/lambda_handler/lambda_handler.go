@@ -0,0 +1,47 @@
+package lambdahandler
+
+import (
+	"context"
+	"log"
+	"os"
+	"strconv"
+
+	"main/src/webhooks/application/dispatcher"
+	webhook "main/src/webhooks/application/handler"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+var (
+	WEBHOOKS_TABLE       = os.Getenv("WEBHOOKS_TABLE")
+	DELIVERIES_TABLE     = os.Getenv("DELIVERIES_TABLE")
+	WEBHOOK_MAX_ATTEMPTS = os.Getenv("WEBHOOK_MAX_ATTEMPTS")
+	RETRY_BATCH          = os.Getenv("RETRY_BATCH")
+)
+
+// Handler runs on a schedule and sends again the webhook deliveries whose
+// backoff has elapsed, dead-lettering those out of attempts.
+func Handler(ctx context.Context, event events.CloudWatchEvent) error {
+	limit, err := strconv.Atoi(RETRY_BATCH)
+	if err != nil {
+		limit = dispatcher.DefaultRetryBatch
+	}
+	options := dispatcher.DefaultOptions()
+	if attempts, err := strconv.Atoi(WEBHOOK_MAX_ATTEMPTS); err == nil && attempts > 0 {
+		options.MaxAttempts = attempts
+	}
+
+	webhookMicro := webhook.MicroAWSWebhookDynamoDB{
+		Ctx:             ctx,
+		WebhooksTable:   WEBHOOKS_TABLE,
+		DeliveriesTable: DELIVERIES_TABLE,
+	}
+	report, errWebhookMicro := webhookMicro.RetryWebhookDeliveries(options, limit)
+	if errWebhookMicro != nil {
+		log.Printf("Error while retrying webhook deliveries, %s", errWebhookMicro.ToString())
+		return errWebhookMicro.ToError()
+	}
+
+	log.Printf("Retried webhook deliveries: %+v", report)
+	return nil
+}
@@ -0,0 +1,58 @@
+package lambdahandler
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"os"
+	"time"
+
+	book "main/src/books/application/handler"
+	"main/src/books/application/search"
+	"main/utils/apigateway"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+var (
+	BUCKET_NAME = os.Getenv("BUCKET_NAME")
+	BUCKET_KEY  = os.Getenv("BUCKET_KEY")
+)
+
+// indexMaxAge is how long a warm Lambda answers from the index it loaded
+// before reading the stored one again.
+const indexMaxAge = time.Minute
+
+var (
+	index    *search.Index
+	loadedAt time.Time
+)
+
+// Handler serves GET /books/search?q=, with optional author, subject,
+// publisher and language filters and limit/offset paging.
+func Handler(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+
+	query, errQuery := search.ParseQuery(request.QueryStringParameters)
+	if errQuery != nil {
+		return apigateway.APIGatewayError(errQuery.Code, errQuery.ToString())
+	}
+
+	if index == nil || time.Since(loadedAt) > indexMaxAge {
+		bookMicro := book.MicroAWSBookDynamoDB{
+			Ctx:        ctx,
+			BucketName: BUCKET_NAME,
+			BucketKey:  BUCKET_KEY,
+		}
+		loaded, errBookMicro := bookMicro.LoadSearchIndex()
+		if errBookMicro != nil {
+			log.Printf("Error while loading search index, %s", errBookMicro.ToString())
+			if index == nil {
+				return apigateway.APIGatewayError(errBookMicro.Code, errBookMicro.ToString())
+			}
+		} else {
+			index, loadedAt = loaded, time.Now()
+		}
+	}
+
+	return apigateway.APIGatewayDataResponse(http.StatusOK, index.Search(query))
+}
@@ -0,0 +1,110 @@
+// Code generated by mockery v2.39.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	model "main/src/books/domain/model"
+	error "main/utils/error"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// BookCacheRepository is an autogenerated mock type for the BookCacheRepository type
+type BookCacheRepository struct {
+	mock.Mock
+}
+
+// DeleteBook provides a mock function with given fields: id
+func (_m *BookCacheRepository) DeleteBook(id string) *error.Error {
+	ret := _m.Called(id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteBook")
+	}
+
+	var r0 *error.Error
+	if rf, ok := ret.Get(0).(func(string) *error.Error); ok {
+		r0 = rf(id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*error.Error)
+		}
+	}
+
+	return r0
+}
+
+// GetBook provides a mock function with given fields: id
+func (_m *BookCacheRepository) GetBook(id string) (*model.Book, bool, *error.Error) {
+	ret := _m.Called(id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetBook")
+	}
+
+	var r0 *model.Book
+	var r1 bool
+	var r2 *error.Error
+	if rf, ok := ret.Get(0).(func(string) (*model.Book, bool, *error.Error)); ok {
+		return rf(id)
+	}
+	if rf, ok := ret.Get(0).(func(string) *model.Book); ok {
+		r0 = rf(id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Book)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) bool); ok {
+		r1 = rf(id)
+	} else {
+		r1 = ret.Get(1).(bool)
+	}
+
+	if rf, ok := ret.Get(2).(func(string) *error.Error); ok {
+		r2 = rf(id)
+	} else {
+		if ret.Get(2) != nil {
+			r2 = ret.Get(2).(*error.Error)
+		}
+	}
+
+	return r0, r1, r2
+}
+
+// SetBook provides a mock function with given fields: id, book, ttl
+func (_m *BookCacheRepository) SetBook(id string, book *model.Book, ttl time.Duration) *error.Error {
+	ret := _m.Called(id, book, ttl)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetBook")
+	}
+
+	var r0 *error.Error
+	if rf, ok := ret.Get(0).(func(string, *model.Book, time.Duration) *error.Error); ok {
+		r0 = rf(id, book, ttl)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*error.Error)
+		}
+	}
+
+	return r0
+}
+
+// NewBookCacheRepository creates a new instance of BookCacheRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewBookCacheRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *BookCacheRepository {
+	mock := &BookCacheRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
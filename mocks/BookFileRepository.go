@@ -0,0 +1,191 @@
+// Code generated by mockery v2.39.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	bytes "bytes"
+	io "io"
+	error "main/utils/error"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// BookFileRepository is an autogenerated mock type for the BookFileRepository type
+type BookFileRepository struct {
+	mock.Mock
+}
+
+// DeleteBookFile provides a mock function with given fields: _a0
+func (_m *BookFileRepository) DeleteBookFile(_a0 string) *error.Error {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteBookFile")
+	}
+
+	var r0 *error.Error
+	if rf, ok := ret.Get(0).(func(string) *error.Error); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*error.Error)
+		}
+	}
+
+	return r0
+}
+
+// GetBookFile provides a mock function with given fields: _a0
+func (_m *BookFileRepository) GetBookFile(_a0 string) ([]byte, *error.Error) {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetBookFile")
+	}
+
+	var r0 []byte
+	var r1 *error.Error
+	if rf, ok := ret.Get(0).(func(string) ([]byte, *error.Error)); ok {
+		return rf(_a0)
+	}
+	if rf, ok := ret.Get(0).(func(string) []byte); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) *error.Error); ok {
+		r1 = rf(_a0)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*error.Error)
+		}
+	}
+
+	return r0, r1
+}
+
+// GetBookFileVersion provides a mock function with given fields: _a0
+func (_m *BookFileRepository) GetBookFileVersion(_a0 string) ([]byte, string, *error.Error) {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetBookFileVersion")
+	}
+
+	var r0 []byte
+	var r1 string
+	var r2 *error.Error
+	if rf, ok := ret.Get(0).(func(string) ([]byte, string, *error.Error)); ok {
+		return rf(_a0)
+	}
+	if rf, ok := ret.Get(0).(func(string) []byte); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) string); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+
+	if rf, ok := ret.Get(2).(func(string) *error.Error); ok {
+		r2 = rf(_a0)
+	} else {
+		if ret.Get(2) != nil {
+			r2 = ret.Get(2).(*error.Error)
+		}
+	}
+
+	return r0, r1, r2
+}
+
+// SaveBookFile provides a mock function with given fields: _a0, _a1, _a2
+func (_m *BookFileRepository) SaveBookFile(_a0 *bytes.Reader, _a1 string, _a2 string) *error.Error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveBookFile")
+	}
+
+	var r0 *error.Error
+	if rf, ok := ret.Get(0).(func(*bytes.Reader, string, string) *error.Error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*error.Error)
+		}
+	}
+
+	return r0
+}
+
+// SaveBookFileVersion provides a mock function with given fields: _a0, _a1, _a2, _a3
+func (_m *BookFileRepository) SaveBookFileVersion(_a0 *bytes.Reader, _a1 string, _a2 string, _a3 string) (string, *error.Error) {
+	ret := _m.Called(_a0, _a1, _a2, _a3)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveBookFileVersion")
+	}
+
+	var r0 string
+	var r1 *error.Error
+	if rf, ok := ret.Get(0).(func(*bytes.Reader, string, string, string) (string, *error.Error)); ok {
+		return rf(_a0, _a1, _a2, _a3)
+	}
+	if rf, ok := ret.Get(0).(func(*bytes.Reader, string, string, string) string); ok {
+		r0 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(*bytes.Reader, string, string, string) *error.Error); ok {
+		r1 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*error.Error)
+		}
+	}
+
+	return r0, r1
+}
+
+// UploadBookFile provides a mock function with given fields: _a0, _a1, _a2
+func (_m *BookFileRepository) UploadBookFile(_a0 io.Reader, _a1 string, _a2 string) *error.Error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UploadBookFile")
+	}
+
+	var r0 *error.Error
+	if rf, ok := ret.Get(0).(func(io.Reader, string, string) *error.Error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*error.Error)
+		}
+	}
+
+	return r0
+}
+
+// NewBookFileRepository creates a new instance of BookFileRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewBookFileRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *BookFileRepository {
+	mock := &BookFileRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,122 @@
+// Code generated by mockery v2.39.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	bytes "bytes"
+	io "io"
+	error "main/utils/error"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// BookFileService is an autogenerated mock type for the BookFileService type
+type BookFileService struct {
+	mock.Mock
+}
+
+// DeleteBookFile provides a mock function with given fields: _a0
+func (_m *BookFileService) DeleteBookFile(_a0 string) *error.Error {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteBookFile")
+	}
+
+	var r0 *error.Error
+	if rf, ok := ret.Get(0).(func(string) *error.Error); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*error.Error)
+		}
+	}
+
+	return r0
+}
+
+// GetBookFile provides a mock function with given fields: _a0
+func (_m *BookFileService) GetBookFile(_a0 string) ([]byte, *error.Error) {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetBookFile")
+	}
+
+	var r0 []byte
+	var r1 *error.Error
+	if rf, ok := ret.Get(0).(func(string) ([]byte, *error.Error)); ok {
+		return rf(_a0)
+	}
+	if rf, ok := ret.Get(0).(func(string) []byte); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) *error.Error); ok {
+		r1 = rf(_a0)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*error.Error)
+		}
+	}
+
+	return r0, r1
+}
+
+// SaveBookFile provides a mock function with given fields: _a0, _a1, _a2
+func (_m *BookFileService) SaveBookFile(_a0 *bytes.Reader, _a1 string, _a2 string) *error.Error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveBookFile")
+	}
+
+	var r0 *error.Error
+	if rf, ok := ret.Get(0).(func(*bytes.Reader, string, string) *error.Error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*error.Error)
+		}
+	}
+
+	return r0
+}
+
+// UploadBookFile provides a mock function with given fields: _a0, _a1, _a2
+func (_m *BookFileService) UploadBookFile(_a0 io.Reader, _a1 string, _a2 string) *error.Error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UploadBookFile")
+	}
+
+	var r0 *error.Error
+	if rf, ok := ret.Get(0).(func(io.Reader, string, string) *error.Error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*error.Error)
+		}
+	}
+
+	return r0
+}
+
+// NewBookFileService creates a new instance of BookFileService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewBookFileService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *BookFileService {
+	mock := &BookFileService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,93 @@
+// Code generated by mockery v2.39.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	model "main/src/books/domain/model"
+	error "main/utils/error"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// BookHistoryRepository is an autogenerated mock type for the BookHistoryRepository type
+type BookHistoryRepository struct {
+	mock.Mock
+}
+
+// GetBookRevision provides a mock function with given fields: _a0, _a1
+func (_m *BookHistoryRepository) GetBookRevision(_a0 string, _a1 int) (*model.BookRevision, *error.Error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetBookRevision")
+	}
+
+	var r0 *model.BookRevision
+	var r1 *error.Error
+	if rf, ok := ret.Get(0).(func(string, int) (*model.BookRevision, *error.Error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(string, int) *model.BookRevision); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.BookRevision)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, int) *error.Error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*error.Error)
+		}
+	}
+
+	return r0, r1
+}
+
+// GetBookRevisions provides a mock function with given fields: _a0, _a1, _a2
+func (_m *BookHistoryRepository) GetBookRevisions(_a0 string, _a1 int, _a2 int) ([]model.BookRevision, *error.Error) {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetBookRevisions")
+	}
+
+	var r0 []model.BookRevision
+	var r1 *error.Error
+	if rf, ok := ret.Get(0).(func(string, int, int) ([]model.BookRevision, *error.Error)); ok {
+		return rf(_a0, _a1, _a2)
+	}
+	if rf, ok := ret.Get(0).(func(string, int, int) []model.BookRevision); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.BookRevision)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, int, int) *error.Error); ok {
+		r1 = rf(_a0, _a1, _a2)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*error.Error)
+		}
+	}
+
+	return r0, r1
+}
+
+// NewBookHistoryRepository creates a new instance of BookHistoryRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewBookHistoryRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *BookHistoryRepository {
+	mock := &BookHistoryRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,101 @@
+// Code generated by mockery v2.39.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	model "main/src/books/domain/model"
+	error "main/utils/error"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// BookOutboxRepository is an autogenerated mock type for the BookOutboxRepository type
+type BookOutboxRepository struct {
+	mock.Mock
+}
+
+// GetPendingEvents provides a mock function with given fields: _a0
+func (_m *BookOutboxRepository) GetPendingEvents(_a0 int) ([]model.BookEvent, *error.Error) {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPendingEvents")
+	}
+
+	var r0 []model.BookEvent
+	var r1 *error.Error
+	if rf, ok := ret.Get(0).(func(int) ([]model.BookEvent, *error.Error)); ok {
+		return rf(_a0)
+	}
+	if rf, ok := ret.Get(0).(func(int) []model.BookEvent); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.BookEvent)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int) *error.Error); ok {
+		r1 = rf(_a0)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*error.Error)
+		}
+	}
+
+	return r0, r1
+}
+
+// MarkEventPublished provides a mock function with given fields: _a0
+func (_m *BookOutboxRepository) MarkEventPublished(_a0 string) *error.Error {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MarkEventPublished")
+	}
+
+	var r0 *error.Error
+	if rf, ok := ret.Get(0).(func(string) *error.Error); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*error.Error)
+		}
+	}
+
+	return r0
+}
+
+// RecordEventFailure provides a mock function with given fields: _a0, _a1
+func (_m *BookOutboxRepository) RecordEventFailure(_a0 string, _a1 string) *error.Error {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RecordEventFailure")
+	}
+
+	var r0 *error.Error
+	if rf, ok := ret.Get(0).(func(string, string) *error.Error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*error.Error)
+		}
+	}
+
+	return r0
+}
+
+// NewBookOutboxRepository creates a new instance of BookOutboxRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewBookOutboxRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *BookOutboxRepository {
+	mock := &BookOutboxRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
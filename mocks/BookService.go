@@ -15,23 +15,35 @@ type BookService struct {
 }
 
 // CreateBatchBooks provides a mock function with given fields: _a0
-func (_m *BookService) CreateBatchBooks(_a0 []model.Book) *error.Error {
+func (_m *BookService) CreateBatchBooks(_a0 []model.Book) (*model.BatchReport, *error.Error) {
 	ret := _m.Called(_a0)
 
 	if len(ret) == 0 {
 		panic("no return value specified for CreateBatchBooks")
 	}
 
-	var r0 *error.Error
-	if rf, ok := ret.Get(0).(func([]model.Book) *error.Error); ok {
+	var r0 *model.BatchReport
+	var r1 *error.Error
+	if rf, ok := ret.Get(0).(func([]model.Book) (*model.BatchReport, *error.Error)); ok {
+		return rf(_a0)
+	}
+	if rf, ok := ret.Get(0).(func([]model.Book) *model.BatchReport); ok {
 		r0 = rf(_a0)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*error.Error)
+			r0 = ret.Get(0).(*model.BatchReport)
 		}
 	}
 
-	return r0
+	if rf, ok := ret.Get(1).(func([]model.Book) *error.Error); ok {
+		r1 = rf(_a0)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*error.Error)
+		}
+	}
+
+	return r0, r1
 }
 
 // CreateBook provides a mock function with given fields: _a0
@@ -86,6 +98,38 @@ func (_m *BookService) DeleteBookByID(_a0 string) *error.Error {
 	return r0
 }
 
+// DeleteBooksByIDs provides a mock function with given fields: _a0
+func (_m *BookService) DeleteBooksByIDs(_a0 []string) (*model.BatchReport, *error.Error) {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteBooksByIDs")
+	}
+
+	var r0 *model.BatchReport
+	var r1 *error.Error
+	if rf, ok := ret.Get(0).(func([]string) (*model.BatchReport, *error.Error)); ok {
+		return rf(_a0)
+	}
+	if rf, ok := ret.Get(0).(func([]string) *model.BatchReport); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.BatchReport)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func([]string) *error.Error); ok {
+		r1 = rf(_a0)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*error.Error)
+		}
+	}
+
+	return r0, r1
+}
+
 // GetAllBooks provides a mock function with given fields:
 func (_m *BookService) GetAllBooks() ([]model.Book, *error.Error) {
 	ret := _m.Called()
@@ -150,6 +194,142 @@ func (_m *BookService) GetBookByID(_a0 string) (*model.Book, *error.Error) {
 	return r0, r1
 }
 
+// GetBooksByIDs provides a mock function with given fields: _a0
+func (_m *BookService) GetBooksByIDs(_a0 []string) (*model.BooksByIDs, *error.Error) {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetBooksByIDs")
+	}
+
+	var r0 *model.BooksByIDs
+	var r1 *error.Error
+	if rf, ok := ret.Get(0).(func([]string) (*model.BooksByIDs, *error.Error)); ok {
+		return rf(_a0)
+	}
+	if rf, ok := ret.Get(0).(func([]string) *model.BooksByIDs); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.BooksByIDs)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func([]string) *error.Error); ok {
+		r1 = rf(_a0)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*error.Error)
+		}
+	}
+
+	return r0, r1
+}
+
+// GetRecentBooks provides a mock function with given fields: _a0
+func (_m *BookService) GetRecentBooks(_a0 int) ([]model.Book, *error.Error) {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRecentBooks")
+	}
+
+	var r0 []model.Book
+	var r1 *error.Error
+	if rf, ok := ret.Get(0).(func(int) ([]model.Book, *error.Error)); ok {
+		return rf(_a0)
+	}
+	if rf, ok := ret.Get(0).(func(int) []model.Book); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Book)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int) *error.Error); ok {
+		r1 = rf(_a0)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*error.Error)
+		}
+	}
+
+	return r0, r1
+}
+
+// GetTrashedBooks provides a mock function with given fields: _a0
+func (_m *BookService) GetTrashedBooks(_a0 int) ([]model.Book, *error.Error) {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTrashedBooks")
+	}
+
+	var r0 []model.Book
+	var r1 *error.Error
+	if rf, ok := ret.Get(0).(func(int) ([]model.Book, *error.Error)); ok {
+		return rf(_a0)
+	}
+	if rf, ok := ret.Get(0).(func(int) []model.Book); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Book)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int) *error.Error); ok {
+		r1 = rf(_a0)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*error.Error)
+		}
+	}
+
+	return r0, r1
+}
+
+// PurgeBook provides a mock function with given fields: _a0
+func (_m *BookService) PurgeBook(_a0 string) *error.Error {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PurgeBook")
+	}
+
+	var r0 *error.Error
+	if rf, ok := ret.Get(0).(func(string) *error.Error); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*error.Error)
+		}
+	}
+
+	return r0
+}
+
+// RestoreTrashedBook provides a mock function with given fields: _a0
+func (_m *BookService) RestoreTrashedBook(_a0 string) *error.Error {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RestoreTrashedBook")
+	}
+
+	var r0 *error.Error
+	if rf, ok := ret.Get(0).(func(string) *error.Error); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*error.Error)
+		}
+	}
+
+	return r0
+}
+
 // UpdateBookByID provides a mock function with given fields: _a0, _a1
 func (_m *BookService) UpdateBookByID(_a0 string, _a1 *model.Book) (*model.Book, *error.Error) {
 	ret := _m.Called(_a0, _a1)
@@ -182,6 +362,38 @@ func (_m *BookService) UpdateBookByID(_a0 string, _a1 *model.Book) (*model.Book,
 	return r0, r1
 }
 
+// UpdateBooks provides a mock function with given fields: _a0
+func (_m *BookService) UpdateBooks(_a0 []model.Book) (*model.BatchReport, *error.Error) {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateBooks")
+	}
+
+	var r0 *model.BatchReport
+	var r1 *error.Error
+	if rf, ok := ret.Get(0).(func([]model.Book) (*model.BatchReport, *error.Error)); ok {
+		return rf(_a0)
+	}
+	if rf, ok := ret.Get(0).(func([]model.Book) *model.BatchReport); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.BatchReport)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func([]model.Book) *error.Error); ok {
+		r1 = rf(_a0)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*error.Error)
+		}
+	}
+
+	return r0, r1
+}
+
 // NewBookService creates a new instance of BookService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewBookService(t interface {
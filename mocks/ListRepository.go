@@ -0,0 +1,153 @@
+// Code generated by mockery v2.39.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	model "main/src/lists/domain/model"
+	error "main/utils/error"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// ListRepository is an autogenerated mock type for the ListRepository type
+type ListRepository struct {
+	mock.Mock
+}
+
+// CreateList provides a mock function with given fields: _a0
+func (_m *ListRepository) CreateList(_a0 *model.ReadingList) *error.Error {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateList")
+	}
+
+	var r0 *error.Error
+	if rf, ok := ret.Get(0).(func(*model.ReadingList) *error.Error); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*error.Error)
+		}
+	}
+
+	return r0
+}
+
+// DeleteList provides a mock function with given fields: _a0
+func (_m *ListRepository) DeleteList(_a0 string) *error.Error {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteList")
+	}
+
+	var r0 *error.Error
+	if rf, ok := ret.Get(0).(func(string) *error.Error); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*error.Error)
+		}
+	}
+
+	return r0
+}
+
+// GetListByID provides a mock function with given fields: _a0
+func (_m *ListRepository) GetListByID(_a0 string) (*model.ReadingList, *error.Error) {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetListByID")
+	}
+
+	var r0 *model.ReadingList
+	var r1 *error.Error
+	if rf, ok := ret.Get(0).(func(string) (*model.ReadingList, *error.Error)); ok {
+		return rf(_a0)
+	}
+	if rf, ok := ret.Get(0).(func(string) *model.ReadingList); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.ReadingList)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) *error.Error); ok {
+		r1 = rf(_a0)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*error.Error)
+		}
+	}
+
+	return r0, r1
+}
+
+// GetListsByOwnerID provides a mock function with given fields: _a0
+func (_m *ListRepository) GetListsByOwnerID(_a0 string) ([]model.ReadingList, *error.Error) {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetListsByOwnerID")
+	}
+
+	var r0 []model.ReadingList
+	var r1 *error.Error
+	if rf, ok := ret.Get(0).(func(string) ([]model.ReadingList, *error.Error)); ok {
+		return rf(_a0)
+	}
+	if rf, ok := ret.Get(0).(func(string) []model.ReadingList); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.ReadingList)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) *error.Error); ok {
+		r1 = rf(_a0)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*error.Error)
+		}
+	}
+
+	return r0, r1
+}
+
+// UpdateList provides a mock function with given fields: _a0, _a1
+func (_m *ListRepository) UpdateList(_a0 *model.ReadingList, _a1 int) *error.Error {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateList")
+	}
+
+	var r0 *error.Error
+	if rf, ok := ret.Get(0).(func(*model.ReadingList, int) *error.Error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*error.Error)
+		}
+	}
+
+	return r0
+}
+
+// NewListRepository creates a new instance of ListRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewListRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ListRepository {
+	mock := &ListRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
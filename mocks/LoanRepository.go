@@ -0,0 +1,291 @@
+// Code generated by mockery v2.39.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	model "main/src/loans/domain/model"
+	error "main/utils/error"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// LoanRepository is an autogenerated mock type for the LoanRepository type
+type LoanRepository struct {
+	mock.Mock
+}
+
+// CheckoutCopy provides a mock function with given fields: _a0, _a1
+func (_m *LoanRepository) CheckoutCopy(_a0 *model.BookCopy, _a1 *model.Loan) *error.Error {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CheckoutCopy")
+	}
+
+	var r0 *error.Error
+	if rf, ok := ret.Get(0).(func(*model.BookCopy, *model.Loan) *error.Error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*error.Error)
+		}
+	}
+
+	return r0
+}
+
+// CreateCopies provides a mock function with given fields: _a0
+func (_m *LoanRepository) CreateCopies(_a0 []model.BookCopy) *error.Error {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateCopies")
+	}
+
+	var r0 *error.Error
+	if rf, ok := ret.Get(0).(func([]model.BookCopy) *error.Error); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*error.Error)
+		}
+	}
+
+	return r0
+}
+
+// CreateHeldCopy provides a mock function with given fields: _a0, _a1
+func (_m *LoanRepository) CreateHeldCopy(_a0 *model.BookCopy, _a1 *model.Hold) *error.Error {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateHeldCopy")
+	}
+
+	var r0 *error.Error
+	if rf, ok := ret.Get(0).(func(*model.BookCopy, *model.Hold) *error.Error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*error.Error)
+		}
+	}
+
+	return r0
+}
+
+// CreateHold provides a mock function with given fields: _a0
+func (_m *LoanRepository) CreateHold(_a0 *model.Hold) *error.Error {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateHold")
+	}
+
+	var r0 *error.Error
+	if rf, ok := ret.Get(0).(func(*model.Hold) *error.Error); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*error.Error)
+		}
+	}
+
+	return r0
+}
+
+// GetCopiesByBookID provides a mock function with given fields: _a0
+func (_m *LoanRepository) GetCopiesByBookID(_a0 string) ([]model.BookCopy, *error.Error) {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetCopiesByBookID")
+	}
+
+	var r0 []model.BookCopy
+	var r1 *error.Error
+	if rf, ok := ret.Get(0).(func(string) ([]model.BookCopy, *error.Error)); ok {
+		return rf(_a0)
+	}
+	if rf, ok := ret.Get(0).(func(string) []model.BookCopy); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.BookCopy)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) *error.Error); ok {
+		r1 = rf(_a0)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*error.Error)
+		}
+	}
+
+	return r0, r1
+}
+
+// GetLoanByID provides a mock function with given fields: _a0
+func (_m *LoanRepository) GetLoanByID(_a0 string) (*model.Loan, *error.Error) {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetLoanByID")
+	}
+
+	var r0 *model.Loan
+	var r1 *error.Error
+	if rf, ok := ret.Get(0).(func(string) (*model.Loan, *error.Error)); ok {
+		return rf(_a0)
+	}
+	if rf, ok := ret.Get(0).(func(string) *model.Loan); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Loan)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) *error.Error); ok {
+		r1 = rf(_a0)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*error.Error)
+		}
+	}
+
+	return r0, r1
+}
+
+// GetOverdueLoans provides a mock function with given fields: _a0
+func (_m *LoanRepository) GetOverdueLoans(_a0 time.Time) ([]model.Loan, *error.Error) {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetOverdueLoans")
+	}
+
+	var r0 []model.Loan
+	var r1 *error.Error
+	if rf, ok := ret.Get(0).(func(time.Time) ([]model.Loan, *error.Error)); ok {
+		return rf(_a0)
+	}
+	if rf, ok := ret.Get(0).(func(time.Time) []model.Loan); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Loan)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(time.Time) *error.Error); ok {
+		r1 = rf(_a0)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*error.Error)
+		}
+	}
+
+	return r0, r1
+}
+
+// GetWaitingHoldsByBookID provides a mock function with given fields: _a0
+func (_m *LoanRepository) GetWaitingHoldsByBookID(_a0 string) ([]model.Hold, *error.Error) {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetWaitingHoldsByBookID")
+	}
+
+	var r0 []model.Hold
+	var r1 *error.Error
+	if rf, ok := ret.Get(0).(func(string) ([]model.Hold, *error.Error)); ok {
+		return rf(_a0)
+	}
+	if rf, ok := ret.Get(0).(func(string) []model.Hold); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Hold)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) *error.Error); ok {
+		r1 = rf(_a0)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*error.Error)
+		}
+	}
+
+	return r0, r1
+}
+
+// RenewLoan provides a mock function with given fields: _a0, _a1, _a2
+func (_m *LoanRepository) RenewLoan(_a0 string, _a1 time.Time, _a2 int) (*model.Loan, *error.Error) {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RenewLoan")
+	}
+
+	var r0 *model.Loan
+	var r1 *error.Error
+	if rf, ok := ret.Get(0).(func(string, time.Time, int) (*model.Loan, *error.Error)); ok {
+		return rf(_a0, _a1, _a2)
+	}
+	if rf, ok := ret.Get(0).(func(string, time.Time, int) *model.Loan); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Loan)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, time.Time, int) *error.Error); ok {
+		r1 = rf(_a0, _a1, _a2)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*error.Error)
+		}
+	}
+
+	return r0, r1
+}
+
+// ReturnLoan provides a mock function with given fields: _a0, _a1
+func (_m *LoanRepository) ReturnLoan(_a0 *model.Loan, _a1 *model.Hold) *error.Error {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReturnLoan")
+	}
+
+	var r0 *error.Error
+	if rf, ok := ret.Get(0).(func(*model.Loan, *model.Hold) *error.Error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*error.Error)
+		}
+	}
+
+	return r0
+}
+
+// NewLoanRepository creates a new instance of LoanRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewLoanRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *LoanRepository {
+	mock := &LoanRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
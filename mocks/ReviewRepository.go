@@ -0,0 +1,153 @@
+// Code generated by mockery v2.39.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	model "main/src/reviews/domain/model"
+	error "main/utils/error"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// ReviewRepository is an autogenerated mock type for the ReviewRepository type
+type ReviewRepository struct {
+	mock.Mock
+}
+
+// CreateReview provides a mock function with given fields: _a0
+func (_m *ReviewRepository) CreateReview(_a0 *model.Review) *error.Error {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateReview")
+	}
+
+	var r0 *error.Error
+	if rf, ok := ret.Get(0).(func(*model.Review) *error.Error); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*error.Error)
+		}
+	}
+
+	return r0
+}
+
+// DeleteReview provides a mock function with given fields: _a0
+func (_m *ReviewRepository) DeleteReview(_a0 *model.Review) *error.Error {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteReview")
+	}
+
+	var r0 *error.Error
+	if rf, ok := ret.Get(0).(func(*model.Review) *error.Error); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*error.Error)
+		}
+	}
+
+	return r0
+}
+
+// GetReview provides a mock function with given fields: _a0, _a1
+func (_m *ReviewRepository) GetReview(_a0 string, _a1 string) (*model.Review, *error.Error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetReview")
+	}
+
+	var r0 *model.Review
+	var r1 *error.Error
+	if rf, ok := ret.Get(0).(func(string, string) (*model.Review, *error.Error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(string, string) *model.Review); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Review)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string) *error.Error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*error.Error)
+		}
+	}
+
+	return r0, r1
+}
+
+// GetReviewsByBookID provides a mock function with given fields: _a0
+func (_m *ReviewRepository) GetReviewsByBookID(_a0 string) ([]model.Review, *error.Error) {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetReviewsByBookID")
+	}
+
+	var r0 []model.Review
+	var r1 *error.Error
+	if rf, ok := ret.Get(0).(func(string) ([]model.Review, *error.Error)); ok {
+		return rf(_a0)
+	}
+	if rf, ok := ret.Get(0).(func(string) []model.Review); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Review)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) *error.Error); ok {
+		r1 = rf(_a0)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*error.Error)
+		}
+	}
+
+	return r0, r1
+}
+
+// UpdateReview provides a mock function with given fields: _a0, _a1
+func (_m *ReviewRepository) UpdateReview(_a0 *model.Review, _a1 *model.Review) *error.Error {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateReview")
+	}
+
+	var r0 *error.Error
+	if rf, ok := ret.Get(0).(func(*model.Review, *model.Review) *error.Error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*error.Error)
+		}
+	}
+
+	return r0
+}
+
+// NewReviewRepository creates a new instance of ReviewRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewReviewRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ReviewRepository {
+	mock := &ReviewRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
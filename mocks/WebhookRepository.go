@@ -0,0 +1,239 @@
+// Code generated by mockery v2.39.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	model "main/src/webhooks/domain/model"
+	error "main/utils/error"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// WebhookRepository is an autogenerated mock type for the WebhookRepository type
+type WebhookRepository struct {
+	mock.Mock
+}
+
+// CreateDelivery provides a mock function with given fields: _a0
+func (_m *WebhookRepository) CreateDelivery(_a0 *model.Delivery) *error.Error {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateDelivery")
+	}
+
+	var r0 *error.Error
+	if rf, ok := ret.Get(0).(func(*model.Delivery) *error.Error); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*error.Error)
+		}
+	}
+
+	return r0
+}
+
+// CreateSubscription provides a mock function with given fields: _a0
+func (_m *WebhookRepository) CreateSubscription(_a0 *model.Subscription) *error.Error {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateSubscription")
+	}
+
+	var r0 *error.Error
+	if rf, ok := ret.Get(0).(func(*model.Subscription) *error.Error); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*error.Error)
+		}
+	}
+
+	return r0
+}
+
+// DeleteSubscription provides a mock function with given fields: _a0
+func (_m *WebhookRepository) DeleteSubscription(_a0 string) *error.Error {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteSubscription")
+	}
+
+	var r0 *error.Error
+	if rf, ok := ret.Get(0).(func(string) *error.Error); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*error.Error)
+		}
+	}
+
+	return r0
+}
+
+// GetDeliveriesBySubscription provides a mock function with given fields: _a0, _a1, _a2
+func (_m *WebhookRepository) GetDeliveriesBySubscription(_a0 string, _a1 model.DeliveryStatus, _a2 int) ([]model.Delivery, *error.Error) {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetDeliveriesBySubscription")
+	}
+
+	var r0 []model.Delivery
+	var r1 *error.Error
+	if rf, ok := ret.Get(0).(func(string, model.DeliveryStatus, int) ([]model.Delivery, *error.Error)); ok {
+		return rf(_a0, _a1, _a2)
+	}
+	if rf, ok := ret.Get(0).(func(string, model.DeliveryStatus, int) []model.Delivery); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Delivery)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, model.DeliveryStatus, int) *error.Error); ok {
+		r1 = rf(_a0, _a1, _a2)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*error.Error)
+		}
+	}
+
+	return r0, r1
+}
+
+// GetDueDeliveries provides a mock function with given fields: _a0, _a1
+func (_m *WebhookRepository) GetDueDeliveries(_a0 time.Time, _a1 int) ([]model.Delivery, *error.Error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetDueDeliveries")
+	}
+
+	var r0 []model.Delivery
+	var r1 *error.Error
+	if rf, ok := ret.Get(0).(func(time.Time, int) ([]model.Delivery, *error.Error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(time.Time, int) []model.Delivery); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Delivery)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(time.Time, int) *error.Error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*error.Error)
+		}
+	}
+
+	return r0, r1
+}
+
+// GetSubscriptionByID provides a mock function with given fields: _a0
+func (_m *WebhookRepository) GetSubscriptionByID(_a0 string) (*model.Subscription, *error.Error) {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSubscriptionByID")
+	}
+
+	var r0 *model.Subscription
+	var r1 *error.Error
+	if rf, ok := ret.Get(0).(func(string) (*model.Subscription, *error.Error)); ok {
+		return rf(_a0)
+	}
+	if rf, ok := ret.Get(0).(func(string) *model.Subscription); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Subscription)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) *error.Error); ok {
+		r1 = rf(_a0)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*error.Error)
+		}
+	}
+
+	return r0, r1
+}
+
+// GetSubscriptions provides a mock function with given fields:
+func (_m *WebhookRepository) GetSubscriptions() ([]model.Subscription, *error.Error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSubscriptions")
+	}
+
+	var r0 []model.Subscription
+	var r1 *error.Error
+	if rf, ok := ret.Get(0).(func() ([]model.Subscription, *error.Error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() []model.Subscription); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Subscription)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() *error.Error); ok {
+		r1 = rf()
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*error.Error)
+		}
+	}
+
+	return r0, r1
+}
+
+// SaveDelivery provides a mock function with given fields: _a0
+func (_m *WebhookRepository) SaveDelivery(_a0 *model.Delivery) *error.Error {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveDelivery")
+	}
+
+	var r0 *error.Error
+	if rf, ok := ret.Get(0).(func(*model.Delivery) *error.Error); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*error.Error)
+		}
+	}
+
+	return r0
+}
+
+// NewWebhookRepository creates a new instance of WebhookRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewWebhookRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *WebhookRepository {
+	mock := &WebhookRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,148 @@
+// Package catalog serializes books into the record formats library systems
+// load: MARCXML, binary MARC 21 (ISO 2709) and Dublin Core.
+package catalog
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+
+	"main/src/books/domain/model"
+	appError "main/utils/error"
+)
+
+type MediaType string
+
+const (
+	MediaJSON       MediaType = "application/json"
+	MediaMARCXML    MediaType = "application/marcxml+xml"
+	MediaMARC       MediaType = "application/marc"
+	MediaDublinCore MediaType = "application/oai_dc+xml"
+)
+
+// Formats lists the catalog formats in server preference order, which breaks
+// ties between media types the client weighs the same.
+var Formats = []MediaType{MediaJSON, MediaMARCXML, MediaMARC, MediaDublinCore}
+
+// Negotiate picks the response media type for an Accept header among offers,
+// which default to Formats. An empty header or a wildcard gets the first
+// offer; false means nothing acceptable is offered.
+func Negotiate(accept string, offers ...MediaType) (MediaType, bool) {
+	if len(offers) == 0 {
+		offers = Formats
+	}
+	if strings.TrimSpace(accept) == "" {
+		return offers[0], true
+	}
+
+	type candidate struct {
+		media MediaType
+		q     float64
+		order int
+	}
+	var candidates []candidate
+	for order, part := range strings.Split(accept, ",") {
+		params := strings.Split(part, ";")
+		mediaRange := strings.ToLower(strings.TrimSpace(params[0]))
+		q := 1.0
+		for _, param := range params[1:] {
+			key, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+			if strings.TrimSpace(key) == "q" {
+				if parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil {
+					q = parsed
+				}
+			}
+		}
+		if q <= 0 {
+			continue
+		}
+		for _, media := range offers {
+			if matches(mediaRange, media) {
+				candidates = append(candidates, candidate{media: media, q: q, order: order})
+				break
+			}
+		}
+	}
+	if len(candidates) == 0 {
+		return "", false
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].q > candidates[j].q })
+	return candidates[0].media, true
+}
+
+func matches(mediaRange string, media MediaType) bool {
+	switch mediaRange {
+	case "*/*":
+		return true
+	case "application/marc21":
+		return media == MediaMARC
+	}
+	if strings.HasSuffix(mediaRange, "/*") {
+		return strings.HasPrefix(string(media), strings.TrimSuffix(mediaRange, "*"))
+	}
+	return mediaRange == string(media)
+}
+
+var errUnsupported = errors.New("unsupported catalog format")
+
+// Writer encodes books one at a time, like the exporter's row writers.
+type Writer interface {
+	Write(*model.Book) error
+	Close() error
+}
+
+// NewWriter starts a stream of records. XML formats are wrapped in a
+// <collection> element; MARC records are simply concatenated.
+func NewWriter(w io.Writer, media MediaType) (Writer, error) {
+	return newWriter(w, media, true)
+}
+
+func newWriter(w io.Writer, media MediaType, collection bool) (Writer, error) {
+	switch media {
+	case MediaMARCXML:
+		return newMARCXMLWriter(w, collection)
+	case MediaMARC:
+		return &marcWriter{out: w}, nil
+	case MediaDublinCore:
+		return newDCWriter(w, collection)
+	}
+	return nil, fmt.Errorf("%w %s", errUnsupported, media)
+}
+
+// MarshalBook serializes one book as a standalone record.
+func MarshalBook(media MediaType, book *model.Book) ([]byte, *appError.Error) {
+	return marshal(media, []model.Book{*book}, false)
+}
+
+// MarshalBooks serializes books as a collection.
+func MarshalBooks(media MediaType, books []model.Book) ([]byte, *appError.Error) {
+	return marshal(media, books, true)
+}
+
+func marshal(media MediaType, books []model.Book, collection bool) ([]byte, *appError.Error) {
+	var out bytes.Buffer
+	writer, err := newWriter(&out, media, collection)
+	if err != nil {
+		if errors.Is(err, errUnsupported) {
+			return nil, appError.NewBadRequestError("Unsupported catalog format " + string(media) + ".")
+		}
+		log.Printf("Error starting %s serialization: %v", media, err)
+		return nil, appError.NewUnexpectedError(err.Error())
+	}
+	for i := range books {
+		if err := writer.Write(&books[i]); err != nil {
+			log.Printf("Error serializing book as %s: %v, ID: %s", media, err, books[i].ID)
+			return nil, appError.NewUnexpectedError(err.Error())
+		}
+	}
+	if err := writer.Close(); err != nil {
+		log.Printf("Error finishing %s serialization: %v", media, err)
+		return nil, appError.NewUnexpectedError(err.Error())
+	}
+	return out.Bytes(), nil
+}
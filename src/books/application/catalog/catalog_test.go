@@ -0,0 +1,176 @@
+package catalog_test
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strconv"
+	"strings"
+	"testing"
+
+	"main/src/books/application/catalog"
+	"main/src/books/domain/model"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type CatalogSuite struct {
+	suite.Suite
+	book *model.Book
+}
+
+func (suite *CatalogSuite) SetupTest() {
+	suite.book = &model.Book{
+		ID:          "5f0c8a2e-3b0b-4f53-9d4a-0c1f9c6c2b11",
+		Name:        "The Great Gatsby",
+		Subtitle:    "A Novel",
+		Description: "Jay Gatsby & the green light.",
+		ImgURL:      "https://example.com/gatsby.jpg",
+		ISBN:        "9780743273565",
+		Publisher:   "Riverbank Press",
+		PublishedOn: "2004-09-30",
+		Contributors: []model.Contributor{
+			{Name: "Jane Doe", Role: "foreword"},
+			{Name: "F. Scott Fitzgerald", Role: "author"},
+		},
+		Subjects: []model.Subject{
+			{Scheme: "BISAC", Code: "FIC004000", Main: true},
+			{Scheme: "keywords", Heading: "jazz age; long island"},
+		},
+	}
+}
+
+func (suite *CatalogSuite) field(record *catalog.Record, tag string) *catalog.DataField {
+	for i := range record.DataFields {
+		if record.DataFields[i].Tag == tag {
+			return &record.DataFields[i]
+		}
+	}
+	return nil
+}
+
+func (suite *CatalogSuite) TestNewRecord() {
+	record := catalog.NewRecord(suite.book)
+
+	suite.Equal("001", record.ControlFields[0].Tag)
+	suite.Equal(suite.book.ID, record.ControlFields[0].Value)
+	suite.Len(record.ControlFields[1].Value, 40)
+	suite.Equal("s2004", record.ControlFields[1].Value[6:11])
+
+	var tags []string
+	for _, field := range record.DataFields {
+		tags = append(tags, field.Tag)
+	}
+	suite.Equal([]string{"020", "072", "100", "245", "264", "520", "653", "653", "700", "856"}, tags)
+
+	author := suite.field(record, "100")
+	suite.Equal("Fitzgerald, F. Scott", author.Subfields[0].Value)
+	suite.Equal("author", author.Subfields[1].Value)
+
+	title := suite.field(record, "245")
+	suite.Equal(byte('1'), title.Ind1)
+	suite.Equal(byte('4'), title.Ind2)
+	suite.Equal("The Great Gatsby :", title.Subfields[0].Value)
+	suite.Equal("A Novel", title.Subfields[1].Value)
+}
+
+func (suite *CatalogSuite) TestNewRecordWithoutAuthor() {
+	record := catalog.NewRecord(&model.Book{ID: "1", Name: "Atlas"})
+
+	suite.Equal("nuuuu", record.ControlFields[1].Value[6:11])
+	suite.Nil(suite.field(record, "100"))
+	suite.Equal(byte('0'), suite.field(record, "245").Ind1)
+}
+
+func (suite *CatalogSuite) TestMarshalISO2709() {
+	data, err := catalog.NewRecord(suite.book).MarshalISO2709()
+	suite.Nil(err)
+
+	length, _ := strconv.Atoi(string(data[:5]))
+	suite.Equal(len(data), length)
+	suite.Equal(byte(0x1D), data[len(data)-1])
+	suite.Equal("nam a22", string(data[5:12]))
+
+	base, _ := strconv.Atoi(string(data[12:17]))
+	directory := data[24 : base-1]
+	suite.Zero(len(directory) % 12)
+	for i := 0; i < len(directory); i += 12 {
+		tag := string(directory[i : i+3])
+		size, _ := strconv.Atoi(string(directory[i+3 : i+7]))
+		start, _ := strconv.Atoi(string(directory[i+7 : i+12]))
+		field := data[base+start : base+start+size]
+		suite.Equal(byte(0x1E), field[len(field)-1], "field %s", tag)
+		if tag == "001" {
+			suite.Equal(suite.book.ID, string(field[:len(field)-1]))
+		}
+		if tag == "245" {
+			suite.Equal("14\x1faThe Great Gatsby :\x1fbA Novel\x1e", string(field))
+		}
+	}
+}
+
+func (suite *CatalogSuite) TestMarshalISO2709FieldTooLong() {
+	suite.book.Description = strings.Repeat("x", 10000)
+	_, err := catalog.NewRecord(suite.book).MarshalISO2709()
+	suite.NotNil(err)
+}
+
+func (suite *CatalogSuite) TestMarshalMARCXML() {
+	data, err := catalog.MarshalBook(catalog.MediaMARCXML, suite.book)
+	suite.Nil(err)
+	suite.Contains(string(data), `<record xmlns="http://www.loc.gov/MARC21/slim">`)
+	suite.Contains(string(data), `<subfield code="a">Jay Gatsby &amp; the green light.</subfield>`)
+
+	var parsed struct {
+		Records []struct {
+			Leader string `xml:"leader"`
+		} `xml:"record"`
+	}
+	data, err = catalog.MarshalBooks(catalog.MediaMARCXML, []model.Book{*suite.book, *suite.book})
+	suite.Nil(err)
+	suite.Nil(xml.Unmarshal(data, &parsed))
+	suite.Len(parsed.Records, 2)
+	suite.Len(parsed.Records[0].Leader, 24)
+}
+
+func (suite *CatalogSuite) TestMarshalDublinCore() {
+	data, err := catalog.MarshalBook(catalog.MediaDublinCore, suite.book)
+	suite.Nil(err)
+	out := string(data)
+	suite.Contains(out, `<dc:title>The Great Gatsby: A Novel</dc:title>`)
+	suite.Contains(out, `<dc:creator>F. Scott Fitzgerald</dc:creator>`)
+	suite.Contains(out, `<dc:contributor>Jane Doe</dc:contributor>`)
+	suite.Contains(out, `<dc:identifier>urn:isbn:9780743273565</dc:identifier>`)
+	suite.Contains(out, `<dc:subject>BISAC FIC004000</dc:subject>`)
+}
+
+func (suite *CatalogSuite) TestMarshalMARCCollection() {
+	data, err := catalog.MarshalBooks(catalog.MediaMARC, []model.Book{*suite.book, {ID: "2", Name: "Atlas"}})
+	suite.Nil(err)
+	suite.Equal(2, bytes.Count(data, []byte{0x1D}))
+
+	_, err = catalog.MarshalBooks(catalog.MediaJSON, nil)
+	suite.NotNil(err)
+}
+
+func (suite *CatalogSuite) TestNegotiate() {
+	cases := map[string]catalog.MediaType{
+		"":                                  catalog.MediaJSON,
+		"*/*":                               catalog.MediaJSON,
+		"application/marcxml+xml":           catalog.MediaMARCXML,
+		"text/html, application/marc;q=0.9": catalog.MediaMARC,
+		"application/json;q=0.5, application/oai_dc+xml": catalog.MediaDublinCore,
+		"application/marcxml+xml;q=0, */*;q=0.1":         catalog.MediaJSON,
+	}
+	for accept, expected := range cases {
+		media, ok := catalog.Negotiate(accept)
+		suite.True(ok, accept)
+		suite.Equal(expected, media, accept)
+	}
+
+	_, ok := catalog.Negotiate("text/html, application/marcxml+xml;q=0")
+	suite.False(ok)
+}
+
+func TestCatalogSuite(t *testing.T) {
+	suite.Run(t, new(CatalogSuite))
+}
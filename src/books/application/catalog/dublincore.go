@@ -0,0 +1,99 @@
+package catalog
+
+import (
+	"encoding/xml"
+	"io"
+
+	"main/src/books/domain/model"
+)
+
+const (
+	oaiDCNamespace = "http://www.openarchives.org/OAI/2.0/oai_dc/"
+	dcNamespace    = "http://purl.org/dc/elements/1.1/"
+)
+
+// dcRecord is a simple Dublin Core record in the oai_dc container that
+// OAI-PMH harvesters and most library systems understand.
+type dcRecord struct {
+	XMLName     xml.Name `xml:"oai_dc:dc"`
+	OAIDC       string   `xml:"xmlns:oai_dc,attr"`
+	DC          string   `xml:"xmlns:dc,attr"`
+	Title       string   `xml:"dc:title"`
+	Creator     []string `xml:"dc:creator"`
+	Contributor []string `xml:"dc:contributor"`
+	Subject     []string `xml:"dc:subject"`
+	Description string   `xml:"dc:description,omitempty"`
+	Publisher   string   `xml:"dc:publisher,omitempty"`
+	Date        string   `xml:"dc:date,omitempty"`
+	Type        string   `xml:"dc:type"`
+	Identifier  []string `xml:"dc:identifier"`
+	Relation    string   `xml:"dc:relation,omitempty"`
+}
+
+func newDCRecord(book *model.Book) *dcRecord {
+	record := &dcRecord{
+		OAIDC:       oaiDCNamespace,
+		DC:          dcNamespace,
+		Title:       book.Name,
+		Description: book.Description,
+		Publisher:   book.Publisher,
+		Date:        book.PublishedOn,
+		Type:        "Text",
+		Identifier:  []string{"urn:uuid:" + book.ID},
+		Relation:    book.ImgURL,
+	}
+	if book.Subtitle != "" {
+		record.Title += ": " + book.Subtitle
+	}
+	if book.ISBN != "" {
+		record.Identifier = append(record.Identifier, "urn:isbn:"+book.ISBN)
+	}
+	for _, contributor := range book.Contributors {
+		if contributor.Role == "author" {
+			record.Creator = append(record.Creator, contributor.Name)
+		} else {
+			record.Contributor = append(record.Contributor, contributor.Name)
+		}
+	}
+	for _, subject := range book.Subjects {
+		if subject.Heading != "" {
+			record.Subject = append(record.Subject, subject.Heading)
+		} else if subject.Code != "" {
+			record.Subject = append(record.Subject, subject.Scheme+" "+subject.Code)
+		}
+	}
+	return record
+}
+
+// dcWriter writes oai_dc records, wrapped in a <collection> element when
+// there may be more than one.
+type dcWriter struct {
+	encoder    *xml.Encoder
+	collection bool
+}
+
+func newDCWriter(w io.Writer, collection bool) (*dcWriter, error) {
+	dw := &dcWriter{encoder: xml.NewEncoder(w), collection: collection}
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return nil, err
+	}
+	if collection {
+		if err := dw.encoder.EncodeToken(xml.StartElement{Name: xml.Name{Local: "collection"}}); err != nil {
+			return nil, err
+		}
+	}
+	return dw, nil
+}
+
+func (dw *dcWriter) Write(book *model.Book) error {
+	return dw.encoder.Encode(newDCRecord(book))
+}
+
+func (dw *dcWriter) Close() error {
+	if dw.collection {
+		if err := dw.encoder.EncodeToken(xml.EndElement{Name: xml.Name{Local: "collection"}}); err != nil {
+			return err
+		}
+	}
+	return dw.encoder.Flush()
+}
@@ -0,0 +1,92 @@
+package catalog
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+
+	"main/src/books/domain/model"
+)
+
+// ISO 2709 delimiters.
+const (
+	subfieldDelimiter = 0x1F
+	fieldTerminator   = 0x1E
+	recordTerminator  = 0x1D
+)
+
+// Lengths are stored as fixed width decimals, which caps fields at 9999 bytes
+// and records at 99999.
+const (
+	maxFieldLength  = 9999
+	maxRecordLength = 99999
+)
+
+var delimiters = strings.NewReplacer(
+	string(rune(subfieldDelimiter)), "",
+	string(rune(fieldTerminator)), "",
+	string(rune(recordTerminator)), "",
+)
+
+// MarshalISO2709 encodes the record as binary MARC 21: the leader, a directory
+// of 12 byte entries and the variable fields.
+func (r *Record) MarshalISO2709() ([]byte, error) {
+	var directory, fields bytes.Buffer
+	entry := func(tag string, field []byte) error {
+		if len(field) > maxFieldLength {
+			return fmt.Errorf("field %s is %d bytes long, the limit is %d", tag, len(field), maxFieldLength)
+		}
+		fmt.Fprintf(&directory, "%s%04d%05d", tag, len(field), fields.Len())
+		fields.Write(field)
+		return nil
+	}
+
+	for _, field := range r.ControlFields {
+		if err := entry(field.Tag, append([]byte(delimiters.Replace(field.Value)), fieldTerminator)); err != nil {
+			return nil, err
+		}
+	}
+	for _, field := range r.DataFields {
+		data := []byte{field.Ind1, field.Ind2}
+		for _, subfield := range field.Subfields {
+			data = append(data, subfieldDelimiter, subfield.Code)
+			data = append(data, delimiters.Replace(subfield.Value)...)
+		}
+		if err := entry(field.Tag, append(data, fieldTerminator)); err != nil {
+			return nil, err
+		}
+	}
+	directory.WriteByte(fieldTerminator)
+
+	baseAddress := len(r.Leader) + directory.Len()
+	length := baseAddress + fields.Len() + 1
+	if length > maxRecordLength {
+		return nil, fmt.Errorf("record is %d bytes long, the limit is %d", length, maxRecordLength)
+	}
+
+	out := make([]byte, 0, length)
+	out = fmt.Appendf(out, "%05d%s%05d%s", length, r.Leader[5:12], baseAddress, r.Leader[17:])
+	out = append(out, directory.Bytes()...)
+	out = append(out, fields.Bytes()...)
+	return append(out, recordTerminator), nil
+}
+
+// marcWriter concatenates binary records, which is how MARC files are shared.
+type marcWriter struct {
+	out io.Writer
+}
+
+func (mw *marcWriter) Write(book *model.Book) error {
+	record := NewRecord(book)
+	data, err := record.MarshalISO2709()
+	if err != nil {
+		return fmt.Errorf("book %s: %w", book.ID, err)
+	}
+	_, err = mw.out.Write(data)
+	return err
+}
+
+func (mw *marcWriter) Close() error {
+	return nil
+}
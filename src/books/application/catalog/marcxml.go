@@ -0,0 +1,89 @@
+package catalog
+
+import (
+	"encoding/xml"
+	"io"
+
+	"main/src/books/domain/model"
+)
+
+const marcNamespace = "http://www.loc.gov/MARC21/slim"
+
+type marcxmlRecord struct {
+	XMLName       xml.Name              `xml:"record"`
+	Namespace     string                `xml:"xmlns,attr,omitempty"`
+	Leader        string                `xml:"leader"`
+	ControlFields []marcxmlControlField `xml:"controlfield"`
+	DataFields    []marcxmlDataField    `xml:"datafield"`
+}
+
+type marcxmlControlField struct {
+	Tag   string `xml:"tag,attr"`
+	Value string `xml:",chardata"`
+}
+
+type marcxmlDataField struct {
+	Tag       string            `xml:"tag,attr"`
+	Ind1      string            `xml:"ind1,attr"`
+	Ind2      string            `xml:"ind2,attr"`
+	Subfields []marcxmlSubfield `xml:"subfield"`
+}
+
+type marcxmlSubfield struct {
+	Code  string `xml:"code,attr"`
+	Value string `xml:",chardata"`
+}
+
+func (r *Record) marcxml() *marcxmlRecord {
+	out := &marcxmlRecord{Leader: r.Leader}
+	for _, field := range r.ControlFields {
+		out.ControlFields = append(out.ControlFields, marcxmlControlField{Tag: field.Tag, Value: field.Value})
+	}
+	for _, field := range r.DataFields {
+		data := marcxmlDataField{Tag: field.Tag, Ind1: string(field.Ind1), Ind2: string(field.Ind2)}
+		for _, subfield := range field.Subfields {
+			data.Subfields = append(data.Subfields, marcxmlSubfield{Code: string(subfield.Code), Value: subfield.Value})
+		}
+		out.DataFields = append(out.DataFields, data)
+	}
+	return out
+}
+
+// marcxmlWriter writes a MARCXML <collection>, or a single namespaced
+// <record> when collection is false.
+type marcxmlWriter struct {
+	encoder    *xml.Encoder
+	collection bool
+}
+
+func newMARCXMLWriter(w io.Writer, collection bool) (*marcxmlWriter, error) {
+	mw := &marcxmlWriter{encoder: xml.NewEncoder(w), collection: collection}
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return nil, err
+	}
+	if collection {
+		start := xml.StartElement{Name: xml.Name{Space: marcNamespace, Local: "collection"}}
+		if err := mw.encoder.EncodeToken(start); err != nil {
+			return nil, err
+		}
+	}
+	return mw, nil
+}
+
+func (mw *marcxmlWriter) Write(book *model.Book) error {
+	record := NewRecord(book).marcxml()
+	if !mw.collection {
+		record.Namespace = marcNamespace
+	}
+	return mw.encoder.Encode(record)
+}
+
+func (mw *marcxmlWriter) Close() error {
+	if mw.collection {
+		end := xml.EndElement{Name: xml.Name{Space: marcNamespace, Local: "collection"}}
+		if err := mw.encoder.EncodeToken(end); err != nil {
+			return err
+		}
+	}
+	return mw.encoder.Flush()
+}
@@ -0,0 +1,198 @@
+package catalog
+
+import (
+	"sort"
+	"strings"
+
+	"main/src/books/domain/model"
+)
+
+// Record is a MARC 21 bibliographic record. Fields are kept in tag order so
+// every serialization lists them the way catalogers expect.
+type Record struct {
+	Leader        string
+	ControlFields []ControlField
+	DataFields    []DataField
+}
+
+type ControlField struct {
+	Tag   string
+	Value string
+}
+
+type DataField struct {
+	Tag       string
+	Ind1      byte
+	Ind2      byte
+	Subfields []Subfield
+}
+
+type Subfield struct {
+	Code  byte
+	Value string
+}
+
+// leader describes a new, language material, monograph record in Unicode,
+// at minimal level (7) with ISBD punctuation (i). The record length and base
+// address are filled in when the record is written as ISO 2709.
+const leader = "00000nam a22000007i 4500"
+
+// subjectSources maps subject schemes to MARC source codes ($2).
+var subjectSources = map[string]string{
+	"BISAC": "bisacsh",
+	"BIC":   "bicssc",
+	"Thema": "thema",
+}
+
+// NewRecord maps a book onto MARC 21. The first author becomes the main entry
+// (100) and every other contributor an added entry (700).
+func NewRecord(book *model.Book) *Record {
+	record := &Record{Leader: leader}
+	record.control("001", book.ID)
+	record.control("008", fixedLengthData(book))
+
+	if book.ISBN != "" {
+		record.data("020", ' ', ' ', Subfield{'a', book.ISBN})
+	}
+	for _, subject := range book.Subjects {
+		if source, ok := subjectSources[subject.Scheme]; ok && subject.Code != "" {
+			record.data("072", ' ', '7', Subfield{'a', subject.Code}, Subfield{'2', source})
+		}
+	}
+
+	mainEntry := -1
+	for i, contributor := range book.Contributors {
+		if contributor.Role == "author" {
+			mainEntry = i
+			break
+		}
+	}
+	if mainEntry >= 0 {
+		record.data("100", nameIndicator(book.Contributors[mainEntry].Name), ' ', nameSubfields(book.Contributors[mainEntry])...)
+	}
+
+	titleIndicator := byte('0')
+	if mainEntry >= 0 {
+		titleIndicator = '1'
+	}
+	if book.Subtitle != "" {
+		record.data("245", titleIndicator, nonFiling(book.Name), Subfield{'a', book.Name + " :"}, Subfield{'b', book.Subtitle})
+	} else {
+		record.data("245", titleIndicator, nonFiling(book.Name), Subfield{'a', book.Name})
+	}
+
+	if book.Publisher != "" || book.PublishedOn != "" {
+		var publication []Subfield
+		if book.Publisher != "" {
+			publication = append(publication, Subfield{'b', book.Publisher})
+		}
+		if year := publicationYear(book); year != "" {
+			publication = append(publication, Subfield{'c', year})
+		}
+		record.data("264", ' ', '1', publication...)
+	}
+	if book.Description != "" {
+		record.data("520", ' ', ' ', Subfield{'a', book.Description})
+	}
+
+	for _, subject := range book.Subjects {
+		if subject.Heading == "" {
+			continue
+		}
+		if source, ok := subjectSources[subject.Scheme]; ok {
+			record.data("650", ' ', '7', Subfield{'a', subject.Heading}, Subfield{'2', source})
+			continue
+		}
+		// Keywords and unknown schemes are uncontrolled index terms.
+		for _, term := range strings.Split(subject.Heading, ";") {
+			if term = strings.TrimSpace(term); term != "" {
+				record.data("653", ' ', ' ', Subfield{'a', term})
+			}
+		}
+	}
+
+	for i, contributor := range book.Contributors {
+		if i != mainEntry {
+			record.data("700", nameIndicator(contributor.Name), ' ', nameSubfields(contributor)...)
+		}
+	}
+	if book.ImgURL != "" {
+		record.data("856", '4', '2', Subfield{'3', "Cover image"}, Subfield{'u', book.ImgURL})
+	}
+
+	sort.SliceStable(record.DataFields, func(i, j int) bool {
+		return record.DataFields[i].Tag < record.DataFields[j].Tag
+	})
+	return record
+}
+
+func (r *Record) control(tag, value string) {
+	r.ControlFields = append(r.ControlFields, ControlField{Tag: tag, Value: value})
+}
+
+func (r *Record) data(tag string, ind1, ind2 byte, subfields ...Subfield) {
+	r.DataFields = append(r.DataFields, DataField{Tag: tag, Ind1: ind1, Ind2: ind2, Subfields: subfields})
+}
+
+// fixedLengthData builds the 40 character 008 field. Only the publication
+// date is known; the language is undetermined and the source is "other".
+func fixedLengthData(book *model.Book) string {
+	dateType, date1 := "n", "uuuu"
+	if year := publicationYear(book); year != "" {
+		dateType, date1 = "s", year
+	}
+	return "      " + dateType + date1 + "    " + "xx " + strings.Repeat(" ", 17) + "und" + " " + "d"
+}
+
+func publicationYear(book *model.Book) string {
+	if len(book.PublishedOn) < 4 {
+		return ""
+	}
+	year := book.PublishedOn[:4]
+	for _, c := range year {
+		if c < '0' || c > '9' {
+			return ""
+		}
+	}
+	return year
+}
+
+// nonFiling counts the leading article a title index must skip.
+func nonFiling(title string) byte {
+	lower := strings.ToLower(title)
+	for _, article := range []string{"the ", "an ", "a "} {
+		if strings.HasPrefix(lower, article) {
+			return byte('0' + len(article))
+		}
+	}
+	return '0'
+}
+
+// nameIndicator is 1 (surname) for names inverted by invertName and 0
+// (forename) for single word names.
+func nameIndicator(name string) byte {
+	if strings.Contains(strings.TrimSpace(name), " ") {
+		return '1'
+	}
+	return '0'
+}
+
+// invertName turns a direct order name into "Surname, Forenames".
+func invertName(name string) string {
+	name = strings.TrimSpace(name)
+	space := strings.LastIndex(name, " ")
+	if space < 0 {
+		return name
+	}
+	return name[space+1:] + ", " + name[:space]
+}
+
+func nameSubfields(contributor model.Contributor) []Subfield {
+	subfields := []Subfield{{'a', invertName(contributor.Name)}}
+	// Unknown ONIX role codes are kept as-is on the book but are not
+	// meaningful relator terms.
+	if contributor.Role != "" && strings.ToLower(contributor.Role) == contributor.Role {
+		subfields = append(subfields, Subfield{'e', contributor.Role})
+	}
+	return subfields
+}
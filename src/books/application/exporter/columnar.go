@@ -0,0 +1,169 @@
+package exporter
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"main/src/books/domain/model"
+)
+
+// The columnar format stores rows in groups, each group holding all values of
+// one column before the next, which compresses far better than row formats:
+//
+//	magic     "BKCOL1\n"
+//	schema    uvarint length + JSON {"columns": [{"name", "type"}]}
+//	groups    uvarint row count, then per column: every value of the group
+//	          (string: uvarint length + bytes, int64: varint)
+//	end       a group with a row count of 0
+const columnarMagic = "BKCOL1\n"
+
+// rowGroupSize bounds how many rows are buffered before a group is written.
+const rowGroupSize = 10000
+
+// Schema describes the columns of a columnar export.
+type Schema struct {
+	Columns []column `json:"columns"`
+}
+
+type columnarWriter struct {
+	out   *bufio.Writer
+	group []model.Book
+	buf   [binary.MaxVarintLen64]byte
+}
+
+func newColumnarWriter(w io.Writer) (*columnarWriter, error) {
+	cw := &columnarWriter{out: bufio.NewWriter(w)}
+	schema, err := json.Marshal(Schema{Columns: columns})
+	if err != nil {
+		return nil, err
+	}
+	if _, err := cw.out.WriteString(columnarMagic); err != nil {
+		return nil, err
+	}
+	if err := cw.writeBytes(schema); err != nil {
+		return nil, err
+	}
+	return cw, nil
+}
+
+func (cw *columnarWriter) Write(book *model.Book) error {
+	cw.group = append(cw.group, *book)
+	if len(cw.group) == rowGroupSize {
+		return cw.flushGroup()
+	}
+	return nil
+}
+
+func (cw *columnarWriter) Close() error {
+	if err := cw.flushGroup(); err != nil {
+		return err
+	}
+	if err := cw.writeUvarint(0); err != nil {
+		return err
+	}
+	return cw.out.Flush()
+}
+
+func (cw *columnarWriter) flushGroup() error {
+	if len(cw.group) == 0 {
+		return nil
+	}
+	if err := cw.writeUvarint(uint64(len(cw.group))); err != nil {
+		return err
+	}
+	for _, c := range columns {
+		for i := range cw.group {
+			var err error
+			if c.Type == columnInt {
+				n := binary.PutVarint(cw.buf[:], c.value(&cw.group[i]))
+				_, err = cw.out.Write(cw.buf[:n])
+			} else {
+				err = cw.writeBytes([]byte(c.text(&cw.group[i])))
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+	cw.group = cw.group[:0]
+	return nil
+}
+
+func (cw *columnarWriter) writeUvarint(v uint64) error {
+	n := binary.PutUvarint(cw.buf[:], v)
+	_, err := cw.out.Write(cw.buf[:n])
+	return err
+}
+
+func (cw *columnarWriter) writeBytes(b []byte) error {
+	if err := cw.writeUvarint(uint64(len(b))); err != nil {
+		return err
+	}
+	_, err := cw.out.Write(b)
+	return err
+}
+
+// ReadColumnar decodes a columnar export, returning its schema and rows with
+// each value rendered as text in schema order.
+func ReadColumnar(r io.Reader) (*Schema, [][]string, error) {
+	in := bufio.NewReader(r)
+	magic := make([]byte, len(columnarMagic))
+	if _, err := io.ReadFull(in, magic); err != nil || string(magic) != columnarMagic {
+		return nil, nil, errors.New("not a columnar export")
+	}
+	rawSchema, err := readBytes(in)
+	if err != nil {
+		return nil, nil, err
+	}
+	var schema Schema
+	if err := json.Unmarshal(rawSchema, &schema); err != nil {
+		return nil, nil, fmt.Errorf("invalid schema: %w", err)
+	}
+
+	var rows [][]string
+	for {
+		count, err := binary.ReadUvarint(in)
+		if err != nil {
+			return nil, nil, err
+		}
+		if count == 0 {
+			return &schema, rows, nil
+		}
+		group := make([][]string, count)
+		for i := range group {
+			group[i] = make([]string, len(schema.Columns))
+		}
+		for col, c := range schema.Columns {
+			for i := range group {
+				if c.Type == columnInt {
+					v, err := binary.ReadVarint(in)
+					if err != nil {
+						return nil, nil, err
+					}
+					group[i][col] = fmt.Sprint(v)
+					continue
+				}
+				v, err := readBytes(in)
+				if err != nil {
+					return nil, nil, err
+				}
+				group[i][col] = string(v)
+			}
+		}
+		rows = append(rows, group...)
+	}
+}
+
+func readBytes(in *bufio.Reader) ([]byte, error) {
+	size, err := binary.ReadUvarint(in)
+	if err != nil {
+		return nil, err
+	}
+	b := make([]byte, size)
+	_, err = io.ReadFull(in, b)
+	return b, err
+}
@@ -0,0 +1,72 @@
+package exporter
+
+import (
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"main/src/books/domain/model"
+)
+
+type columnType string
+
+const (
+	columnString columnType = "string"
+	columnInt    columnType = "int64"
+)
+
+// column is one exported book attribute. Every format writes the same
+// columns in the same order.
+type column struct {
+	Name string     `json:"name"`
+	Type columnType `json:"type"`
+
+	text  func(*model.Book) string
+	value func(*model.Book) int64
+}
+
+func (c column) format(book *model.Book) string {
+	if c.Type == columnInt {
+		return strconv.FormatInt(c.value(book), 10)
+	}
+	return c.text(book)
+}
+
+// Columns added later are appended, so existing columns keep their position.
+// Lists are written as their JSON array and times as RFC 3339, both empty
+// when the book has none.
+var columns = []column{
+	{Name: "ID", Type: columnString, text: func(b *model.Book) string { return b.ID }},
+	{Name: "name", Type: columnString, text: func(b *model.Book) string { return b.Name }},
+	{Name: "description", Type: columnString, text: func(b *model.Book) string { return b.Description }},
+	{Name: "img_url", Type: columnString, text: func(b *model.Book) string { return b.ImgURL }},
+	{Name: "isbn", Type: columnString, text: func(b *model.Book) string { return b.ISBN }},
+	{Name: "rating_count", Type: columnInt, value: func(b *model.Book) int64 { return int64(b.RatingCount) }},
+	{Name: "rating_total", Type: columnInt, value: func(b *model.Book) int64 { return int64(b.RatingTotal) }},
+	{Name: "subtitle", Type: columnString, text: func(b *model.Book) string { return b.Subtitle }},
+	{Name: "publisher", Type: columnString, text: func(b *model.Book) string { return b.Publisher }},
+	{Name: "published_on", Type: columnString, text: func(b *model.Book) string { return b.PublishedOn }},
+	{Name: "contributors", Type: columnString, text: func(b *model.Book) string { return listText(b.Contributors) }},
+	{Name: "subjects", Type: columnString, text: func(b *model.Book) string { return listText(b.Subjects) }},
+	{Name: "prices", Type: columnString, text: func(b *model.Book) string { return listText(b.Prices) }},
+	{Name: "availability", Type: columnString, text: func(b *model.Book) string { return b.Availability }},
+	{Name: "record_reference", Type: columnString, text: func(b *model.Book) string { return b.RecordReference }},
+	{Name: "created_at", Type: columnString, text: func(b *model.Book) string { return timeText(b.CreatedAt) }},
+	{Name: "updated_at", Type: columnString, text: func(b *model.Book) string { return timeText(b.UpdatedAt) }},
+}
+
+func listText[T any](list []T) string {
+	if len(list) == 0 {
+		return ""
+	}
+	// Slices of plain structs always marshal.
+	content, _ := json.Marshal(list)
+	return string(content)
+}
+
+func timeText(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
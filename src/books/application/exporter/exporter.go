@@ -0,0 +1,209 @@
+package exporter
+
+import (
+	"compress/gzip"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"log"
+	"sync"
+	"time"
+
+	"main/src/books/application/catalog"
+	"main/src/books/domain/model"
+	"main/src/books/domain/repository"
+	appError "main/utils/error"
+)
+
+// Exports are written under ExportPrefix and ManifestKey always describes the
+// most recent one, so consumers have a fixed place to find the download.
+const (
+	ExportPrefix = "exports/"
+	ManifestKey  = ExportPrefix + "latest.json"
+)
+
+// DefaultSegments is the number of parallel scan segments when none is given.
+const DefaultSegments = 4
+
+type Format string
+
+const (
+	FormatCSV      Format = "csv"
+	FormatNDJSON   Format = "ndjson"
+	FormatColumnar Format = "columnar"
+	// Library formats for loading the catalog into an ILS.
+	FormatMARCXML    Format = "marcxml"
+	FormatMARC       Format = "marc"
+	FormatDublinCore Format = "dc"
+)
+
+// Extension is the file extension of the format, without compression.
+func (f Format) Extension() string {
+	switch f {
+	case FormatColumnar:
+		return ".bkcol"
+	case FormatMARCXML:
+		return ".marc.xml"
+	case FormatMARC:
+		return ".mrc"
+	case FormatDublinCore:
+		return ".dc.xml"
+	}
+	return "." + string(f)
+}
+
+func ParseFormat(value string) (Format, *appError.Error) {
+	switch format := Format(value); format {
+	case FormatCSV, FormatNDJSON, FormatColumnar, FormatMARCXML, FormatMARC, FormatDublinCore:
+		return format, nil
+	case "":
+		return FormatCSV, nil
+	}
+	return "", appError.NewBadRequestError("Unsupported export format " + value + ", expected csv, ndjson, columnar, marcxml, marc or dc.")
+}
+
+type Options struct {
+	Format   Format
+	Gzip     bool
+	Segments int
+}
+
+// Manifest records where an export was stored and what it contains.
+type Manifest struct {
+	Key        string    `json:"key"`
+	Format     Format    `json:"format"`
+	Gzip       bool      `json:"gzip"`
+	Rows       int       `json:"rows"`
+	ExportedAt time.Time `json:"exported_at"`
+}
+
+// ExportKey names an export file by its time, so nightly dumps never collide.
+func ExportKey(options Options, at time.Time) string {
+	key := ExportPrefix + "books-" + at.UTC().Format("20060102T150405Z") + options.Format.Extension()
+	if options.Gzip {
+		key += ".gz"
+	}
+	return key
+}
+
+// rowWriter encodes books one at a time in a given format.
+type rowWriter interface {
+	Write(*model.Book) error
+	Close() error
+}
+
+type Exporter struct {
+	books repository.BookRepository
+}
+
+func NewExporter(books repository.BookRepository) *Exporter {
+	return &Exporter{
+		books: books,
+	}
+}
+
+// Export streams every book from the repository into w and returns how many
+// were written. Books arrive from parallel scan segments, so their order is
+// not stable between exports.
+func (e *Exporter) Export(w io.Writer, options Options) (int, *appError.Error) {
+	if options.Segments < 1 {
+		options.Segments = DefaultSegments
+	}
+
+	out := w
+	var compressor *gzip.Writer
+	if options.Gzip {
+		compressor = gzip.NewWriter(w)
+		out = compressor
+	}
+	rows, err := newRowWriter(out, options.Format)
+	if err != nil {
+		log.Printf("Error starting %s export: %v", options.Format, err)
+		return 0, appError.NewUnexpectedError(err.Error())
+	}
+
+	var mu sync.Mutex
+	count := 0
+	errScan := e.books.ScanBooks(options.Segments, func(books []model.Book) *appError.Error {
+		mu.Lock()
+		defer mu.Unlock()
+		for i := range books {
+			books[i].SummarizeRating()
+			if err := rows.Write(&books[i]); err != nil {
+				log.Printf("Error writing book to export: %v, ID: %s", err, books[i].ID)
+				return appError.NewUnexpectedError(err.Error())
+			}
+			count++
+		}
+		return nil
+	})
+	if errScan != nil {
+		return 0, errScan
+	}
+
+	if err := rows.Close(); err != nil {
+		log.Printf("Error finishing %s export: %v", options.Format, err)
+		return 0, appError.NewUnexpectedError(err.Error())
+	}
+	if compressor != nil {
+		if err := compressor.Close(); err != nil {
+			log.Printf("Error finishing gzip stream: %v", err)
+			return 0, appError.NewUnexpectedError(err.Error())
+		}
+	}
+	log.Printf("Exported %d books as %s, gzip: %t", count, options.Format, options.Gzip)
+	return count, nil
+}
+
+func newRowWriter(w io.Writer, format Format) (rowWriter, error) {
+	switch format {
+	case FormatNDJSON:
+		return &ndjsonWriter{encoder: json.NewEncoder(w)}, nil
+	case FormatColumnar:
+		return newColumnarWriter(w)
+	case FormatMARCXML:
+		return catalog.NewWriter(w, catalog.MediaMARCXML)
+	case FormatMARC:
+		return catalog.NewWriter(w, catalog.MediaMARC)
+	case FormatDublinCore:
+		return catalog.NewWriter(w, catalog.MediaDublinCore)
+	}
+	return newCSVWriter(w)
+}
+
+type csvWriter struct {
+	out    *csv.Writer
+	record []string
+}
+
+func newCSVWriter(w io.Writer) (*csvWriter, error) {
+	cw := &csvWriter{out: csv.NewWriter(w), record: make([]string, len(columns))}
+	for i, c := range columns {
+		cw.record[i] = c.Name
+	}
+	return cw, cw.out.Write(cw.record)
+}
+
+func (cw *csvWriter) Write(book *model.Book) error {
+	for i, c := range columns {
+		cw.record[i] = c.format(book)
+	}
+	return cw.out.Write(cw.record)
+}
+
+func (cw *csvWriter) Close() error {
+	cw.out.Flush()
+	return cw.out.Error()
+}
+
+type ndjsonWriter struct {
+	encoder *json.Encoder
+}
+
+func (nw *ndjsonWriter) Write(book *model.Book) error {
+	return nw.encoder.Encode(book)
+}
+
+func (nw *ndjsonWriter) Close() error {
+	return nil
+}
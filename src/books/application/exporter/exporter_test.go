@@ -0,0 +1,149 @@
+package exporter_test
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/csv"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"main/src/books/application/exporter"
+	"main/src/books/domain/model"
+	appError "main/utils/error"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	repoMock "main/mocks"
+)
+
+type ExporterSuite struct {
+	suite.Suite
+	bookRepository *repoMock.BookRepository
+	exporter       *exporter.Exporter
+	books          []model.Book
+}
+
+const MethodScanBooks = "ScanBooks"
+
+func (suite *ExporterSuite) SetupTest() {
+	suite.bookRepository = new(repoMock.BookRepository)
+	suite.exporter = exporter.NewExporter(suite.bookRepository)
+	createdAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	suite.books = []model.Book{
+		{
+			ID: uuid.NewString(), Name: "The Great Gatsby", Description: "A classic, \"novel\".", ImgURL: "https://example.com/g.jpg", ISBN: "9780743273565", RatingCount: 2, RatingTotal: 9,
+			Publisher: "Scribner", PublishedOn: "1925-04-10",
+			Contributors: []model.Contributor{{Name: "F. Scott Fitzgerald", Role: "author"}},
+			CreatedAt:    &createdAt,
+		},
+		{ID: uuid.NewString(), Name: "Moby Dick", ImgURL: "https://example.com/m.jpg"},
+		{ID: uuid.NewString(), Name: "Ulysses", ImgURL: "https://example.com/u.jpg"},
+	}
+	// Each book arrives as its own page, as if from separate segments.
+	suite.bookRepository.On(MethodScanBooks, 2, mock.Anything).Run(func(args mock.Arguments) {
+		visit := args.Get(1).(func([]model.Book) *appError.Error)
+		for _, book := range suite.books {
+			visit([]model.Book{book})
+		}
+	}).Return(nil)
+}
+
+func (suite *ExporterSuite) TestExportCSV() {
+	var out bytes.Buffer
+	count, err := suite.exporter.Export(&out, exporter.Options{Format: exporter.FormatCSV, Segments: 2})
+	suite.Nil(err)
+	suite.Equal(3, count)
+
+	records, errCSV := csv.NewReader(&out).ReadAll()
+	suite.NoError(errCSV)
+	suite.Len(records, 4)
+	suite.Equal([]string{
+		"ID", "name", "description", "img_url", "isbn", "rating_count", "rating_total",
+		"subtitle", "publisher", "published_on", "contributors", "subjects", "prices",
+		"availability", "record_reference", "created_at", "updated_at",
+	}, records[0])
+	suite.Equal("A classic, \"novel\".", records[1][2])
+	suite.Equal("9", records[1][6])
+	suite.Equal("Scribner", records[1][8])
+	suite.Equal(`[{"name":"F. Scott Fitzgerald","role":"author"}]`, records[1][10])
+	suite.Equal("", records[1][11], "no subjects")
+	suite.Equal("2024-05-01T12:00:00Z", records[1][15])
+	suite.Equal("", records[2][15], "stored before the timestamps")
+}
+
+func (suite *ExporterSuite) TestExportNDJSONGzip() {
+	var out bytes.Buffer
+	count, err := suite.exporter.Export(&out, exporter.Options{Format: exporter.FormatNDJSON, Gzip: true, Segments: 2})
+	suite.Nil(err)
+	suite.Equal(3, count)
+
+	reader, errGzip := gzip.NewReader(&out)
+	suite.NoError(errGzip)
+	scanner := bufio.NewScanner(reader)
+	var lines []model.Book
+	for scanner.Scan() {
+		var book model.Book
+		suite.NoError(json.Unmarshal(scanner.Bytes(), &book))
+		lines = append(lines, book)
+	}
+	suite.Len(lines, 3)
+	suite.Equal(4.5, lines[0].Rating.Average)
+}
+
+func (suite *ExporterSuite) TestExportColumnarRoundTrip() {
+	var out bytes.Buffer
+	count, err := suite.exporter.Export(&out, exporter.Options{Format: exporter.FormatColumnar, Segments: 2})
+	suite.Nil(err)
+	suite.Equal(3, count)
+
+	schema, rows, errRead := exporter.ReadColumnar(&out)
+	suite.NoError(errRead)
+	suite.Len(schema.Columns, 17)
+	suite.Equal("rating_total", schema.Columns[6].Name)
+	suite.Equal("updated_at", schema.Columns[16].Name)
+	suite.Len(rows, 3)
+	suite.Equal(suite.books[1].ID, rows[1][0])
+	suite.Equal("Moby Dick", rows[1][1])
+	suite.Equal("9", rows[0][6])
+	suite.Equal("1925-04-10", rows[0][9])
+}
+
+func (suite *ExporterSuite) TestExportScanError() {
+	repo := new(repoMock.BookRepository)
+	repo.On(MethodScanBooks, exporter.DefaultSegments, mock.Anything).Return(appError.NewUnexpectedError("scan failed"))
+
+	_, err := exporter.NewExporter(repo).Export(&bytes.Buffer{}, exporter.Options{Format: exporter.FormatCSV})
+	suite.NotNil(err)
+}
+
+func (suite *ExporterSuite) TestExportMARC() {
+	var out bytes.Buffer
+	count, err := suite.exporter.Export(&out, exporter.Options{Format: exporter.FormatMARC, Segments: 2})
+	suite.Nil(err)
+	suite.Equal(3, count)
+	suite.Equal(3, bytes.Count(out.Bytes(), []byte{0x1D}))
+
+	out.Reset()
+	_, err = suite.exporter.Export(&out, exporter.Options{Format: exporter.FormatMARCXML, Segments: 2})
+	suite.Nil(err)
+	suite.Equal(3, bytes.Count(out.Bytes(), []byte("<record>")))
+	suite.Contains(out.String(), `<subfield code="a">9780743273565</subfield>`)
+}
+
+func (suite *ExporterSuite) TestExportKey() {
+	at := time.Date(2024, 5, 1, 3, 0, 0, 0, time.UTC)
+	suite.Equal("exports/books-20240501T030000Z.ndjson.gz", exporter.ExportKey(exporter.Options{Format: exporter.FormatNDJSON, Gzip: true}, at))
+	suite.Equal("exports/books-20240501T030000Z.bkcol", exporter.ExportKey(exporter.Options{Format: exporter.FormatColumnar}, at))
+	suite.Equal("exports/books-20240501T030000Z.mrc", exporter.ExportKey(exporter.Options{Format: exporter.FormatMARC}, at))
+
+	_, err := exporter.ParseFormat("xlsx")
+	suite.NotNil(err)
+}
+
+func TestExporterSuite(t *testing.T) {
+	suite.Run(t, new(ExporterSuite))
+}
@@ -0,0 +1,100 @@
+package feed
+
+import (
+	"encoding/xml"
+	"time"
+
+	appError "main/utils/error"
+)
+
+const atomNamespace = "http://www.w3.org/2005/Atom"
+
+type atomFeed struct {
+	XMLName   xml.Name    `xml:"feed"`
+	Namespace string      `xml:"xmlns,attr"`
+	ID        string      `xml:"id"`
+	Title     string      `xml:"title"`
+	Subtitle  string      `xml:"subtitle,omitempty"`
+	Updated   string      `xml:"updated"`
+	Author    atomAuthor  `xml:"author"`
+	Links     []atomLink  `xml:"link"`
+	Entries   []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Rel  string `xml:"rel,attr,omitempty"`
+	Href string `xml:"href,attr"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+type atomEntry struct {
+	ID         string         `xml:"id"`
+	Title      string         `xml:"title"`
+	Published  string         `xml:"published,omitempty"`
+	Updated    string         `xml:"updated"`
+	Authors    []atomAuthor   `xml:"author"`
+	Categories []atomCategory `xml:"category"`
+	Links      []atomLink     `xml:"link"`
+	Summary    string         `xml:"summary,omitempty"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+// Atom renders the feed as Atom 1.0. Entries carry both the time a book was
+// added and its last update, so readers can surface changed books.
+func (f *Feed) Atom() ([]byte, *appError.Error) {
+	feedUpdated := f.LastModified()
+	if feedUpdated.IsZero() {
+		// Atom requires an updated date; an empty feed never changed.
+		feedUpdated = time.Unix(0, 0).UTC()
+	}
+	document := &atomFeed{
+		Namespace: atomNamespace,
+		ID:        f.SelfURL,
+		Title:     f.Title,
+		Subtitle:  f.Description,
+		Updated:   feedUpdated.Format(time.RFC3339),
+		Author:    atomAuthor{Name: f.Title},
+		Links: []atomLink{
+			{Rel: "self", Href: f.SelfURL, Type: "application/atom+xml"},
+			{Rel: "alternate", Href: f.Site.BaseURL, Type: "text/html"},
+		},
+	}
+	if f.Site.Name != "" {
+		document.Author.Name = f.Site.Name
+	}
+
+	for i := range f.Books {
+		book := &f.Books[i]
+		entry := atomEntry{
+			ID:      "urn:uuid:" + book.ID,
+			Title:   title(book),
+			Updated: feedUpdated.Format(time.RFC3339),
+			Summary: book.Description,
+			Links:   []atomLink{{Rel: "alternate", Href: f.Site.BookURL(book), Type: "text/html"}},
+		}
+		if at := published(book); !at.IsZero() {
+			entry.Published = at.Format(time.RFC3339)
+			entry.Updated = updated(book).Format(time.RFC3339)
+		}
+		for _, name := range authors(book) {
+			entry.Authors = append(entry.Authors, atomAuthor{Name: name})
+		}
+		for _, subject := range book.Subjects {
+			if subject.Heading != "" {
+				entry.Categories = append(entry.Categories, atomCategory{Term: subject.Heading})
+			}
+		}
+		if book.ImgURL != "" {
+			entry.Links = append(entry.Links, atomLink{Rel: "enclosure", Href: book.ImgURL})
+		}
+		document.Entries = append(document.Entries, entry)
+	}
+	return marshalXML(document)
+}
@@ -0,0 +1,130 @@
+// Package feed publishes the most recently added and updated books as RSS 2.0
+// and Atom 1.0, with the validators clients need for conditional GET.
+package feed
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"main/src/books/application/webpage"
+	"main/src/books/domain/model"
+)
+
+type Format string
+
+const (
+	FormatRSS  Format = "rss"
+	FormatAtom Format = "atom"
+)
+
+// ContentType is the media type a feed is served with.
+func (f Format) ContentType() string {
+	if f == FormatAtom {
+		return "application/atom+xml; charset=utf-8"
+	}
+	return "application/rss+xml; charset=utf-8"
+}
+
+const (
+	DefaultLimit = 50
+	// MaxAge is how long clients and caches may reuse a feed without asking.
+	MaxAge = 5 * time.Minute
+)
+
+// Feed is the list of recent books, newest first, and where it is published.
+// Book links point at the public book pages of Site.
+type Feed struct {
+	Site        *webpage.Site
+	Title       string
+	Description string
+	SelfURL     string
+	Books       []model.Book
+}
+
+// LastModified is the newest update among the books, or the zero time when
+// none of them is timestamped.
+func (f *Feed) LastModified() time.Time {
+	var latest time.Time
+	for _, book := range f.Books {
+		if book.UpdatedAt != nil && book.UpdatedAt.After(latest) {
+			latest = *book.UpdatedAt
+		}
+	}
+	return latest.UTC()
+}
+
+// ETag identifies the feed content: the books it lists, their versions and
+// the format they are rendered in.
+func (f *Feed) ETag(format Format) string {
+	hash := sha256.New()
+	hash.Write([]byte(format))
+	for _, book := range f.Books {
+		hash.Write([]byte{0})
+		hash.Write([]byte(book.ID))
+		if book.UpdatedAt != nil {
+			hash.Write([]byte(strconv.FormatInt(book.UpdatedAt.UnixNano(), 10)))
+		}
+	}
+	return `"` + hex.EncodeToString(hash.Sum(nil)[:16]) + `"`
+}
+
+// NotModified evaluates the conditional request headers. As RFC 9110
+// requires, If-Modified-Since is ignored when If-None-Match is present.
+func NotModified(ifNoneMatch, ifModifiedSince, etag string, lastModified time.Time) bool {
+	if strings.TrimSpace(ifNoneMatch) != "" {
+		for _, candidate := range strings.Split(ifNoneMatch, ",") {
+			candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+			if candidate == "*" || candidate == strings.TrimPrefix(etag, "W/") {
+				return true
+			}
+		}
+		return false
+	}
+	if ifModifiedSince == "" || lastModified.IsZero() {
+		return false
+	}
+	since, err := http.ParseTime(ifModifiedSince)
+	if err != nil {
+		return false
+	}
+	return !lastModified.Truncate(time.Second).After(since)
+}
+
+// published is when a book was added, falling back to its last update.
+func published(book *model.Book) time.Time {
+	if book.CreatedAt != nil {
+		return book.CreatedAt.UTC()
+	}
+	if book.UpdatedAt != nil {
+		return book.UpdatedAt.UTC()
+	}
+	return time.Time{}
+}
+
+func updated(book *model.Book) time.Time {
+	if book.UpdatedAt != nil {
+		return book.UpdatedAt.UTC()
+	}
+	return published(book)
+}
+
+func authors(book *model.Book) []string {
+	var names []string
+	for _, contributor := range book.Contributors {
+		if contributor.Role == "author" {
+			names = append(names, contributor.Name)
+		}
+	}
+	return names
+}
+
+func title(book *model.Book) string {
+	if book.Subtitle != "" {
+		return book.Name + ": " + book.Subtitle
+	}
+	return book.Name
+}
@@ -0,0 +1,141 @@
+package feed_test
+
+import (
+	"encoding/xml"
+	"net/http"
+	"testing"
+	"time"
+
+	"main/src/books/application/feed"
+	"main/src/books/application/webpage"
+	"main/src/books/domain/model"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type FeedSuite struct {
+	suite.Suite
+	feed *feed.Feed
+}
+
+func at(value string) *time.Time {
+	t, _ := time.Parse(time.RFC3339, value)
+	return &t
+}
+
+func (suite *FeedSuite) SetupTest() {
+	suite.feed = &feed.Feed{
+		Site:        &webpage.Site{Name: "Books Store", BaseURL: "https://books.example.com"},
+		Title:       "New and updated books",
+		Description: "Recently added & updated books",
+		SelfURL:     "https://api.example.com/dev/feeds/recent.atom",
+		Books: []model.Book{
+			{
+				ID:           "2",
+				Name:         "Dune",
+				Description:  "Spice & sand",
+				Contributors: []model.Contributor{{Name: "Frank Herbert", Role: "author"}},
+				Subjects:     []model.Subject{{Heading: "Science fiction"}},
+				CreatedAt:    at("2024-03-01T10:00:00Z"),
+				UpdatedAt:    at("2024-05-02T08:30:00Z"),
+			},
+			{
+				ID:        "1",
+				Name:      "Atlas",
+				CreatedAt: at("2024-04-01T09:00:00Z"),
+				UpdatedAt: at("2024-04-01T09:00:00Z"),
+			},
+		},
+	}
+}
+
+func (suite *FeedSuite) TestRSS() {
+	data, err := suite.feed.RSS()
+	suite.Nil(err)
+
+	var doc struct {
+		Version string `xml:"version,attr"`
+		Channel struct {
+			Title         string `xml:"title"`
+			LastBuildDate string `xml:"lastBuildDate"`
+			Items         []struct {
+				Title   string   `xml:"title"`
+				Link    string   `xml:"link"`
+				GUID    string   `xml:"guid"`
+				PubDate string   `xml:"pubDate"`
+				Creator []string `xml:"http://purl.org/dc/elements/1.1/ creator"`
+			} `xml:"item"`
+		} `xml:"channel"`
+	}
+	suite.NoError(xml.Unmarshal(data, &doc))
+	suite.Equal("2.0", doc.Version)
+	suite.Equal("Thu, 02 May 2024 08:30:00 +0000", doc.Channel.LastBuildDate)
+	suite.Len(doc.Channel.Items, 2)
+	suite.Equal("https://books.example.com/books/2", doc.Channel.Items[0].Link)
+	suite.Equal("urn:uuid:2", doc.Channel.Items[0].GUID)
+	suite.Equal([]string{"Frank Herbert"}, doc.Channel.Items[0].Creator)
+	suite.Equal("Mon, 01 Apr 2024 09:00:00 +0000", doc.Channel.Items[1].PubDate)
+}
+
+func (suite *FeedSuite) TestAtom() {
+	data, err := suite.feed.Atom()
+	suite.Nil(err)
+
+	var doc struct {
+		XMLName xml.Name `xml:"http://www.w3.org/2005/Atom feed"`
+		Updated string   `xml:"updated"`
+		Entries []struct {
+			ID        string `xml:"id"`
+			Published string `xml:"published"`
+			Updated   string `xml:"updated"`
+			Summary   string `xml:"summary"`
+			Category  []struct {
+				Term string `xml:"term,attr"`
+			} `xml:"category"`
+		} `xml:"entry"`
+	}
+	suite.NoError(xml.Unmarshal(data, &doc))
+	suite.Equal("2024-05-02T08:30:00Z", doc.Updated)
+	suite.Len(doc.Entries, 2)
+	suite.Equal("2024-03-01T10:00:00Z", doc.Entries[0].Published)
+	suite.Equal("2024-05-02T08:30:00Z", doc.Entries[0].Updated)
+	suite.Equal("Spice & sand", doc.Entries[0].Summary)
+	suite.Equal("Science fiction", doc.Entries[0].Category[0].Term)
+}
+
+func (suite *FeedSuite) TestAtomEmpty() {
+	suite.feed.Books = nil
+	data, err := suite.feed.Atom()
+	suite.Nil(err)
+	suite.Contains(string(data), "<updated>1970-01-01T00:00:00Z</updated>")
+	suite.True(suite.feed.LastModified().IsZero())
+}
+
+func (suite *FeedSuite) TestETag() {
+	rss := suite.feed.ETag(feed.FormatRSS)
+	suite.Equal(rss, suite.feed.ETag(feed.FormatRSS))
+	suite.NotEqual(rss, suite.feed.ETag(feed.FormatAtom))
+
+	suite.feed.Books[1].UpdatedAt = at("2024-06-01T00:00:00Z")
+	suite.NotEqual(rss, suite.feed.ETag(feed.FormatRSS))
+}
+
+func (suite *FeedSuite) TestNotModified() {
+	etag := suite.feed.ETag(feed.FormatRSS)
+	lastModified := suite.feed.LastModified()
+	since := lastModified.Format(http.TimeFormat)
+	earlier := lastModified.Add(-time.Hour).Format(http.TimeFormat)
+
+	suite.True(feed.NotModified(etag, "", etag, lastModified))
+	suite.True(feed.NotModified(`"other", W/`+etag, "", etag, lastModified))
+	suite.True(feed.NotModified("*", "", etag, lastModified))
+	suite.False(feed.NotModified(`"other"`, since, etag, lastModified), "If-None-Match takes precedence")
+	suite.True(feed.NotModified("", since, etag, lastModified))
+	suite.False(feed.NotModified("", earlier, etag, lastModified))
+	suite.False(feed.NotModified("", "yesterday", etag, lastModified))
+	suite.False(feed.NotModified("", "", etag, lastModified))
+}
+
+func TestFeedSuite(t *testing.T) {
+	suite.Run(t, new(FeedSuite))
+}
@@ -0,0 +1,92 @@
+package feed
+
+import (
+	"encoding/xml"
+	"log"
+	"time"
+
+	appError "main/utils/error"
+)
+
+type rss struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Atom    string     `xml:"xmlns:atom,attr"`
+	DC      string     `xml:"xmlns:dc,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	Self          atomLink  `xml:"atom:link"`
+	LastBuildDate string    `xml:"lastBuildDate,omitempty"`
+	TTL           int       `xml:"ttl"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string   `xml:"title"`
+	Link        string   `xml:"link"`
+	GUID        rssGUID  `xml:"guid"`
+	PubDate     string   `xml:"pubDate,omitempty"`
+	Creators    []string `xml:"dc:creator"`
+	Categories  []string `xml:"category"`
+	Description string   `xml:"description,omitempty"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+// RSS renders the feed as RSS 2.0. Items are identified by book, so an
+// updated book replaces its earlier item in readers.
+func (f *Feed) RSS() ([]byte, *appError.Error) {
+	channel := rssChannel{
+		Title:       f.Title,
+		Link:        f.Site.BaseURL,
+		Description: f.Description,
+		Self:        atomLink{Rel: "self", Href: f.SelfURL, Type: "application/rss+xml"},
+		TTL:         int(MaxAge / time.Minute),
+	}
+	if lastModified := f.LastModified(); !lastModified.IsZero() {
+		channel.LastBuildDate = lastModified.Format(time.RFC1123Z)
+	}
+	for i := range f.Books {
+		book := &f.Books[i]
+		item := rssItem{
+			Title:       title(book),
+			Link:        f.Site.BookURL(book),
+			GUID:        rssGUID{Value: "urn:uuid:" + book.ID},
+			Creators:    authors(book),
+			Description: book.Description,
+		}
+		if at := updated(book); !at.IsZero() {
+			item.PubDate = at.Format(time.RFC1123Z)
+		}
+		for _, subject := range book.Subjects {
+			if subject.Heading != "" {
+				item.Categories = append(item.Categories, subject.Heading)
+			}
+		}
+		channel.Items = append(channel.Items, item)
+	}
+
+	return marshalXML(&rss{
+		Version: "2.0",
+		Atom:    atomNamespace,
+		DC:      "http://purl.org/dc/elements/1.1/",
+		Channel: channel,
+	})
+}
+
+func marshalXML(document interface{}) ([]byte, *appError.Error) {
+	data, err := xml.Marshal(document)
+	if err != nil {
+		log.Printf("Error marshaling feed: %v", err)
+		return nil, appError.NewUnexpectedError(err.Error())
+	}
+	return append([]byte(xml.Header), data...), nil
+}
@@ -3,12 +3,24 @@ package handler
 import (
 	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
+	"io"
 	"log"
+	"net/http"
+	"time"
 
+	"main/src/books/application/exporter"
+	"main/src/books/application/importer"
+	"main/src/books/application/onix"
+	"main/src/books/application/outbox"
+	"main/src/books/application/search"
 	"main/src/books/application/service"
+	"main/src/books/application/sitemap"
+	"main/src/books/application/stream"
+	"main/src/books/application/trash"
 	"main/src/books/domain/model"
-	"main/src/books/infrastructure/adapter"
-	"main/src/books/infrastructure/configuration"
+	"main/src/books/domain/repository"
 	appError "main/utils/error"
 )
 
@@ -17,120 +29,482 @@ type MicroAWSBookDynamoDB struct {
 	TableName  string
 	BucketName string
 	BucketKey  string
+	// OutboxTableName receives the events of book changes; without it no
+	// events are recorded.
+	OutboxTableName string
+	// HistoryTableName receives a revision of every book change, attributed
+	// to Actor; without it no history is kept.
+	HistoryTableName string
+	Actor            string
+	// TrashRetention is how long deleted books stay in the trash before
+	// they are purged; the repository default when zero.
+	TrashRetention time.Duration
+	// Container provides the repositories and clients, the default
+	// container when nil.
+	Container *BookContainer
 }
 
-func (micro *MicroAWSBookDynamoDB) GetAllBooks() ([]model.Book, *appError.Error) {
-	dynamoClient, err := configuration.GetDynamoDBClient(micro.Ctx)
-	if err != nil {
-		log.Println("Error while defining local/AWS database")
-		return nil, appError.NewUnexpectedError(err.Error())
+func (micro *MicroAWSBookDynamoDB) container() *BookContainer {
+	if micro.Container != nil {
+		return micro.Container
 	}
-	if micro.TableName == "" {
-		micro.TableName = configuration.GetDynamoDBBookTable()
+	return DefaultBookContainer()
+}
+
+func (micro *MicroAWSBookDynamoDB) GetAllBooks() ([]model.Book, *appError.Error) {
+	bookService, errService := micro.bookService(false)
+	if errService != nil {
+		return nil, errService
 	}
-	bookInfrastructure := adapter.NewBookDynamoDBRepository(micro.Ctx, dynamoClient, micro.TableName)
-	bookService := service.NewBookServiceDynamoDB(bookInfrastructure)
 
 	return bookService.GetAllBooks()
 }
 
-func (micro *MicroAWSBookDynamoDB) CreateBook(book *model.Book) (*model.Book, *appError.Error) {
-	dynamoClient, err := configuration.GetDynamoDBClient(micro.Ctx)
-	if err != nil {
-		log.Println("Error while defining local/AWS database")
-		return nil, appError.NewUnexpectedError(err.Error())
+func (micro *MicroAWSBookDynamoDB) GetRecentBooks(limit int) ([]model.Book, *appError.Error) {
+	bookService, errService := micro.bookService(false)
+	if errService != nil {
+		return nil, errService
 	}
-	if micro.TableName == "" {
-		micro.TableName = configuration.GetDynamoDBBookTable()
+
+	return bookService.GetRecentBooks(limit)
+}
+
+func (micro *MicroAWSBookDynamoDB) CreateBook(book *model.Book) (*model.Book, *appError.Error) {
+	bookService, errService := micro.bookService(true)
+	if errService != nil {
+		return nil, errService
 	}
-	bookInfrastructure := adapter.NewBookDynamoDBRepository(micro.Ctx, dynamoClient, micro.TableName)
-	bookService := service.NewBookServiceDynamoDB(bookInfrastructure)
 
 	return bookService.CreateBook(book)
 }
 
-func (micro *MicroAWSBookDynamoDB) CreateBatchBooks(books []model.Book) *appError.Error {
-	dynamoClient, err := configuration.GetDynamoDBClient(micro.Ctx)
-	if err != nil {
-		log.Println("Error while defining local/AWS database")
-		return appError.NewUnexpectedError(err.Error())
+func (micro *MicroAWSBookDynamoDB) CreateBatchBooks(books []model.Book) (*model.BatchReport, *appError.Error) {
+	bookService, errService := micro.bookService(true)
+	if errService != nil {
+		return nil, errService
 	}
-	if micro.TableName == "" {
-		micro.TableName = configuration.GetDynamoDBBookTable()
-	}
-	bookInfrastructure := adapter.NewBookDynamoDBRepository(micro.Ctx, dynamoClient, micro.TableName)
-	bookService := service.NewBookServiceDynamoDB(bookInfrastructure)
 
 	return bookService.CreateBatchBooks(books)
 }
 
 func (micro *MicroAWSBookDynamoDB) GetBookByID(bookID string) (*model.Book, *appError.Error) {
-	dynamoClient, err := configuration.GetDynamoDBClient(micro.Ctx)
-	if err != nil {
-		log.Println("Error while defining local/AWS database")
-		return nil, appError.NewUnexpectedError(err.Error())
-	}
-	if micro.TableName == "" {
-		micro.TableName = configuration.GetDynamoDBBookTable()
+	bookService, errService := micro.bookService(false)
+	if errService != nil {
+		return nil, errService
 	}
-	bookInfrastructure := adapter.NewBookDynamoDBRepository(micro.Ctx, dynamoClient, micro.TableName)
-	bookService := service.NewBookServiceDynamoDB(bookInfrastructure)
 
 	return bookService.GetBookByID(bookID)
 }
 
-func (micro *MicroAWSBookDynamoDB) UpdateBookByID(bookID string, book *model.Book) (*model.Book, *appError.Error) {
-	dynamoClient, err := configuration.GetDynamoDBClient(micro.Ctx)
-	if err != nil {
-		log.Println("Error while defining local/AWS database")
-		return nil, appError.NewUnexpectedError(err.Error())
+func (micro *MicroAWSBookDynamoDB) GetBooksByIDs(bookIDs []string) (*model.BooksByIDs, *appError.Error) {
+	bookService, errService := micro.bookService(false)
+	if errService != nil {
+		return nil, errService
 	}
-	if micro.TableName == "" {
-		micro.TableName = configuration.GetDynamoDBBookTable()
+
+	return bookService.GetBooksByIDs(bookIDs)
+}
+
+func (micro *MicroAWSBookDynamoDB) UpdateBookByID(bookID string, book *model.Book) (*model.Book, *appError.Error) {
+	bookService, errService := micro.bookService(true)
+	if errService != nil {
+		return nil, errService
 	}
-	bookInfrastructure := adapter.NewBookDynamoDBRepository(micro.Ctx, dynamoClient, micro.TableName)
-	bookService := service.NewBookServiceDynamoDB(bookInfrastructure)
 
 	return bookService.UpdateBookByID(bookID, book)
 }
 
 func (micro *MicroAWSBookDynamoDB) DeleteBookByID(bookID string) *appError.Error {
-	dynamoClient, err := configuration.GetDynamoDBClient(micro.Ctx)
-	if err != nil {
-		log.Println("Error while defining local/AWS database")
-		return appError.NewUnexpectedError(err.Error())
-	}
-	if micro.TableName == "" {
-		micro.TableName = configuration.GetDynamoDBBookTable()
+	bookService, errService := micro.bookService(true)
+	if errService != nil {
+		return errService
 	}
-	bookInfrastructure := adapter.NewBookDynamoDBRepository(micro.Ctx, dynamoClient, micro.TableName)
-	bookService := service.NewBookServiceDynamoDB(bookInfrastructure)
 
 	return bookService.DeleteBookByID(bookID)
 }
 
-func (micro *MicroAWSBookDynamoDB) SaveBookFile(file *bytes.Reader, bucketKey, fileExt string) *appError.Error {
-	s3Client, err := configuration.GetAWSS3Client(micro.Ctx)
-	if err != nil {
-		log.Println("Error while defining local/AWS database")
-		return appError.NewUnexpectedError(err.Error())
+func (micro *MicroAWSBookDynamoDB) UpdateBooks(books []model.Book) (*model.BatchReport, *appError.Error) {
+	bookService, errService := micro.bookServiceWithFiles()
+	if errService != nil {
+		return nil, errService
+	}
+	return bookService.UpdateBooks(books)
+}
+
+func (micro *MicroAWSBookDynamoDB) DeleteBooksByIDs(bookIDs []string) (*model.BatchReport, *appError.Error) {
+	bookService, errService := micro.bookServiceWithFiles()
+	if errService != nil {
+		return nil, errService
+	}
+	return bookService.DeleteBooksByIDs(bookIDs)
+}
+
+// GetTrashedBooks returns up to limit books of the trash, most recently
+// deleted first.
+func (micro *MicroAWSBookDynamoDB) GetTrashedBooks(limit int) ([]model.Book, *appError.Error) {
+	bookService, errService := micro.bookService(false)
+	if errService != nil {
+		return nil, errService
 	}
 
-	bookInfrastructure := adapter.NewBookFileRepositoryS3(micro.Ctx, s3Client, micro.BucketName, micro.BucketKey)
+	return bookService.GetTrashedBooks(limit)
+}
+
+// RestoreTrashedBook takes a book out of the trash, recording the restore as
+// a new revision by Actor.
+func (micro *MicroAWSBookDynamoDB) RestoreTrashedBook(bookID string) *appError.Error {
+	bookService, errService := micro.bookService(true)
+	if errService != nil {
+		return errService
+	}
+
+	return bookService.RestoreTrashedBook(bookID)
+}
+
+// PurgeBook deletes a book of the trash for good; the stream removes its
+// image.
+func (micro *MicroAWSBookDynamoDB) PurgeBook(bookID string) *appError.Error {
+	bookService, errService := micro.bookService(false)
+	if errService != nil {
+		return errService
+	}
+
+	return bookService.PurgeBook(bookID)
+}
+
+// BackupBooks writes a consistent copy of the bolt file of the store to w,
+// returning its size. Only the bolt store can be backed up this way.
+func (micro *MicroAWSBookDynamoDB) BackupBooks(w io.Writer) (int64, *appError.Error) {
+	bookInfrastructure, errRepository := micro.bookRepository(false)
+	if errRepository != nil {
+		return 0, errRepository
+	}
+	backup, ok := bookInfrastructure.(interface {
+		Backup(io.Writer) (int64, *appError.Error)
+	})
+	if !ok {
+		return 0, appError.NewBadRequestError("Backups are only available for the bolt store.")
+	}
+	return backup.Backup(w)
+}
+
+func (micro *MicroAWSBookDynamoDB) bookRepository(tracked bool) (repository.BookRepository, *appError.Error) {
+	return micro.container().BookRepository(micro, tracked)
+}
+
+func (micro *MicroAWSBookDynamoDB) bookFileRepository() (repository.BookFileRepository, *appError.Error) {
+	return micro.container().BookFileRepository(micro)
+}
+
+// bookService is the book service of the store chosen by BOOKS_STORE,
+// reading books by ID through the cache of the container.
+func (micro *MicroAWSBookDynamoDB) bookService(tracked bool) (service.BookService, *appError.Error) {
+	bookInfrastructure, errRepository := micro.bookRepository(tracked)
+	if errRepository != nil {
+		return nil, errRepository
+	}
+	return micro.cached(service.NewBookServiceDynamoDB(bookInfrastructure)), nil
+}
+
+func (micro *MicroAWSBookDynamoDB) cached(bookService service.BookService) service.BookService {
+	if cache := micro.container().BookCache; cache != nil {
+		return service.NewBookServiceCached(bookService, cache)
+	}
+	return bookService
+}
+
+// bookServiceWithFiles builds a book service that also cleans up stored
+// images, as needed by the batch operations.
+func (micro *MicroAWSBookDynamoDB) bookServiceWithFiles() (service.BookService, *appError.Error) {
+	bookInfrastructure, errRepository := micro.bookRepository(true)
+	if errRepository != nil {
+		return nil, errRepository
+	}
+	fileInfrastructure, errFiles := micro.bookFileRepository()
+	if errFiles != nil {
+		return nil, errFiles
+	}
+	fileService := service.NewBookFileServiceS3(fileInfrastructure)
+
+	return micro.cached(service.NewBookServiceDynamoDBWithFiles(bookInfrastructure, fileService, micro.BucketKey)), nil
+}
+
+func (micro *MicroAWSBookDynamoDB) SaveBookFile(file *bytes.Reader, bucketKey, fileExt string) *appError.Error {
+	bookInfrastructure, errFiles := micro.bookFileRepository()
+	if errFiles != nil {
+		return errFiles
+	}
 	bookService := service.NewBookFileServiceS3(bookInfrastructure)
 
 	return bookService.SaveBookFile(file, bucketKey, fileExt)
 }
 
+func (micro *MicroAWSBookDynamoDB) GetBookFile(bucketKey string) ([]byte, *appError.Error) {
+	fileInfrastructure, errFiles := micro.bookFileRepository()
+	if errFiles != nil {
+		return nil, errFiles
+	}
+	fileService := service.NewBookFileServiceS3(fileInfrastructure)
+
+	return fileService.GetBookFile(bucketKey)
+}
+
 func (micro *MicroAWSBookDynamoDB) DeleteBookFile(bucketKey string) *appError.Error {
-	s3Client, err := configuration.GetAWSS3Client(micro.Ctx)
+	bookInfrastructure, errFiles := micro.bookFileRepository()
+	if errFiles != nil {
+		return errFiles
+	}
+	bookService := service.NewBookFileServiceS3(bookInfrastructure)
+
+	return bookService.DeleteBookFile(bucketKey)
+}
+
+// ImportBookFile imports the catalog file stored at key in the bucket and
+// writes the report next to it under the reports prefix. CSV files use the
+// header mapping stored beside them when there is one.
+func (micro *MicroAWSBookDynamoDB) ImportBookFile(key string) (*importer.Report, *appError.Error) {
+	format, errFormat := importer.FormatFromName(key)
+	if errFormat != nil {
+		return nil, errFormat
+	}
+	fileInfrastructure, errFiles := micro.bookFileRepository()
+	if errFiles != nil {
+		return nil, errFiles
+	}
+	fileService := service.NewBookFileServiceS3(fileInfrastructure)
+
+	var mapping importer.Mapping
+	if format == importer.FormatCSV {
+		content, errFile := fileService.GetBookFile(importer.MappingKey(key))
+		if errFile == nil {
+			if mapping, errFile = importer.ParseMapping(content); errFile != nil {
+				return nil, errFile
+			}
+		} else if errFile.Code != http.StatusNotFound {
+			return nil, errFile
+		}
+	}
+	content, errFile := fileService.GetBookFile(key)
+	if errFile != nil {
+		return nil, errFile
+	}
+
+	bookService, errService := micro.bookService(true)
+	if errService != nil {
+		return nil, errService
+	}
+
+	report, errImport := importer.NewImporter(bookService).Import(key, bytes.NewReader(content), format, mapping)
+	if errImport != nil {
+		return nil, errImport
+	}
+
+	if errFile := saveImportReport(fileService, key, report); errFile != nil {
+		return nil, errFile
+	}
+	return report, nil
+}
+
+// ImportONIXFile applies the ONIX message stored at key in the bucket and
+// writes the import log under the reports prefix.
+func (micro *MicroAWSBookDynamoDB) ImportONIXFile(key string) (*onix.Log, *appError.Error) {
+	fileInfrastructure, errFiles := micro.bookFileRepository()
+	if errFiles != nil {
+		return nil, errFiles
+	}
+	fileService := service.NewBookFileServiceS3(fileInfrastructure)
+
+	content, errFile := fileService.GetBookFile(key)
+	if errFile != nil {
+		return nil, errFile
+	}
+	bookService, errService := micro.bookServiceWithFiles()
+	if errService != nil {
+		return nil, errService
+	}
+
+	importLog, errImport := onix.NewImporter(bookService).Import(key, bytes.NewReader(content))
+	if errImport != nil {
+		return nil, errImport
+	}
+	if errFile := saveImportReport(fileService, key, importLog); errFile != nil {
+		return nil, errFile
+	}
+	return importLog, nil
+}
+
+func saveImportReport(fileService service.BookFileService, key string, report interface{}) *appError.Error {
+	reportContent, err := json.MarshalIndent(report, "", "  ")
 	if err != nil {
-		log.Println("Error while defining local/AWS database")
+		log.Printf("Error marshaling import report: %v", err)
 		return appError.NewUnexpectedError(err.Error())
 	}
+	return fileService.SaveBookFile(bytes.NewReader(reportContent), importer.ReportKey(key), ".json")
+}
 
-	bookInfrastructure := adapter.NewBookFileRepositoryS3(micro.Ctx, s3Client, micro.BucketName, micro.BucketKey)
-	bookService := service.NewBookFileServiceS3(bookInfrastructure)
+// ExportBooks dumps the whole catalog to the bucket and points the manifest
+// at the new file.
+func (micro *MicroAWSBookDynamoDB) ExportBooks(options exporter.Options) (*exporter.Manifest, *appError.Error) {
+	bookInfrastructure, errRepository := micro.bookRepository(false)
+	if errRepository != nil {
+		return nil, errRepository
+	}
+	fileInfrastructure, errFiles := micro.bookFileRepository()
+	if errFiles != nil {
+		return nil, errFiles
+	}
+	fileService := service.NewBookFileServiceS3(fileInfrastructure)
 
-	return bookService.DeleteBookFile(bucketKey)
+	exportedAt := time.Now().UTC().Truncate(time.Second)
+	manifest := &exporter.Manifest{
+		Key:        exporter.ExportKey(options, exportedAt),
+		Format:     options.Format,
+		Gzip:       options.Gzip,
+		ExportedAt: exportedAt,
+	}
+	fileExt := options.Format.Extension()
+	if options.Gzip {
+		fileExt = ".gz"
+	}
+
+	// The export is uploaded as it is written, so the catalog is never held
+	// in memory whole.
+	reader, writer := io.Pipe()
+	var errExport *appError.Error
+	exported := make(chan struct{})
+	go func() {
+		defer close(exported)
+		manifest.Rows, errExport = exporter.NewExporter(bookInfrastructure).Export(writer, options)
+		if errExport != nil {
+			writer.CloseWithError(errors.New(errExport.Message))
+			return
+		}
+		writer.Close()
+	}()
+	errFile := fileService.UploadBookFile(reader, manifest.Key, fileExt)
+	// An upload that gave up leaves the exporter blocked on the pipe.
+	reader.Close()
+	<-exported
+	if errExport != nil {
+		return nil, errExport
+	}
+	if errFile != nil {
+		return nil, errFile
+	}
+
+	manifestContent, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		log.Printf("Error marshaling export manifest: %v", err)
+		return nil, appError.NewUnexpectedError(err.Error())
+	}
+	if errFile := fileService.SaveBookFile(bytes.NewReader(manifestContent), exporter.ManifestKey, ".json"); errFile != nil {
+		return nil, errFile
+	}
+	return manifest, nil
+}
+
+// GenerateSitemaps rewrites the sitemaps of the public book pages in the
+// bucket.
+func (micro *MicroAWSBookDynamoDB) GenerateSitemaps(options sitemap.Options) (*sitemap.Index, *appError.Error) {
+	bookInfrastructure, errRepository := micro.bookRepository(false)
+	if errRepository != nil {
+		return nil, errRepository
+	}
+	fileInfrastructure, errFiles := micro.bookFileRepository()
+	if errFiles != nil {
+		return nil, errFiles
+	}
+
+	return sitemap.NewGenerator(bookInfrastructure, fileInfrastructure).Generate(options, time.Now())
+}
+
+// LoadSearchIndex reads the search index from the bucket.
+func (micro *MicroAWSBookDynamoDB) LoadSearchIndex() (*search.Index, *appError.Error) {
+	fileInfrastructure, errFiles := micro.bookFileRepository()
+	if errFiles != nil {
+		return nil, errFiles
+	}
+
+	return search.NewStore(fileInfrastructure).Load()
+}
+
+// RebuildSearchIndex indexes the whole catalog again and replaces the
+// stored index, returning how many books it holds.
+func (micro *MicroAWSBookDynamoDB) RebuildSearchIndex(segments int) (int, *appError.Error) {
+	bookInfrastructure, errRepository := micro.bookRepository(false)
+	if errRepository != nil {
+		return 0, errRepository
+	}
+	fileInfrastructure, errFiles := micro.bookFileRepository()
+	if errFiles != nil {
+		return 0, errFiles
+	}
+
+	index, errRebuild := search.RebuildStore(bookInfrastructure, fileInfrastructure, segments)
+	if errRebuild != nil {
+		return 0, errRebuild
+	}
+	return index.Len(), nil
+}
+
+// BookChangeHandlers are the systems that follow the stream of the books
+// table.
+func (micro *MicroAWSBookDynamoDB) BookChangeHandlers() ([]stream.ChangeHandler, *appError.Error) {
+	fileInfrastructure, errFiles := micro.bookFileRepository()
+	if errFiles != nil {
+		return nil, errFiles
+	}
+
+	fileService := service.NewBookFileServiceS3(fileInfrastructure)
+
+	return []stream.ChangeHandler{
+		search.NewStreamIndexer(search.NewStore(fileInfrastructure)),
+		trash.NewFileCleaner(fileService, micro.BucketKey),
+	}, nil
+}
+
+// Event publishers the relay can deliver book events to.
+const (
+	PublisherEventBridge = "eventbridge"
+	PublisherSNS         = "sns"
+	PublisherSQS         = "sqs"
+)
+
+// RelayBookEvents publishes up to limit pending events of the outbox to the
+// event bus, topic or queue named by target.
+func (micro *MicroAWSBookDynamoDB) RelayBookEvents(publisher, target string, limit int) (*outbox.Report, *appError.Error) {
+	eventPublisher, errPublisher := micro.container().EventPublisher(micro, publisher, target)
+	if errPublisher != nil {
+		return nil, errPublisher
+	}
+	outboxInfrastructure, errRepository := micro.container().BookOutboxRepository(micro)
+	if errRepository != nil {
+		return nil, errRepository
+	}
+
+	return outbox.NewRelay(outboxInfrastructure, eventPublisher).Run(limit)
+}
+
+// GetBookHistory returns up to limit revisions of a book, newest first,
+// older than revision before when it is set.
+func (micro *MicroAWSBookDynamoDB) GetBookHistory(bookID string, limit, before int) ([]model.BookRevision, *appError.Error) {
+	historyService, err := micro.container().BookHistoryService(micro)
+	if err != nil {
+		return nil, err
+	}
+	return historyService.GetBookHistory(bookID, limit, before)
+}
+
+// RestoreBookRevision brings a book back to its state after revision,
+// recording the restore as a new revision by Actor.
+func (micro *MicroAWSBookDynamoDB) RestoreBookRevision(bookID string, revision int) (*model.Book, *appError.Error) {
+	historyService, err := micro.container().BookHistoryService(micro)
+	if err != nil {
+		return nil, err
+	}
+	restored, errRestore := historyService.RestoreBookRevision(bookID, revision)
+	if cache := micro.container().BookCache; errRestore == nil && cache != nil {
+		cache.Invalidate(bookID)
+	}
+	return restored, errRestore
 }
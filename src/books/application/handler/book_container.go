@@ -0,0 +1,254 @@
+package handler
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"sync"
+
+	"main/src/books/application/service"
+	"main/src/books/domain/repository"
+	"main/src/books/infrastructure/adapter"
+	"main/src/books/infrastructure/configuration"
+	appError "main/utils/error"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/eventbridge"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/sns"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+)
+
+// BookContainer builds the dependencies of the book handlers. The default
+// container keeps the SDK clients, database handles and book cache for the
+// life of the Lambda container, and builds from them the repositories of
+// each invocation, which carry its context and actor. Tests swap the
+// builders for fakes.
+type BookContainer struct {
+	// BookRepository is the book repository of the store chosen by
+	// BOOKS_STORE. On DynamoDB a tracked repository records the events and
+	// revisions of its changes; the SQL and bolt stores record neither, and
+	// the event-sourced store keeps them as its streams.
+	BookRepository func(micro *MicroAWSBookDynamoDB, tracked bool) (repository.BookRepository, *appError.Error)
+	// BookFileRepository is where the book files live: the bolt file with
+	// the books for the bolt store, the bucket otherwise.
+	BookFileRepository   func(micro *MicroAWSBookDynamoDB) (repository.BookFileRepository, *appError.Error)
+	BookOutboxRepository func(micro *MicroAWSBookDynamoDB) (repository.BookOutboxRepository, *appError.Error)
+	EventPublisher       func(micro *MicroAWSBookDynamoDB, publisher, target string) (repository.EventPublisher, *appError.Error)
+	BookHistoryService   func(micro *MicroAWSBookDynamoDB) (service.BookHistoryService, *appError.Error)
+	// DynamoDBClient is the client the other contexts keep their tables
+	// with, shared with the books.
+	DynamoDBClient func(ctx context.Context) (*dynamodb.Client, *appError.Error)
+	// BookCache caches the books read by ID; nil disables it.
+	BookCache *service.BookCache
+}
+
+// NewBookContainer builds a container whose clients are created on first
+// use, so handlers only pay for the ones they need.
+func NewBookContainer() *BookContainer {
+	clients := &bookClients{prepared: make(map[interface{}]bool)}
+	container := &BookContainer{
+		BookRepository:       clients.bookRepository,
+		BookFileRepository:   clients.bookFileRepository,
+		BookOutboxRepository: clients.bookOutboxRepository,
+		EventPublisher:       clients.eventPublisher,
+		BookHistoryService:   clients.bookHistoryService,
+		DynamoDBClient:       clients.dynamoDBClient,
+	}
+	if config := configuration.GetBookCacheConfig(); config.Size > 0 {
+		container.BookCache = service.NewBookCache(config.Size, config.TTL, config.NegativeTTL)
+	}
+	return container
+}
+
+var (
+	defaultBookContainerMu sync.Mutex
+	defaultBookContainer   *BookContainer
+)
+
+// DefaultBookContainer is the container of the handlers that set none,
+// built once per Lambda container.
+func DefaultBookContainer() *BookContainer {
+	defaultBookContainerMu.Lock()
+	defer defaultBookContainerMu.Unlock()
+	if defaultBookContainer == nil {
+		defaultBookContainer = NewBookContainer()
+	}
+	return defaultBookContainer
+}
+
+// UseBookContainer replaces the default container, so that tests can run
+// the Lambda handlers on fakes. nil brings the default back.
+func UseBookContainer(container *BookContainer) {
+	defaultBookContainerMu.Lock()
+	defer defaultBookContainerMu.Unlock()
+	defaultBookContainer = container
+}
+
+// bookClients are the clients and database handles of the default
+// container, created on first use.
+type bookClients struct {
+	mu          sync.Mutex
+	dynamoDB    *dynamodb.Client
+	s3          *s3.Client
+	eventBridge *eventbridge.Client
+	sns         *sns.Client
+	sqs         *sqs.Client
+	// prepared are the SQL connection pools whose schema is up to date and
+	// the bolt files whose buckets exist.
+	prepared map[interface{}]bool
+}
+
+// client returns the client in cached, building it the first time.
+func client[T comparable](clients *bookClients, cached *T, ctx context.Context, build func(context.Context) (T, error)) (T, *appError.Error) {
+	clients.mu.Lock()
+	defer clients.mu.Unlock()
+	var none T
+	if *cached == none {
+		built, err := build(ctx)
+		if err != nil {
+			log.Println("Error while defining local/AWS client")
+			return none, appError.NewUnexpectedError(err.Error())
+		}
+		*cached = built
+	}
+	return *cached, nil
+}
+
+// prepare runs setup on a database handle the first time it is used.
+func (clients *bookClients) prepare(db interface{}, setup func() *appError.Error) *appError.Error {
+	clients.mu.Lock()
+	defer clients.mu.Unlock()
+	if clients.prepared[db] {
+		return nil
+	}
+	if err := setup(); err != nil {
+		return err
+	}
+	clients.prepared[db] = true
+	return nil
+}
+
+func (clients *bookClients) dynamoDBClient(ctx context.Context) (*dynamodb.Client, *appError.Error) {
+	return client(clients, &clients.dynamoDB, ctx, configuration.GetDynamoDBClient)
+}
+
+func (clients *bookClients) bookRepository(micro *MicroAWSBookDynamoDB, tracked bool) (repository.BookRepository, *appError.Error) {
+	switch configuration.GetBookStore() {
+	case configuration.BookStoreSQL:
+		db, err := configuration.GetSQLDB(micro.Ctx)
+		if err != nil {
+			log.Println("Error while defining SQL database")
+			return nil, appError.NewUnexpectedError(err.Error())
+		}
+		bookInfrastructure := adapter.NewBookSQLRepository(micro.Ctx, db).WithTrashRetention(micro.TrashRetention)
+		// The schema is brought up to date once per connection pool.
+		if errMigrate := clients.prepare(db, bookInfrastructure.Migrate); errMigrate != nil {
+			return nil, errMigrate
+		}
+		return bookInfrastructure, nil
+	case configuration.BookStoreBolt:
+		db, err := configuration.GetBoltDB()
+		if err != nil {
+			log.Println("Error while opening bolt database")
+			return nil, appError.NewUnexpectedError(err.Error())
+		}
+		bookInfrastructure := adapter.NewBookBoltRepository(micro.Ctx, db).WithTrashRetention(micro.TrashRetention)
+		if errBuckets := clients.prepare(db, bookInfrastructure.CreateBuckets); errBuckets != nil {
+			return nil, errBuckets
+		}
+		return bookInfrastructure, nil
+	}
+
+	dynamoClient, errClient := clients.dynamoDBClient(micro.Ctx)
+	if errClient != nil {
+		return nil, errClient
+	}
+	if configuration.GetBookStore() == configuration.BookStoreEventSourced {
+		// The streams are the history of the books, nothing else is tracked.
+		return adapter.NewBookEventSourcedRepository(micro.Ctx, dynamoClient,
+			configuration.GetDynamoDBBookEventsTable(), configuration.GetDynamoDBBookSnapshotsTable()).
+			WithTrashRetention(micro.TrashRetention), nil
+	}
+	if micro.TableName == "" {
+		micro.TableName = configuration.GetDynamoDBBookTable()
+	}
+	if !tracked {
+		return adapter.NewBookDynamoDBRepository(micro.Ctx, dynamoClient, micro.TableName).
+			WithTrashRetention(micro.TrashRetention), nil
+	}
+	return adapter.NewBookDynamoDBRepositoryWithOutbox(micro.Ctx, dynamoClient, micro.TableName, micro.OutboxTableName).
+		WithHistory(micro.HistoryTableName, micro.Actor).
+		WithTrashRetention(micro.TrashRetention), nil
+}
+
+func (clients *bookClients) bookFileRepository(micro *MicroAWSBookDynamoDB) (repository.BookFileRepository, *appError.Error) {
+	if configuration.GetBookStore() == configuration.BookStoreBolt {
+		db, err := configuration.GetBoltDB()
+		if err != nil {
+			log.Println("Error while opening bolt database")
+			return nil, appError.NewUnexpectedError(err.Error())
+		}
+		return adapter.NewBookFileRepositoryBolt(micro.Ctx, db), nil
+	}
+	s3Client, errClient := client(clients, &clients.s3, micro.Ctx, configuration.GetAWSS3Client)
+	if errClient != nil {
+		return nil, errClient
+	}
+	return adapter.NewBookFileRepositoryS3(micro.Ctx, s3Client, micro.BucketName, micro.BucketKey), nil
+}
+
+func (clients *bookClients) bookOutboxRepository(micro *MicroAWSBookDynamoDB) (repository.BookOutboxRepository, *appError.Error) {
+	dynamoClient, errClient := clients.dynamoDBClient(micro.Ctx)
+	if errClient != nil {
+		return nil, errClient
+	}
+	return adapter.NewBookOutboxDynamoDBRepository(micro.Ctx, dynamoClient, micro.OutboxTableName), nil
+}
+
+func (clients *bookClients) eventPublisher(micro *MicroAWSBookDynamoDB, publisher, target string) (repository.EventPublisher, *appError.Error) {
+	switch publisher {
+	case PublisherEventBridge:
+		eventBridgeClient, errClient := client(clients, &clients.eventBridge, micro.Ctx, configuration.GetAWSEventBridgeClient)
+		if errClient != nil {
+			return nil, errClient
+		}
+		return adapter.NewBookEventPublisherEventBridge(micro.Ctx, eventBridgeClient, target), nil
+	case PublisherSNS:
+		snsClient, errClient := client(clients, &clients.sns, micro.Ctx, configuration.GetAWSSNSClient)
+		if errClient != nil {
+			return nil, errClient
+		}
+		return adapter.NewBookEventPublisherSNS(micro.Ctx, snsClient, target), nil
+	case PublisherSQS:
+		sqsClient, errClient := client(clients, &clients.sqs, micro.Ctx, configuration.GetAWSSQSClient)
+		if errClient != nil {
+			return nil, errClient
+		}
+		return adapter.NewBookEventPublisherSQS(micro.Ctx, sqsClient, target), nil
+	}
+	return nil, appError.NewUnexpectedError("Unknown event publisher: " + publisher)
+}
+
+// bookHistoryService reads the revisions the tracked DynamoDB repository
+// records. The other stores record none, so they have no history to serve.
+func (clients *bookClients) bookHistoryService(micro *MicroAWSBookDynamoDB) (service.BookHistoryService, *appError.Error) {
+	if configuration.GetBookStore() != configuration.BookStoreDynamoDB {
+		return nil, appError.NewError(http.StatusNotImplemented, "Book history is only available for the DynamoDB book store.")
+	}
+	dynamoClient, errClient := clients.dynamoDBClient(micro.Ctx)
+	if errClient != nil {
+		return nil, errClient
+	}
+	if micro.TableName == "" {
+		micro.TableName = configuration.GetDynamoDBBookTable()
+	}
+	if micro.HistoryTableName == "" {
+		micro.HistoryTableName = configuration.GetDynamoDBBookHistoryTable()
+	}
+	bookInfrastructure := adapter.NewBookDynamoDBRepositoryWithOutbox(micro.Ctx, dynamoClient, micro.TableName, micro.OutboxTableName).
+		WithHistory(micro.HistoryTableName, micro.Actor)
+	historyInfrastructure := adapter.NewBookHistoryDynamoDBRepository(micro.Ctx, dynamoClient, micro.HistoryTableName)
+
+	return service.NewBookHistoryServiceDynamoDB(bookInfrastructure, historyInfrastructure), nil
+}
@@ -0,0 +1,173 @@
+package importer
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"path/filepath"
+	"strings"
+
+	"main/src/books/application/service"
+	"main/src/books/domain/model"
+	appError "main/utils/error"
+)
+
+// Objects dropped under ImportPrefix are imported and their report is written
+// under ReportPrefix with the same relative name.
+const (
+	ImportPrefix = "imports/"
+	ReportPrefix = "import-reports/"
+)
+
+// batchSize bounds how many rows are handed to CreateBatchBooks at once.
+const batchSize = 500
+
+type Format string
+
+const (
+	FormatCSV    Format = "csv"
+	FormatJSON   Format = "json"
+	FormatNDJSON Format = "ndjson"
+)
+
+// FormatFromName picks the format from the file extension.
+func FormatFromName(name string) (Format, *appError.Error) {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".csv":
+		return FormatCSV, nil
+	case ".json":
+		return FormatJSON, nil
+	case ".ndjson", ".jsonl":
+		return FormatNDJSON, nil
+	}
+	return "", appError.NewBadRequestError("Unsupported import file " + name + ", expected .csv, .json or .ndjson.")
+}
+
+// MappingKey is where the CSV header mapping for an import object is looked up.
+func MappingKey(key string) string {
+	return strings.TrimSuffix(key, filepath.Ext(key)) + ".mapping.json"
+}
+
+// ReportKey is where the report for an import object is written.
+func ReportKey(key string) string {
+	name := strings.TrimPrefix(key, ImportPrefix)
+	return ReportPrefix + strings.TrimSuffix(name, filepath.Ext(name)) + ".report.json"
+}
+
+// RowError explains why a row was not imported. Row is 1-based; for CSV it
+// is the line in the file, header included.
+type RowError struct {
+	Row    int      `json:"row"`
+	ID     string   `json:"ID,omitempty"`
+	Errors []string `json:"errors"`
+}
+
+type Report struct {
+	Source     string     `json:"source"`
+	Rows       int        `json:"rows"`
+	Created    int        `json:"created"`
+	Duplicates int        `json:"duplicates"`
+	Invalid    int        `json:"invalid"`
+	Failed     int        `json:"failed"`
+	Errors     []RowError `json:"errors"`
+}
+
+func (r *Report) reject(row int, id string, messages ...string) {
+	r.Errors = append(r.Errors, RowError{Row: row, ID: id, Errors: messages})
+}
+
+// row is one parsed record, or the reason it could not be parsed.
+type row struct {
+	number int
+	book   model.Book
+	err    string
+}
+
+type Importer struct {
+	books service.BookService
+}
+
+func NewImporter(books service.BookService) *Importer {
+	return &Importer{
+		books: books,
+	}
+}
+
+// Import parses content, skips rows that repeat an earlier ID or ISBN and
+// creates the rest through CreateBatchBooks, which validates every book and
+// leaves the books already in the catalog as they are.
+// Only errors that stop the whole file are returned; row problems go to the
+// report.
+func (im *Importer) Import(source string, content io.Reader, format Format, mapping Mapping) (*Report, *appError.Error) {
+	rows, err := parse(content, format, mapping)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &Report{Source: source, Rows: len(rows), Errors: []RowError{}}
+	pending := make([]row, 0, len(rows))
+	seenIDs := make(map[string]int)
+	seenISBNs := make(map[string]int)
+	for _, r := range rows {
+		if r.err != "" {
+			report.Invalid++
+			report.reject(r.number, r.book.ID, r.err)
+			continue
+		}
+		isbn := model.NormalizeISBN(r.book.ISBN)
+		if first, ok := seenIDs[r.book.ID]; ok && r.book.ID != "" {
+			report.Duplicates++
+			report.reject(r.number, r.book.ID, fmt.Sprintf("Duplicate ID of row %d.", first))
+			continue
+		}
+		if first, ok := seenISBNs[isbn]; ok && isbn != "" {
+			report.Duplicates++
+			report.reject(r.number, r.book.ID, fmt.Sprintf("Duplicate ISBN of row %d.", first))
+			continue
+		}
+		if r.book.ID != "" {
+			seenIDs[r.book.ID] = r.number
+		}
+		if isbn != "" {
+			seenISBNs[isbn] = r.number
+		}
+		pending = append(pending, r)
+	}
+
+	for start := 0; start < len(pending); start += batchSize {
+		end := start + batchSize
+		if end > len(pending) {
+			end = len(pending)
+		}
+		chunk := pending[start:end]
+		books := make([]model.Book, len(chunk))
+		for i, r := range chunk {
+			books[i] = r.book
+		}
+
+		batch, err := im.books.CreateBatchBooks(books)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range batch.Items {
+			number := chunk[item.Index].number
+			switch item.Status {
+			case model.BatchItemCreated:
+				report.Created++
+			case model.BatchItemInvalid:
+				report.Invalid++
+				report.reject(number, item.ID, item.Errors...)
+			case model.BatchItemConflict:
+				report.Duplicates++
+				report.reject(number, item.ID, "ID already in the catalog.")
+			default:
+				report.Failed++
+				report.reject(number, item.ID, item.Errors...)
+			}
+		}
+	}
+
+	log.Printf("Import of %s completed, rows: %d, created: %d, duplicates: %d, invalid: %d, failed: %d",
+		source, report.Rows, report.Created, report.Duplicates, report.Invalid, report.Failed)
+	return report, nil
+}
@@ -0,0 +1,137 @@
+package importer_test
+
+import (
+	"strings"
+	"testing"
+
+	"main/src/books/application/importer"
+	"main/src/books/domain/model"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	repoMock "main/mocks"
+)
+
+type ImporterSuite struct {
+	suite.Suite
+	bookService *repoMock.BookService
+	importer    *importer.Importer
+}
+
+const MethodCreateBatchBooks = "CreateBatchBooks"
+
+func (suite *ImporterSuite) SetupTest() {
+	suite.bookService = new(repoMock.BookService)
+	suite.importer = importer.NewImporter(suite.bookService)
+}
+
+// createdReport marks every book handed to CreateBatchBooks as created.
+func createdReport(books []model.Book) *model.BatchReport {
+	report := &model.BatchReport{}
+	for i, book := range books {
+		report.Add(model.BatchItemResult{Index: i, ID: book.ID, Status: model.BatchItemCreated})
+	}
+	return report
+}
+
+func (suite *ImporterSuite) TestImportCSVWithMapping() {
+	mapping, err := importer.ParseMapping([]byte(`{"Title": "name", "Summary": "description", "Cover": "img_url", "ISBN-13": "isbn"}`))
+	suite.Nil(err)
+	content := "Title,Summary,Cover,ISBN-13,Ignored\n" +
+		"The Great Gatsby,A classic novel.,https://example.com/g.jpg,978-0-7432-7356-5,x\n" +
+		"Gatsby Again,Same book.,https://example.com/g2.jpg,9780743273565,y\n" +
+		"Moby Dick,A whale.,https://example.com/m.jpg,,z\n"
+
+	var sent []model.Book
+	suite.bookService.On(MethodCreateBatchBooks, mock.AnythingOfType("[]model.Book")).
+		Run(func(args mock.Arguments) { sent = args.Get(0).([]model.Book) }).
+		Return(func(books []model.Book) *model.BatchReport { return createdReport(books) }, nil)
+
+	report, err := suite.importer.Import("catalog.csv", strings.NewReader(content), importer.FormatCSV, mapping)
+	suite.Nil(err)
+	suite.Equal(3, report.Rows)
+	suite.Equal(2, report.Created)
+	suite.Equal(1, report.Duplicates)
+	suite.Equal(3, report.Errors[0].Row)
+	suite.Len(sent, 2)
+	suite.Equal("The Great Gatsby", sent[0].Name)
+	suite.Equal("A classic novel.", sent[0].Description)
+	suite.Equal("978-0-7432-7356-5", sent[0].ISBN)
+}
+
+func (suite *ImporterSuite) TestImportNDJSONReportsRowErrors() {
+	content := `{"name": "Good", "img_url": "https://example.com/a.jpg"}
+
+not json
+{"name": "", "img_url": "ftp://example.com/b.jpg"}
+`
+	suite.bookService.On(MethodCreateBatchBooks, mock.AnythingOfType("[]model.Book")).Return(&model.BatchReport{
+		Items: []model.BatchItemResult{
+			{Index: 0, Status: model.BatchItemCreated},
+			{Index: 1, Status: model.BatchItemInvalid, Errors: []string{"Name cannot be empty."}},
+		},
+	}, nil)
+
+	report, err := suite.importer.Import("catalog.ndjson", strings.NewReader(content), importer.FormatNDJSON, nil)
+	suite.Nil(err)
+	suite.Equal(3, report.Rows)
+	suite.Equal(1, report.Created)
+	suite.Equal(2, report.Invalid)
+	suite.Equal(3, report.Errors[0].Row)
+	suite.Equal(4, report.Errors[1].Row)
+}
+
+func (suite *ImporterSuite) TestImportLeavesStoredBooksAlone() {
+	content := `{"ID": "123e4567-e89b-12d3-a456-426614174000", "name": "Stored", "img_url": "https://example.com/s.jpg"}
+{"name": "New", "img_url": "https://example.com/n.jpg"}
+`
+	suite.bookService.On(MethodCreateBatchBooks, mock.AnythingOfType("[]model.Book")).Return(&model.BatchReport{
+		Items: []model.BatchItemResult{
+			{Index: 0, ID: "123e4567-e89b-12d3-a456-426614174000", Status: model.BatchItemConflict},
+			{Index: 1, Status: model.BatchItemCreated},
+		},
+	}, nil)
+
+	report, err := suite.importer.Import("catalog.ndjson", strings.NewReader(content), importer.FormatNDJSON, nil)
+	suite.Nil(err)
+	suite.Equal(1, report.Created)
+	suite.Equal(1, report.Duplicates)
+	suite.Zero(report.Failed)
+	suite.Equal(1, report.Errors[0].Row)
+	suite.Equal([]string{"ID already in the catalog."}, report.Errors[0].Errors)
+}
+
+func (suite *ImporterSuite) TestImportJSONArray() {
+	content := `[{"ID": "123e4567-e89b-12d3-a456-426614174000", "name": "One", "img_url": "https://example.com/1.jpg"},
+		{"ID": "123e4567-e89b-12d3-a456-426614174000", "name": "Copy", "img_url": "https://example.com/2.jpg"}]`
+	suite.bookService.On(MethodCreateBatchBooks, mock.AnythingOfType("[]model.Book")).
+		Return(func(books []model.Book) *model.BatchReport { return createdReport(books) }, nil)
+
+	report, err := suite.importer.Import("catalog.json", strings.NewReader(content), importer.FormatJSON, nil)
+	suite.Nil(err)
+	suite.Equal(1, report.Created)
+	suite.Equal(1, report.Duplicates)
+	suite.Equal(2, report.Errors[0].Row)
+
+	_, err = suite.importer.Import("catalog.json", strings.NewReader(`{"name": "not an array"}`), importer.FormatJSON, nil)
+	suite.NotNil(err)
+}
+
+func (suite *ImporterSuite) TestFormatAndKeys() {
+	format, err := importer.FormatFromName("imports/catalog.NDJSON")
+	suite.Nil(err)
+	suite.Equal(importer.FormatNDJSON, format)
+	_, err = importer.FormatFromName("imports/catalog.xlsx")
+	suite.NotNil(err)
+
+	suite.Equal("imports/catalog.mapping.json", importer.MappingKey("imports/catalog.csv"))
+	suite.Equal("import-reports/2024/catalog.report.json", importer.ReportKey("imports/2024/catalog.csv"))
+
+	_, err = importer.ParseMapping([]byte(`{"Title": "title"}`))
+	suite.NotNil(err)
+}
+
+func TestImporterSuite(t *testing.T) {
+	suite.Run(t, new(ImporterSuite))
+}
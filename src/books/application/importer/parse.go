@@ -0,0 +1,150 @@
+package importer
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+
+	appError "main/utils/error"
+
+	"github.com/mitchellh/mapstructure"
+)
+
+// bookFields are the book attributes a CSV column can be mapped onto, named
+// as in the JSON representation.
+var bookFields = map[string]bool{
+	"ID":          true,
+	"name":        true,
+	"description": true,
+	"img_url":     true,
+	"isbn":        true,
+}
+
+// Mapping maps CSV header names to book fields. Without a mapping the headers
+// must already be book field names; unmapped columns are ignored.
+type Mapping map[string]string
+
+func ParseMapping(content []byte) (Mapping, *appError.Error) {
+	var mapping Mapping
+	if err := json.Unmarshal(content, &mapping); err != nil {
+		log.Printf("Error parsing import mapping: %v", err)
+		return nil, appError.NewBadRequestError("Import mapping must be a JSON object of header to field.")
+	}
+	for header, field := range mapping {
+		if !bookFields[field] {
+			return nil, appError.NewBadRequestError("Header " + header + " is mapped to unknown field " + field + ".")
+		}
+	}
+	return mapping, nil
+}
+
+func parse(content io.Reader, format Format, mapping Mapping) ([]row, *appError.Error) {
+	switch format {
+	case FormatCSV:
+		return parseCSV(content, mapping)
+	case FormatJSON:
+		return parseJSON(content)
+	case FormatNDJSON:
+		return parseNDJSON(content)
+	}
+	return nil, appError.NewBadRequestError("Unsupported import format " + string(format) + ".")
+}
+
+func parseCSV(content io.Reader, mapping Mapping) ([]row, *appError.Error) {
+	reader := csv.NewReader(content)
+	reader.TrimLeadingSpace = true
+	header, err := reader.Read()
+	if err != nil {
+		log.Printf("Error reading CSV header: %v", err)
+		return nil, appError.NewBadRequestError("CSV file must start with a header row.")
+	}
+
+	fields := make([]string, len(header))
+	for i, column := range header {
+		column = strings.TrimSpace(strings.TrimPrefix(column, "\uFEFF"))
+		if mapping != nil {
+			fields[i] = mapping[column]
+		} else if bookFields[column] {
+			fields[i] = column
+		}
+	}
+
+	var rows []row
+	for number := 2; ; number++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		r := row{number: number}
+		if err != nil {
+			r.err = err.Error()
+			rows = append(rows, r)
+			continue
+		}
+		values := make(map[string]interface{}, len(fields))
+		for i, field := range fields {
+			if field != "" && i < len(record) {
+				values[field] = strings.TrimSpace(record[i])
+			}
+		}
+		if err := mapstructure.Decode(values, &r.book); err != nil {
+			r.err = err.Error()
+		}
+		rows = append(rows, r)
+	}
+	return rows, nil
+}
+
+func parseJSON(content io.Reader) ([]row, *appError.Error) {
+	decoder := json.NewDecoder(content)
+	if token, err := decoder.Token(); err != nil || token != json.Delim('[') {
+		return nil, appError.NewBadRequestError("JSON import file must contain an array of books.")
+	}
+
+	var rows []row
+	for number := 1; decoder.More(); number++ {
+		var raw json.RawMessage
+		if err := decoder.Decode(&raw); err != nil {
+			log.Printf("Error decoding JSON import file: %v", err)
+			return nil, appError.NewBadRequestError(fmt.Sprintf("Malformed JSON at element %d.", number))
+		}
+		rows = append(rows, decodeRow(number, raw))
+	}
+	return rows, nil
+}
+
+func parseNDJSON(content io.Reader) ([]row, *appError.Error) {
+	scanner := bufio.NewScanner(content)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	var rows []row
+	for number := 1; scanner.Scan(); number++ {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		rows = append(rows, decodeRow(number, line))
+	}
+	if err := scanner.Err(); err != nil {
+		log.Printf("Error reading NDJSON import file: %v", err)
+		if errors.Is(err, bufio.ErrTooLong) {
+			return nil, appError.NewBadRequestError("NDJSON line exceeds 1 MiB.")
+		}
+		return nil, appError.NewUnexpectedError(err.Error())
+	}
+	return rows, nil
+}
+
+func decodeRow(number int, raw []byte) row {
+	r := row{number: number}
+	if err := json.Unmarshal(raw, &r.book); err != nil {
+		r.err = "Malformed book: " + err.Error()
+	}
+	return r
+}
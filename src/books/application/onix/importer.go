@@ -0,0 +1,231 @@
+package onix
+
+import (
+	"io"
+	"log"
+
+	"main/src/books/application/service"
+	"main/src/books/domain/model"
+	appError "main/utils/error"
+)
+
+type Outcome string
+
+const (
+	OutcomeCreated Outcome = "created"
+	OutcomeUpdated Outcome = "updated"
+	OutcomeDeleted Outcome = "deleted"
+	OutcomeSkipped Outcome = "skipped"
+	OutcomeInvalid Outcome = "invalid"
+	OutcomeFailed  Outcome = "failed"
+)
+
+// Entry records what happened to one Product of the message.
+type Entry struct {
+	RecordReference  string   `json:"record_reference"`
+	ID               string   `json:"ID,omitempty"`
+	NotificationType string   `json:"notification_type"`
+	Outcome          Outcome  `json:"outcome"`
+	Messages         []string `json:"messages,omitempty"`
+}
+
+// Log is the detailed result of an ONIX import, one entry per Product in
+// message order.
+type Log struct {
+	Source   string  `json:"source"`
+	Sender   string  `json:"sender,omitempty"`
+	SentAt   string  `json:"sent_at,omitempty"`
+	Products int     `json:"products"`
+	Created  int     `json:"created"`
+	Updated  int     `json:"updated"`
+	Deleted  int     `json:"deleted"`
+	Skipped  int     `json:"skipped"`
+	Invalid  int     `json:"invalid"`
+	Failed   int     `json:"failed"`
+	Entries  []Entry `json:"entries"`
+}
+
+func (l *Log) count(outcome Outcome) {
+	switch outcome {
+	case OutcomeCreated:
+		l.Created++
+	case OutcomeUpdated:
+		l.Updated++
+	case OutcomeDeleted:
+		l.Deleted++
+	case OutcomeSkipped:
+		l.Skipped++
+	case OutcomeInvalid:
+		l.Invalid++
+	case OutcomeFailed:
+		l.Failed++
+	}
+}
+
+type Importer struct {
+	books service.BookService
+}
+
+func NewImporter(books service.BookService) *Importer {
+	return &Importer{
+		books: books,
+	}
+}
+
+// Import applies the notifications of an ONIX message. Each record reference
+// maps to a fixed book ID, so replaying a message converges to the same
+// catalog: replacements and block updates overwrite, and deleting a record
+// that is already gone is skipped. When a message repeats a record, only its
+// last notification is applied.
+func (im *Importer) Import(source string, content io.Reader) (*Log, *appError.Error) {
+	message, err := Parse(content)
+	if err != nil {
+		return nil, err
+	}
+
+	importLog := &Log{
+		Source:   source,
+		Sender:   message.Header.SenderName,
+		SentAt:   message.Header.SentDateTime,
+		Products: len(message.Products),
+		Entries:  make([]Entry, len(message.Products)),
+	}
+	latest := make(map[string]int, len(message.Products))
+	var ids []string
+	for i, product := range message.Products {
+		entry := &importLog.Entries[i]
+		entry.RecordReference = product.RecordReference
+		entry.NotificationType = product.NotificationType
+		if product.RecordReference == "" {
+			entry.Outcome = OutcomeInvalid
+			entry.Messages = []string{"RecordReference is missing."}
+			continue
+		}
+		if notificationAction(product.NotificationType) == actionIgnore {
+			entry.Outcome = OutcomeSkipped
+			entry.Messages = []string{"Notification type " + product.NotificationType + " is not imported."}
+			continue
+		}
+		entry.ID = BookID(product.RecordReference)
+		if previous, ok := latest[entry.ID]; ok {
+			importLog.Entries[previous].Outcome = OutcomeSkipped
+			importLog.Entries[previous].Messages = []string{"Superseded by a later notification for the same record."}
+		} else {
+			ids = append(ids, entry.ID)
+		}
+		latest[entry.ID] = i
+	}
+
+	existing, err := im.existingBooks(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	var creates, updates []model.Book
+	var deletes []string
+	for i := range message.Products {
+		product := &message.Products[i]
+		entry := &importLog.Entries[i]
+		if entry.ID == "" || latest[entry.ID] != i {
+			continue
+		}
+		current, exists := existing[entry.ID]
+		switch notificationAction(product.NotificationType) {
+		case actionDelete:
+			if !exists {
+				entry.Outcome = OutcomeSkipped
+				entry.Messages = []string{"Record is already absent."}
+				continue
+			}
+			deletes = append(deletes, entry.ID)
+		case actionBlockUpdate:
+			if !exists {
+				entry.Outcome = OutcomeInvalid
+				entry.Messages = []string{"Block update for a record that was never imported."}
+				continue
+			}
+			book := current
+			entry.Messages = applyProduct(&book, product)
+			updates = append(updates, book)
+		case actionReplace:
+			book := model.Book{ID: entry.ID, RecordReference: product.RecordReference}
+			entry.Messages = applyProduct(&book, product)
+			if exists && book.ImgURL == "" {
+				book.ImgURL = current.ImgURL
+				entry.Messages = append(entry.Messages, "No cover image in record, kept the current one.")
+			}
+			if exists {
+				updates = append(updates, book)
+			} else {
+				creates = append(creates, book)
+			}
+		}
+	}
+
+	if len(creates) > 0 {
+		report, err := im.books.CreateBatchBooks(creates)
+		if err != nil {
+			return nil, err
+		}
+		im.record(importLog, latest, report)
+	}
+	if len(updates) > 0 {
+		report, err := im.books.UpdateBooks(updates)
+		if err != nil {
+			return nil, err
+		}
+		im.record(importLog, latest, report)
+	}
+	if len(deletes) > 0 {
+		report, err := im.books.DeleteBooksByIDs(deletes)
+		if err != nil {
+			return nil, err
+		}
+		im.record(importLog, latest, report)
+	}
+
+	for _, entry := range importLog.Entries {
+		importLog.count(entry.Outcome)
+	}
+	log.Printf("ONIX import of %s completed, products: %d, created: %d, updated: %d, deleted: %d, skipped: %d, invalid: %d, failed: %d",
+		source, importLog.Products, importLog.Created, importLog.Updated, importLog.Deleted, importLog.Skipped, importLog.Invalid, importLog.Failed)
+	return importLog, nil
+}
+
+// record copies the batch outcomes onto the log entries of their records.
+func (im *Importer) record(importLog *Log, latest map[string]int, report *model.BatchReport) {
+	for _, item := range report.Items {
+		entry := &importLog.Entries[latest[item.ID]]
+		switch item.Status {
+		case model.BatchItemCreated:
+			entry.Outcome = OutcomeCreated
+		case model.BatchItemUpdated:
+			entry.Outcome = OutcomeUpdated
+		case model.BatchItemDeleted:
+			entry.Outcome = OutcomeDeleted
+		case model.BatchItemInvalid:
+			entry.Outcome = OutcomeInvalid
+		default:
+			entry.Outcome = OutcomeFailed
+		}
+		entry.Messages = append(entry.Messages, item.Errors...)
+	}
+}
+
+func (im *Importer) existingBooks(ids []string) (map[string]model.Book, *appError.Error) {
+	existing := make(map[string]model.Book, len(ids))
+	for start := 0; start < len(ids); start += service.MaxBatchGetIDs {
+		end := start + service.MaxBatchGetIDs
+		if end > len(ids) {
+			end = len(ids)
+		}
+		found, err := im.books.GetBooksByIDs(ids[start:end])
+		if err != nil {
+			return nil, err
+		}
+		for _, book := range found.Books {
+			existing[book.ID] = book
+		}
+	}
+	return existing, nil
+}
@@ -0,0 +1,286 @@
+package onix
+
+import (
+	"html"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"main/src/books/domain/model"
+
+	"github.com/google/uuid"
+)
+
+// recordNamespace derives book IDs from ONIX record references, so the same
+// record always maps to the same book no matter how often it is sent.
+var recordNamespace = uuid.MustParse("6f1c8a4e-3d2b-5e7a-9c10-4b8e2f6d1a35")
+
+// BookID is the ID of the book imported from the given record reference.
+func BookID(recordReference string) string {
+	return uuid.NewSHA1(recordNamespace, []byte(recordReference)).String()
+}
+
+type action string
+
+const (
+	actionReplace     action = "replace"
+	actionBlockUpdate action = "block_update"
+	actionDelete      action = "delete"
+	actionIgnore      action = "ignore"
+)
+
+// notificationAction maps an ONIX notification type (list 1) to what the
+// import does with the record.
+func notificationAction(notificationType string) action {
+	switch notificationType {
+	case "01", "02", "03", "08":
+		return actionReplace
+	case "04", "09":
+		return actionBlockUpdate
+	case "05":
+		return actionDelete
+	}
+	return actionIgnore
+}
+
+// maxDescription matches the limit enforced by Book.Validate.
+const maxDescription = 200
+
+var contributorRoles = map[string]string{
+	"A01": "author",
+	"A12": "illustrator",
+	"A13": "photographer",
+	"A15": "preface",
+	"A23": "foreword",
+	"B01": "editor",
+	"B06": "translator",
+	"E07": "reader",
+}
+
+var subjectSchemes = map[string]string{
+	"10": "BISAC",
+	"12": "BIC",
+	"20": "keywords",
+	"93": "Thema",
+}
+
+// applyProduct copies the blocks present in product onto book. Blocks that
+// are absent leave the matching fields untouched, which is what a block
+// update needs; a full replacement starts from an empty book instead. It
+// returns warnings about data that had to be adjusted.
+func applyProduct(book *model.Book, product *Product) []string {
+	var warnings []string
+	if isbn := productISBN(product.ProductIdentifiers); isbn != "" {
+		book.ISBN = isbn
+	}
+	if detail := product.DescriptiveDetail; detail != nil {
+		book.Name, book.Subtitle = title(detail.TitleDetails)
+		book.Contributors = contributors(detail.Contributors)
+		book.Subjects = subjects(detail.Subjects)
+	}
+	if detail := product.CollateralDetail; detail != nil {
+		description, truncated := description(detail.TextContents)
+		book.Description = description
+		if truncated {
+			warnings = append(warnings, "Description truncated to 200 characters.")
+		}
+		if cover := coverURL(detail.SupportingResources); cover != "" {
+			book.ImgURL = cover
+		}
+	}
+	if detail := product.PublishingDetail; detail != nil {
+		book.Publisher = publisher(detail.Publishers)
+		book.PublishedOn = publicationDate(detail.PublishingDates)
+	}
+	if supply := product.ProductSupply; supply != nil {
+		book.Availability, book.Prices = supplyDetails(supply.SupplyDetails)
+	}
+	return warnings
+}
+
+// productISBN prefers the ISBN-13, then a GTIN-13 in the book range, then
+// the ISBN-10.
+func productISBN(identifiers []ProductIdentifier) string {
+	byType := make(map[string]string, len(identifiers))
+	for _, identifier := range identifiers {
+		byType[identifier.ProductIDType] = strings.TrimSpace(identifier.IDValue)
+	}
+	if isbn := byType["15"]; isbn != "" {
+		return isbn
+	}
+	if gtin := byType["03"]; strings.HasPrefix(gtin, "978") || strings.HasPrefix(gtin, "979") {
+		return gtin
+	}
+	return byType["02"]
+}
+
+// title returns the distinctive title (type 01) at product level.
+func title(details []TitleDetail) (string, string) {
+	for _, detail := range details {
+		if detail.TitleType != "01" {
+			continue
+		}
+		for _, element := range detail.TitleElements {
+			if element.TitleElementLevel != "" && element.TitleElementLevel != "01" {
+				continue
+			}
+			text := element.TitleText
+			if text == "" {
+				text = strings.TrimSpace(element.TitlePrefix + " " + element.TitleWithoutPrefix)
+			}
+			return collapse(text), collapse(element.Subtitle)
+		}
+	}
+	return "", ""
+}
+
+func contributors(records []ContributorRecord) []model.Contributor {
+	sorted := append([]ContributorRecord(nil), records...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].SequenceNumber < sorted[j].SequenceNumber })
+
+	var result []model.Contributor
+	for _, record := range sorted {
+		name := record.PersonName
+		if name == "" {
+			name = strings.TrimSpace(record.NamesBeforeKey + " " + record.KeyNames)
+		}
+		if name == "" {
+			name = record.CorporateName
+		}
+		if name = collapse(name); name == "" {
+			continue
+		}
+		role := ""
+		if len(record.ContributorRole) > 0 {
+			role = record.ContributorRole[0]
+			if known, ok := contributorRoles[role]; ok {
+				role = known
+			}
+		}
+		result = append(result, model.Contributor{Name: name, Role: role})
+	}
+	return result
+}
+
+func subjects(records []SubjectRecord) []model.Subject {
+	var result []model.Subject
+	for _, record := range records {
+		scheme := record.SubjectSchemeIdentifier
+		if known, ok := subjectSchemes[scheme]; ok {
+			scheme = known
+		}
+		result = append(result, model.Subject{
+			Scheme:  scheme,
+			Code:    strings.TrimSpace(record.SubjectCode),
+			Heading: collapse(record.SubjectHeadingText),
+			Main:    record.MainSubject != nil,
+		})
+	}
+	return result
+}
+
+var markup = regexp.MustCompile(`<[^>]*>`)
+
+// description prefers the short description (text type 02) over the main
+// one (03), strips any markup and cuts it at a word to fit the book limit.
+func description(contents []TextContent) (string, bool) {
+	var text string
+	for _, textType := range []string{"02", "03"} {
+		for _, content := range contents {
+			if content.TextType == textType && text == "" {
+				text = content.Text.Body
+			}
+		}
+	}
+	text = strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(text, "<![CDATA["), "]]>"))
+	// Escaped HTML (text format 02) is unescaped before the markup is removed.
+	text = html.UnescapeString(text)
+	text = collapse(html.UnescapeString(markup.ReplaceAllString(text, " ")))
+	if len(text) <= maxDescription {
+		return text, false
+	}
+
+	cut := maxDescription - len("...")
+	for cut > 0 && !utf8.RuneStart(text[cut]) {
+		cut--
+	}
+	if space := strings.LastIndex(text[:cut], " "); space > 0 {
+		cut = space
+	}
+	return strings.TrimSpace(text[:cut]) + "...", true
+}
+
+// coverURL returns the downloadable front cover image (content type 01,
+// mode 03, form 02).
+func coverURL(resources []SupportingResource) string {
+	for _, resource := range resources {
+		if resource.ResourceContentType != "01" || resource.ResourceMode != "03" {
+			continue
+		}
+		for _, version := range resource.ResourceVersions {
+			if version.ResourceForm == "02" && version.ResourceLink != "" {
+				return strings.TrimSpace(version.ResourceLink)
+			}
+		}
+	}
+	return ""
+}
+
+func publisher(records []PublisherRecord) string {
+	for _, record := range records {
+		if record.PublishingRole == "01" {
+			return collapse(record.PublisherName)
+		}
+	}
+	return ""
+}
+
+// publicationDate renders the publication date (role 01) as an ISO date with
+// the precision the publisher gave.
+func publicationDate(dates []PublishingDate) string {
+	for _, date := range dates {
+		if date.PublishingDateRole != "01" {
+			continue
+		}
+		value := strings.TrimSpace(date.Date.Value)
+		switch {
+		case (date.Date.Format == "" || date.Date.Format == "00") && len(value) == 8:
+			return value[:4] + "-" + value[4:6] + "-" + value[6:]
+		case date.Date.Format == "01" && len(value) == 6:
+			return value[:4] + "-" + value[4:]
+		}
+		return value
+	}
+	return ""
+}
+
+func supplyDetails(details []SupplyDetail) (string, []model.Price) {
+	availability := ""
+	var prices []model.Price
+	for _, detail := range details {
+		if availability == "" {
+			availability = detail.ProductAvailability
+		}
+		for _, price := range detail.Prices {
+			if price.PriceAmount == "" {
+				continue
+			}
+			territory := price.CountriesIncluded
+			if territory == "" {
+				territory = price.RegionsIncluded
+			}
+			prices = append(prices, model.Price{
+				Type:      price.PriceType,
+				Amount:    strings.TrimSpace(price.PriceAmount),
+				Currency:  price.CurrencyCode,
+				Territory: territory,
+			})
+		}
+	}
+	return availability, prices
+}
+
+func collapse(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
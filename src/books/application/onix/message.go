@@ -0,0 +1,177 @@
+package onix
+
+import (
+	"encoding/xml"
+	"io"
+	"log"
+
+	appError "main/utils/error"
+)
+
+// The structs below cover the parts of an ONIX 3.0 message the catalog uses,
+// with reference tag names. Elements are matched without their namespace.
+
+type Header struct {
+	SenderName   string `xml:"Sender>SenderName"`
+	SentDateTime string `xml:"SentDateTime"`
+}
+
+type Product struct {
+	RecordReference    string              `xml:"RecordReference"`
+	NotificationType   string              `xml:"NotificationType"`
+	ProductIdentifiers []ProductIdentifier `xml:"ProductIdentifier"`
+	DescriptiveDetail  *DescriptiveDetail  `xml:"DescriptiveDetail"`
+	CollateralDetail   *CollateralDetail   `xml:"CollateralDetail"`
+	PublishingDetail   *PublishingDetail   `xml:"PublishingDetail"`
+	ProductSupply      *ProductSupply      `xml:"ProductSupply"`
+}
+
+type ProductIdentifier struct {
+	ProductIDType string `xml:"ProductIDType"`
+	IDValue       string `xml:"IDValue"`
+}
+
+type DescriptiveDetail struct {
+	TitleDetails []TitleDetail       `xml:"TitleDetail"`
+	Contributors []ContributorRecord `xml:"Contributor"`
+	Subjects     []SubjectRecord     `xml:"Subject"`
+}
+
+type TitleDetail struct {
+	TitleType     string         `xml:"TitleType"`
+	TitleElements []TitleElement `xml:"TitleElement"`
+}
+
+type TitleElement struct {
+	TitleElementLevel  string `xml:"TitleElementLevel"`
+	TitleText          string `xml:"TitleText"`
+	TitlePrefix        string `xml:"TitlePrefix"`
+	TitleWithoutPrefix string `xml:"TitleWithoutPrefix"`
+	Subtitle           string `xml:"Subtitle"`
+}
+
+type ContributorRecord struct {
+	SequenceNumber  int      `xml:"SequenceNumber"`
+	ContributorRole []string `xml:"ContributorRole"`
+	PersonName      string   `xml:"PersonName"`
+	NamesBeforeKey  string   `xml:"NamesBeforeKey"`
+	KeyNames        string   `xml:"KeyNames"`
+	CorporateName   string   `xml:"CorporateName"`
+}
+
+type SubjectRecord struct {
+	MainSubject             *struct{} `xml:"MainSubject"`
+	SubjectSchemeIdentifier string    `xml:"SubjectSchemeIdentifier"`
+	SubjectCode             string    `xml:"SubjectCode"`
+	SubjectHeadingText      string    `xml:"SubjectHeadingText"`
+}
+
+type CollateralDetail struct {
+	TextContents        []TextContent        `xml:"TextContent"`
+	SupportingResources []SupportingResource `xml:"SupportingResource"`
+}
+
+type TextContent struct {
+	TextType string `xml:"TextType"`
+	Text     Text   `xml:"Text"`
+}
+
+// Text keeps the raw markup, since ONIX descriptions are often XHTML.
+type Text struct {
+	Format string `xml:"textformat,attr"`
+	Body   string `xml:",innerxml"`
+}
+
+type SupportingResource struct {
+	ResourceContentType string            `xml:"ResourceContentType"`
+	ResourceMode        string            `xml:"ResourceMode"`
+	ResourceVersions    []ResourceVersion `xml:"ResourceVersion"`
+}
+
+type ResourceVersion struct {
+	ResourceForm string `xml:"ResourceForm"`
+	ResourceLink string `xml:"ResourceLink"`
+}
+
+type PublishingDetail struct {
+	Publishers      []PublisherRecord `xml:"Publisher"`
+	PublishingDates []PublishingDate  `xml:"PublishingDate"`
+}
+
+type PublisherRecord struct {
+	PublishingRole string `xml:"PublishingRole"`
+	PublisherName  string `xml:"PublisherName"`
+}
+
+type PublishingDate struct {
+	PublishingDateRole string `xml:"PublishingDateRole"`
+	Date               Date   `xml:"Date"`
+}
+
+type Date struct {
+	Format string `xml:"dateformat,attr"`
+	Value  string `xml:",chardata"`
+}
+
+type ProductSupply struct {
+	SupplyDetails []SupplyDetail `xml:"SupplyDetail"`
+}
+
+type SupplyDetail struct {
+	ProductAvailability string        `xml:"ProductAvailability"`
+	Prices              []PriceRecord `xml:"Price"`
+}
+
+type PriceRecord struct {
+	PriceType         string `xml:"PriceType"`
+	PriceAmount       string `xml:"PriceAmount"`
+	CurrencyCode      string `xml:"CurrencyCode"`
+	CountriesIncluded string `xml:"Territory>CountriesIncluded"`
+	RegionsIncluded   string `xml:"Territory>RegionsIncluded"`
+}
+
+// Message is a parsed ONIX message. Products keep the order of the file.
+type Message struct {
+	Header   Header
+	Products []Product
+}
+
+// Parse reads an ONIX 3.0 message, decoding one Product at a time so large
+// feeds do not need to be held as a single document tree.
+func Parse(r io.Reader) (*Message, *appError.Error) {
+	decoder := xml.NewDecoder(r)
+	message := &Message{}
+	root := false
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			log.Printf("Error reading ONIX message: %v", err)
+			return nil, appError.NewBadRequestError("Malformed ONIX message: " + err.Error())
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		switch start.Name.Local {
+		case "ONIXMessage":
+			root = true
+		case "Header":
+			if err := decoder.DecodeElement(&message.Header, &start); err != nil {
+				return nil, appError.NewBadRequestError("Malformed ONIX header: " + err.Error())
+			}
+		case "Product":
+			var product Product
+			if err := decoder.DecodeElement(&product, &start); err != nil {
+				return nil, appError.NewBadRequestError("Malformed ONIX product: " + err.Error())
+			}
+			message.Products = append(message.Products, product)
+		}
+	}
+	if !root {
+		return nil, appError.NewBadRequestError("Not an ONIX message, ONIXMessage element is missing.")
+	}
+	return message, nil
+}
@@ -0,0 +1,154 @@
+package onix_test
+
+import (
+	"os"
+	"testing"
+
+	"main/src/books/application/onix"
+	"main/src/books/application/service"
+	"main/src/books/domain/model"
+	appError "main/utils/error"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	repoMock "main/mocks"
+)
+
+const fixtures = "../../../../json/onix_feeds/"
+
+type ONIXSuite struct {
+	suite.Suite
+	bookRepository *repoMock.BookRepository
+	importer       *onix.Importer
+}
+
+const (
+	MethodGetBooksByIDs    = "GetBooksByIDs"
+	MethodCreateBatchBooks = "CreateBatchBooks"
+	MethodUpdateBooks      = "UpdateBooks"
+	MethodDeleteBooksByIDs = "DeleteBooksByIDs"
+)
+
+func (suite *ONIXSuite) SetupTest() {
+	suite.bookRepository = new(repoMock.BookRepository)
+	suite.importer = onix.NewImporter(service.NewBookServiceDynamoDB(suite.bookRepository))
+}
+
+func (suite *ONIXSuite) open(name string) *os.File {
+	file, err := os.Open(fixtures + name)
+	suite.Require().NoError(err)
+	suite.T().Cleanup(func() { file.Close() })
+	return file
+}
+
+func (suite *ONIXSuite) TestParse() {
+	message, err := onix.Parse(suite.open("new_titles.xml"))
+	suite.Nil(err)
+	suite.Equal("Riverbank Press", message.Header.SenderName)
+	suite.Len(message.Products, 3)
+	suite.Equal("com.riverbankpress.0001", message.Products[0].RecordReference)
+	suite.Len(message.Products[0].DescriptiveDetail.Contributors, 2)
+}
+
+func (suite *ONIXSuite) TestParseRejectsOtherXML() {
+	_, err := onix.Parse(suite.open("../apigateway_requests/json_request.json"))
+	suite.NotNil(err)
+}
+
+func (suite *ONIXSuite) TestImportNewTitles() {
+	var created []model.Book
+	suite.bookRepository.On(MethodGetBooksByIDs, mock.Anything).Return(&model.BooksByIDs{MissingIDs: []string{}}, nil)
+	suite.bookRepository.On(MethodCreateBatchBooks, mock.Anything).
+		Run(func(args mock.Arguments) { created = args.Get(0).([]model.Book) }).
+		Return(map[string]*appError.Error{})
+
+	importLog, err := suite.importer.Import("new_titles.xml", suite.open("new_titles.xml"))
+	suite.Nil(err)
+	suite.Equal(3, importLog.Products)
+	suite.Equal(2, importLog.Created)
+	suite.Equal(1, importLog.Invalid)
+	suite.Equal(onix.OutcomeInvalid, importLog.Entries[2].Outcome)
+
+	suite.Require().Len(created, 2)
+	gatsby := created[0]
+	suite.Equal(onix.BookID("com.riverbankpress.0001"), gatsby.ID)
+	suite.Equal("com.riverbankpress.0001", gatsby.RecordReference)
+	suite.Equal("The Great Gatsby", gatsby.Name)
+	suite.Equal("A Novel", gatsby.Subtitle)
+	suite.Equal("9780743273565", gatsby.ISBN)
+	suite.Equal([]model.Contributor{{Name: "F. Scott Fitzgerald", Role: "author"}, {Name: "Jane Doe", Role: "foreword"}}, gatsby.Contributors)
+	suite.Equal(model.Subject{Scheme: "BISAC", Code: "FIC004000", Main: true}, gatsby.Subjects[0])
+	suite.Equal("keywords", gatsby.Subjects[1].Scheme)
+	suite.LessOrEqual(len(gatsby.Description), 200)
+	suite.Contains(gatsby.Description, "The story of the mysteriously wealthy Jay Gatsby")
+	suite.Contains(importLog.Entries[0].Messages, "Description truncated to 200 characters.")
+	suite.Equal("https://covers.riverbankpress.com/9780743273565.jpg", gatsby.ImgURL)
+	suite.Equal("Riverbank Press", gatsby.Publisher)
+	suite.Equal("2004-09-30", gatsby.PublishedOn)
+	suite.Equal("21", gatsby.Availability)
+	suite.Equal(model.Price{Type: "01", Amount: "15.00", Currency: "USD", Territory: "US CA"}, gatsby.Prices[0])
+
+	tides := created[1]
+	suite.Equal("Tides of Winter", tides.Name)
+	suite.Equal("080442957X", tides.ISBN)
+	suite.Equal("Short stories from the northern coast.", tides.Description)
+	suite.Equal("The Harbour Collective", tides.Contributors[0].Name)
+	suite.Equal("2024-11", tides.PublishedOn)
+}
+
+func (suite *ONIXSuite) TestImportUpdatesIsIdempotent() {
+	gatsbyID := onix.BookID("com.riverbankpress.0001")
+	tidesID := onix.BookID("com.riverbankpress.0002")
+	stored := []model.Book{
+		{ID: gatsbyID, RecordReference: "com.riverbankpress.0001", Name: "The Great Gatsby", ImgURL: "https://covers.riverbankpress.com/g.jpg", Availability: "21", RatingCount: 2, RatingTotal: 9},
+		{ID: tidesID, RecordReference: "com.riverbankpress.0002", Name: "Tides of Winter", ImgURL: "https://covers.riverbankpress.com/t.jpg"},
+	}
+	suite.bookRepository.On(MethodGetBooksByIDs, mock.Anything).Return(func(ids []string) *model.BooksByIDs {
+		result := &model.BooksByIDs{MissingIDs: []string{}}
+		for _, book := range stored {
+			for _, id := range ids {
+				if id == book.ID {
+					result.Books = append(result.Books, book)
+				}
+			}
+		}
+		return result
+	}, nil)
+	var updated []model.Book
+	suite.bookRepository.On(MethodCreateBatchBooks, mock.Anything).Return(map[string]*appError.Error{})
+	suite.bookRepository.On(MethodUpdateBooks, mock.Anything).
+		Run(func(args mock.Arguments) { updated = args.Get(0).([]model.Book) }).
+		Return(map[string]*appError.Error{})
+	suite.bookRepository.On(MethodDeleteBooksByIDs, []string{tidesID}).Return(map[string]*appError.Error{})
+
+	importLog, err := suite.importer.Import("updates.xml", suite.open("updates.xml"))
+	suite.Nil(err)
+	suite.Equal(8, importLog.Products)
+
+	outcomes := make([]onix.Outcome, len(importLog.Entries))
+	for i, entry := range importLog.Entries {
+		outcomes[i] = entry.Outcome
+	}
+	suite.Equal([]onix.Outcome{
+		onix.OutcomeUpdated, // block update of supply only
+		onix.OutcomeDeleted,
+		onix.OutcomeSkipped, // deleting a record that is already gone
+		onix.OutcomeInvalid, // block update of an unknown record
+		onix.OutcomeSkipped, // superseded by the next notification
+		onix.OutcomeCreated,
+		onix.OutcomeSkipped, // unsupported notification type
+		onix.OutcomeInvalid, // missing record reference
+	}, outcomes)
+
+	suite.Require().Len(updated, 1)
+	suite.Equal("The Great Gatsby", updated[0].Name)
+	suite.Equal("31", updated[0].Availability)
+	suite.Equal("17.50", updated[0].Prices[0].Amount)
+	suite.Equal(2, updated[0].RatingCount)
+	suite.bookRepository.AssertExpectations(suite.T())
+}
+
+func TestONIXSuite(t *testing.T) {
+	suite.Run(t, new(ONIXSuite))
+}
@@ -0,0 +1,201 @@
+package opds
+
+import (
+	"encoding/xml"
+	"log"
+	"time"
+
+	"main/src/books/domain/model"
+	appError "main/utils/error"
+)
+
+const (
+	atomNamespace       = "http://www.w3.org/2005/Atom"
+	dcTermsNamespace    = "http://purl.org/dc/terms/"
+	opdsNamespace       = "http://opds-spec.org/2010/catalog"
+	openSearchNamespace = "http://a9.com/-/spec/opensearch/1.1/"
+)
+
+type atomFeed struct {
+	XMLName    xml.Name `xml:"feed"`
+	Namespace  string   `xml:"xmlns,attr"`
+	DCTerms    string   `xml:"xmlns:dc,attr"`
+	OPDS       string   `xml:"xmlns:opds,attr"`
+	OpenSearch string   `xml:"xmlns:opensearch,attr"`
+
+	ID           string      `xml:"id"`
+	Title        string      `xml:"title"`
+	Updated      string      `xml:"updated"`
+	TotalResults *int        `xml:"opensearch:totalResults"`
+	ItemsPerPage *int        `xml:"opensearch:itemsPerPage"`
+	StartIndex   *int        `xml:"opensearch:startIndex"`
+	Links        []atomLink  `xml:"link"`
+	Entries      []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Rel   string `xml:"rel,attr,omitempty"`
+	Href  string `xml:"href,attr"`
+	Type  string `xml:"type,attr,omitempty"`
+	Title string `xml:"title,attr,omitempty"`
+}
+
+type atomEntry struct {
+	ID         string         `xml:"id"`
+	Title      string         `xml:"title"`
+	Updated    string         `xml:"updated"`
+	Authors    []atomAuthor   `xml:"author"`
+	Identifier string         `xml:"dc:identifier,omitempty"`
+	Publisher  string         `xml:"dc:publisher,omitempty"`
+	Issued     string         `xml:"dc:issued,omitempty"`
+	Categories []atomCategory `xml:"category"`
+	Summary    *atomText      `xml:"summary"`
+	Content    *atomText      `xml:"content"`
+	Links      []atomLink     `xml:"link"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+type atomCategory struct {
+	Scheme string `xml:"scheme,attr,omitempty"`
+	Term   string `xml:"term,attr"`
+	Label  string `xml:"label,attr,omitempty"`
+}
+
+type atomText struct {
+	Type  string `xml:"type,attr"`
+	Value string `xml:",chardata"`
+}
+
+func (c *Catalog) newFeed(id, title string, links ...atomLink) *atomFeed {
+	return &atomFeed{
+		Namespace:  atomNamespace,
+		DCTerms:    dcTermsNamespace,
+		OPDS:       opdsNamespace,
+		OpenSearch: openSearchNamespace,
+		ID:         id,
+		Title:      title,
+		Updated:    c.Updated.UTC().Format(time.RFC3339),
+		Links: append([]atomLink{
+			{Rel: "start", Href: c.href(PathRoot, nil), Type: MediaNavigation},
+			{Rel: "search", Href: c.href(PathOpenSearch, nil), Type: MediaOpenSearch},
+		}, links...),
+	}
+}
+
+// RootAtom is the OPDS 1.2 start feed, a navigation feed pointing at the
+// acquisition feed of all books.
+func (c *Catalog) RootAtom() ([]byte, *appError.Error) {
+	feed := c.newFeed("urn:books:opds:root", c.Title,
+		atomLink{Rel: "self", Href: c.href(PathRoot, nil), Type: MediaNavigation})
+	feed.Entries = []atomEntry{{
+		ID:      "urn:books:opds:books",
+		Title:   "All books",
+		Updated: feed.Updated,
+		Content: &atomText{Type: "text", Value: "Every book in the catalog, by title."},
+		Links:   []atomLink{{Rel: "subsection", Href: c.href(PathBooks, nil), Type: MediaAcquisition}},
+	}}
+	return marshalAtom(feed)
+}
+
+// AcquisitionAtom is an OPDS 1.2 acquisition feed for one page of books,
+// with OpenSearch counters and links to the neighbouring pages.
+func (c *Catalog) AcquisitionAtom(page *Page) ([]byte, *appError.Error) {
+	route, id, title := PathBooks, "urn:books:opds:books", "All books"
+	if page.Query != "" {
+		route, id, title = PathSearch, "urn:books:opds:search", "Search results for "+page.Query
+	}
+
+	var links []atomLink
+	pageLinks := page.pageLinks()
+	for _, rel := range pageRels {
+		if query, ok := pageLinks[rel]; ok {
+			links = append(links, atomLink{Rel: rel, Href: c.href(route, query), Type: MediaAcquisition})
+		}
+	}
+	feed := c.newFeed(id, title, links...)
+	startIndex := (page.Number-1)*page.Size + 1
+	feed.TotalResults, feed.ItemsPerPage, feed.StartIndex = &page.Total, &page.Size, &startIndex
+
+	for i := range page.Books {
+		feed.Entries = append(feed.Entries, c.atomEntry(&page.Books[i], feed.Updated))
+	}
+	return marshalAtom(feed)
+}
+
+func (c *Catalog) atomEntry(book *model.Book, updated string) atomEntry {
+	entry := atomEntry{
+		ID:        "urn:uuid:" + book.ID,
+		Title:     book.Name,
+		Updated:   updated,
+		Publisher: book.Publisher,
+		Issued:    book.PublishedOn,
+		Links: []atomLink{
+			{Rel: "alternate", Href: c.bookHref(book), Type: "application/json"},
+			{Rel: relBorrow, Href: c.borrowHref(book), Type: "application/json"},
+		},
+	}
+	if book.Subtitle != "" {
+		entry.Title += ": " + book.Subtitle
+	}
+	if book.ISBN != "" {
+		entry.Identifier = "urn:isbn:" + book.ISBN
+	}
+	for _, name := range authors(book) {
+		entry.Authors = append(entry.Authors, atomAuthor{Name: name})
+	}
+	for _, subject := range book.Subjects {
+		term := subject.Code
+		if term == "" {
+			term = subject.Heading
+		}
+		entry.Categories = append(entry.Categories, atomCategory{Scheme: subject.Scheme, Term: term, Label: subject.Heading})
+	}
+	if book.Description != "" {
+		entry.Summary = &atomText{Type: "text", Value: book.Description}
+	}
+	if book.ImgURL != "" {
+		mediaType := imageType(book.ImgURL)
+		entry.Links = append(entry.Links,
+			atomLink{Rel: relImage, Href: book.ImgURL, Type: mediaType},
+			atomLink{Rel: relThumbnail, Href: book.ImgURL, Type: mediaType})
+	}
+	return entry
+}
+
+type openSearchDescription struct {
+	XMLName     xml.Name      `xml:"OpenSearchDescription"`
+	Namespace   string        `xml:"xmlns,attr"`
+	ShortName   string        `xml:"ShortName"`
+	Description string        `xml:"Description"`
+	URL         openSearchURL `xml:"Url"`
+}
+
+type openSearchURL struct {
+	Type     string `xml:"type,attr"`
+	Template string `xml:"template,attr"`
+}
+
+// OpenSearch describes how OPDS 1.2 clients build search URLs.
+func (c *Catalog) OpenSearch() ([]byte, *appError.Error) {
+	return marshalAtom(&openSearchDescription{
+		Namespace:   openSearchNamespace,
+		ShortName:   "Search",
+		Description: "Search " + c.Title + " by title, author or ISBN.",
+		URL: openSearchURL{
+			Type:     MediaAcquisition,
+			Template: c.href(PathSearch, nil) + "?q={searchTerms}",
+		},
+	})
+}
+
+func marshalAtom(document interface{}) ([]byte, *appError.Error) {
+	data, err := xml.Marshal(document)
+	if err != nil {
+		log.Printf("Error marshaling OPDS feed: %v", err)
+		return nil, appError.NewUnexpectedError(err.Error())
+	}
+	return append([]byte(xml.Header), data...), nil
+}
@@ -0,0 +1,145 @@
+package opds
+
+import (
+	"encoding/json"
+	"log"
+
+	"main/src/books/domain/model"
+	appError "main/utils/error"
+)
+
+type feedV2 struct {
+	Metadata     feedMetadataV2  `json:"metadata"`
+	Links        []linkV2        `json:"links"`
+	Navigation   []linkV2        `json:"navigation,omitempty"`
+	Publications []publicationV2 `json:"publications,omitempty"`
+}
+
+type feedMetadataV2 struct {
+	Title         string `json:"title"`
+	NumberOfItems *int   `json:"numberOfItems,omitempty"`
+	ItemsPerPage  *int   `json:"itemsPerPage,omitempty"`
+	CurrentPage   *int   `json:"currentPage,omitempty"`
+}
+
+type linkV2 struct {
+	Rel       string `json:"rel,omitempty"`
+	Href      string `json:"href"`
+	Type      string `json:"type,omitempty"`
+	Title     string `json:"title,omitempty"`
+	Templated bool   `json:"templated,omitempty"`
+}
+
+type publicationV2 struct {
+	Metadata publicationMetadataV2 `json:"metadata"`
+	Links    []linkV2              `json:"links"`
+	Images   []linkV2              `json:"images,omitempty"`
+}
+
+type publicationMetadataV2 struct {
+	Type        string      `json:"@type"`
+	Identifier  string      `json:"identifier"`
+	Title       string      `json:"title"`
+	Subtitle    string      `json:"subtitle,omitempty"`
+	Author      []nameV2    `json:"author,omitempty"`
+	Publisher   string      `json:"publisher,omitempty"`
+	Published   string      `json:"published,omitempty"`
+	Description string      `json:"description,omitempty"`
+	Subject     []subjectV2 `json:"subject,omitempty"`
+}
+
+type nameV2 struct {
+	Name string `json:"name"`
+}
+
+type subjectV2 struct {
+	Name   string `json:"name"`
+	Code   string `json:"code,omitempty"`
+	Scheme string `json:"scheme,omitempty"`
+}
+
+func (c *Catalog) newFeedV2(title string, links ...linkV2) *feedV2 {
+	return &feedV2{
+		Metadata: feedMetadataV2{Title: title},
+		Links: append([]linkV2{
+			{Rel: "start", Href: c.href(PathRootV2, nil), Type: MediaOPDS2},
+			{Rel: "search", Href: c.href(PathSearchV2, nil) + "{?q}", Type: MediaOPDS2, Templated: true},
+		}, links...),
+	}
+}
+
+// RootJSON is the OPDS 2.0 start feed.
+func (c *Catalog) RootJSON() ([]byte, *appError.Error) {
+	feed := c.newFeedV2(c.Title, linkV2{Rel: "self", Href: c.href(PathRootV2, nil), Type: MediaOPDS2})
+	feed.Navigation = []linkV2{{Rel: "subsection", Href: c.href(PathBooksV2, nil), Type: MediaOPDS2, Title: "All books"}}
+	return marshalJSON(feed)
+}
+
+// PublicationsJSON is an OPDS 2.0 feed for one page of books.
+func (c *Catalog) PublicationsJSON(page *Page) ([]byte, *appError.Error) {
+	route, title := PathBooksV2, "All books"
+	if page.Query != "" {
+		route, title = PathSearchV2, "Search results for "+page.Query
+	}
+
+	var links []linkV2
+	pageLinks := page.pageLinks()
+	for _, rel := range pageRels {
+		if query, ok := pageLinks[rel]; ok {
+			links = append(links, linkV2{Rel: rel, Href: c.href(route, query), Type: MediaOPDS2})
+		}
+	}
+	feed := c.newFeedV2(title, links...)
+	feed.Metadata.NumberOfItems, feed.Metadata.ItemsPerPage, feed.Metadata.CurrentPage = &page.Total, &page.Size, &page.Number
+
+	for i := range page.Books {
+		feed.Publications = append(feed.Publications, c.publication(&page.Books[i]))
+	}
+	return marshalJSON(feed)
+}
+
+func (c *Catalog) publication(book *model.Book) publicationV2 {
+	metadata := publicationMetadataV2{
+		Type:        "http://schema.org/Book",
+		Identifier:  "urn:uuid:" + book.ID,
+		Title:       book.Name,
+		Subtitle:    book.Subtitle,
+		Publisher:   book.Publisher,
+		Published:   book.PublishedOn,
+		Description: book.Description,
+	}
+	if book.ISBN != "" {
+		metadata.Identifier = "urn:isbn:" + book.ISBN
+	}
+	for _, name := range authors(book) {
+		metadata.Author = append(metadata.Author, nameV2{Name: name})
+	}
+	for _, subject := range book.Subjects {
+		name := subject.Heading
+		if name == "" {
+			name = subject.Code
+		}
+		metadata.Subject = append(metadata.Subject, subjectV2{Name: name, Code: subject.Code, Scheme: subject.Scheme})
+	}
+
+	publication := publicationV2{
+		Metadata: metadata,
+		Links: []linkV2{
+			{Rel: "self", Href: c.bookHref(book), Type: "application/json"},
+			{Rel: relBorrow, Href: c.borrowHref(book), Type: "application/json"},
+		},
+	}
+	if book.ImgURL != "" {
+		publication.Images = []linkV2{{Href: book.ImgURL, Type: imageType(book.ImgURL)}}
+	}
+	return publication
+}
+
+func marshalJSON(feed *feedV2) ([]byte, *appError.Error) {
+	data, err := json.Marshal(feed)
+	if err != nil {
+		log.Printf("Error marshaling OPDS 2.0 feed: %v", err)
+		return nil, appError.NewUnexpectedError(err.Error())
+	}
+	return data, nil
+}
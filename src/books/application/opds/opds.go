@@ -0,0 +1,215 @@
+// Package opds renders the book collection as OPDS catalogs, the format
+// e-reader apps browse: OPDS 1.2 Atom feeds and OPDS 2.0 JSON feeds.
+package opds
+
+import (
+	"net/url"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"main/src/books/domain/model"
+	appError "main/utils/error"
+)
+
+// Catalog routes. OPDS 2.0 mirrors the 1.2 routes under /opds/v2 and
+// describes search with a templated link instead of an OpenSearch document.
+const (
+	PathRoot       = "/opds"
+	PathBooks      = "/opds/books"
+	PathSearch     = "/opds/search"
+	PathOpenSearch = "/opds/opensearch.xml"
+	PathRootV2     = "/opds/v2"
+	PathBooksV2    = "/opds/v2/books"
+	PathSearchV2   = "/opds/v2/search"
+)
+
+const (
+	MediaNavigation  = "application/atom+xml;profile=opds-catalog;kind=navigation"
+	MediaAcquisition = "application/atom+xml;profile=opds-catalog;kind=acquisition"
+	MediaOpenSearch  = "application/opensearchdescription+xml"
+	MediaOPDS2       = "application/opds+json"
+)
+
+// Link relations used by both versions.
+const (
+	relBorrow    = "http://opds-spec.org/acquisition/borrow"
+	relImage     = "http://opds-spec.org/image"
+	relThumbnail = "http://opds-spec.org/image/thumbnail"
+)
+
+const (
+	DefaultPageSize = 50
+	MaxPageSize     = 200
+)
+
+// Catalog holds what every feed needs besides the books. BaseURL is the
+// public API address links are built on; when empty, links are relative.
+type Catalog struct {
+	BaseURL string
+	Title   string
+	Updated time.Time
+}
+
+func (c *Catalog) href(route string, query url.Values) string {
+	href := strings.TrimSuffix(c.BaseURL, "/") + route
+	if len(query) > 0 {
+		href += "?" + query.Encode()
+	}
+	return href
+}
+
+func (c *Catalog) bookHref(book *model.Book) string {
+	return c.href("/books/"+book.ID, nil)
+}
+
+func (c *Catalog) borrowHref(book *model.Book) string {
+	return c.href("/books/"+book.ID+"/checkout", nil)
+}
+
+// Page is one page of an acquisition feed, either the whole collection or
+// the results of a search.
+type Page struct {
+	Books  []model.Book
+	Query  string
+	Number int
+	Size   int
+	Total  int
+}
+
+// LastPage is the number of the last page, at least 1 so an empty catalog
+// still has a valid first page.
+func (p *Page) LastPage() int {
+	if p.Total == 0 {
+		return 1
+	}
+	return (p.Total + p.Size - 1) / p.Size
+}
+
+// ParsePageParams reads the page and size query parameters.
+func ParsePageParams(params map[string]string) (int, int, *appError.Error) {
+	number, size := 1, DefaultPageSize
+	if value := params["page"]; value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 {
+			return 0, 0, appError.NewBadRequestError("page must be a positive number.")
+		}
+		number = parsed
+	}
+	if value := params["size"]; value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > MaxPageSize {
+			return 0, 0, appError.NewBadRequestError("size must be between 1 and " + strconv.Itoa(MaxPageSize) + ".")
+		}
+		size = parsed
+	}
+	return number, size, nil
+}
+
+// NewPage sorts books by title so pages are stable between requests, keeps
+// the ones matching query and cuts out the requested page.
+func NewPage(books []model.Book, query string, number, size int) (*Page, *appError.Error) {
+	query = strings.TrimSpace(query)
+	matched := make([]model.Book, 0, len(books))
+	for _, book := range books {
+		if query == "" || matches(&book, query) {
+			matched = append(matched, book)
+		}
+	}
+	sort.SliceStable(matched, func(i, j int) bool {
+		left, right := strings.ToLower(matched[i].Name), strings.ToLower(matched[j].Name)
+		if left != right {
+			return left < right
+		}
+		return matched[i].ID < matched[j].ID
+	})
+
+	page := &Page{Query: query, Number: number, Size: size, Total: len(matched)}
+	if number > page.LastPage() {
+		return nil, appError.NewNotFoundError("Page " + strconv.Itoa(number) + " does not exist.")
+	}
+	start := (number - 1) * size
+	end := start + size
+	if end > len(matched) {
+		end = len(matched)
+	}
+	page.Books = matched[start:end]
+	return page, nil
+}
+
+// matches looks for every word of the query in the title, subtitle,
+// contributors or ISBN.
+func matches(book *model.Book, query string) bool {
+	fields := []string{book.Name, book.Subtitle, book.ISBN}
+	for _, contributor := range book.Contributors {
+		fields = append(fields, contributor.Name)
+	}
+	text := strings.ToLower(strings.Join(fields, " "))
+	for _, word := range strings.Fields(strings.ToLower(query)) {
+		if !strings.Contains(text, word) {
+			return false
+		}
+	}
+	return true
+}
+
+// pageLinks returns the query of the first, previous, next and last pages,
+// with nil for links that do not apply.
+func (p *Page) pageLinks() map[string]url.Values {
+	at := func(number int) url.Values {
+		query := url.Values{}
+		if p.Query != "" {
+			query.Set("q", p.Query)
+		}
+		if p.Size != DefaultPageSize {
+			query.Set("size", strconv.Itoa(p.Size))
+		}
+		if number > 1 {
+			query.Set("page", strconv.Itoa(number))
+		}
+		return query
+	}
+	links := map[string]url.Values{
+		"self":  at(p.Number),
+		"first": at(1),
+		"last":  at(p.LastPage()),
+	}
+	if p.Number > 1 {
+		links["previous"] = at(p.Number - 1)
+	}
+	if p.Number < p.LastPage() {
+		links["next"] = at(p.Number + 1)
+	}
+	return links
+}
+
+// pageRels keeps page links in a fixed order in the rendered feeds.
+var pageRels = []string{"self", "first", "previous", "next", "last"}
+
+// imageType guesses the cover media type from the image URL extension.
+func imageType(imgURL string) string {
+	if parsed, err := url.Parse(imgURL); err == nil {
+		imgURL = parsed.Path
+	}
+	switch strings.ToLower(path.Ext(imgURL)) {
+	case ".png":
+		return "image/png"
+	case ".gif":
+		return "image/gif"
+	case ".webp":
+		return "image/webp"
+	}
+	return "image/jpeg"
+}
+
+func authors(book *model.Book) []string {
+	var names []string
+	for _, contributor := range book.Contributors {
+		if contributor.Role == "author" {
+			names = append(names, contributor.Name)
+		}
+	}
+	return names
+}
@@ -0,0 +1,199 @@
+package opds_test
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"testing"
+	"time"
+
+	"main/src/books/application/opds"
+	"main/src/books/domain/model"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type OPDSSuite struct {
+	suite.Suite
+	catalog *opds.Catalog
+	books   []model.Book
+}
+
+type atomFeed struct {
+	TotalResults int `xml:"totalResults"`
+	Links        []struct {
+		Rel  string `xml:"rel,attr"`
+		Href string `xml:"href,attr"`
+		Type string `xml:"type,attr"`
+	} `xml:"link"`
+	Entries []struct {
+		ID     string `xml:"id"`
+		Title  string `xml:"title"`
+		Author []struct {
+			Name string `xml:"name"`
+		} `xml:"author"`
+		Links []struct {
+			Rel  string `xml:"rel,attr"`
+			Href string `xml:"href,attr"`
+			Type string `xml:"type,attr"`
+		} `xml:"link"`
+	} `xml:"entry"`
+}
+
+func (suite *OPDSSuite) SetupTest() {
+	suite.catalog = &opds.Catalog{
+		BaseURL: "https://api.example.com/Prod",
+		Title:   "Books Store",
+		Updated: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+	}
+	suite.books = nil
+	for i := 5; i >= 1; i-- {
+		suite.books = append(suite.books, model.Book{
+			ID:     fmt.Sprintf("id-%d", i),
+			Name:   fmt.Sprintf("Book %d", i),
+			ImgURL: fmt.Sprintf("https://bucket.s3.amazonaws.com/books/%d.png", i),
+		})
+	}
+	suite.books[0].Contributors = []model.Contributor{{Name: "Ann Lee", Role: "author"}, {Name: "Bo Kim", Role: "editor"}}
+}
+
+func (suite *OPDSSuite) links(feed *atomFeed) map[string]string {
+	links := map[string]string{}
+	for _, link := range feed.Links {
+		links[link.Rel] = link.Href
+	}
+	return links
+}
+
+func (suite *OPDSSuite) TestNewPage() {
+	page, err := opds.NewPage(suite.books, "", 2, 2)
+	suite.Nil(err)
+	suite.Equal(5, page.Total)
+	suite.Equal(3, page.LastPage())
+	suite.Equal("Book 3", page.Books[0].Name)
+	suite.Equal("Book 4", page.Books[1].Name)
+
+	_, err = opds.NewPage(suite.books, "", 4, 2)
+	suite.NotNil(err)
+
+	page, err = opds.NewPage(suite.books, "lee book", 1, 10)
+	suite.Nil(err)
+	suite.Len(page.Books, 1)
+	suite.Equal("id-5", page.Books[0].ID)
+
+	page, err = opds.NewPage(nil, "", 1, 10)
+	suite.Nil(err)
+	suite.Equal(1, page.LastPage())
+}
+
+func (suite *OPDSSuite) TestParsePageParams() {
+	number, size, err := opds.ParsePageParams(nil)
+	suite.Nil(err)
+	suite.Equal(1, number)
+	suite.Equal(opds.DefaultPageSize, size)
+
+	_, _, err = opds.ParsePageParams(map[string]string{"page": "0"})
+	suite.NotNil(err)
+	_, _, err = opds.ParsePageParams(map[string]string{"size": "1000"})
+	suite.NotNil(err)
+}
+
+func (suite *OPDSSuite) TestAcquisitionAtom() {
+	page, _ := opds.NewPage(suite.books, "", 2, 2)
+	data, err := suite.catalog.AcquisitionAtom(page)
+	suite.Nil(err)
+
+	var feed atomFeed
+	suite.NoError(xml.Unmarshal(data, &feed))
+	suite.Equal(5, feed.TotalResults)
+	links := suite.links(&feed)
+	suite.Equal("https://api.example.com/Prod/opds/books?page=2&size=2", links["self"])
+	suite.Equal("https://api.example.com/Prod/opds/books?size=2", links["previous"])
+	suite.Equal("https://api.example.com/Prod/opds/books?page=3&size=2", links["next"])
+	suite.Equal("https://api.example.com/Prod/opds/opensearch.xml", links["search"])
+
+	suite.Len(feed.Entries, 2)
+	entry := feed.Entries[0]
+	suite.Equal("urn:uuid:id-3", entry.ID)
+	rels := map[string]string{}
+	for _, link := range entry.Links {
+		rels[link.Rel] = link.Type
+	}
+	suite.Equal("image/png", rels["http://opds-spec.org/image"])
+	suite.Contains(rels, "http://opds-spec.org/acquisition/borrow")
+}
+
+func (suite *OPDSSuite) TestSearchAtom() {
+	page, _ := opds.NewPage(suite.books, "ann", 1, opds.DefaultPageSize)
+	data, err := suite.catalog.AcquisitionAtom(page)
+	suite.Nil(err)
+
+	var feed atomFeed
+	suite.NoError(xml.Unmarshal(data, &feed))
+	suite.Equal("https://api.example.com/Prod/opds/search?q=ann", suite.links(&feed)["self"])
+	suite.NotContains(suite.links(&feed), "next")
+	suite.Len(feed.Entries, 1)
+	suite.Equal([]struct {
+		Name string `xml:"name"`
+	}{{Name: "Ann Lee"}}, feed.Entries[0].Author)
+}
+
+func (suite *OPDSSuite) TestRootAndOpenSearch() {
+	data, err := suite.catalog.RootAtom()
+	suite.Nil(err)
+	suite.Contains(string(data), `href="https://api.example.com/Prod/opds/books"`)
+
+	data, err = suite.catalog.OpenSearch()
+	suite.Nil(err)
+	suite.Contains(string(data), `template="https://api.example.com/Prod/opds/search?q={searchTerms}"`)
+}
+
+func (suite *OPDSSuite) TestPublicationsJSON() {
+	page, _ := opds.NewPage(suite.books, "", 1, 2)
+	data, err := suite.catalog.PublicationsJSON(page)
+	suite.Nil(err)
+
+	var feed struct {
+		Metadata struct {
+			NumberOfItems int `json:"numberOfItems"`
+			CurrentPage   int `json:"currentPage"`
+		} `json:"metadata"`
+		Links []struct {
+			Rel       string `json:"rel"`
+			Href      string `json:"href"`
+			Templated bool   `json:"templated"`
+		} `json:"links"`
+		Publications []struct {
+			Metadata struct {
+				Title  string `json:"title"`
+				Author []struct {
+					Name string `json:"name"`
+				} `json:"author"`
+			} `json:"metadata"`
+			Images []struct {
+				Href string `json:"href"`
+				Type string `json:"type"`
+			} `json:"images"`
+		} `json:"publications"`
+	}
+	suite.NoError(json.Unmarshal(data, &feed))
+	suite.Equal(5, feed.Metadata.NumberOfItems)
+	suite.Equal(1, feed.Metadata.CurrentPage)
+	suite.Len(feed.Publications, 2)
+	suite.Equal("Book 1", feed.Publications[0].Metadata.Title)
+	suite.Equal("image/png", feed.Publications[0].Images[0].Type)
+
+	rels := map[string]string{}
+	for _, link := range feed.Links {
+		rels[link.Rel] = link.Href
+		if link.Rel == "search" {
+			suite.True(link.Templated)
+		}
+	}
+	suite.Equal("https://api.example.com/Prod/opds/v2/books?page=2&size=2", rels["next"])
+	suite.NotContains(rels, "previous")
+}
+
+func TestOPDSSuite(t *testing.T) {
+	suite.Run(t, new(OPDSSuite))
+}
@@ -0,0 +1,47 @@
+package outbox
+
+import (
+	"sync"
+
+	"main/src/books/domain/model"
+	appError "main/utils/error"
+)
+
+// MemoryPublisher keeps the events it is given, for tests and local runs.
+// Like a FIFO queue, it drops an event whose ID it already has.
+type MemoryPublisher struct {
+	// Fail, when set, decides whether an event is rejected.
+	Fail func(*model.BookEvent) *appError.Error
+
+	mu     sync.Mutex
+	events []model.BookEvent
+	seen   map[string]bool
+}
+
+func NewMemoryPublisher() *MemoryPublisher {
+	return &MemoryPublisher{
+		seen: make(map[string]bool),
+	}
+}
+
+func (p *MemoryPublisher) Publish(event *model.BookEvent) *appError.Error {
+	if p.Fail != nil {
+		if err := p.Fail(event); err != nil {
+			return err
+		}
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !p.seen[event.ID] {
+		p.seen[event.ID] = true
+		p.events = append(p.events, *event)
+	}
+	return nil
+}
+
+// Events returns the published events in publishing order.
+func (p *MemoryPublisher) Events() []model.BookEvent {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]model.BookEvent(nil), p.events...)
+}
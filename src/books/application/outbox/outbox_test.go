@@ -0,0 +1,104 @@
+package outbox_test
+
+import (
+	"testing"
+
+	"main/src/books/application/outbox"
+	"main/src/books/domain/model"
+	appError "main/utils/error"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	repoMock "main/mocks"
+)
+
+type OutboxSuite struct {
+	suite.Suite
+	events    *repoMock.BookOutboxRepository
+	publisher *outbox.MemoryPublisher
+	relay     *outbox.Relay
+}
+
+const (
+	MethodGetPendingEvents   = "GetPendingEvents"
+	MethodMarkEventPublished = "MarkEventPublished"
+	MethodRecordEventFailure = "RecordEventFailure"
+)
+
+func (suite *OutboxSuite) SetupTest() {
+	suite.events = new(repoMock.BookOutboxRepository)
+	suite.publisher = outbox.NewMemoryPublisher()
+	suite.relay = outbox.NewRelay(suite.events, suite.publisher)
+}
+
+func (suite *OutboxSuite) pending() []model.BookEvent {
+	return []model.BookEvent{
+		{ID: "e1", Type: model.BookCreated, BookID: "a"},
+		{ID: "e2", Type: model.BookUpdated, BookID: "b"},
+		{ID: "e3", Type: model.BookImageChanged, BookID: "b"},
+		{ID: "e4", Type: model.BookDeleted, BookID: "a"},
+	}
+}
+
+func (suite *OutboxSuite) ids(events []model.BookEvent) []string {
+	var ids []string
+	for _, event := range events {
+		ids = append(ids, event.ID)
+	}
+	return ids
+}
+
+func (suite *OutboxSuite) TestRunPublishesInOrder() {
+	suite.events.On(MethodGetPendingEvents, outbox.DefaultBatchSize).Return(suite.pending(), nil)
+	suite.events.On(MethodMarkEventPublished, mock.Anything).Return(nil)
+
+	report, err := suite.relay.Run(0)
+	suite.Nil(err)
+	suite.Equal(&outbox.Report{Published: 4}, report)
+	suite.Equal([]string{"e1", "e2", "e3", "e4"}, suite.ids(suite.publisher.Events()))
+	suite.events.AssertNumberOfCalls(suite.T(), MethodMarkEventPublished, 4)
+}
+
+func (suite *OutboxSuite) TestRunHoldsBackBookAfterFailure() {
+	suite.publisher.Fail = func(event *model.BookEvent) *appError.Error {
+		if event.ID == "e2" {
+			return appError.NewUnexpectedError("throttled")
+		}
+		return nil
+	}
+	suite.events.On(MethodGetPendingEvents, 10).Return(suite.pending(), nil)
+	suite.events.On(MethodMarkEventPublished, mock.Anything).Return(nil)
+	suite.events.On(MethodRecordEventFailure, "e2", "throttled").Return(nil)
+
+	report, err := suite.relay.Run(10)
+	suite.Nil(err)
+	suite.Equal(&outbox.Report{Published: 2, Failed: 1, Deferred: 1}, report)
+	suite.Equal([]string{"e1", "e4"}, suite.ids(suite.publisher.Events()))
+	suite.events.AssertNotCalled(suite.T(), MethodMarkEventPublished, "e2")
+	suite.events.AssertNotCalled(suite.T(), MethodMarkEventPublished, "e3")
+}
+
+func (suite *OutboxSuite) TestRunRepublishesUnmarkedEvents() {
+	suite.events.On(MethodGetPendingEvents, 10).Return(suite.pending()[:1], nil)
+	suite.events.On(MethodMarkEventPublished, "e1").Return(appError.NewUnexpectedError("timeout")).Once()
+	suite.events.On(MethodMarkEventPublished, "e1").Return(nil).Once()
+
+	_, err := suite.relay.Run(10)
+	suite.Nil(err)
+	_, err = suite.relay.Run(10)
+	suite.Nil(err)
+	suite.Len(suite.publisher.Events(), 1, "the duplicate is dropped by its ID")
+}
+
+func (suite *OutboxSuite) TestRunFailsWhenOutboxUnreadable() {
+	suite.events.On(MethodGetPendingEvents, 10).Return(nil, appError.NewUnexpectedError("denied"))
+
+	_, err := suite.relay.Run(10)
+	suite.NotNil(err)
+	suite.Empty(suite.publisher.Events())
+}
+
+func TestOutboxSuite(t *testing.T) {
+	suite.Run(t, new(OutboxSuite))
+}
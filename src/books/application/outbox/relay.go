@@ -0,0 +1,74 @@
+// Package outbox relays the book events the repository writes to the outbox,
+// in the same transaction as each change, to an EventPublisher.
+package outbox
+
+import (
+	"log"
+
+	"main/src/books/domain/repository"
+	appError "main/utils/error"
+)
+
+// DefaultBatchSize is how many pending events a run publishes at most.
+const DefaultBatchSize = 100
+
+type Report struct {
+	Published int `json:"published"`
+	Failed    int `json:"failed"`
+	// Deferred events wait behind a failed event of the same book.
+	Deferred int `json:"deferred"`
+}
+
+type Relay struct {
+	events    repository.BookOutboxRepository
+	publisher repository.EventPublisher
+}
+
+func NewRelay(events repository.BookOutboxRepository, publisher repository.EventPublisher) *Relay {
+	return &Relay{
+		events:    events,
+		publisher: publisher,
+	}
+}
+
+// Run publishes up to limit pending events, oldest first. An event is marked
+// published only after the publisher accepted it, so a crash in between
+// publishes it again: delivery is at least once and consumers deduplicate by
+// event ID. When an event fails, the later events of its book are held back
+// for the next run to keep each book's events in order.
+func (r *Relay) Run(limit int) (*Report, *appError.Error) {
+	if limit < 1 {
+		limit = DefaultBatchSize
+	}
+	events, err := r.events.GetPendingEvents(limit)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &Report{}
+	blocked := make(map[string]bool)
+	for i := range events {
+		event := &events[i]
+		if blocked[event.BookID] {
+			report.Deferred++
+			continue
+		}
+		if errPublish := r.publisher.Publish(event); errPublish != nil {
+			log.Printf("Error publishing event %s of book %s, attempt %d: %s", event.ID, event.BookID, event.Attempts+1, errPublish.ToString())
+			blocked[event.BookID] = true
+			report.Failed++
+			if errRecord := r.events.RecordEventFailure(event.ID, errPublish.Message); errRecord != nil {
+				log.Printf("Error recording failure of event %s: %s", event.ID, errRecord.ToString())
+			}
+			continue
+		}
+		report.Published++
+		if errMark := r.events.MarkEventPublished(event.ID); errMark != nil {
+			// The event stays pending and will be published again.
+			log.Printf("Error marking event %s published: %s", event.ID, errMark.ToString())
+		}
+	}
+
+	log.Printf("Relayed book events, published: %d, failed: %d, deferred: %d", report.Published, report.Failed, report.Deferred)
+	return report, nil
+}
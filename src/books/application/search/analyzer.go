@@ -0,0 +1,171 @@
+package search
+
+import (
+	"strings"
+	"unicode"
+)
+
+type Language string
+
+const (
+	LanguageEnglish Language = "en"
+	LanguageSpanish Language = "es"
+)
+
+// Languages the analyzer stems. Queries are stemmed for all of them, since
+// their language is not known.
+var Languages = []Language{LanguageEnglish, LanguageSpanish}
+
+var folding = strings.NewReplacer(
+	"á", "a", "à", "a", "â", "a", "ä", "a",
+	"é", "e", "è", "e", "ê", "e", "ë", "e",
+	"í", "i", "ì", "i", "î", "i", "ï", "i",
+	"ó", "o", "ò", "o", "ô", "o", "ö", "o",
+	"ú", "u", "ù", "u", "û", "u", "ü", "u",
+	"ñ", "n", "ç", "c",
+)
+
+// Tokenize lowercases text, folds accents and splits it on anything that is
+// not a letter or a digit. Apostrophes are dropped so "Gatsby's" and
+// "Gatsbys" match.
+func Tokenize(text string) []string {
+	text = folding.Replace(strings.ToLower(text))
+	text = strings.NewReplacer("'", "", "’", "").Replace(text)
+	return strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+var stopWords = map[Language]map[string]bool{
+	LanguageEnglish: set("a", "an", "and", "are", "as", "at", "be", "but", "by", "for", "from", "in", "into",
+		"is", "it", "of", "on", "or", "that", "the", "their", "this", "to", "was", "with"),
+	LanguageSpanish: set("a", "al", "como", "con", "de", "del", "el", "en", "es", "la", "las", "lo", "los",
+		"o", "para", "por", "que", "se", "su", "sus", "un", "una", "y"),
+}
+
+func set(words ...string) map[string]bool {
+	m := make(map[string]bool, len(words))
+	for _, word := range words {
+		m[word] = true
+	}
+	return m
+}
+
+func isStopWord(token string) bool {
+	for _, language := range Languages {
+		if stopWords[language][token] {
+			return true
+		}
+	}
+	return false
+}
+
+// DetectLanguage guesses the language of text from the stop words it uses,
+// defaulting to English.
+func DetectLanguage(text string) Language {
+	var english, spanish int
+	for _, token := range Tokenize(text) {
+		if stopWords[LanguageEnglish][token] {
+			english++
+		}
+		if stopWords[LanguageSpanish][token] {
+			spanish++
+		}
+	}
+	if spanish > english {
+		return LanguageSpanish
+	}
+	return LanguageEnglish
+}
+
+// Analyze turns text into index terms: tokens without stop words, stemmed
+// for language.
+func Analyze(text string, language Language) []string {
+	tokens := Tokenize(text)
+	terms := make([]string, 0, len(tokens))
+	for _, token := range tokens {
+		if !isStopWord(token) {
+			terms = append(terms, Stem(token, language))
+		}
+	}
+	return terms
+}
+
+// Stem reduces a token to its stem with a light, suffix-stripping stemmer.
+// It favors recall on plurals and common inflections over linguistic
+// accuracy.
+func Stem(token string, language Language) string {
+	if language == LanguageSpanish {
+		return stemSpanish(token)
+	}
+	return stemEnglish(token)
+}
+
+func isVowel(b byte) bool {
+	return strings.IndexByte("aeiouy", b) >= 0
+}
+
+func hasVowel(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if isVowel(s[i]) {
+			return true
+		}
+	}
+	return false
+}
+
+func stemEnglish(w string) string {
+	if len(w) <= 3 {
+		return w
+	}
+	switch {
+	case strings.HasSuffix(w, "sses"):
+		w = w[:len(w)-2]
+	case strings.HasSuffix(w, "ies") && len(w) > 4:
+		w = w[:len(w)-3] + "y"
+	case strings.HasSuffix(w, "s") && !strings.HasSuffix(w, "ss") && !strings.HasSuffix(w, "us") && !strings.HasSuffix(w, "is"):
+		w = w[:len(w)-1]
+	}
+
+	for _, suffix := range []string{"ingly", "edly", "ing", "ed"} {
+		stem := strings.TrimSuffix(w, suffix)
+		if stem == w || len(stem) < 3 || !hasVowel(stem) {
+			continue
+		}
+		// Undouble the final consonant: running -> run, hopped -> hop.
+		if n := len(stem); stem[n-1] == stem[n-2] && !isVowel(stem[n-1]) && strings.IndexByte("lsz", stem[n-1]) < 0 {
+			stem = stem[:n-1]
+		}
+		return stem
+	}
+	for _, suffix := range []string{"ness", "ment", "ful", "ly"} {
+		if stem := strings.TrimSuffix(w, suffix); stem != w && len(stem) >= 4 {
+			return stem
+		}
+	}
+	return w
+}
+
+// stemSpanish strips plurals and gender endings, after the light stemmer of
+// Savoy's "Report on CLEF-2001 Experiments".
+func stemSpanish(w string) string {
+	n := len(w)
+	if n < 5 {
+		return w
+	}
+	switch w[n-1] {
+	case 'o', 'a', 'e':
+		return w[:n-1]
+	case 's':
+		if strings.HasSuffix(w, "eses") {
+			return w[:n-2]
+		}
+		if strings.HasSuffix(w, "ces") {
+			return w[:n-3] + "z"
+		}
+		if strings.IndexByte("oae", w[n-2]) >= 0 {
+			return w[:n-2]
+		}
+	}
+	return w
+}
@@ -0,0 +1,218 @@
+// Package search is a small full-text search engine over the catalog: an
+// inverted index of book names, descriptions, authors and subjects, ranked
+// with BM25, with prefix and typo-tolerant matching and facet counts.
+package search
+
+import (
+	"sort"
+	"strings"
+
+	"main/src/books/domain/model"
+)
+
+// Field weights: a match in the name counts three times one in the
+// description.
+const (
+	weightName        = 3.0
+	weightAuthors     = 2.0
+	weightSubjects    = 1.5
+	weightDescription = 1.0
+)
+
+// Facets the results can be counted and filtered by.
+const (
+	FacetAuthor    = "author"
+	FacetSubject   = "subject"
+	FacetPublisher = "publisher"
+	FacetLanguage  = "language"
+)
+
+var Facets = []string{FacetAuthor, FacetSubject, FacetPublisher, FacetLanguage}
+
+// Document is what the index keeps of a book: enough to show a result and
+// count facets without reading the table.
+type Document struct {
+	ID        string   `json:"ID"`
+	Name      string   `json:"name"`
+	Subtitle  string   `json:"subtitle,omitempty"`
+	Authors   []string `json:"authors,omitempty"`
+	Subjects  []string `json:"subjects,omitempty"`
+	Publisher string   `json:"publisher,omitempty"`
+	ImgURL    string   `json:"img_url,omitempty"`
+	Language  Language `json:"language"`
+
+	// Terms are the weighted term frequencies of the book, kept so it can be
+	// removed from the postings.
+	Terms  map[string]float64 `json:"-"`
+	Length float64            `json:"-"`
+}
+
+func (d *Document) facet(name string) []string {
+	switch name {
+	case FacetAuthor:
+		return d.Authors
+	case FacetSubject:
+		return d.Subjects
+	case FacetPublisher:
+		if d.Publisher != "" {
+			return []string{d.Publisher}
+		}
+	case FacetLanguage:
+		return []string{string(d.Language)}
+	}
+	return nil
+}
+
+// Index is an inverted index of books. It is not safe for concurrent writes.
+type Index struct {
+	Documents map[string]*Document
+	// Postings maps a term to the weighted frequency of the term per book.
+	Postings    map[string]map[string]float64
+	TotalLength float64
+
+	vocabulary []string
+}
+
+func NewIndex() *Index {
+	return &Index{
+		Documents: make(map[string]*Document),
+		Postings:  make(map[string]map[string]float64),
+	}
+}
+
+func (ix *Index) Len() int {
+	return len(ix.Documents)
+}
+
+// Add indexes a book, replacing any earlier version of it.
+func (ix *Index) Add(book *model.Book) {
+	ix.Remove(book.ID)
+
+	doc := &Document{
+		ID:        book.ID,
+		Name:      book.Name,
+		Subtitle:  book.Subtitle,
+		Publisher: book.Publisher,
+		ImgURL:    book.ImgURL,
+		Language:  DetectLanguage(book.Name + " " + book.Subtitle + " " + book.Description),
+		Terms:     make(map[string]float64),
+	}
+	for _, contributor := range book.Contributors {
+		if contributor.Role == "author" {
+			doc.Authors = append(doc.Authors, contributor.Name)
+		}
+	}
+	for _, subject := range book.Subjects {
+		if subject.Heading != "" {
+			doc.Subjects = append(doc.Subjects, subject.Heading)
+		}
+	}
+
+	add := func(text string, weight float64) {
+		for _, term := range Analyze(text, doc.Language) {
+			doc.Terms[term] += weight
+			doc.Length += weight
+		}
+	}
+	add(book.Name+" "+book.Subtitle, weightName)
+	add(strings.Join(doc.Authors, " "), weightAuthors)
+	add(strings.Join(doc.Subjects, " "), weightSubjects)
+	add(book.Description, weightDescription)
+
+	for term, frequency := range doc.Terms {
+		postings, ok := ix.Postings[term]
+		if !ok {
+			postings = make(map[string]float64)
+			ix.Postings[term] = postings
+			ix.vocabulary = nil
+		}
+		postings[doc.ID] = frequency
+	}
+	ix.Documents[doc.ID] = doc
+	ix.TotalLength += doc.Length
+}
+
+// Remove drops a book from the index. Unknown IDs are ignored.
+func (ix *Index) Remove(bookID string) {
+	doc, ok := ix.Documents[bookID]
+	if !ok {
+		return
+	}
+	for term := range doc.Terms {
+		delete(ix.Postings[term], bookID)
+		if len(ix.Postings[term]) == 0 {
+			delete(ix.Postings, term)
+			ix.vocabulary = nil
+		}
+	}
+	delete(ix.Documents, bookID)
+	ix.TotalLength -= doc.Length
+}
+
+// terms is the sorted vocabulary, rebuilt after terms come or go.
+func (ix *Index) terms() []string {
+	if ix.vocabulary == nil {
+		ix.vocabulary = make([]string, 0, len(ix.Postings))
+		for term := range ix.Postings {
+			ix.vocabulary = append(ix.vocabulary, term)
+		}
+		sort.Strings(ix.vocabulary)
+	}
+	return ix.vocabulary
+}
+
+// withPrefix returns the terms starting with prefix, at most limit of them.
+func (ix *Index) withPrefix(prefix string, limit int) []string {
+	vocabulary := ix.terms()
+	start := sort.SearchStrings(vocabulary, prefix)
+	var terms []string
+	for i := start; i < len(vocabulary) && len(terms) < limit && strings.HasPrefix(vocabulary[i], prefix); i++ {
+		terms = append(terms, vocabulary[i])
+	}
+	return terms
+}
+
+// similar returns the terms within maxDistance edits of token. Candidates
+// must share its first letter, which keeps the scan cheap and rarely loses a
+// real typo.
+func (ix *Index) similar(token string, maxDistance int) []string {
+	vocabulary := ix.terms()
+	start := sort.SearchStrings(vocabulary, token[:1])
+	var terms []string
+	for i := start; i < len(vocabulary) && vocabulary[i][0] == token[0]; i++ {
+		term := vocabulary[i]
+		if diff := len(term) - len(token); diff > maxDistance || -diff > maxDistance {
+			continue
+		}
+		if distance(token, term) <= maxDistance {
+			terms = append(terms, term)
+		}
+	}
+	return terms
+}
+
+// distance is the Damerau-Levenshtein (optimal string alignment) distance,
+// so a swap of two letters counts as one typo.
+func distance(a, b string) int {
+	rows := make([][]int, len(a)+1)
+	for i := range rows {
+		rows[i] = make([]int, len(b)+1)
+		rows[i][0] = i
+	}
+	for j := range rows[0] {
+		rows[0][j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			rows[i][j] = min(rows[i-1][j]+1, rows[i][j-1]+1, rows[i-1][j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				rows[i][j] = min(rows[i][j], rows[i-2][j-2]+1)
+			}
+		}
+	}
+	return rows[len(a)][len(b)]
+}
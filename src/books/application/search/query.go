@@ -0,0 +1,274 @@
+package search
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	appError "main/utils/error"
+)
+
+const (
+	DefaultLimit = 20
+	MaxLimit     = 100
+	// MaxFacetValues is how many values are counted per facet.
+	MaxFacetValues = 10
+)
+
+// BM25 parameters.
+const (
+	k1 = 1.2
+	b  = 0.75
+)
+
+// Inexact matches rank below exact ones.
+const (
+	boostPrefix = 0.8
+	boostTypo   = 0.6
+	// maxExpansions caps how many terms a prefix or a typo expands to.
+	maxExpansions = 50
+)
+
+// Query is a search: free text, exact facet filters and a page of results.
+// Without text, every book matching the filters is returned by name.
+type Query struct {
+	Text    string
+	Filters map[string]string
+	Limit   int
+	Offset  int
+}
+
+// ParseQuery reads a query from request parameters: q, limit, offset and
+// one parameter per facet.
+func ParseQuery(params map[string]string) (*Query, *appError.Error) {
+	query := &Query{
+		Text:    strings.TrimSpace(params["q"]),
+		Filters: make(map[string]string),
+		Limit:   DefaultLimit,
+	}
+	if value := params["limit"]; value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 1 || limit > MaxLimit {
+			return nil, appError.NewValidationError(fmt.Sprintf("limit must be a number between 1 and %d.", MaxLimit))
+		}
+		query.Limit = limit
+	}
+	if value := params["offset"]; value != "" {
+		offset, err := strconv.Atoi(value)
+		if err != nil || offset < 0 {
+			return nil, appError.NewValidationError("offset must be a positive number.")
+		}
+		query.Offset = offset
+	}
+	for _, facet := range Facets {
+		if value := strings.TrimSpace(params[facet]); value != "" {
+			query.Filters[facet] = value
+		}
+	}
+	if query.Text == "" && len(query.Filters) == 0 {
+		return nil, appError.NewValidationError("The q parameter or a facet filter is required.")
+	}
+	return query, nil
+}
+
+type Hit struct {
+	Document
+	Score float64 `json:"score"`
+}
+
+type FacetCount struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+type Result struct {
+	Query  string                  `json:"query"`
+	Total  int                     `json:"total"`
+	Hits   []Hit                   `json:"hits"`
+	Facets map[string][]FacetCount `json:"facets"`
+}
+
+// alternative is an index term a query token may match, with the boost of
+// that kind of match.
+type alternative struct {
+	term  string
+	boost float64
+}
+
+// Search ranks the books matching every token of the query. Each token
+// matches its stems in all languages; the last one also matches as a
+// prefix, for search as you type, and a token with no exact match falls
+// back to terms one or two typos away.
+func (ix *Index) Search(query *Query) *Result {
+	tokens := Tokenize(query.Text)
+	if kept := withoutStopWords(tokens); len(kept) > 0 {
+		tokens = kept
+	}
+	prefix := query.Text != "" && !strings.HasSuffix(query.Text, " ")
+
+	var scores map[string]float64
+	if len(tokens) == 0 {
+		scores = make(map[string]float64, len(ix.Documents))
+		for id := range ix.Documents {
+			scores[id] = 0
+		}
+	}
+	for i, token := range tokens {
+		tokenScores := ix.scoreToken(ix.alternatives(token, prefix && i == len(tokens)-1))
+		if scores == nil {
+			scores = tokenScores
+			continue
+		}
+		for id, score := range scores {
+			if tokenScore, ok := tokenScores[id]; ok {
+				scores[id] = score + tokenScore
+			} else {
+				delete(scores, id)
+			}
+		}
+	}
+
+	result := &Result{Query: query.Text, Hits: []Hit{}, Facets: make(map[string][]FacetCount)}
+	var hits []Hit
+	for id, score := range scores {
+		doc := ix.Documents[id]
+		if matchesFilters(doc, query.Filters) {
+			hits = append(hits, Hit{Document: *doc, Score: math.Round(score*1000) / 1000})
+		}
+	}
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		if hits[i].Name != hits[j].Name {
+			return hits[i].Name < hits[j].Name
+		}
+		return hits[i].ID < hits[j].ID
+	})
+
+	result.Total = len(hits)
+	for _, facet := range Facets {
+		result.Facets[facet] = countFacet(hits, facet)
+	}
+	if query.Offset < len(hits) {
+		end := len(hits)
+		if query.Limit > 0 && query.Offset+query.Limit < end {
+			end = query.Offset + query.Limit
+		}
+		result.Hits = hits[query.Offset:end]
+	}
+	return result
+}
+
+func withoutStopWords(tokens []string) []string {
+	kept := make([]string, 0, len(tokens))
+	for _, token := range tokens {
+		if !isStopWord(token) {
+			kept = append(kept, token)
+		}
+	}
+	return kept
+}
+
+func (ix *Index) alternatives(token string, prefix bool) []alternative {
+	var (
+		alternatives []alternative
+		stems        []string
+		exact        bool
+	)
+	for _, language := range Languages {
+		term := Stem(token, language)
+		if len(stems) > 0 && stems[len(stems)-1] == term {
+			continue
+		}
+		stems = append(stems, term)
+		alternatives = append(alternatives, alternative{term: term, boost: 1})
+		if _, ok := ix.Postings[term]; ok {
+			exact = true
+		}
+	}
+	if prefix {
+		for _, term := range ix.withPrefix(token, maxExpansions) {
+			alternatives = append(alternatives, alternative{term: term, boost: boostPrefix})
+		}
+	}
+	if !exact && len(token) >= 4 {
+		maxDistance := 1
+		if len(token) >= 8 {
+			maxDistance = 2
+		}
+		var similar []string
+		for _, stem := range stems {
+			similar = append(similar, ix.similar(stem, maxDistance)...)
+		}
+		if len(similar) > maxExpansions {
+			similar = similar[:maxExpansions]
+		}
+		for _, term := range similar {
+			alternatives = append(alternatives, alternative{term: term, boost: boostTypo})
+		}
+	}
+	return alternatives
+}
+
+// scoreToken scores every book containing one of the alternatives by the
+// best of them.
+func (ix *Index) scoreToken(alternatives []alternative) map[string]float64 {
+	scores := make(map[string]float64)
+	total := float64(len(ix.Documents))
+	averageLength := ix.TotalLength / math.Max(total, 1)
+	for _, alt := range alternatives {
+		postings := ix.Postings[alt.term]
+		frequency := float64(len(postings))
+		idf := math.Log(1 + (total-frequency+0.5)/(frequency+0.5))
+		for id, tf := range postings {
+			norm := 1 - b + b*ix.Documents[id].Length/averageLength
+			score := alt.boost * idf * tf * (k1 + 1) / (tf + k1*norm)
+			if score > scores[id] {
+				scores[id] = score
+			}
+		}
+	}
+	return scores
+}
+
+func matchesFilters(doc *Document, filters map[string]string) bool {
+	for facet, value := range filters {
+		matched := false
+		for _, candidate := range doc.facet(facet) {
+			if strings.EqualFold(candidate, value) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
+
+func countFacet(hits []Hit, facet string) []FacetCount {
+	counts := make(map[string]int)
+	for i := range hits {
+		for _, value := range hits[i].facet(facet) {
+			counts[value]++
+		}
+	}
+	values := make([]FacetCount, 0, len(counts))
+	for value, count := range counts {
+		values = append(values, FacetCount{Value: value, Count: count})
+	}
+	sort.Slice(values, func(i, j int) bool {
+		if values[i].Count != values[j].Count {
+			return values[i].Count > values[j].Count
+		}
+		return values[i].Value < values[j].Value
+	})
+	if len(values) > MaxFacetValues {
+		values = values[:MaxFacetValues]
+	}
+	return values
+}
@@ -0,0 +1,258 @@
+package search_test
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"testing"
+
+	"main/src/books/application/search"
+	"main/src/books/application/stream"
+	"main/src/books/domain/model"
+	appError "main/utils/error"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	repoMock "main/mocks"
+)
+
+type SearchSuite struct {
+	suite.Suite
+	index *search.Index
+	books []model.Book
+}
+
+const (
+	MethodGetBookFileVersion  = "GetBookFileVersion"
+	MethodSaveBookFileVersion = "SaveBookFileVersion"
+	MethodScanBooks           = "ScanBooks"
+)
+
+func (suite *SearchSuite) SetupTest() {
+	suite.books = []model.Book{
+		{
+			ID:           "1",
+			Name:         "The Wizard of Earthsea",
+			Description:  "A young wizard learns the true names of things.",
+			Contributors: []model.Contributor{{Name: "Ursula K. Le Guin", Role: "author"}},
+			Subjects:     []model.Subject{{Heading: "Fantasy"}},
+			Publisher:    "Parnassus",
+		},
+		{
+			ID:           "2",
+			Name:         "Cien años de soledad",
+			Description:  "La historia de la familia Buendía en el pueblo de Macondo.",
+			Contributors: []model.Contributor{{Name: "Gabriel García Márquez", Role: "author"}},
+			Subjects:     []model.Subject{{Heading: "Realismo mágico"}},
+		},
+		{
+			ID:           "3",
+			Name:         "Running Wizards",
+			Description:  "Stories about wizards running a library.",
+			Contributors: []model.Contributor{{Name: "Jane Doe", Role: "author"}},
+			Subjects:     []model.Subject{{Heading: "Fantasy"}, {Heading: "Humor"}},
+		},
+	}
+	suite.index = search.NewIndex()
+	for i := range suite.books {
+		suite.index.Add(&suite.books[i])
+	}
+}
+
+func (suite *SearchSuite) ids(result *search.Result) []string {
+	var ids []string
+	for _, hit := range result.Hits {
+		ids = append(ids, hit.ID)
+	}
+	return ids
+}
+
+func (suite *SearchSuite) TestAnalyze() {
+	suite.Equal([]string{"wizard", "run", "story"}, search.Analyze("The Wizards' running stories", search.LanguageEnglish))
+	suite.Equal([]string{"cien", "anos", "soledad"}, search.Analyze("Cien años de soledad", search.LanguageSpanish))
+	suite.Equal(search.LanguageSpanish, search.DetectLanguage("La historia de la familia en el pueblo"))
+	suite.Equal(search.LanguageEnglish, search.DetectLanguage("The history of the family"))
+}
+
+func (suite *SearchSuite) TestSearchRanksNameMatchesFirst() {
+	result := suite.index.Search(&search.Query{Text: "wizard ", Limit: 10})
+	suite.Equal(2, result.Total)
+	suite.Equal([]string{"3", "1"}, suite.ids(result))
+	suite.Greater(result.Hits[0].Score, 0.0)
+}
+
+func (suite *SearchSuite) TestSearchRequiresEveryToken() {
+	result := suite.index.Search(&search.Query{Text: "wizard library ", Limit: 10})
+	suite.Equal([]string{"3"}, suite.ids(result))
+}
+
+func (suite *SearchSuite) TestSearchSpanishAndAccents() {
+	result := suite.index.Search(&search.Query{Text: "garcia marquez ", Limit: 10})
+	suite.Equal([]string{"2"}, suite.ids(result))
+	suite.Equal(search.LanguageSpanish, result.Hits[0].Language)
+
+	result = suite.index.Search(&search.Query{Text: "historias ", Limit: 10})
+	suite.Equal([]string{"2"}, suite.ids(result))
+}
+
+func (suite *SearchSuite) TestSearchPrefixAndTypos() {
+	result := suite.index.Search(&search.Query{Text: "earths", Limit: 10})
+	suite.Equal([]string{"1"}, suite.ids(result))
+
+	result = suite.index.Search(&search.Query{Text: "wizrad ", Limit: 10})
+	suite.Equal(2, result.Total)
+
+	result = suite.index.Search(&search.Query{Text: "macondo ", Limit: 10})
+	exact := result.Hits[0].Score
+	result = suite.index.Search(&search.Query{Text: "makondo ", Limit: 10})
+	suite.Equal([]string{"2"}, suite.ids(result))
+	suite.Less(result.Hits[0].Score, exact)
+}
+
+func (suite *SearchSuite) TestSearchFacets() {
+	result := suite.index.Search(&search.Query{Filters: map[string]string{search.FacetSubject: "fantasy"}, Limit: 10})
+	suite.Equal([]string{"3", "1"}, suite.ids(result))
+	suite.Equal([]search.FacetCount{{Value: "Fantasy", Count: 2}, {Value: "Humor", Count: 1}}, result.Facets[search.FacetSubject])
+	suite.Equal([]search.FacetCount{{Value: "en", Count: 2}}, result.Facets[search.FacetLanguage])
+
+	result = suite.index.Search(&search.Query{Text: "wizard ", Filters: map[string]string{search.FacetPublisher: "Parnassus"}, Limit: 1})
+	suite.Equal([]string{"1"}, suite.ids(result))
+}
+
+func (suite *SearchSuite) TestSearchPagination() {
+	result := suite.index.Search(&search.Query{Text: "wizard ", Limit: 1, Offset: 1})
+	suite.Equal(2, result.Total)
+	suite.Equal([]string{"1"}, suite.ids(result))
+
+	result = suite.index.Search(&search.Query{Text: "wizard ", Limit: 1, Offset: 5})
+	suite.Empty(result.Hits)
+}
+
+func (suite *SearchSuite) TestRemoveAndReplace() {
+	suite.index.Remove("3")
+	suite.Equal(2, suite.index.Len())
+	suite.Equal([]string{"1"}, suite.ids(suite.index.Search(&search.Query{Text: "wizard ", Limit: 10})))
+
+	book := suite.books[0]
+	book.Name = "Tombs of Atuan"
+	book.Description = ""
+	suite.index.Add(&book)
+	suite.Empty(suite.index.Search(&search.Query{Text: "wizard ", Limit: 10}).Hits)
+	suite.Equal([]string{"1"}, suite.ids(suite.index.Search(&search.Query{Text: "atuan ", Limit: 10})))
+}
+
+func (suite *SearchSuite) TestParseQuery() {
+	query, err := search.ParseQuery(map[string]string{"q": " dune ", "author": "Frank Herbert", "limit": "5"})
+	suite.Nil(err)
+	suite.Equal("dune", query.Text)
+	suite.Equal(5, query.Limit)
+	suite.Equal("Frank Herbert", query.Filters[search.FacetAuthor])
+
+	_, err = search.ParseQuery(map[string]string{})
+	suite.NotNil(err)
+	_, err = search.ParseQuery(map[string]string{"q": "dune", "limit": "1000"})
+	suite.NotNil(err)
+}
+
+func (suite *SearchSuite) TestStoreRoundTrip() {
+	files := new(repoMock.BookFileRepository)
+	files.On(MethodGetBookFileVersion, search.IndexKey).Return(nil, "", appError.NewNotFoundError("missing")).Once()
+	var saved []byte
+	files.On(MethodSaveBookFileVersion, mock.Anything, search.IndexKey, ".gz", "").Run(func(args mock.Arguments) {
+		saved, _ = io.ReadAll(args.Get(0).(*bytes.Reader))
+	}).Return("v1", nil)
+
+	store := search.NewStore(files)
+	suite.Nil(store.Save(), "nothing to save yet")
+	files.AssertNotCalled(suite.T(), MethodSaveBookFileVersion, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+
+	suite.Nil(store.IndexBooks(suite.books...))
+	suite.Nil(store.RemoveBooks("2"))
+	suite.Nil(store.Save())
+
+	files.On(MethodGetBookFileVersion, search.IndexKey).Return(saved, "v1", nil)
+	loaded, err := search.NewStore(files).Load()
+	suite.Nil(err)
+	suite.Equal(2, loaded.Len())
+	suite.Equal([]string{"3", "1"}, suite.ids(loaded.Search(&search.Query{Text: "wizard", Limit: 10})))
+}
+
+func (suite *SearchSuite) TestStoreLoadError() {
+	files := new(repoMock.BookFileRepository)
+	files.On(MethodGetBookFileVersion, search.IndexKey).Return(nil, "", &appError.Error{Code: http.StatusForbidden, Message: "denied"})
+
+	_, err := search.NewStore(files).Load()
+	suite.NotNil(err)
+}
+
+func (suite *SearchSuite) TestStoreReplaysChangesOnAConcurrentSave() {
+	// Another batch indexed book 2 after this one loaded the empty index.
+	other := search.NewIndex()
+	other.Add(&suite.books[1])
+	otherContent, err := other.Marshal()
+	suite.NoError(err)
+
+	files := new(repoMock.BookFileRepository)
+	files.On(MethodGetBookFileVersion, search.IndexKey).Return(nil, "", appError.NewNotFoundError("missing")).Once()
+	files.On(MethodSaveBookFileVersion, mock.Anything, search.IndexKey, ".gz", "").
+		Return("", appError.NewConflictError("changed")).Once()
+	files.On(MethodGetBookFileVersion, search.IndexKey).Return(otherContent, "v1", nil).Once()
+	var saved []byte
+	files.On(MethodSaveBookFileVersion, mock.Anything, search.IndexKey, ".gz", "v1").Run(func(args mock.Arguments) {
+		saved, _ = io.ReadAll(args.Get(0).(*bytes.Reader))
+	}).Return("v2", nil).Once()
+
+	store := search.NewStore(files)
+	suite.Nil(store.IndexBooks(suite.books[0]))
+	suite.Nil(store.Save())
+
+	loaded, errDecode := search.UnmarshalIndex(bytes.NewReader(saved))
+	suite.NoError(errDecode)
+	suite.Equal(2, loaded.Len(), "both batches are kept")
+	files.AssertExpectations(suite.T())
+}
+
+func (suite *SearchSuite) TestRebuildScansAgainWhenTheIndexChangedDuringTheScan() {
+	files := new(repoMock.BookFileRepository)
+	files.On(MethodGetBookFileVersion, search.IndexKey).Return(nil, "", appError.NewNotFoundError("missing")).Once()
+	files.On(MethodSaveBookFileVersion, mock.Anything, search.IndexKey, ".gz", "").
+		Return("", appError.NewConflictError("changed")).Once()
+	files.On(MethodGetBookFileVersion, search.IndexKey).Return([]byte("not an index"), "v1", nil).Once()
+	files.On(MethodSaveBookFileVersion, mock.Anything, search.IndexKey, ".gz", "v1").Return("v2", nil).Once()
+	books := new(repoMock.BookRepository)
+	books.On(MethodScanBooks, 2, mock.Anything).Run(func(args mock.Arguments) {
+		args.Get(1).(func([]model.Book) *appError.Error)(suite.books)
+	}).Return(nil)
+
+	ix, err := search.RebuildStore(books, files, 2)
+	suite.Nil(err)
+	suite.Equal(len(suite.books), ix.Len())
+	books.AssertNumberOfCalls(suite.T(), MethodScanBooks, 2)
+	files.AssertExpectations(suite.T())
+}
+
+func (suite *SearchSuite) TestStreamIndexer() {
+	files := new(repoMock.BookFileRepository)
+	files.On(MethodGetBookFileVersion, search.IndexKey).Return(nil, "", appError.NewNotFoundError("missing"))
+	files.On(MethodSaveBookFileVersion, mock.Anything, search.IndexKey, ".gz", "").Return("v1", nil)
+	store := search.NewStore(files)
+	indexer := search.NewStreamIndexer(store)
+
+	suite.Nil(indexer.HandleChange(&stream.Change{Type: stream.ChangeInsert, BookID: "1", New: &suite.books[0]}))
+	suite.Nil(indexer.HandleChange(&stream.Change{Type: stream.ChangeInsert, BookID: "3", New: &suite.books[2]}))
+	renamed := suite.books[0]
+	renamed.Name = "Tehanu"
+	suite.Nil(indexer.HandleChange(&stream.Change{Type: stream.ChangeModify, BookID: "1", Old: &suite.books[0], New: &renamed}))
+	suite.Nil(indexer.HandleChange(&stream.Change{Type: stream.ChangeRemove, BookID: "3", Old: &suite.books[2]}))
+	suite.Nil(indexer.Flush())
+
+	index, _ := store.Load()
+	suite.Equal(1, index.Len())
+	suite.Equal([]string{"1"}, suite.ids(index.Search(&search.Query{Text: "tehanu", Limit: 10})))
+	files.AssertNumberOfCalls(suite.T(), MethodSaveBookFileVersion, 1)
+}
+
+func TestSearchSuite(t *testing.T) {
+	suite.Run(t, new(SearchSuite))
+}
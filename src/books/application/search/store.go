@@ -0,0 +1,249 @@
+package search
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/gob"
+	"io"
+	"log"
+	"net/http"
+	"sync"
+
+	"main/src/books/domain/model"
+	"main/src/books/domain/repository"
+	appError "main/utils/error"
+)
+
+// IndexKey is where the index is kept in the file store.
+const IndexKey = "search/books.idx.gz"
+
+// DefaultSegments is the number of parallel scan segments of a rebuild.
+const DefaultSegments = 4
+
+// Marshal encodes the index as gzip-compressed gob.
+func (ix *Index) Marshal() ([]byte, error) {
+	var content bytes.Buffer
+	compressor := gzip.NewWriter(&content)
+	if err := gob.NewEncoder(compressor).Encode(ix); err != nil {
+		return nil, err
+	}
+	if err := compressor.Close(); err != nil {
+		return nil, err
+	}
+	return content.Bytes(), nil
+}
+
+func UnmarshalIndex(r io.Reader) (*Index, error) {
+	decompressor, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	ix := NewIndex()
+	if err := gob.NewDecoder(decompressor).Decode(ix); err != nil {
+		return nil, err
+	}
+	return ix, nil
+}
+
+// maxSaveAttempts bounds how often Save replays the changes on an index
+// another writer saved in the meantime.
+const maxSaveAttempts = 5
+
+// maxRebuildAttempts bounds how often a rebuild scans again because a stream
+// batch saved the index during its scan.
+const maxRebuildAttempts = 3
+
+// Store loads the index from the file store once, applies changes in memory
+// and writes it back on Save.
+//
+// Stream batches of different shards and the rebuild all write the same
+// file. Save only replaces the version that was loaded: when another writer
+// saved first, the index is loaded again and the changes replayed on it.
+type Store struct {
+	files   repository.BookFileRepository
+	mu      sync.Mutex
+	index   *Index
+	version string
+	// changes are the updates since the load, kept to be replayed.
+	changes  []func(*Index)
+	replaced bool
+	dirty    bool
+}
+
+func NewStore(files repository.BookFileRepository) *Store {
+	return &Store{
+		files: files,
+	}
+}
+
+// Load returns the stored index, or an empty one when none was saved yet.
+func (s *Store) Load() (*Index, *appError.Error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.load()
+}
+
+func (s *Store) load() (*Index, *appError.Error) {
+	if s.index != nil {
+		return s.index, nil
+	}
+	content, version, errFile := s.files.GetBookFileVersion(IndexKey)
+	if errFile != nil {
+		if errFile.Code != http.StatusNotFound {
+			return nil, errFile
+		}
+		log.Printf("No search index at %s yet, starting an empty one", IndexKey)
+		s.index = NewIndex()
+		s.version = ""
+		return s.index, nil
+	}
+	// The version is kept even if the content does not decode, so a
+	// rebuild can still replace it.
+	s.version = version
+	ix, err := UnmarshalIndex(bytes.NewReader(content))
+	if err != nil {
+		log.Printf("Error decoding search index: %v", err)
+		return nil, appError.NewUnexpectedError(err.Error())
+	}
+	s.index = ix
+	return s.index, nil
+}
+
+// apply runs change on the loaded index and keeps it for a replay.
+func (s *Store) apply(change func(*Index)) *appError.Error {
+	ix, err := s.load()
+	if err != nil {
+		return err
+	}
+	change(ix)
+	s.changes = append(s.changes, change)
+	s.dirty = true
+	return nil
+}
+
+func (s *Store) IndexBooks(books ...model.Book) *appError.Error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(books) == 0 {
+		_, err := s.load()
+		return err
+	}
+	return s.apply(func(ix *Index) {
+		for i := range books {
+			ix.Add(&books[i])
+		}
+	})
+}
+
+func (s *Store) RemoveBooks(bookIDs ...string) *appError.Error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(bookIDs) == 0 {
+		_, err := s.load()
+		return err
+	}
+	return s.apply(func(ix *Index) {
+		for _, bookID := range bookIDs {
+			ix.Remove(bookID)
+		}
+	})
+}
+
+// Replace swaps in a freshly built index, to be written on Save. It only
+// replaces the version loaded before, so load the store before the scan
+// that built ix: a stream batch saved since then makes Save fail with a
+// conflict instead of being lost.
+func (s *Store) Replace(ix *Index) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.index = ix
+	s.changes = nil
+	s.replaced = true
+	s.dirty = true
+}
+
+// Save writes the index back if it changed since it was loaded.
+func (s *Store) Save() *appError.Error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.dirty {
+		return nil
+	}
+	for attempt := 1; ; attempt++ {
+		content, err := s.index.Marshal()
+		if err != nil {
+			log.Printf("Error encoding search index: %v", err)
+			return appError.NewUnexpectedError(err.Error())
+		}
+		version, errFile := s.files.SaveBookFileVersion(bytes.NewReader(content), IndexKey, ".gz", s.version)
+		if errFile == nil {
+			s.version = version
+			s.changes = nil
+			s.replaced = false
+			s.dirty = false
+			log.Printf("Saved search index with %d books", s.index.Len())
+			return nil
+		}
+		// A replaced index has no changes to replay, its scan is stale.
+		if errFile.Code != http.StatusConflict || s.replaced || attempt == maxSaveAttempts {
+			return errFile
+		}
+
+		log.Printf("Search index was saved by another writer, replaying %d changes", len(s.changes))
+		s.index = nil
+		ix, errLoad := s.load()
+		if errLoad != nil {
+			return errLoad
+		}
+		for _, change := range s.changes {
+			change(ix)
+		}
+	}
+}
+
+// Rebuild indexes every book in the repository from scratch.
+func Rebuild(books repository.BookRepository, segments int) (*Index, *appError.Error) {
+	if segments < 1 {
+		segments = DefaultSegments
+	}
+	ix := NewIndex()
+	var mu sync.Mutex
+	errScan := books.ScanBooks(segments, func(page []model.Book) *appError.Error {
+		mu.Lock()
+		defer mu.Unlock()
+		for i := range page {
+			ix.Add(&page[i])
+		}
+		return nil
+	})
+	if errScan != nil {
+		return nil, errScan
+	}
+	return ix, nil
+}
+
+// RebuildStore rebuilds the index and replaces the stored one with it,
+// scanning again when the stored index changed during the scan.
+func RebuildStore(books repository.BookRepository, files repository.BookFileRepository, segments int) (*Index, *appError.Error) {
+	for attempt := 1; ; attempt++ {
+		store := NewStore(files)
+		// An index that does not decode is what the rebuild repairs, only
+		// its version is needed.
+		if _, errLoad := store.Load(); errLoad != nil && store.version == "" {
+			return nil, errLoad
+		}
+		ix, errRebuild := Rebuild(books, segments)
+		if errRebuild != nil {
+			return nil, errRebuild
+		}
+		store.Replace(ix)
+		errSave := store.Save()
+		if errSave == nil {
+			return ix, nil
+		}
+		if errSave.Code != http.StatusConflict || attempt == maxRebuildAttempts {
+			return nil, errSave
+		}
+		log.Printf("Search index was saved during the rebuild, scanning again")
+	}
+}
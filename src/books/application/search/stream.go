@@ -0,0 +1,30 @@
+package search
+
+import (
+	"main/src/books/application/stream"
+	appError "main/utils/error"
+)
+
+// StreamIndexer keeps the index in step with the books table by following
+// its stream. Changes are applied in memory and the index is saved once per
+// batch.
+type StreamIndexer struct {
+	store *Store
+}
+
+func NewStreamIndexer(store *Store) *StreamIndexer {
+	return &StreamIndexer{
+		store: store,
+	}
+}
+
+func (s *StreamIndexer) HandleChange(change *stream.Change) *appError.Error {
+	if change.Removed() {
+		return s.store.RemoveBooks(change.BookID)
+	}
+	return s.store.IndexBooks(*change.New)
+}
+
+func (s *StreamIndexer) Flush() *appError.Error {
+	return s.store.Save()
+}
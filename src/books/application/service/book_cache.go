@@ -0,0 +1,217 @@
+package service
+
+import (
+	"container/list"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"main/src/books/domain/model"
+	"main/src/books/domain/repository"
+	appError "main/utils/error"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// BookCacheStats are the counters of a book cache since it was created.
+type BookCacheStats struct {
+	// Hits are the reads answered by the cache, NegativeHits those of them
+	// that answered the book does not exist.
+	Hits         int64 `json:"hits"`
+	NegativeHits int64 `json:"negative_hits"`
+	// Misses are the reads the in-process cache could not answer;
+	// SharedHits of them were answered by the shared cache and Loads reached
+	// the wrapped service. Concurrent misses of a book share a single load.
+	Misses        int64 `json:"misses"`
+	SharedHits    int64 `json:"shared_hits"`
+	Loads         int64 `json:"loads"`
+	Evictions     int64 `json:"evictions"`
+	Invalidations int64 `json:"invalidations"`
+}
+
+// BookCache keeps the most recently read books in process, each for a TTL,
+// and remembers for a shorter TTL the books that do not exist. It is meant
+// to live as long as the Lambda container, shared by the services built for
+// every invocation; changes made elsewhere show after the TTL at most.
+type BookCache struct {
+	size        int
+	ttl         time.Duration
+	negativeTTL time.Duration
+	shared      repository.BookCacheRepository
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	// order holds the entries, most recently used first.
+	order *list.List
+	// version changes with every invalidation, so a load that started
+	// before it does not store what it read.
+	version uint64
+	loads   singleflight.Group
+
+	hits, negativeHits, misses, sharedHits, loaded, evictions, invalidations atomic.Int64
+}
+
+type bookCacheEntry struct {
+	id string
+	// book is nil when the book does not exist.
+	book      *model.Book
+	expiresAt time.Time
+}
+
+// NewBookCache keeps up to size books for ttl, and the answer that a book
+// does not exist for negativeTTL.
+func NewBookCache(size int, ttl, negativeTTL time.Duration) *BookCache {
+	return &BookCache{
+		size:        size,
+		ttl:         ttl,
+		negativeTTL: negativeTTL,
+		entries:     make(map[string]*list.Element, size),
+		order:       list.New(),
+	}
+}
+
+// WithShared looks the books the process does not have up in shared before
+// loading them, and keeps what it loads there too.
+func (c *BookCache) WithShared(shared repository.BookCacheRepository) *BookCache {
+	c.shared = shared
+	return c
+}
+
+func (c *BookCache) Stats() BookCacheStats {
+	return BookCacheStats{
+		Hits:          c.hits.Load(),
+		NegativeHits:  c.negativeHits.Load(),
+		Misses:        c.misses.Load(),
+		SharedHits:    c.sharedHits.Load(),
+		Loads:         c.loaded.Load(),
+		Evictions:     c.evictions.Load(),
+		Invalidations: c.invalidations.Load(),
+	}
+}
+
+// Invalidate forgets the books, here and in the shared cache, so the next
+// reads load them again.
+func (c *BookCache) Invalidate(ids ...string) {
+	c.mu.Lock()
+	c.version++
+	for _, id := range ids {
+		if element, ok := c.entries[id]; ok {
+			c.order.Remove(element)
+			delete(c.entries, id)
+		}
+		c.loads.Forget(id)
+	}
+	c.mu.Unlock()
+	c.invalidations.Add(int64(len(ids)))
+
+	if c.shared == nil {
+		return
+	}
+	for _, id := range ids {
+		if err := c.shared.DeleteBook(id); err != nil {
+			log.Printf("Error invalidating book in the shared cache: %s, ID: %s", err.ToString(), id)
+		}
+	}
+}
+
+// get returns the cached book, nil when it is known not to exist, and
+// whether the cache had an answer.
+func (c *BookCache) get(id string) (*model.Book, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	element, ok := c.entries[id]
+	if !ok {
+		c.misses.Add(1)
+		return nil, false
+	}
+	entry := element.Value.(*bookCacheEntry)
+	if !time.Now().Before(entry.expiresAt) {
+		c.order.Remove(element)
+		delete(c.entries, id)
+		c.misses.Add(1)
+		return nil, false
+	}
+	c.order.MoveToFront(element)
+	c.hits.Add(1)
+	if entry.book == nil {
+		c.negativeHits.Add(1)
+	}
+	return entry.book, true
+}
+
+// load reads a book the process does not have through the shared cache
+// and then read, once for all the concurrent callers.
+func (c *BookCache) load(id string, read func() (*model.Book, *appError.Error)) (*model.Book, *appError.Error) {
+	value, _, _ := c.loads.Do(id, func() (interface{}, error) {
+		c.mu.Lock()
+		version := c.version
+		c.mu.Unlock()
+
+		if c.shared != nil {
+			book, found, errShared := c.shared.GetBook(id)
+			if errShared != nil {
+				log.Printf("Error reading book from the shared cache: %s, ID: %s", errShared.ToString(), id)
+			} else if found {
+				c.sharedHits.Add(1)
+				c.put(id, book, version)
+				return bookLoad{book: book}, nil
+			}
+		}
+
+		c.loaded.Add(1)
+		book, err := read()
+		if err != nil {
+			return bookLoad{err: err}, nil
+		}
+		if book != nil && book.ID == "" {
+			book = nil
+		}
+		if c.put(id, book, version) && c.shared != nil {
+			if errShared := c.shared.SetBook(id, book, c.entryTTL(book)); errShared != nil {
+				log.Printf("Error writing book to the shared cache: %s, ID: %s", errShared.ToString(), id)
+			}
+		}
+		return bookLoad{book: book}, nil
+	})
+	result := value.(bookLoad)
+	return result.book, result.err
+}
+
+// bookLoad is the outcome of a load shared by concurrent callers.
+type bookLoad struct {
+	book *model.Book
+	err  *appError.Error
+}
+
+// put keeps book unless the cache was invalidated since version was read,
+// evicting the least recently used books beyond the size. It tells whether
+// the book was kept.
+func (c *BookCache) put(id string, book *model.Book, version uint64) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.version != version {
+		return false
+	}
+	entry := &bookCacheEntry{id: id, book: book, expiresAt: time.Now().Add(c.entryTTL(book))}
+	if element, ok := c.entries[id]; ok {
+		element.Value = entry
+		c.order.MoveToFront(element)
+		return true
+	}
+	c.entries[id] = c.order.PushFront(entry)
+	for c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*bookCacheEntry).id)
+		c.evictions.Add(1)
+	}
+	return true
+}
+
+func (c *BookCache) entryTTL(book *model.Book) time.Duration {
+	if book == nil {
+		return c.negativeTTL
+	}
+	return c.ttl
+}
@@ -2,10 +2,13 @@ package service
 
 import (
 	"bytes"
+	"io"
 	appError "main/utils/error"
 )
 
 type BookFileService interface {
 	DeleteBookFile(string) *appError.Error
+	GetBookFile(string) ([]byte, *appError.Error)
 	SaveBookFile(*bytes.Reader, string, string) *appError.Error
+	UploadBookFile(io.Reader, string, string) *appError.Error
 }
@@ -2,6 +2,7 @@ package service
 
 import (
 	"bytes"
+	"io"
 	"main/src/books/domain/repository"
 	appError "main/utils/error"
 )
@@ -20,6 +21,14 @@ func (service *BookFileServiceS3) SaveBookFile(file *bytes.Reader, bucketKey, fi
 	return service.repo.SaveBookFile(file, bucketKey, fileExt)
 }
 
+func (service *BookFileServiceS3) UploadBookFile(file io.Reader, bucketKey, fileExt string) *appError.Error {
+	return service.repo.UploadBookFile(file, bucketKey, fileExt)
+}
+
+func (service *BookFileServiceS3) GetBookFile(bucketKey string) ([]byte, *appError.Error) {
+	return service.repo.GetBookFile(bucketKey)
+}
+
 func (service *BookFileServiceS3) DeleteBookFile(bucketKey string) *appError.Error {
 	return service.repo.DeleteBookFile(bucketKey)
 }
\ No newline at end of file
@@ -0,0 +1,11 @@
+package service
+
+import (
+	"main/src/books/domain/model"
+	appError "main/utils/error"
+)
+
+type BookHistoryService interface {
+	GetBookHistory(string, int, int) ([]model.BookRevision, *appError.Error)
+	RestoreBookRevision(string, int) (*model.Book, *appError.Error)
+}
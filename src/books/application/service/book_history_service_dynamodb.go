@@ -0,0 +1,73 @@
+package service
+
+import (
+	"fmt"
+	"strconv"
+
+	"main/src/books/domain/model"
+	"main/src/books/domain/repository"
+	appError "main/utils/error"
+	"main/utils/lib"
+)
+
+const (
+	DefaultHistoryRevisions = 20
+	MaxHistoryRevisions     = 100
+)
+
+type BookHistoryServiceDynamoDB struct {
+	repo    repository.BookRepository
+	history repository.BookHistoryRepository
+}
+
+func NewBookHistoryServiceDynamoDB(repo repository.BookRepository, history repository.BookHistoryRepository) BookHistoryService {
+	return &BookHistoryServiceDynamoDB{
+		repo:    repo,
+		history: history,
+	}
+}
+
+// GetBookHistory returns up to limit revisions of a book, newest first,
+// starting below revision before when it is set.
+func (service *BookHistoryServiceDynamoDB) GetBookHistory(bookID string, limit, before int) ([]model.BookRevision, *appError.Error) {
+	if err := lib.ValidateUUID(bookID); err != nil {
+		return nil, err
+	}
+	if limit == 0 {
+		limit = DefaultHistoryRevisions
+	}
+	if limit < 1 || limit > MaxHistoryRevisions {
+		return nil, appError.NewValidationError(fmt.Sprintf("limit must be a number between 1 and %d.", MaxHistoryRevisions))
+	}
+	if before < 0 {
+		return nil, appError.NewValidationError("before must be a revision number.")
+	}
+	return service.history.GetBookRevisions(bookID, before, limit)
+}
+
+// RestoreBookRevision brings the book back to its state after revision,
+// recreating it if it was deleted since. The restore is itself a new
+// revision, so it can be undone the same way.
+func (service *BookHistoryServiceDynamoDB) RestoreBookRevision(bookID string, revision int) (*model.Book, *appError.Error) {
+	if err := lib.ValidateUUID(bookID); err != nil {
+		return nil, err
+	}
+	if revision < 1 {
+		return nil, appError.NewValidationError("revision must be a positive number.")
+	}
+	bookRevision, err := service.history.GetBookRevision(bookID, revision)
+	if err != nil {
+		return nil, err
+	}
+	if bookRevision.Book == nil {
+		return nil, appError.NewValidationError("Revision " + strconv.Itoa(revision) + " deleted the book, restore an earlier one.")
+	}
+
+	book := *bookRevision.Book
+	book.ID = bookID
+	book.UpdatedAt = now()
+	if err := service.repo.RestoreBook(&book); err != nil {
+		return nil, err
+	}
+	return &book, nil
+}
@@ -0,0 +1,90 @@
+package service_test
+
+import (
+	"testing"
+
+	"main/src/books/application/service"
+	"main/src/books/domain/model"
+	appError "main/utils/error"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	repoMock "main/mocks"
+)
+
+type BookHistoryServiceDynamoDBSuite struct {
+	suite.Suite
+	bookRepository    *repoMock.BookRepository
+	historyRepository *repoMock.BookHistoryRepository
+	historyService    service.BookHistoryService
+	bookID            string
+}
+
+const (
+	MethodGetBookRevisions = "GetBookRevisions"
+	MethodGetBookRevision  = "GetBookRevision"
+	MethodRestoreBook      = "RestoreBook"
+)
+
+func (suite *BookHistoryServiceDynamoDBSuite) SetupTest() {
+	suite.bookRepository = new(repoMock.BookRepository)
+	suite.historyRepository = new(repoMock.BookHistoryRepository)
+	suite.historyService = service.NewBookHistoryServiceDynamoDB(suite.bookRepository, suite.historyRepository)
+	suite.bookID = uuid.NewString()
+}
+
+func (suite *BookHistoryServiceDynamoDBSuite) TestGetBookHistory() {
+	revisions := []model.BookRevision{{BookID: suite.bookID, Revision: 2}, {BookID: suite.bookID, Revision: 1}}
+	suite.historyRepository.On(MethodGetBookRevisions, suite.bookID, 0, service.DefaultHistoryRevisions).Return(revisions, nil)
+	history, err := suite.historyService.GetBookHistory(suite.bookID, 0, 0)
+	suite.Nil(err)
+	suite.Equal(revisions, history)
+	suite.historyRepository.AssertExpectations(suite.T())
+}
+
+func (suite *BookHistoryServiceDynamoDBSuite) TestGetBookHistoryInvalidParameters() {
+	_, err := suite.historyService.GetBookHistory("invalid", 10, 0)
+	suite.NotNil(err)
+	_, err = suite.historyService.GetBookHistory(suite.bookID, service.MaxHistoryRevisions+1, 0)
+	suite.NotNil(err)
+	_, err = suite.historyService.GetBookHistory(suite.bookID, 10, -1)
+	suite.NotNil(err)
+	suite.historyRepository.AssertNotCalled(suite.T(), MethodGetBookRevisions, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *BookHistoryServiceDynamoDBSuite) TestRestoreBookRevision() {
+	snapshot := &model.Book{ID: suite.bookID, Name: "Original name", Description: "Before the vandalism", Revision: 3}
+	suite.historyRepository.On(MethodGetBookRevision, suite.bookID, 3).Return(&model.BookRevision{BookID: suite.bookID, Revision: 3, Book: snapshot}, nil)
+	suite.bookRepository.On(MethodRestoreBook, mock.MatchedBy(func(book *model.Book) bool {
+		return book.Name == "Original name" && book.UpdatedAt != nil
+	})).Return(nil)
+
+	restored, err := suite.historyService.RestoreBookRevision(suite.bookID, 3)
+	suite.Nil(err)
+	suite.Equal("Before the vandalism", restored.Description)
+	suite.NotNil(restored.UpdatedAt)
+	suite.Nil(snapshot.UpdatedAt, "The revision is left untouched")
+	suite.bookRepository.AssertExpectations(suite.T())
+}
+
+func (suite *BookHistoryServiceDynamoDBSuite) TestRestoreDeletion() {
+	suite.historyRepository.On(MethodGetBookRevision, suite.bookID, 4).Return(&model.BookRevision{BookID: suite.bookID, Revision: 4, Operation: model.BookOperationDeleted}, nil)
+	_, err := suite.historyService.RestoreBookRevision(suite.bookID, 4)
+	suite.NotNil(err)
+	suite.bookRepository.AssertNotCalled(suite.T(), MethodRestoreBook, mock.Anything)
+}
+
+func (suite *BookHistoryServiceDynamoDBSuite) TestRestoreMissingRevision() {
+	suite.historyRepository.On(MethodGetBookRevision, suite.bookID, 9).Return(nil, appError.NewNotFoundError("Revision 9 not found."))
+	_, err := suite.historyService.RestoreBookRevision(suite.bookID, 9)
+	suite.Equal(404, err.Code)
+
+	_, err = suite.historyService.RestoreBookRevision(suite.bookID, 0)
+	suite.NotNil(err)
+}
+
+func TestBookHistoryServiceDynamoDBSuite(t *testing.T) {
+	suite.Run(t, new(BookHistoryServiceDynamoDBSuite))
+}
@@ -9,8 +9,15 @@ import (
 type BookService interface {
 	GetAllBooks() ([]model.Book, *appError.Error)
 	CreateBook(*model.Book) (*model.Book, *appError.Error)
-	CreateBatchBooks([]model.Book) *appError.Error
+	CreateBatchBooks([]model.Book) (*model.BatchReport, *appError.Error)
 	GetBookByID(string) (*model.Book, *appError.Error)
+	GetBooksByIDs([]string) (*model.BooksByIDs, *appError.Error)
 	UpdateBookByID(string, *model.Book) (*model.Book, *appError.Error)
 	DeleteBookByID(string) *appError.Error
+	UpdateBooks([]model.Book) (*model.BatchReport, *appError.Error)
+	DeleteBooksByIDs([]string) (*model.BatchReport, *appError.Error)
+	GetRecentBooks(int) ([]model.Book, *appError.Error)
+	GetTrashedBooks(int) ([]model.Book, *appError.Error)
+	RestoreTrashedBook(string) *appError.Error
+	PurgeBook(string) *appError.Error
 }
@@ -0,0 +1,108 @@
+package service
+
+import (
+	"main/src/books/domain/model"
+	appError "main/utils/error"
+)
+
+// BookServiceCached reads books by ID through a BookCache and invalidates
+// the books it changes. Changes must go through the same decorator, or
+// through Invalidate, for reads not to return stale books until the TTL.
+type BookServiceCached struct {
+	BookService
+	cache *BookCache
+}
+
+// NewBookServiceCached wraps service with cache. Every other method than
+// GetBookByID is passed through.
+func NewBookServiceCached(service BookService, cache *BookCache) BookService {
+	return &BookServiceCached{
+		BookService: service,
+		cache:       cache,
+	}
+}
+
+// GetBookByID returns the cached book, or loads it once for all the
+// concurrent callers. Like the wrapped service, it returns an empty book
+// when there is none, which is cached too.
+func (service *BookServiceCached) GetBookByID(bookID string) (*model.Book, *appError.Error) {
+	book, ok := service.cache.get(bookID)
+	if !ok {
+		var err *appError.Error
+		book, err = service.cache.load(bookID, func() (*model.Book, *appError.Error) {
+			return service.BookService.GetBookByID(bookID)
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	if book == nil {
+		return &model.Book{}, nil
+	}
+	// Callers get their own copy of the cached book, lists included.
+	return book.Clone(), nil
+}
+
+func (service *BookServiceCached) CreateBook(book *model.Book) (*model.Book, *appError.Error) {
+	created, err := service.BookService.CreateBook(book)
+	// The book may have been cached as missing.
+	service.cache.Invalidate(book.ID)
+	return created, err
+}
+
+func (service *BookServiceCached) CreateBatchBooks(books []model.Book) (*model.BatchReport, *appError.Error) {
+	report, err := service.BookService.CreateBatchBooks(books)
+	service.cache.Invalidate(reportedIDs(report)...)
+	return report, err
+}
+
+func (service *BookServiceCached) UpdateBookByID(bookID string, book *model.Book) (*model.Book, *appError.Error) {
+	updated, err := service.BookService.UpdateBookByID(bookID, book)
+	service.cache.Invalidate(bookID)
+	return updated, err
+}
+
+func (service *BookServiceCached) DeleteBookByID(bookID string) *appError.Error {
+	err := service.BookService.DeleteBookByID(bookID)
+	service.cache.Invalidate(bookID)
+	return err
+}
+
+func (service *BookServiceCached) UpdateBooks(books []model.Book) (*model.BatchReport, *appError.Error) {
+	report, err := service.BookService.UpdateBooks(books)
+	service.cache.Invalidate(reportedIDs(report)...)
+	return report, err
+}
+
+func (service *BookServiceCached) DeleteBooksByIDs(bookIDs []string) (*model.BatchReport, *appError.Error) {
+	report, err := service.BookService.DeleteBooksByIDs(bookIDs)
+	service.cache.Invalidate(bookIDs...)
+	return report, err
+}
+
+func (service *BookServiceCached) RestoreTrashedBook(bookID string) *appError.Error {
+	err := service.BookService.RestoreTrashedBook(bookID)
+	service.cache.Invalidate(bookID)
+	return err
+}
+
+func (service *BookServiceCached) PurgeBook(bookID string) *appError.Error {
+	err := service.BookService.PurgeBook(bookID)
+	service.cache.Invalidate(bookID)
+	return err
+}
+
+// reportedIDs are the IDs of the items of a batch report, which include the
+// IDs the service generated.
+func reportedIDs(report *model.BatchReport) []string {
+	if report == nil {
+		return nil
+	}
+	ids := make([]string, 0, len(report.Items))
+	for _, item := range report.Items {
+		if item.ID != "" {
+			ids = append(ids, item.ID)
+		}
+	}
+	return ids
+}
@@ -0,0 +1,177 @@
+package service_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"main/src/books/application/service"
+	"main/src/books/domain/model"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	repoMock "main/mocks"
+)
+
+type BookServiceCachedSuite struct {
+	suite.Suite
+	inner       *repoMock.BookService
+	cache       *service.BookCache
+	bookService service.BookService
+	testBook    *model.Book
+}
+
+func (suite *BookServiceCachedSuite) SetupTest() {
+	suite.inner = new(repoMock.BookService)
+	suite.cache = service.NewBookCache(2, time.Minute, time.Minute)
+	suite.bookService = service.NewBookServiceCached(suite.inner, suite.cache)
+	suite.testBook = &model.Book{ID: uuid.NewString(), Name: "Test Book"}
+}
+
+func (suite *BookServiceCachedSuite) TestGetBookByIDIsReadThrough() {
+	suite.inner.On(MethodGetBookByID, suite.testBook.ID).Return(suite.testBook, nil).Once()
+
+	for i := 0; i < 3; i++ {
+		book, err := suite.bookService.GetBookByID(suite.testBook.ID)
+		suite.Nil(err)
+		suite.Equal(suite.testBook.Name, book.Name)
+		book.Name = "Changed by the caller"
+	}
+	suite.inner.AssertExpectations(suite.T())
+	suite.Equal(service.BookCacheStats{Hits: 2, Misses: 1, Loads: 1}, suite.cache.Stats())
+}
+
+func (suite *BookServiceCachedSuite) TestCallersDoNotShareTheListsOfTheCachedBook() {
+	suite.testBook.Contributors = []model.Contributor{{Name: "Frank Herbert", Role: "author"}}
+	suite.testBook.Prices = []model.Price{{Amount: "9.99", Currency: "USD"}}
+	suite.inner.On(MethodGetBookByID, suite.testBook.ID).Return(suite.testBook, nil).Once()
+
+	book, err := suite.bookService.GetBookByID(suite.testBook.ID)
+	suite.Nil(err)
+	book.Contributors[0].Name = "Changed by the caller"
+	book.Prices[0].Amount = "0.00"
+
+	cached, err := suite.bookService.GetBookByID(suite.testBook.ID)
+	suite.Nil(err)
+	suite.Equal("Frank Herbert", cached.Contributors[0].Name)
+	suite.Equal("9.99", cached.Prices[0].Amount)
+}
+
+func (suite *BookServiceCachedSuite) TestMissingBooksAreCached() {
+	missingID := uuid.NewString()
+	suite.inner.On(MethodGetBookByID, missingID).Return(&model.Book{}, nil).Once()
+
+	for i := 0; i < 2; i++ {
+		book, err := suite.bookService.GetBookByID(missingID)
+		suite.Nil(err)
+		suite.Empty(book.ID)
+	}
+	suite.inner.AssertExpectations(suite.T())
+	suite.Equal(int64(1), suite.cache.Stats().NegativeHits)
+}
+
+func (suite *BookServiceCachedSuite) TestChangesInvalidate() {
+	suite.inner.On(MethodGetBookByID, suite.testBook.ID).Return(suite.testBook, nil).Times(3)
+	suite.inner.On(MethodUpdateBookByID, suite.testBook.ID, mock.Anything).Return(suite.testBook, nil)
+	suite.inner.On(MethodDeleteBooksByIDs, []string{suite.testBook.ID}).Return(&model.BatchReport{}, nil)
+
+	suite.bookService.GetBookByID(suite.testBook.ID)
+	suite.bookService.UpdateBookByID(suite.testBook.ID, &model.Book{Name: "Updated"})
+	suite.bookService.GetBookByID(suite.testBook.ID)
+	suite.bookService.DeleteBooksByIDs([]string{suite.testBook.ID})
+	suite.bookService.GetBookByID(suite.testBook.ID)
+
+	suite.Equal(int64(3), suite.cache.Stats().Loads)
+	suite.Equal(int64(2), suite.cache.Stats().Invalidations)
+}
+
+func (suite *BookServiceCachedSuite) TestCreateForgetsMissingBook() {
+	suite.inner.On(MethodGetBookByID, suite.testBook.ID).Return(&model.Book{}, nil).Once()
+	suite.inner.On(MethodCreateBook, suite.testBook).Return(suite.testBook, nil)
+	suite.inner.On(MethodGetBookByID, suite.testBook.ID).Return(suite.testBook, nil).Once()
+
+	suite.bookService.GetBookByID(suite.testBook.ID)
+	suite.bookService.CreateBook(suite.testBook)
+	book, err := suite.bookService.GetBookByID(suite.testBook.ID)
+	suite.Nil(err)
+	suite.Equal(suite.testBook.ID, book.ID)
+}
+
+func (suite *BookServiceCachedSuite) TestLeastRecentlyUsedIsEvicted() {
+	ids := []string{uuid.NewString(), uuid.NewString(), uuid.NewString()}
+	for _, id := range ids {
+		suite.inner.On(MethodGetBookByID, id).Return(&model.Book{ID: id}, nil)
+	}
+
+	suite.bookService.GetBookByID(ids[0])
+	suite.bookService.GetBookByID(ids[1])
+	suite.bookService.GetBookByID(ids[0])
+	suite.bookService.GetBookByID(ids[2])
+	suite.bookService.GetBookByID(ids[0])
+	suite.bookService.GetBookByID(ids[1])
+
+	suite.inner.AssertNumberOfCalls(suite.T(), MethodGetBookByID, 4)
+	suite.Equal(int64(2), suite.cache.Stats().Evictions)
+}
+
+func (suite *BookServiceCachedSuite) TestExpiredBooksAreLoadedAgain() {
+	suite.bookService = service.NewBookServiceCached(suite.inner, service.NewBookCache(2, time.Millisecond, time.Millisecond))
+	suite.inner.On(MethodGetBookByID, suite.testBook.ID).Return(suite.testBook, nil)
+
+	suite.bookService.GetBookByID(suite.testBook.ID)
+	time.Sleep(2 * time.Millisecond)
+	suite.bookService.GetBookByID(suite.testBook.ID)
+
+	suite.inner.AssertNumberOfCalls(suite.T(), MethodGetBookByID, 2)
+}
+
+func (suite *BookServiceCachedSuite) TestConcurrentMissesLoadOnce() {
+	release := make(chan time.Time)
+	suite.inner.On(MethodGetBookByID, suite.testBook.ID).WaitUntil(release).Return(suite.testBook, nil).Once()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			book, err := suite.bookService.GetBookByID(suite.testBook.ID)
+			suite.Nil(err)
+			suite.Equal(suite.testBook.ID, book.ID)
+		}()
+	}
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	suite.inner.AssertExpectations(suite.T())
+	suite.Equal(int64(1), suite.cache.Stats().Loads)
+}
+
+func (suite *BookServiceCachedSuite) TestSharedCache() {
+	shared := new(repoMock.BookCacheRepository)
+	cache := service.NewBookCache(2, time.Minute, time.Second).WithShared(shared)
+	suite.bookService = service.NewBookServiceCached(suite.inner, cache)
+	missingID := uuid.NewString()
+	shared.On("GetBook", suite.testBook.ID).Return(suite.testBook, true, nil)
+	shared.On("GetBook", missingID).Return(nil, false, nil)
+	shared.On("SetBook", missingID, (*model.Book)(nil), time.Second).Return(nil)
+	shared.On("DeleteBook", suite.testBook.ID).Return(nil)
+	suite.inner.On(MethodGetBookByID, missingID).Return(&model.Book{}, nil).Once()
+	suite.inner.On(MethodDeleteBookByID, suite.testBook.ID).Return(nil)
+
+	book, _ := suite.bookService.GetBookByID(suite.testBook.ID)
+	suite.Equal(suite.testBook.Name, book.Name)
+	book, _ = suite.bookService.GetBookByID(missingID)
+	suite.Empty(book.ID)
+	suite.bookService.DeleteBookByID(suite.testBook.ID)
+
+	shared.AssertExpectations(suite.T())
+	suite.inner.AssertExpectations(suite.T())
+	suite.Equal(int64(1), cache.Stats().SharedHits)
+}
+
+func TestBookServiceCachedSuite(t *testing.T) {
+	suite.Run(t, new(BookServiceCachedSuite))
+}
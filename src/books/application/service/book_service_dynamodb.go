@@ -1,7 +1,9 @@
 package service
 
 import (
-	"sync"
+	"fmt"
+	"net/http"
+	"time"
 	"github.com/google/uuid"
 	"main/src/books/domain/model"
 	"main/src/books/domain/repository"
@@ -9,8 +11,22 @@ import (
 	"main/utils/lib"
 )
 
+const (
+	MaxBatchGetIDs  = 1000
+	MaxRecentBooks  = 100
+	MaxTrashedBooks = 100
+)
+
+// now is truncated to whole seconds so stored timestamps sort lexicographically.
+func now() *time.Time {
+	at := time.Now().UTC().Truncate(time.Second)
+	return &at
+}
+
 type BookServiceDynamoDB struct {
-	repo repository.BookRepository
+	repo      repository.BookRepository
+	files     BookFileService
+	bucketKey string
 }
 
 func NewBookServiceDynamoDB(repo repository.BookRepository) BookService {
@@ -19,8 +35,25 @@ func NewBookServiceDynamoDB(repo repository.BookRepository) BookService {
 	}
 }
 
+// NewBookServiceDynamoDBWithFiles also removes the images stored under
+// bucketKey when batch updates replace them.
+func NewBookServiceDynamoDBWithFiles(repo repository.BookRepository, files BookFileService, bucketKey string) BookService {
+	return &BookServiceDynamoDB{
+		repo:      repo,
+		files:     files,
+		bucketKey: bucketKey,
+	}
+}
+
 func (service *BookServiceDynamoDB) GetAllBooks() ([]model.Book, *appError.Error) {
-	return service.repo.GetAllBooks()
+	books, err := service.repo.GetAllBooks()
+	if err != nil {
+		return nil, err
+	}
+	for i := range books {
+		books[i].SummarizeRating()
+	}
+	return books, nil
 }
 
 func (service *BookServiceDynamoDB) CreateBook(book *model.Book) (*model.Book, *appError.Error) {
@@ -30,42 +63,97 @@ func (service *BookServiceDynamoDB) CreateBook(book *model.Book) (*model.Book, *
 	if err := book.Validate(); err != nil {
 		return nil, err
 	}
+	book.CreatedAt = now()
+	book.UpdatedAt = book.CreatedAt
 	return service.repo.CreateBook(book)
 }
 
-func (service *BookServiceDynamoDB) CreateBatchBooks(books []model.Book) *appError.Error {
-	var wg sync.WaitGroup
-	errorChan := make(chan *appError.Error, len(books))
+// CreateBatchBooks validates every book, writes the valid ones and reports
+// the outcome of each item in request order.
+func (service *BookServiceDynamoDB) CreateBatchBooks(books []model.Book) (*model.BatchReport, *appError.Error) {
+	if len(books) == 0 {
+		return nil, appError.NewBadRequestError("At least one book is required.")
+	}
+
+	items := make([]model.BatchItemResult, len(books))
+	valid := make([]model.Book, 0, len(books))
+	seen := make(map[string]bool, len(books))
+	createdAt := now()
 	for i, book := range books {
-		wg.Add(1)
 		if book.ID == "" {
 			book.ID = uuid.NewString()
 		}
+		book.CreatedAt, book.UpdatedAt = createdAt, createdAt
 		books[i] = book
-		go func(b model.Book) {
-			defer wg.Done()
-			if err := b.Validate(); err != nil {
-				errorChan <- err
-				return
+		items[i] = model.BatchItemResult{Index: i, ID: book.ID, Status: model.BatchItemCreated}
+
+		var messages []string
+		for _, err := range book.ValidateAll() {
+			messages = append(messages, err.ToString())
+		}
+		if seen[book.ID] {
+			messages = append(messages, "Duplicate ID in batch.")
+		}
+		seen[book.ID] = true
+		if len(messages) > 0 {
+			items[i].Status = model.BatchItemInvalid
+			items[i].Errors = messages
+			continue
+		}
+		valid = append(valid, book)
+	}
+
+	var failed map[string]*appError.Error
+	if len(valid) > 0 {
+		failed = service.repo.CreateBatchBooks(valid)
+	}
+
+	report := &model.BatchReport{Items: make([]model.BatchItemResult, 0, len(items))}
+	for _, item := range items {
+		if reason, ok := failed[item.ID]; ok && item.Status == model.BatchItemCreated {
+			item.Status = model.BatchItemFailed
+			if reason.Code == http.StatusConflict {
+				item.Status = model.BatchItemConflict
 			}
-			errorChan <- nil
-		}(book)
-	}
-	wg.Wait()
-	close(errorChan)
-	for err := range errorChan {
-		if err != nil {
-			return err
+			item.Errors = []string{reason.ToString()}
 		}
+		report.Add(item)
 	}
-	return service.repo.CreateBatchBooks(books)
+	return report, nil
 }
 
 func (service *BookServiceDynamoDB) GetBookByID(bookID string) (*model.Book, *appError.Error) {
 	if err := lib.ValidateUUID(bookID); err != nil {
 		return nil, err
 	}
-	return service.repo.GetBookByID(bookID)
+	book, err := service.repo.GetBookByID(bookID)
+	if err != nil {
+		return nil, err
+	}
+	book.SummarizeRating()
+	return book, nil
+}
+
+func (service *BookServiceDynamoDB) GetBooksByIDs(bookIDs []string) (*model.BooksByIDs, *appError.Error) {
+	if len(bookIDs) == 0 {
+		return nil, appError.NewValidationError("At least one book ID is required.")
+	}
+	if len(bookIDs) > MaxBatchGetIDs {
+		return nil, appError.NewValidationError(fmt.Sprintf("Cannot request more than %d books at once.", MaxBatchGetIDs))
+	}
+	for _, bookID := range bookIDs {
+		if err := lib.ValidateUUID(bookID); err != nil {
+			return nil, err
+		}
+	}
+	result, err := service.repo.GetBooksByIDs(bookIDs)
+	if err != nil {
+		return nil, err
+	}
+	for i := range result.Books {
+		result.Books[i].SummarizeRating()
+	}
+	return result, nil
 }
 
 func (service *BookServiceDynamoDB) UpdateBookByID(bookID string, book *model.Book) (*model.Book, *appError.Error) {
@@ -75,12 +163,214 @@ func (service *BookServiceDynamoDB) UpdateBookByID(bookID string, book *model.Bo
 	if err := book.Validate(); err != nil {
 		return nil, err
 	}
+	book.UpdatedAt = now()
 	return service.repo.UpdateBookByID(bookID, book)
 }
 
+// DeleteBookByID moves the book to the trash. Its image is kept until the
+// book is purged.
 func (service *BookServiceDynamoDB) DeleteBookByID(bookID string) *appError.Error {
 	if err := lib.ValidateUUID(bookID); err != nil {
 		return err
 	}
 	return service.repo.DeleteBookByID(bookID)
 }
+
+// UpdateBooks replaces the fields of every existing book and reports the
+// outcome of each item in request order. When a book's image changes, the
+// previous file is removed from the bucket.
+func (service *BookServiceDynamoDB) UpdateBooks(books []model.Book) (*model.BatchReport, *appError.Error) {
+	if len(books) == 0 {
+		return nil, appError.NewBadRequestError("At least one book is required.")
+	}
+
+	items := make([]model.BatchItemResult, len(books))
+	ids := make([]string, 0, len(books))
+	seen := make(map[string]bool, len(books))
+	for i, book := range books {
+		items[i] = model.BatchItemResult{Index: i, ID: book.ID, Status: model.BatchItemUpdated}
+		var messages []string
+		for _, err := range book.ValidateAll() {
+			messages = append(messages, err.ToString())
+		}
+		if seen[book.ID] {
+			messages = append(messages, "Duplicate ID in batch.")
+		}
+		seen[book.ID] = true
+		if len(messages) > 0 {
+			items[i].Status = model.BatchItemInvalid
+			items[i].Errors = messages
+			continue
+		}
+		ids = append(ids, book.ID)
+	}
+
+	previous, err := service.existingBooks(ids)
+	if err != nil {
+		return nil, err
+	}
+	toUpdate := make([]model.Book, 0, len(previous))
+	updatedAt := now()
+	for i, book := range books {
+		if _, ok := previous[book.ID]; ok && items[i].Status == model.BatchItemUpdated {
+			book.UpdatedAt = updatedAt
+			toUpdate = append(toUpdate, book)
+		}
+	}
+	var failed map[string]*appError.Error
+	if len(toUpdate) > 0 {
+		failed = service.repo.UpdateBooks(toUpdate)
+	}
+
+	report := &model.BatchReport{Items: make([]model.BatchItemResult, 0, len(items))}
+	for i, item := range items {
+		if item.Status == model.BatchItemUpdated {
+			old, exists := previous[item.ID]
+			if reason, ok := failed[item.ID]; ok {
+				exists = reason.Code != http.StatusNotFound
+				item.Status = model.BatchItemFailed
+				item.Errors = []string{reason.ToString()}
+			}
+			if !exists {
+				item.Status = model.BatchItemNotFound
+				item.Errors = []string{"Book " + item.ID + " not found."}
+			}
+			if item.Status == model.BatchItemUpdated && old.ImgURL != books[i].ImgURL {
+				item.Errors = service.removeImage(&old)
+			}
+		}
+		report.Add(item)
+	}
+	return report, nil
+}
+
+// DeleteBooksByIDs moves every existing book to the trash and reports the
+// outcome of each ID in request order. Images are kept until the books are
+// purged.
+func (service *BookServiceDynamoDB) DeleteBooksByIDs(bookIDs []string) (*model.BatchReport, *appError.Error) {
+	if len(bookIDs) == 0 {
+		return nil, appError.NewBadRequestError("At least one book ID is required.")
+	}
+
+	items := make([]model.BatchItemResult, len(bookIDs))
+	ids := make([]string, 0, len(bookIDs))
+	seen := make(map[string]bool, len(bookIDs))
+	for i, bookID := range bookIDs {
+		items[i] = model.BatchItemResult{Index: i, ID: bookID, Status: model.BatchItemDeleted}
+		if err := lib.ValidateUUID(bookID); err != nil {
+			items[i].Status = model.BatchItemInvalid
+			items[i].Errors = []string{err.ToString()}
+			continue
+		}
+		if seen[bookID] {
+			items[i].Status = model.BatchItemInvalid
+			items[i].Errors = []string{"Duplicate ID in batch."}
+			continue
+		}
+		seen[bookID] = true
+		ids = append(ids, bookID)
+	}
+
+	existing, err := service.existingBooks(ids)
+	if err != nil {
+		return nil, err
+	}
+	toDelete := make([]string, 0, len(existing))
+	for _, bookID := range ids {
+		if _, ok := existing[bookID]; ok {
+			toDelete = append(toDelete, bookID)
+		}
+	}
+	var failed map[string]*appError.Error
+	if len(toDelete) > 0 {
+		failed = service.repo.DeleteBooksByIDs(toDelete)
+	}
+
+	report := &model.BatchReport{Items: make([]model.BatchItemResult, 0, len(items))}
+	for _, item := range items {
+		if item.Status == model.BatchItemDeleted {
+			if _, exists := existing[item.ID]; !exists {
+				item.Status = model.BatchItemNotFound
+				item.Errors = []string{"Book " + item.ID + " not found."}
+			} else if reason, ok := failed[item.ID]; ok {
+				item.Status = model.BatchItemFailed
+				item.Errors = []string{reason.ToString()}
+			}
+		}
+		report.Add(item)
+	}
+	return report, nil
+}
+
+// GetRecentBooks returns the most recently added or updated books, newest
+// first.
+func (service *BookServiceDynamoDB) GetRecentBooks(limit int) ([]model.Book, *appError.Error) {
+	if limit < 1 || limit > MaxRecentBooks {
+		return nil, appError.NewValidationError(fmt.Sprintf("Limit must be between 1 and %d.", MaxRecentBooks))
+	}
+	books, err := service.repo.GetRecentBooks(limit)
+	if err != nil {
+		return nil, err
+	}
+	for i := range books {
+		books[i].SummarizeRating()
+	}
+	return books, nil
+}
+
+// GetTrashedBooks returns the books waiting in the trash, most recently
+// deleted first.
+func (service *BookServiceDynamoDB) GetTrashedBooks(limit int) ([]model.Book, *appError.Error) {
+	if limit < 1 || limit > MaxTrashedBooks {
+		return nil, appError.NewValidationError(fmt.Sprintf("Limit must be between 1 and %d.", MaxTrashedBooks))
+	}
+	return service.repo.GetTrashedBooks(limit)
+}
+
+// RestoreTrashedBook takes a book out of the trash as it was deleted.
+func (service *BookServiceDynamoDB) RestoreTrashedBook(bookID string) *appError.Error {
+	if err := lib.ValidateUUID(bookID); err != nil {
+		return err
+	}
+	return service.repo.RestoreTrashedBook(bookID)
+}
+
+// PurgeBook deletes a book of the trash for good without waiting for the
+// retention period. Its image is removed by the books table stream.
+func (service *BookServiceDynamoDB) PurgeBook(bookID string) *appError.Error {
+	if err := lib.ValidateUUID(bookID); err != nil {
+		return err
+	}
+	return service.repo.PurgeBook(bookID)
+}
+
+func (service *BookServiceDynamoDB) existingBooks(bookIDs []string) (map[string]model.Book, *appError.Error) {
+	existing := make(map[string]model.Book, len(bookIDs))
+	if len(bookIDs) == 0 {
+		return existing, nil
+	}
+	found, err := service.repo.GetBooksByIDs(bookIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, book := range found.Books {
+		existing[book.ID] = book
+	}
+	return existing, nil
+}
+
+// removeImage deletes the book's stored image. A failure does not undo the
+// record change, so it is returned as a warning for the item.
+func (service *BookServiceDynamoDB) removeImage(book *model.Book) []string {
+	if service.files == nil {
+		return nil
+	}
+	key := book.ImageKey(service.bucketKey)
+	if key == "" {
+		return nil
+	}
+	if err := service.files.DeleteBookFile(key); err != nil {
+		return []string{"Image could not be removed: " + err.ToString()}
+	}
+	return nil
+}
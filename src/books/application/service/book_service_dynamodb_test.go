@@ -1,12 +1,16 @@
 package service_test
 
 import (
+	"net/http"
 	"testing"
+	"time"
 
 	"main/src/books/application/service"
 	"main/src/books/domain/model"
+	appError "main/utils/error"
 
 	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 
 	repoMock "main/mocks"
@@ -25,8 +29,16 @@ const (
 	MethodCreateBook       = "CreateBook"
 	MethodCreateBatchBooks = "CreateBatchBooks"
 	MethodGetBookByID      = "GetBookByID"
+	MethodGetBooksByIDs    = "GetBooksByIDs"
 	MethodUpdateBookByID   = "UpdateBookByID"
 	MethodDeleteBookByID   = "DeleteBookByID"
+	MethodUpdateBooks      = "UpdateBooks"
+	MethodDeleteBooksByIDs = "DeleteBooksByIDs"
+	MethodDeleteBookFile   = "DeleteBookFile"
+	MethodGetRecentBooks   = "GetRecentBooks"
+	MethodGetTrashedBooks  = "GetTrashedBooks"
+	MethodRestoreTrashed   = "RestoreTrashedBook"
+	MethodPurgeBook        = "PurgeBook"
 )
 
 func (suite *BookServiceDynamoDBSuite) SetupTest() {
@@ -47,6 +59,8 @@ func (suite *BookServiceDynamoDBSuite) TestCreateBook() {
 	suite.Nil(err)
 	suite.NotEmpty(createdBook.ID)
 	suite.Equal(createdBook.Name, suite.testBook.Name)
+	suite.NotNil(createdBook.CreatedAt)
+	suite.Equal(createdBook.CreatedAt, createdBook.UpdatedAt)
 	suite.uuidGlobal = createdBook.ID
 	suite.bookRepository.AssertExpectations(suite.T())
 }
@@ -80,6 +94,8 @@ func (suite *BookServiceDynamoDBSuite) TestUpdateBookByID() {
 	suite.Nil(err)
 	suite.NotNil(book, "Book should not be nil")
 	suite.Equal("Updated Test Book", book.Name, "Book name should be updated")
+	suite.NotNil(book.UpdatedAt)
+	suite.Nil(book.CreatedAt)
 	suite.bookRepository.AssertExpectations(suite.T())
 }
 
@@ -90,6 +106,193 @@ func (suite *BookServiceDynamoDBSuite) TestDeleteBookByID() {
 	suite.bookRepository.AssertExpectations(suite.T())
 }
 
+func (suite *BookServiceDynamoDBSuite) TestGetBooksByIDs() {
+	missingID := uuid.NewString()
+	ids := []string{suite.uuidGlobal, missingID}
+	rated := *suite.testBook
+	rated.RatingCount, rated.RatingTotal = 4, 18
+	suite.bookRepository.On(MethodGetBooksByIDs, ids).Return(&model.BooksByIDs{
+		Books:      []model.Book{rated},
+		MissingIDs: []string{missingID},
+	}, nil)
+
+	result, err := suite.bookService.GetBooksByIDs(ids)
+	suite.Nil(err)
+	suite.Len(result.Books, 1)
+	suite.Equal(4.5, result.Books[0].Rating.Average)
+	suite.Equal([]string{missingID}, result.MissingIDs)
+	suite.bookRepository.AssertExpectations(suite.T())
+}
+
+func (suite *BookServiceDynamoDBSuite) TestGetBooksByIDsInvalidID() {
+	_, err := suite.bookService.GetBooksByIDs([]string{suite.uuidGlobal, "not-a-uuid"})
+	suite.NotNil(err)
+	suite.bookRepository.AssertNotCalled(suite.T(), MethodGetBooksByIDs, mock.Anything)
+
+	_, err = suite.bookService.GetBooksByIDs(nil)
+	suite.NotNil(err)
+}
+
+func (suite *BookServiceDynamoDBSuite) TestCreateBatchBooksReport() {
+	failedBook := model.Book{ID: uuid.NewString(), Name: "Throttled", ImgURL: "https://example.com/t.jpg"}
+	books := []model.Book{
+		*suite.testBook,
+		{Name: "", ImgURL: "ftp://example.com/bad.jpg"},
+		failedBook,
+		*suite.testBook,
+	}
+	suite.bookRepository.On(MethodCreateBatchBooks, mock.MatchedBy(func(b []model.Book) bool { return len(b) == 2 })).
+		Return(map[string]*appError.Error{failedBook.ID: appError.NewUnexpectedError("throttled")})
+
+	report, err := suite.bookService.CreateBatchBooks(books)
+	suite.Nil(err)
+	suite.Equal(1, report.Created)
+	suite.Equal(2, report.Invalid)
+	suite.Equal(1, report.Failed)
+	suite.Len(report.Items, 4)
+	suite.Equal(model.BatchItemCreated, report.Items[0].Status)
+	suite.Equal(model.BatchItemInvalid, report.Items[1].Status)
+	suite.Len(report.Items[1].Errors, 2)
+	suite.Equal(model.BatchItemFailed, report.Items[2].Status)
+	suite.Equal([]string{"throttled"}, report.Items[2].Errors)
+	suite.Equal(model.BatchItemInvalid, report.Items[3].Status)
+	suite.bookRepository.AssertExpectations(suite.T())
+}
+
+func (suite *BookServiceDynamoDBSuite) TestCreateBatchBooksReportsStoredIDs() {
+	suite.bookRepository.On(MethodCreateBatchBooks, mock.Anything).
+		Return(map[string]*appError.Error{suite.testBook.ID: appError.NewConflictError("Book " + suite.testBook.ID + " already exists.")})
+
+	report, err := suite.bookService.CreateBatchBooks([]model.Book{*suite.testBook})
+	suite.Nil(err)
+	suite.Equal(1, report.Conflicts)
+	suite.Zero(report.Failed)
+	suite.Equal(model.BatchItemConflict, report.Items[0].Status)
+}
+
+func (suite *BookServiceDynamoDBSuite) TestCreateBatchBooksAllInvalid() {
+	report, err := suite.bookService.CreateBatchBooks([]model.Book{{Name: ""}})
+	suite.Nil(err)
+	suite.Equal(1, report.Invalid)
+	suite.bookRepository.AssertNotCalled(suite.T(), MethodCreateBatchBooks, mock.Anything)
+
+	_, err = suite.bookService.CreateBatchBooks(nil)
+	suite.NotNil(err)
+}
+
+func (suite *BookServiceDynamoDBSuite) TestDeleteBooksByIDs() {
+	fileService := new(repoMock.BookFileService)
+	bookService := service.NewBookServiceDynamoDBWithFiles(suite.bookRepository, fileService, "books/")
+	stored := *suite.testBook
+	stored.ImgURL = "https://bucket.s3.amazonaws.com/books/" + stored.ID + ".jpg"
+	throttled := model.Book{ID: uuid.NewString(), Name: "Throttled", ImgURL: "https://example.com/t.jpg"}
+	missingID := uuid.NewString()
+	ids := []string{stored.ID, "not-a-uuid", missingID, throttled.ID}
+
+	suite.bookRepository.On(MethodGetBooksByIDs, []string{stored.ID, missingID, throttled.ID}).
+		Return(&model.BooksByIDs{Books: []model.Book{stored, throttled}, MissingIDs: []string{missingID}}, nil)
+	suite.bookRepository.On(MethodDeleteBooksByIDs, []string{stored.ID, throttled.ID}).
+		Return(map[string]*appError.Error{throttled.ID: appError.NewUnexpectedError("throttled")})
+
+	report, err := bookService.DeleteBooksByIDs(ids)
+	suite.Nil(err)
+	suite.Equal(1, report.Deleted)
+	suite.Equal(1, report.Invalid)
+	suite.Equal(1, report.NotFound)
+	suite.Equal(1, report.Failed)
+	suite.Equal(model.BatchItemDeleted, report.Items[0].Status)
+	suite.Equal(model.BatchItemNotFound, report.Items[2].Status)
+	suite.Equal(model.BatchItemFailed, report.Items[3].Status)
+	suite.bookRepository.AssertExpectations(suite.T())
+	// Trashed books keep their images until they are purged.
+	fileService.AssertNotCalled(suite.T(), MethodDeleteBookFile, mock.Anything)
+}
+
+func (suite *BookServiceDynamoDBSuite) TestUpdateBooks() {
+	fileService := new(repoMock.BookFileService)
+	bookService := service.NewBookServiceDynamoDBWithFiles(suite.bookRepository, fileService, "books/")
+	previous := *suite.testBook
+	previous.ImgURL = "https://bucket.s3.amazonaws.com/books/old.jpg"
+	deletedMeanwhile := model.Book{ID: uuid.NewString(), Name: "Gone", ImgURL: "https://example.com/g.jpg"}
+	books := []model.Book{*suite.testBook, deletedMeanwhile, {ID: suite.uuidGlobal, Name: ""}}
+
+	suite.bookRepository.On(MethodGetBooksByIDs, []string{suite.testBook.ID, deletedMeanwhile.ID}).
+		Return(&model.BooksByIDs{Books: []model.Book{previous, deletedMeanwhile}, MissingIDs: []string{}}, nil)
+	suite.bookRepository.On(MethodUpdateBooks, mock.MatchedBy(func(b []model.Book) bool {
+		return len(b) == 2 && b[0].ID == suite.testBook.ID && b[1].ID == deletedMeanwhile.ID && b[0].UpdatedAt != nil
+	})).
+		Return(map[string]*appError.Error{deletedMeanwhile.ID: appError.NewNotFoundError("gone")})
+	fileService.On(MethodDeleteBookFile, "books/old.jpg").Return(appError.NewUnexpectedError("s3 down"))
+
+	report, err := bookService.UpdateBooks(books)
+	suite.Nil(err)
+	suite.Equal(1, report.Updated)
+	suite.Equal(1, report.NotFound)
+	suite.Equal(1, report.Invalid)
+	suite.Equal(model.BatchItemUpdated, report.Items[0].Status)
+	suite.Len(report.Items[0].Errors, 1)
+	suite.Equal(model.BatchItemNotFound, report.Items[1].Status)
+	suite.Equal(model.BatchItemInvalid, report.Items[2].Status)
+	suite.bookRepository.AssertExpectations(suite.T())
+	fileService.AssertExpectations(suite.T())
+}
+
+func (suite *BookServiceDynamoDBSuite) TestGetRecentBooks() {
+	rated := *suite.testBook
+	rated.RatingCount, rated.RatingTotal = 2, 7
+	suite.bookRepository.On(MethodGetRecentBooks, 20).Return([]model.Book{rated}, nil)
+
+	books, err := suite.bookService.GetRecentBooks(20)
+	suite.Nil(err)
+	suite.Len(books, 1)
+	suite.Equal(3.5, books[0].Rating.Average)
+
+	_, err = suite.bookService.GetRecentBooks(0)
+	suite.NotNil(err)
+	_, err = suite.bookService.GetRecentBooks(service.MaxRecentBooks + 1)
+	suite.NotNil(err)
+	suite.bookRepository.AssertExpectations(suite.T())
+}
+
+func (suite *BookServiceDynamoDBSuite) TestGetTrashedBooks() {
+	trashed := *suite.testBook
+	deletedAt := time.Now().UTC()
+	trashed.DeletedAt = &deletedAt
+	suite.bookRepository.On(MethodGetTrashedBooks, 50).Return([]model.Book{trashed}, nil)
+
+	books, err := suite.bookService.GetTrashedBooks(50)
+	suite.Nil(err)
+	suite.Len(books, 1)
+	suite.True(books[0].Trashed())
+
+	_, err = suite.bookService.GetTrashedBooks(0)
+	suite.NotNil(err)
+	_, err = suite.bookService.GetTrashedBooks(service.MaxTrashedBooks + 1)
+	suite.NotNil(err)
+	suite.bookRepository.AssertExpectations(suite.T())
+}
+
+func (suite *BookServiceDynamoDBSuite) TestRestoreTrashedBook() {
+	suite.bookRepository.On(MethodRestoreTrashed, suite.uuidGlobal).Return(nil)
+
+	suite.Nil(suite.bookService.RestoreTrashedBook(suite.uuidGlobal))
+	suite.NotNil(suite.bookService.RestoreTrashedBook("not-a-uuid"))
+	suite.bookRepository.AssertNumberOfCalls(suite.T(), MethodRestoreTrashed, 1)
+}
+
+func (suite *BookServiceDynamoDBSuite) TestPurgeBook() {
+	notTrashed := uuid.NewString()
+	suite.bookRepository.On(MethodPurgeBook, suite.uuidGlobal).Return(nil)
+	suite.bookRepository.On(MethodPurgeBook, notTrashed).Return(appError.NewNotFoundError("Book " + notTrashed + " not found in the trash."))
+
+	suite.Nil(suite.bookService.PurgeBook(suite.uuidGlobal))
+	err := suite.bookService.PurgeBook(notTrashed)
+	suite.NotNil(err)
+	suite.Equal(http.StatusNotFound, err.Code)
+	suite.NotNil(suite.bookService.PurgeBook("not-a-uuid"))
+	suite.bookRepository.AssertNumberOfCalls(suite.T(), MethodPurgeBook, 2)
+}
+
 func TestBookServiceDynamoDBSuite(t *testing.T) {
 	suite.Run(t, new(BookServiceDynamoDBSuite))
 }
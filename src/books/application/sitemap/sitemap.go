@@ -0,0 +1,254 @@
+// Package sitemap writes XML sitemaps of the public book pages so search
+// engines can index the catalog.
+package sitemap
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/xml"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"main/src/books/application/webpage"
+	"main/src/books/domain/model"
+	"main/src/books/domain/repository"
+	appError "main/utils/error"
+)
+
+// Sitemaps are written under Prefix, gzip-compressed, and listed in IndexKey,
+// which is left uncompressed so it can be referenced from robots.txt.
+const (
+	Prefix   = "sitemaps/"
+	IndexKey = Prefix + "sitemap-index.xml"
+)
+
+// Protocol limits for a single sitemap file.
+const (
+	MaxURLs  = 50000
+	MaxBytes = 50 * 1024 * 1024
+)
+
+// DefaultSegments is the number of parallel scan segments when none is given.
+const DefaultSegments = 4
+
+const namespace = "http://www.sitemaps.org/schemas/sitemap/0.9"
+
+type Options struct {
+	// Site is where the book pages are published.
+	Site *webpage.Site
+	// BaseURL is where the Prefix of the bucket is served from. Sitemap
+	// locations in the index are built from it.
+	BaseURL string
+	// URLsPerSitemap caps the entries of each sitemap, at most MaxURLs.
+	URLsPerSitemap int
+	Segments       int
+}
+
+// Entry is a sitemap as listed in the index.
+type Entry struct {
+	Key     string    `json:"key"`
+	Loc     string    `json:"loc"`
+	URLs    int       `json:"urls"`
+	LastMod time.Time `json:"lastmod"`
+}
+
+// Index summarizes a generation run.
+type Index struct {
+	Key         string    `json:"key"`
+	Sitemaps    []Entry   `json:"sitemaps"`
+	URLs        int       `json:"urls"`
+	GeneratedAt time.Time `json:"generated_at"`
+}
+
+// SitemapKey names the n-th sitemap file, counting from 1.
+func SitemapKey(n int) string {
+	return fmt.Sprintf("%ssitemap-%05d.xml.gz", Prefix, n)
+}
+
+type Generator struct {
+	books repository.BookRepository
+	files repository.BookFileRepository
+}
+
+func NewGenerator(books repository.BookRepository, files repository.BookFileRepository) *Generator {
+	return &Generator{
+		books: books,
+		files: files,
+	}
+}
+
+// Generate pages through every book, splitting the URLs into sitemaps at the
+// protocol limits, and then writes the index pointing at them. Sitemaps left over from a larger
+// catalog are no longer listed in the index, so crawlers ignore them.
+func (g *Generator) Generate(options Options, at time.Time) (*Index, *appError.Error) {
+	if options.URLsPerSitemap < 1 || options.URLsPerSitemap > MaxURLs {
+		options.URLsPerSitemap = MaxURLs
+	}
+	if options.Segments < 1 {
+		options.Segments = DefaultSegments
+	}
+	index := &Index{Key: IndexKey, GeneratedAt: at.UTC()}
+
+	var (
+		mu      sync.Mutex
+		current *urlset
+	)
+	flush := func() *appError.Error {
+		if current == nil || current.urls == 0 {
+			return nil
+		}
+		entry := Entry{
+			Key:     SitemapKey(len(index.Sitemaps) + 1),
+			URLs:    current.urls,
+			LastMod: current.lastMod,
+		}
+		entry.Loc = strings.TrimSuffix(options.BaseURL, "/") + "/" + strings.TrimPrefix(entry.Key, Prefix)
+		content, err := current.close()
+		if err != nil {
+			log.Printf("Error finishing sitemap %s: %v", entry.Key, err)
+			return appError.NewUnexpectedError(err.Error())
+		}
+		if errFile := g.files.SaveBookFile(bytes.NewReader(content), entry.Key, ".gz"); errFile != nil {
+			return errFile
+		}
+		index.Sitemaps = append(index.Sitemaps, entry)
+		index.URLs += entry.URLs
+		current = nil
+		return nil
+	}
+
+	errScan := g.books.ScanBooks(options.Segments, func(books []model.Book) *appError.Error {
+		mu.Lock()
+		defer mu.Unlock()
+		for i := range books {
+			entry, err := newURL(options.Site, &books[i])
+			if err != nil {
+				log.Printf("Error encoding sitemap URL: %v, ID: %s", err, books[i].ID)
+				return appError.NewUnexpectedError(err.Error())
+			}
+			if current != nil && !current.fits(entry, options.URLsPerSitemap) {
+				if errFlush := flush(); errFlush != nil {
+					return errFlush
+				}
+			}
+			if current == nil {
+				current = newURLSet()
+			}
+			if err := current.add(entry, books[i].UpdatedAt); err != nil {
+				log.Printf("Error writing sitemap URL: %v, ID: %s", err, books[i].ID)
+				return appError.NewUnexpectedError(err.Error())
+			}
+		}
+		return nil
+	})
+	if errScan != nil {
+		return nil, errScan
+	}
+	if errFlush := flush(); errFlush != nil {
+		return nil, errFlush
+	}
+
+	content, errIndex := marshalIndex(index)
+	if errIndex != nil {
+		return nil, errIndex
+	}
+	if errFile := g.files.SaveBookFile(bytes.NewReader(content), IndexKey, ".xml"); errFile != nil {
+		return nil, errFile
+	}
+	log.Printf("Generated %d sitemaps with %d URLs", len(index.Sitemaps), index.URLs)
+	return index, nil
+}
+
+type url struct {
+	XMLName xml.Name `xml:"url"`
+	Loc     string   `xml:"loc"`
+	LastMod string   `xml:"lastmod,omitempty"`
+}
+
+func newURL(site *webpage.Site, book *model.Book) ([]byte, error) {
+	entry := url{Loc: site.BookURL(book)}
+	if book.UpdatedAt != nil {
+		entry.LastMod = book.UpdatedAt.UTC().Format(time.RFC3339)
+	}
+	return xml.Marshal(&entry)
+}
+
+var (
+	urlsetOpen  = []byte(xml.Header + `<urlset xmlns="` + namespace + `">`)
+	urlsetClose = []byte(`</urlset>`)
+)
+
+// urlset is a sitemap being written. Size counts uncompressed bytes, which
+// is what the protocol limit applies to.
+type urlset struct {
+	content bytes.Buffer
+	gzip    *gzip.Writer
+	urls    int
+	size    int
+	lastMod time.Time
+}
+
+func newURLSet() *urlset {
+	set := &urlset{}
+	set.gzip = gzip.NewWriter(&set.content)
+	set.gzip.Write(urlsetOpen)
+	set.size = len(urlsetOpen)
+	return set
+}
+
+func (s *urlset) fits(entry []byte, maxURLs int) bool {
+	return s.urls < maxURLs && s.size+len(entry)+len(urlsetClose) <= MaxBytes
+}
+
+func (s *urlset) add(entry []byte, updatedAt *time.Time) error {
+	if _, err := s.gzip.Write(entry); err != nil {
+		return err
+	}
+	s.urls++
+	s.size += len(entry)
+	if updatedAt != nil && updatedAt.After(s.lastMod) {
+		s.lastMod = updatedAt.UTC()
+	}
+	return nil
+}
+
+func (s *urlset) close() ([]byte, error) {
+	if _, err := s.gzip.Write(urlsetClose); err != nil {
+		return nil, err
+	}
+	if err := s.gzip.Close(); err != nil {
+		return nil, err
+	}
+	return s.content.Bytes(), nil
+}
+
+type sitemapIndex struct {
+	XMLName   xml.Name       `xml:"sitemapindex"`
+	Namespace string         `xml:"xmlns,attr"`
+	Sitemaps  []sitemapEntry `xml:"sitemap"`
+}
+
+type sitemapEntry struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod,omitempty"`
+}
+
+func marshalIndex(index *Index) ([]byte, *appError.Error) {
+	document := &sitemapIndex{Namespace: namespace}
+	for _, entry := range index.Sitemaps {
+		item := sitemapEntry{Loc: entry.Loc}
+		if !entry.LastMod.IsZero() {
+			item.LastMod = entry.LastMod.Format(time.RFC3339)
+		}
+		document.Sitemaps = append(document.Sitemaps, item)
+	}
+	data, err := xml.Marshal(document)
+	if err != nil {
+		log.Printf("Error marshaling sitemap index: %v", err)
+		return nil, appError.NewUnexpectedError(err.Error())
+	}
+	return append([]byte(xml.Header), data...), nil
+}
@@ -0,0 +1,135 @@
+package sitemap_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/xml"
+	"io"
+	"testing"
+	"time"
+
+	"main/src/books/application/sitemap"
+	"main/src/books/application/webpage"
+	"main/src/books/domain/model"
+	appError "main/utils/error"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	repoMock "main/mocks"
+)
+
+type SitemapSuite struct {
+	suite.Suite
+	bookRepository *repoMock.BookRepository
+	fileRepository *repoMock.BookFileRepository
+	generator      *sitemap.Generator
+	options        sitemap.Options
+	saved          map[string][]byte
+}
+
+const (
+	MethodScanBooks    = "ScanBooks"
+	MethodSaveBookFile = "SaveBookFile"
+)
+
+type urlset struct {
+	URLs []struct {
+		Loc     string `xml:"loc"`
+		LastMod string `xml:"lastmod"`
+	} `xml:"url"`
+}
+
+type sitemapIndex struct {
+	XMLName  xml.Name `xml:"http://www.sitemaps.org/schemas/sitemap/0.9 sitemapindex"`
+	Sitemaps []struct {
+		Loc     string `xml:"loc"`
+		LastMod string `xml:"lastmod"`
+	} `xml:"sitemap"`
+}
+
+func (suite *SitemapSuite) SetupTest() {
+	suite.bookRepository = new(repoMock.BookRepository)
+	suite.fileRepository = new(repoMock.BookFileRepository)
+	suite.generator = sitemap.NewGenerator(suite.bookRepository, suite.fileRepository)
+	suite.options = sitemap.Options{
+		Site:           &webpage.Site{BaseURL: "https://books.example.com"},
+		BaseURL:        "https://books.example.com/sitemaps/",
+		URLsPerSitemap: 2,
+		Segments:       2,
+	}
+	suite.saved = map[string][]byte{}
+	suite.fileRepository.On(MethodSaveBookFile, mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		content, _ := io.ReadAll(args.Get(0).(*bytes.Reader))
+		suite.saved[args.String(1)] = content
+	}).Return(nil)
+}
+
+func (suite *SitemapSuite) scan(books ...model.Book) {
+	suite.bookRepository.On(MethodScanBooks, 2, mock.Anything).Run(func(args mock.Arguments) {
+		visit := args.Get(1).(func([]model.Book) *appError.Error)
+		visit(books[:1])
+		visit(books[1:])
+	}).Return(nil)
+}
+
+func (suite *SitemapSuite) readSitemap(key string) *urlset {
+	reader, err := gzip.NewReader(bytes.NewReader(suite.saved[key]))
+	suite.Require().NoError(err)
+	var set urlset
+	suite.Require().NoError(xml.NewDecoder(reader).Decode(&set))
+	return &set
+}
+
+func (suite *SitemapSuite) TestGenerate() {
+	first := time.Date(2024, 4, 1, 9, 0, 0, 0, time.UTC)
+	second := time.Date(2024, 5, 2, 8, 30, 0, 0, time.UTC)
+	suite.scan(
+		model.Book{ID: "1", UpdatedAt: &first},
+		model.Book{ID: "2", UpdatedAt: &second},
+		model.Book{ID: "3"},
+	)
+
+	index, err := suite.generator.Generate(suite.options, second)
+	suite.Nil(err)
+	suite.Equal(3, index.URLs)
+	suite.Len(index.Sitemaps, 2)
+	suite.Equal("sitemaps/sitemap-00001.xml.gz", index.Sitemaps[0].Key)
+	suite.Equal("https://books.example.com/sitemaps/sitemap-00002.xml.gz", index.Sitemaps[1].Loc)
+
+	set := suite.readSitemap(sitemap.SitemapKey(1))
+	suite.Len(set.URLs, 2)
+	suite.Equal("https://books.example.com/books/1", set.URLs[0].Loc)
+	suite.Equal("2024-04-01T09:00:00Z", set.URLs[0].LastMod)
+	set = suite.readSitemap(sitemap.SitemapKey(2))
+	suite.Len(set.URLs, 1)
+	suite.Empty(set.URLs[0].LastMod)
+
+	var doc sitemapIndex
+	suite.NoError(xml.Unmarshal(suite.saved[sitemap.IndexKey], &doc))
+	suite.Len(doc.Sitemaps, 2)
+	suite.Equal("2024-05-02T08:30:00Z", doc.Sitemaps[0].LastMod)
+	suite.Empty(doc.Sitemaps[1].LastMod)
+	suite.fileRepository.AssertCalled(suite.T(), MethodSaveBookFile, mock.Anything, sitemap.IndexKey, ".xml")
+}
+
+func (suite *SitemapSuite) TestGenerateEmptyCatalog() {
+	suite.bookRepository.On(MethodScanBooks, 2, mock.Anything).Return(nil)
+
+	index, err := suite.generator.Generate(suite.options, time.Now())
+	suite.Nil(err)
+	suite.Empty(index.Sitemaps)
+	suite.Contains(string(suite.saved[sitemap.IndexKey]), "<sitemapindex")
+}
+
+func (suite *SitemapSuite) TestGenerateScanError() {
+	suite.bookRepository.On(MethodScanBooks, 2, mock.Anything).Return(appError.NewUnexpectedError("scan failed"))
+
+	_, err := suite.generator.Generate(suite.options, time.Now())
+	suite.NotNil(err)
+	suite.fileRepository.AssertNotCalled(suite.T(), MethodSaveBookFile, mock.Anything, sitemap.IndexKey, mock.Anything)
+}
+
+func TestSitemapSuite(t *testing.T) {
+	suite.Run(t, new(SitemapSuite))
+}
@@ -0,0 +1,149 @@
+// Package stream turns DynamoDB stream records of the books table into typed
+// change events and hands them to the systems that follow the catalog.
+package stream
+
+import (
+	"fmt"
+	"time"
+
+	"main/src/books/domain/model"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+type ChangeType string
+
+const (
+	ChangeInsert ChangeType = "INSERT"
+	ChangeModify ChangeType = "MODIFY"
+	ChangeRemove ChangeType = "REMOVE"
+)
+
+// Change is one book mutation. Old is nil for inserts and New is nil for
+// removals; both are set for modifications.
+type Change struct {
+	EventID        string
+	SequenceNumber string
+	Type           ChangeType
+	BookID         string
+	At             time.Time
+	Old            *model.Book
+	New            *model.Book
+}
+
+// Book is the book as it is after the change, or as it was before a removal.
+func (c *Change) Book() *model.Book {
+	if c.New != nil {
+		return c.New
+	}
+	return c.Old
+}
+
+// Removed tells whether the book left the catalog with the change: it was
+// deleted from the table or moved to the trash.
+func (c *Change) Removed() bool {
+	return c.New == nil || c.New.Trashed()
+}
+
+// DecodeRecord reads a stream record of the books table. The stream must
+// carry new and old images.
+func DecodeRecord(record events.DynamoDBEventRecord) (*Change, error) {
+	change := &Change{
+		EventID:        record.EventID,
+		SequenceNumber: record.Change.SequenceNumber,
+		Type:           ChangeType(record.EventName),
+		At:             record.Change.ApproximateCreationDateTime.UTC(),
+	}
+	if id, ok := record.Change.Keys["ID"]; ok && id.DataType() == events.DataTypeString {
+		change.BookID = id.String()
+	} else {
+		return nil, fmt.Errorf("record %s has no book ID key", record.EventID)
+	}
+
+	var err error
+	switch change.Type {
+	case ChangeInsert:
+		change.New, err = decodeImage(record.Change.NewImage)
+	case ChangeModify:
+		if change.Old, err = decodeImage(record.Change.OldImage); err == nil {
+			change.New, err = decodeImage(record.Change.NewImage)
+		}
+	case ChangeRemove:
+		change.Old, err = decodeImage(record.Change.OldImage)
+	default:
+		return nil, fmt.Errorf("record %s has unknown event %q", record.EventID, record.EventName)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("record %s: %w", record.EventID, err)
+	}
+	return change, nil
+}
+
+func decodeImage(image map[string]events.DynamoDBAttributeValue) (*model.Book, error) {
+	if len(image) == 0 {
+		return nil, fmt.Errorf("missing image, the stream view type must be NEW_AND_OLD_IMAGES")
+	}
+	item, err := attributeMap(image)
+	if err != nil {
+		return nil, err
+	}
+	var book model.Book
+	if err := attributevalue.UnmarshalMap(item, &book); err != nil {
+		return nil, err
+	}
+	return &book, nil
+}
+
+func attributeMap(values map[string]events.DynamoDBAttributeValue) (map[string]types.AttributeValue, error) {
+	item := make(map[string]types.AttributeValue, len(values))
+	for name, value := range values {
+		converted, err := attributeValue(value)
+		if err != nil {
+			return nil, fmt.Errorf("attribute %s: %w", name, err)
+		}
+		item[name] = converted
+	}
+	return item, nil
+}
+
+// attributeValue converts a stream attribute to the SDK type, so the images
+// decode with the same tags the repository writes with.
+func attributeValue(value events.DynamoDBAttributeValue) (types.AttributeValue, error) {
+	switch value.DataType() {
+	case events.DataTypeString:
+		return &types.AttributeValueMemberS{Value: value.String()}, nil
+	case events.DataTypeNumber:
+		return &types.AttributeValueMemberN{Value: value.Number()}, nil
+	case events.DataTypeBinary:
+		return &types.AttributeValueMemberB{Value: value.Binary()}, nil
+	case events.DataTypeBoolean:
+		return &types.AttributeValueMemberBOOL{Value: value.Boolean()}, nil
+	case events.DataTypeNull:
+		return &types.AttributeValueMemberNULL{Value: true}, nil
+	case events.DataTypeStringSet:
+		return &types.AttributeValueMemberSS{Value: value.StringSet()}, nil
+	case events.DataTypeNumberSet:
+		return &types.AttributeValueMemberNS{Value: value.NumberSet()}, nil
+	case events.DataTypeBinarySet:
+		return &types.AttributeValueMemberBS{Value: value.BinarySet()}, nil
+	case events.DataTypeList:
+		list := value.List()
+		converted := make([]types.AttributeValue, len(list))
+		for i, element := range list {
+			var err error
+			if converted[i], err = attributeValue(element); err != nil {
+				return nil, fmt.Errorf("element %d: %w", i, err)
+			}
+		}
+		return &types.AttributeValueMemberL{Value: converted}, nil
+	case events.DataTypeMap:
+		converted, err := attributeMap(value.Map())
+		if err != nil {
+			return nil, err
+		}
+		return &types.AttributeValueMemberM{Value: converted}, nil
+	}
+	return nil, fmt.Errorf("unsupported data type %v", value.DataType())
+}
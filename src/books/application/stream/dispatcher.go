@@ -0,0 +1,94 @@
+package stream
+
+import (
+	"log"
+
+	appError "main/utils/error"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+// ChangeHandler is a system that follows book changes.
+type ChangeHandler interface {
+	HandleChange(*Change) *appError.Error
+}
+
+// Flusher is implemented by handlers that buffer changes and write them once
+// per batch.
+type Flusher interface {
+	Flush() *appError.Error
+}
+
+// ChangeHandlerFunc adapts a function to a ChangeHandler.
+type ChangeHandlerFunc func(*Change) *appError.Error
+
+func (f ChangeHandlerFunc) HandleChange(change *Change) *appError.Error {
+	return f(change)
+}
+
+type Dispatcher struct {
+	handlers []ChangeHandler
+}
+
+func NewDispatcher(handlers ...ChangeHandler) *Dispatcher {
+	return &Dispatcher{
+		handlers: handlers,
+	}
+}
+
+// Process hands every record of the batch to every handler, in stream order.
+// It stops at the first record that cannot be decoded or handled and reports
+// it as the batch item failure, so Lambda retries from that record on and
+// later changes to a book are never applied before earlier ones. Handlers
+// must therefore tolerate seeing a change again.
+func (d *Dispatcher) Process(event events.DynamoDBEvent) events.DynamoDBEventResponse {
+	response := events.DynamoDBEventResponse{BatchItemFailures: []events.DynamoDBBatchItemFailure{}}
+	failed := ""
+	processed := 0
+	for _, record := range event.Records {
+		change, err := DecodeRecord(record)
+		if err != nil {
+			log.Printf("Error decoding stream record: %v", err)
+			failed = record.Change.SequenceNumber
+			break
+		}
+		if errHandle := d.dispatch(change); errHandle != nil {
+			log.Printf("Error handling %s of book %s, %s", change.Type, change.BookID, errHandle.ToString())
+			failed = record.Change.SequenceNumber
+			break
+		}
+		processed++
+	}
+
+	if errFlush := d.flush(); errFlush != nil && processed > 0 {
+		// Nothing of the batch is known to be stored, retry all of it.
+		log.Printf("Error flushing stream handlers, %s", errFlush.ToString())
+		failed = event.Records[0].Change.SequenceNumber
+	}
+	if failed != "" {
+		response.BatchItemFailures = append(response.BatchItemFailures, events.DynamoDBBatchItemFailure{ItemIdentifier: failed})
+	}
+	log.Printf("Processed %d of %d book changes", processed, len(event.Records))
+	return response
+}
+
+func (d *Dispatcher) dispatch(change *Change) *appError.Error {
+	for _, handler := range d.handlers {
+		if err := handler.HandleChange(change); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *Dispatcher) flush() *appError.Error {
+	var failed *appError.Error
+	for _, handler := range d.handlers {
+		if flusher, ok := handler.(Flusher); ok {
+			if err := flusher.Flush(); err != nil && failed == nil {
+				failed = err
+			}
+		}
+	}
+	return failed
+}
@@ -0,0 +1,139 @@
+package stream_test
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+	"time"
+
+	"main/src/books/application/stream"
+	appError "main/utils/error"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/suite"
+)
+
+type StreamSuite struct {
+	suite.Suite
+	event events.DynamoDBEvent
+}
+
+// recorder is a handler that remembers the changes it was given and fails
+// on the book IDs it is told to.
+type recorder struct {
+	changes []*stream.Change
+	failOn  string
+	flushes int
+	flush   *appError.Error
+}
+
+func (r *recorder) HandleChange(change *stream.Change) *appError.Error {
+	if change.BookID == r.failOn {
+		return appError.NewUnexpectedError("handler failed")
+	}
+	r.changes = append(r.changes, change)
+	return nil
+}
+
+func (r *recorder) Flush() *appError.Error {
+	r.flushes++
+	return r.flush
+}
+
+func (suite *StreamSuite) SetupTest() {
+	content, err := os.ReadFile("testdata/books_stream_event.json")
+	suite.Require().NoError(err)
+	suite.Require().NoError(json.Unmarshal(content, &suite.event))
+}
+
+func (suite *StreamSuite) TestDecodeRecord() {
+	insert, err := stream.DecodeRecord(suite.event.Records[0])
+	suite.NoError(err)
+	suite.Equal(stream.ChangeInsert, insert.Type)
+	suite.Equal("0b9d2f3c-4d5e-4f60-8a7b-1c2d3e4f5a6b", insert.BookID)
+	suite.Equal(time.Date(2024, 5, 2, 8, 10, 0, 0, time.UTC), insert.At)
+	suite.Nil(insert.Old)
+	suite.Equal("A Wizard of Earthsea", insert.New.Name)
+	suite.Equal(9, insert.New.RatingTotal)
+	suite.Equal("Ursula K. Le Guin", insert.New.Contributors[0].Name)
+	suite.Equal("FIC009000", insert.New.Subjects[0].Code)
+	suite.Equal(time.Date(2024, 5, 2, 8, 10, 0, 0, time.UTC), *insert.New.CreatedAt)
+
+	modify, err := stream.DecodeRecord(suite.event.Records[1])
+	suite.NoError(err)
+	suite.Equal("https://example.com/earthsea.jpg", modify.Old.ImgURL)
+	suite.Equal("https://example.com/earthsea-2.jpg", modify.New.ImgURL)
+	suite.Equal("The Earthsea Cycle", modify.Book().Subtitle)
+
+	remove, err := stream.DecodeRecord(suite.event.Records[2])
+	suite.NoError(err)
+	suite.Nil(remove.New)
+	suite.Equal("Moby Dick", remove.Book().Name)
+}
+
+func (suite *StreamSuite) TestRemoved() {
+	insert, _ := stream.DecodeRecord(suite.event.Records[0])
+	modify, _ := stream.DecodeRecord(suite.event.Records[1])
+	remove, _ := stream.DecodeRecord(suite.event.Records[2])
+	suite.False(insert.Removed())
+	suite.False(modify.Removed())
+	suite.True(remove.Removed())
+
+	deletedAt := time.Date(2024, 5, 3, 9, 0, 0, 0, time.UTC)
+	modify.New.DeletedAt = &deletedAt
+	suite.True(modify.Removed())
+}
+
+func (suite *StreamSuite) TestDecodeRecordErrors() {
+	record := suite.event.Records[0]
+	record.Change.NewImage = nil
+	_, err := stream.DecodeRecord(record)
+	suite.ErrorContains(err, "NEW_AND_OLD_IMAGES")
+
+	record = suite.event.Records[0]
+	record.EventName = "TRUNCATE"
+	_, err = stream.DecodeRecord(record)
+	suite.Error(err)
+
+	record = suite.event.Records[0]
+	record.Change.Keys = map[string]events.DynamoDBAttributeValue{"ID": events.NewNumberAttribute("1")}
+	_, err = stream.DecodeRecord(record)
+	suite.Error(err)
+}
+
+func (suite *StreamSuite) TestProcess() {
+	first, second := &recorder{}, &recorder{}
+	response := stream.NewDispatcher(first, second).Process(suite.event)
+
+	suite.Empty(response.BatchItemFailures)
+	suite.Len(first.changes, 3)
+	suite.Len(second.changes, 3)
+	suite.Equal(stream.ChangeRemove, second.changes[2].Type)
+	suite.Equal(1, first.flushes)
+}
+
+func (suite *StreamSuite) TestProcessStopsAtFirstFailure() {
+	failing := &recorder{failOn: "7f8e9d0c-1b2a-4c3d-9e8f-7a6b5c4d3e2f"}
+	response := stream.NewDispatcher(failing).Process(suite.event)
+
+	suite.Equal([]events.DynamoDBBatchItemFailure{{ItemIdentifier: "111100000000000000003"}}, response.BatchItemFailures)
+	suite.Len(failing.changes, 2)
+	suite.Equal(1, failing.flushes, "the changes before the failure are still written")
+
+	suite.event.Records[1].Change.NewImage = nil
+	counted := &recorder{}
+	response = stream.NewDispatcher(counted, stream.ChangeHandlerFunc(func(*stream.Change) *appError.Error { return nil })).Process(suite.event)
+	suite.Equal("111100000000000000002", response.BatchItemFailures[0].ItemIdentifier)
+	suite.Len(counted.changes, 1)
+}
+
+func (suite *StreamSuite) TestProcessFlushFailure() {
+	failing := &recorder{flush: appError.NewUnexpectedError("save failed")}
+	response := stream.NewDispatcher(failing).Process(suite.event)
+
+	suite.Equal([]events.DynamoDBBatchItemFailure{{ItemIdentifier: "111100000000000000001"}}, response.BatchItemFailures)
+}
+
+func TestStreamSuite(t *testing.T) {
+	suite.Run(t, new(StreamSuite))
+}
@@ -0,0 +1,43 @@
+// Package trash removes what is left of the books purged from the trash,
+// whether by hand or by the table TTL once the retention period ran out.
+package trash
+
+import (
+	"log"
+
+	"main/src/books/application/service"
+	"main/src/books/application/stream"
+	appError "main/utils/error"
+)
+
+// FileCleaner deletes the image of every book removed from the books table.
+// Trashed books keep their image, so a restore brings it back with them.
+type FileCleaner struct {
+	files     service.BookFileService
+	bucketKey string
+}
+
+func NewFileCleaner(files service.BookFileService, bucketKey string) *FileCleaner {
+	return &FileCleaner{
+		files:     files,
+		bucketKey: bucketKey,
+	}
+}
+
+// HandleChange deletes the stored image of a removed book. Deleting an image
+// that is already gone succeeds, so a retried record is harmless.
+func (c *FileCleaner) HandleChange(change *stream.Change) *appError.Error {
+	if change.Type != stream.ChangeRemove {
+		return nil
+	}
+	key := change.Old.ImageKey(c.bucketKey)
+	if key == "" {
+		return nil
+	}
+	if err := c.files.DeleteBookFile(key); err != nil {
+		log.Printf("Error removing image of purged book %s: %s", change.BookID, err.ToString())
+		return err
+	}
+	log.Printf("Removed image %s of purged book %s", key, change.BookID)
+	return nil
+}
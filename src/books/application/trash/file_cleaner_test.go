@@ -0,0 +1,73 @@
+package trash_test
+
+import (
+	"testing"
+	"time"
+
+	"main/src/books/application/stream"
+	"main/src/books/application/trash"
+	"main/src/books/domain/model"
+	appError "main/utils/error"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	repoMock "main/mocks"
+)
+
+const MethodDeleteBookFile = "DeleteBookFile"
+
+type FileCleanerSuite struct {
+	suite.Suite
+	files   *repoMock.BookFileService
+	cleaner *trash.FileCleaner
+	book    *model.Book
+}
+
+func (suite *FileCleanerSuite) SetupTest() {
+	suite.files = new(repoMock.BookFileService)
+	suite.cleaner = trash.NewFileCleaner(suite.files, "books/")
+	deletedAt := time.Date(2024, 5, 3, 9, 0, 0, 0, time.UTC)
+	suite.book = &model.Book{
+		ID:        "0b9d2f3c-4d5e-4f60-8a7b-1c2d3e4f5a6b",
+		Name:      "A Wizard of Earthsea",
+		ImgURL:    "https://bucket.s3.amazonaws.com/books/0b9d2f3c.jpg",
+		DeletedAt: &deletedAt,
+	}
+}
+
+func (suite *FileCleanerSuite) TestRemovesImageOfPurgedBook() {
+	suite.files.On(MethodDeleteBookFile, "books/0b9d2f3c.jpg").Return(nil)
+
+	err := suite.cleaner.HandleChange(&stream.Change{Type: stream.ChangeRemove, BookID: suite.book.ID, Old: suite.book})
+	suite.Nil(err)
+	suite.files.AssertExpectations(suite.T())
+}
+
+func (suite *FileCleanerSuite) TestKeepsImageOfTrashedBook() {
+	live := *suite.book
+	live.DeletedAt = nil
+
+	err := suite.cleaner.HandleChange(&stream.Change{Type: stream.ChangeModify, BookID: suite.book.ID, Old: &live, New: suite.book})
+	suite.Nil(err)
+	suite.files.AssertNotCalled(suite.T(), MethodDeleteBookFile, mock.Anything)
+}
+
+func (suite *FileCleanerSuite) TestIgnoresImagesOutsideTheBucket() {
+	suite.book.ImgURL = "https://example.com/earthsea.jpg"
+
+	err := suite.cleaner.HandleChange(&stream.Change{Type: stream.ChangeRemove, BookID: suite.book.ID, Old: suite.book})
+	suite.Nil(err)
+	suite.files.AssertNotCalled(suite.T(), MethodDeleteBookFile, mock.Anything)
+}
+
+func (suite *FileCleanerSuite) TestReportsFailure() {
+	suite.files.On(MethodDeleteBookFile, "books/0b9d2f3c.jpg").Return(appError.NewUnexpectedError("s3 down"))
+
+	err := suite.cleaner.HandleChange(&stream.Change{Type: stream.ChangeRemove, BookID: suite.book.ID, Old: suite.book})
+	suite.NotNil(err)
+}
+
+func TestFileCleanerSuite(t *testing.T) {
+	suite.Run(t, new(FileCleanerSuite))
+}
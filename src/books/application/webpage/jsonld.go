@@ -0,0 +1,157 @@
+// Package webpage renders books for search engines and link previews:
+// schema.org JSON-LD and a small HTML page with Open Graph and Twitter card
+// meta tags.
+package webpage
+
+import (
+	"encoding/json"
+	"log"
+
+	"main/src/books/application/catalog"
+	"main/src/books/domain/model"
+	appError "main/utils/error"
+)
+
+// Media types served on book pages besides the catalog formats.
+const (
+	MediaHTML   catalog.MediaType = "text/html"
+	MediaJSONLD catalog.MediaType = "application/ld+json"
+)
+
+// Site describes where book pages are published. BookURL is the public
+// page of a book, used as its canonical URL.
+type Site struct {
+	Name    string
+	BaseURL string
+}
+
+func (s *Site) BookURL(book *model.Book) string {
+	return s.BaseURL + "/books/" + book.ID
+}
+
+type thing struct {
+	Type string `json:"@type"`
+	Name string `json:"name"`
+}
+
+type aggregateRating struct {
+	Type        string  `json:"@type"`
+	RatingValue float64 `json:"ratingValue"`
+	RatingCount int     `json:"ratingCount"`
+	BestRating  int     `json:"bestRating"`
+	WorstRating int     `json:"worstRating"`
+}
+
+type offer struct {
+	Type           string `json:"@type"`
+	Price          string `json:"price"`
+	PriceCurrency  string `json:"priceCurrency"`
+	Availability   string `json:"availability,omitempty"`
+	EligibleRegion string `json:"eligibleRegion,omitempty"`
+	URL            string `json:"url"`
+}
+
+// BookJSONLD is a schema.org description of a book. It is typed as both Book
+// and Product so search engines can show ratings and prices.
+type BookJSONLD struct {
+	Context             string           `json:"@context"`
+	Type                []string         `json:"@type"`
+	ID                  string           `json:"@id"`
+	URL                 string           `json:"url"`
+	Name                string           `json:"name"`
+	AlternativeHeadline string           `json:"alternativeHeadline,omitempty"`
+	Description         string           `json:"description,omitempty"`
+	Image               string           `json:"image,omitempty"`
+	ISBN                string           `json:"isbn,omitempty"`
+	GTIN13              string           `json:"gtin13,omitempty"`
+	Author              []thing          `json:"author,omitempty"`
+	Contributor         []thing          `json:"contributor,omitempty"`
+	Publisher           *thing           `json:"publisher,omitempty"`
+	DatePublished       string           `json:"datePublished,omitempty"`
+	About               []string         `json:"about,omitempty"`
+	AggregateRating     *aggregateRating `json:"aggregateRating,omitempty"`
+	Offers              []offer          `json:"offers,omitempty"`
+}
+
+// availability maps ONIX product availability codes (list 65) to schema.org.
+var availability = map[string]string{
+	"10": "https://schema.org/PreOrder",
+	"11": "https://schema.org/PreOrder",
+	"20": "https://schema.org/InStock",
+	"21": "https://schema.org/InStock",
+	"22": "https://schema.org/InStock",
+	"23": "https://schema.org/InStock",
+	"30": "https://schema.org/BackOrder",
+	"31": "https://schema.org/OutOfStock",
+	"32": "https://schema.org/BackOrder",
+	"40": "https://schema.org/Discontinued",
+	"41": "https://schema.org/Discontinued",
+	"46": "https://schema.org/Discontinued",
+	"51": "https://schema.org/Discontinued",
+}
+
+// NewBookJSONLD describes book as published on site.
+func NewBookJSONLD(site *Site, book *model.Book) *BookJSONLD {
+	url := site.BookURL(book)
+	ld := &BookJSONLD{
+		Context:             "https://schema.org",
+		Type:                []string{"Book", "Product"},
+		ID:                  url + "#book",
+		URL:                 url,
+		Name:                book.Name,
+		AlternativeHeadline: book.Subtitle,
+		Description:         book.Description,
+		Image:               book.ImgURL,
+		ISBN:                book.ISBN,
+		DatePublished:       book.PublishedOn,
+	}
+	if len(book.ISBN) == 13 {
+		ld.GTIN13 = book.ISBN
+	}
+	for _, contributor := range book.Contributors {
+		person := thing{Type: "Person", Name: contributor.Name}
+		if contributor.Role == "author" {
+			ld.Author = append(ld.Author, person)
+		} else {
+			ld.Contributor = append(ld.Contributor, person)
+		}
+	}
+	if book.Publisher != "" {
+		ld.Publisher = &thing{Type: "Organization", Name: book.Publisher}
+	}
+	for _, subject := range book.Subjects {
+		if subject.Heading != "" {
+			ld.About = append(ld.About, subject.Heading)
+		}
+	}
+	if book.Rating != nil && book.Rating.Count > 0 {
+		ld.AggregateRating = &aggregateRating{
+			Type:        "AggregateRating",
+			RatingValue: book.Rating.Average,
+			RatingCount: book.Rating.Count,
+			BestRating:  5,
+			WorstRating: 1,
+		}
+	}
+	for _, price := range book.Prices {
+		ld.Offers = append(ld.Offers, offer{
+			Type:           "Offer",
+			Price:          price.Amount,
+			PriceCurrency:  price.Currency,
+			Availability:   availability[book.Availability],
+			EligibleRegion: price.Territory,
+			URL:            url,
+		})
+	}
+	return ld
+}
+
+// MarshalJSONLD serializes the JSON-LD description of a book.
+func MarshalJSONLD(site *Site, book *model.Book) ([]byte, *appError.Error) {
+	data, err := json.Marshal(NewBookJSONLD(site, book))
+	if err != nil {
+		log.Printf("Error marshaling JSON-LD: %v, ID: %s", err, book.ID)
+		return nil, appError.NewUnexpectedError(err.Error())
+	}
+	return data, nil
+}
@@ -0,0 +1,146 @@
+package webpage
+
+import (
+	"bytes"
+	"html/template"
+	"log"
+	"strings"
+	"unicode/utf8"
+
+	"main/src/books/domain/model"
+	appError "main/utils/error"
+)
+
+// maxMetaDescription keeps meta descriptions within what search results and
+// link previews display.
+const maxMetaDescription = 160
+
+// The JSON-LD value is rendered in a script context, where html/template
+// marshals it as JSON and escapes anything that could close the tag.
+var bookPage = template.Must(template.New("book").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}}{{if .Site.Name}} | {{.Site.Name}}{{end}}</title>
+{{- if .Description}}
+<meta name="description" content="{{.Description}}">
+{{- end}}
+<link rel="canonical" href="{{.URL}}">
+<meta property="og:type" content="book">
+<meta property="og:title" content="{{.Title}}">
+<meta property="og:url" content="{{.URL}}">
+{{- if .Site.Name}}
+<meta property="og:site_name" content="{{.Site.Name}}">
+{{- end}}
+{{- if .Description}}
+<meta property="og:description" content="{{.Description}}">
+{{- end}}
+{{- if .Book.ImgURL}}
+<meta property="og:image" content="{{.Book.ImgURL}}">
+<meta property="og:image:alt" content="Cover of {{.Book.Name}}">
+{{- end}}
+{{- if .Book.ISBN}}
+<meta property="book:isbn" content="{{.Book.ISBN}}">
+{{- end}}
+{{- if .Book.PublishedOn}}
+<meta property="book:release_date" content="{{.Book.PublishedOn}}">
+{{- end}}
+<meta name="twitter:card" content="{{if .Book.ImgURL}}summary_large_image{{else}}summary{{end}}">
+<meta name="twitter:title" content="{{.Title}}">
+{{- if .Description}}
+<meta name="twitter:description" content="{{.Description}}">
+{{- end}}
+{{- if .Book.ImgURL}}
+<meta name="twitter:image" content="{{.Book.ImgURL}}">
+{{- end}}
+<script type="application/ld+json">{{.JSONLD}}</script>
+</head>
+<body>
+<main>
+<article>
+{{- if .Book.ImgURL}}
+<img src="{{.Book.ImgURL}}" alt="Cover of {{.Book.Name}}">
+{{- end}}
+<h1>{{.Book.Name}}</h1>
+{{- if .Book.Subtitle}}
+<p class="subtitle">{{.Book.Subtitle}}</p>
+{{- end}}
+{{- if .Authors}}
+<p class="authors">by {{.Authors}}</p>
+{{- end}}
+{{- if .Book.Description}}
+<p class="description">{{.Book.Description}}</p>
+{{- end}}
+<dl>
+{{- if .Book.ISBN}}
+<dt>ISBN</dt><dd>{{.Book.ISBN}}</dd>
+{{- end}}
+{{- if .Book.Publisher}}
+<dt>Publisher</dt><dd>{{.Book.Publisher}}</dd>
+{{- end}}
+{{- if .Book.PublishedOn}}
+<dt>Published</dt><dd>{{.Book.PublishedOn}}</dd>
+{{- end}}
+{{- if .Book.Rating}}
+<dt>Rating</dt><dd>{{.Book.Rating.Average}} out of 5 ({{.Book.Rating.Count}} reviews)</dd>
+{{- end}}
+</dl>
+</article>
+</main>
+</body>
+</html>
+`))
+
+type pageData struct {
+	Site        *Site
+	Book        *model.Book
+	URL         string
+	Title       string
+	Description string
+	Authors     string
+	JSONLD      *BookJSONLD
+}
+
+// RenderHTML renders the public page of a book with its JSON-LD embedded.
+func RenderHTML(site *Site, book *model.Book) ([]byte, *appError.Error) {
+	data := pageData{
+		Site:        site,
+		Book:        book,
+		URL:         site.BookURL(book),
+		Title:       book.Name,
+		Description: summary(book.Description),
+		JSONLD:      NewBookJSONLD(site, book),
+	}
+	if book.Subtitle != "" {
+		data.Title += ": " + book.Subtitle
+	}
+	var names []string
+	for _, author := range data.JSONLD.Author {
+		names = append(names, author.Name)
+	}
+	data.Authors = strings.Join(names, ", ")
+
+	var out bytes.Buffer
+	if err := bookPage.Execute(&out, data); err != nil {
+		log.Printf("Error rendering book page: %v, ID: %s", err, book.ID)
+		return nil, appError.NewUnexpectedError(err.Error())
+	}
+	return out.Bytes(), nil
+}
+
+// summary cuts a description at a word to fit a meta description.
+func summary(description string) string {
+	description = strings.Join(strings.Fields(description), " ")
+	if len(description) <= maxMetaDescription {
+		return description
+	}
+	cut := strings.LastIndex(description[:maxMetaDescription-len("...")], " ")
+	if cut <= 0 {
+		cut = maxMetaDescription - len("...")
+		for cut > 0 && !utf8.RuneStart(description[cut]) {
+			cut--
+		}
+	}
+	return strings.TrimSpace(description[:cut]) + "..."
+}
@@ -0,0 +1,88 @@
+package webpage_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"main/src/books/application/webpage"
+	"main/src/books/domain/model"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type WebpageSuite struct {
+	suite.Suite
+	site *webpage.Site
+	book *model.Book
+}
+
+func (suite *WebpageSuite) SetupTest() {
+	suite.site = &webpage.Site{Name: "Books Store", BaseURL: "https://books.example.com"}
+	suite.book = &model.Book{
+		ID:           "5f0c8a2e-3b0b-4f53-9d4a-0c1f9c6c2b11",
+		Name:         "The Great Gatsby",
+		Subtitle:     "A Novel",
+		Description:  "Gatsby & Daisy </script><script>alert(1)</script>",
+		ImgURL:       "https://example.com/gatsby.jpg",
+		ISBN:         "9780743273565",
+		Publisher:    "Riverbank Press",
+		PublishedOn:  "2004-09-30",
+		Contributors: []model.Contributor{{Name: "F. Scott Fitzgerald", Role: "author"}, {Name: "Jane Doe", Role: "foreword"}},
+		Prices:       []model.Price{{Amount: "12.99", Currency: "USD", Territory: "US"}},
+		Availability: "20",
+		Rating:       &model.RatingSummary{Average: 4.5, Count: 4},
+	}
+}
+
+func (suite *WebpageSuite) TestMarshalJSONLD() {
+	data, err := webpage.MarshalJSONLD(suite.site, suite.book)
+	suite.Nil(err)
+
+	var ld map[string]interface{}
+	suite.NoError(json.Unmarshal(data, &ld))
+	suite.Equal("https://schema.org", ld["@context"])
+	suite.Equal([]interface{}{"Book", "Product"}, ld["@type"])
+	suite.Equal("https://books.example.com/books/"+suite.book.ID, ld["url"])
+	suite.Equal("9780743273565", ld["gtin13"])
+	suite.Equal("F. Scott Fitzgerald", ld["author"].([]interface{})[0].(map[string]interface{})["name"])
+	suite.Equal(4.5, ld["aggregateRating"].(map[string]interface{})["ratingValue"])
+	offer := ld["offers"].([]interface{})[0].(map[string]interface{})
+	suite.Equal("12.99", offer["price"])
+	suite.Equal("https://schema.org/InStock", offer["availability"])
+}
+
+func (suite *WebpageSuite) TestRenderHTML() {
+	data, err := webpage.RenderHTML(suite.site, suite.book)
+	suite.Nil(err)
+	page := string(data)
+
+	suite.Contains(page, `<title>The Great Gatsby: A Novel | Books Store</title>`)
+	suite.Contains(page, `<link rel="canonical" href="https://books.example.com/books/`+suite.book.ID+`">`)
+	suite.Contains(page, `<meta property="og:image" content="https://example.com/gatsby.jpg">`)
+	suite.Contains(page, `<meta name="twitter:card" content="summary_large_image">`)
+	suite.Contains(page, `<p class="authors">by F. Scott Fitzgerald</p>`)
+	suite.Equal(1, strings.Count(page, "<script"), "the description must not open a script")
+
+	start := strings.Index(page, `<script type="application/ld+json">`) + len(`<script type="application/ld+json">`)
+	end := strings.Index(page, "</script>")
+	var ld map[string]interface{}
+	suite.NoError(json.Unmarshal([]byte(page[start:end]), &ld))
+	suite.Equal(suite.book.Description, ld["description"])
+}
+
+func (suite *WebpageSuite) TestRenderHTMLMinimal() {
+	long := strings.Repeat("word ", 60)
+	data, err := webpage.RenderHTML(&webpage.Site{}, &model.Book{ID: "1", Name: "Atlas", Description: long})
+	suite.Nil(err)
+	page := string(data)
+
+	suite.Contains(page, `<title>Atlas</title>`)
+	suite.Contains(page, `<meta name="twitter:card" content="summary">`)
+	suite.NotContains(page, "og:image")
+	suite.Contains(page, `word word...">`)
+}
+
+func TestWebpageSuite(t *testing.T) {
+	suite.Run(t, new(WebpageSuite))
+}
@@ -3,7 +3,9 @@ package model
 import (
 	appError "main/utils/error"
 	"main/utils/lib"
+	"math"
 	"strings"
+	"time"
 )
 
 type Book struct {
@@ -11,20 +13,238 @@ type Book struct {
 	Name        string `json:"name,omitempty" dynamodbav:"name,omitempty" mapstructure:"name"`
 	Description string `json:"description,omitempty" dynamodbav:"description,omitempty" mapstructure:"description"`
 	ImgURL      string `json:"img_url,omitempty" dynamodbav:"img_url,omitempty" mapstructure:"img_url"`
+	ISBN        string `json:"isbn,omitempty" dynamodbav:"isbn,omitempty" mapstructure:"isbn"`
+	Subtitle    string `json:"subtitle,omitempty" dynamodbav:"subtitle,omitempty" mapstructure:"subtitle"`
+	Publisher   string `json:"publisher,omitempty" dynamodbav:"publisher,omitempty" mapstructure:"publisher"`
+	PublishedOn string `json:"published_on,omitempty" dynamodbav:"published_on,omitempty" mapstructure:"published_on"`
+	RatingCount int    `json:"-" dynamodbav:"rating_count,omitempty" mapstructure:"-"`
+	RatingTotal int    `json:"-" dynamodbav:"rating_total,omitempty" mapstructure:"-"`
+
+	Contributors []Contributor `json:"contributors,omitempty" dynamodbav:"contributors,omitempty" mapstructure:"-"`
+	Subjects     []Subject     `json:"subjects,omitempty" dynamodbav:"subjects,omitempty" mapstructure:"-"`
+	Prices       []Price       `json:"prices,omitempty" dynamodbav:"prices,omitempty" mapstructure:"-"`
+	// Availability is the ONIX product availability code (list 65).
+	Availability string `json:"availability,omitempty" dynamodbav:"availability,omitempty" mapstructure:"-"`
+	// RecordReference identifies the publisher record a book was imported from.
+	RecordReference string `json:"record_reference,omitempty" dynamodbav:"record_reference,omitempty" mapstructure:"-"`
+
+	// CreatedAt and UpdatedAt are set by the service; books stored before
+	// they existed have neither.
+	CreatedAt *time.Time `json:"created_at,omitempty" dynamodbav:"created_at,omitempty" mapstructure:"-"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty" dynamodbav:"updated_at,omitempty" mapstructure:"-"`
+	// Revision is the number of the latest entry of the book history; books
+	// changed before the history existed have none.
+	Revision int `json:"revision,omitempty" dynamodbav:"revision,omitempty" mapstructure:"-"`
+	// DeletedAt is set while the book is in the trash. Reads leave trashed
+	// books out until they are restored or purged.
+	DeletedAt *time.Time `json:"deleted_at,omitempty" dynamodbav:"deleted_at,omitempty" mapstructure:"-"`
+
+	Rating *RatingSummary `json:"rating,omitempty" dynamodbav:"-" mapstructure:"-"`
+}
+
+// Trashed tells whether the book was deleted and waits in the trash.
+func (b *Book) Trashed() bool {
+	return b.DeletedAt != nil
+}
+
+// Clone returns a copy of the book that shares no slice or pointer with it,
+// so either can be changed without the other seeing it.
+func (b *Book) Clone() *Book {
+	clone := *b
+	clone.Contributors = cloneSlice(b.Contributors)
+	clone.Subjects = cloneSlice(b.Subjects)
+	clone.Prices = cloneSlice(b.Prices)
+	clone.CreatedAt = clonePointer(b.CreatedAt)
+	clone.UpdatedAt = clonePointer(b.UpdatedAt)
+	clone.DeletedAt = clonePointer(b.DeletedAt)
+	clone.Rating = clonePointer(b.Rating)
+	return &clone
+}
+
+func cloneSlice[T any](s []T) []T {
+	if s == nil {
+		return nil
+	}
+	return append(make([]T, 0, len(s)), s...)
+}
+
+func clonePointer[T any](p *T) *T {
+	if p == nil {
+		return nil
+	}
+	v := *p
+	return &v
+}
+
+// BooksByIDs is the result of a batch read: the books found, in request
+// order, and the requested IDs that did not match any book.
+type BooksByIDs struct {
+	Books      []Book   `json:"books"`
+	MissingIDs []string `json:"missing_ids"`
+}
+
+type BatchItemStatus string
+
+const (
+	BatchItemCreated  BatchItemStatus = "created"
+	BatchItemUpdated  BatchItemStatus = "updated"
+	BatchItemDeleted  BatchItemStatus = "deleted"
+	BatchItemInvalid  BatchItemStatus = "invalid"
+	BatchItemNotFound BatchItemStatus = "not_found"
+	// BatchItemConflict is a book created with the ID of a stored one,
+	// which is left as it was.
+	BatchItemConflict BatchItemStatus = "conflict"
+	BatchItemFailed   BatchItemStatus = "failed"
+)
+
+// BatchItemResult is the outcome of one book in a batch creation, identified
+// by its position in the request.
+type BatchItemResult struct {
+	Index  int             `json:"index"`
+	ID     string          `json:"ID,omitempty"`
+	Status BatchItemStatus `json:"status"`
+	Errors []string        `json:"errors,omitempty"`
+}
+
+// BatchReport summarizes a batch operation with one result per requested book.
+type BatchReport struct {
+	Created   int               `json:"created,omitempty"`
+	Updated   int               `json:"updated,omitempty"`
+	Deleted   int               `json:"deleted,omitempty"`
+	Invalid   int               `json:"invalid"`
+	NotFound  int               `json:"not_found,omitempty"`
+	Conflicts int               `json:"conflicts,omitempty"`
+	Failed    int               `json:"failed"`
+	Items     []BatchItemResult `json:"items"`
+}
+
+func (r *BatchReport) Add(item BatchItemResult) {
+	switch item.Status {
+	case BatchItemCreated:
+		r.Created++
+	case BatchItemUpdated:
+		r.Updated++
+	case BatchItemDeleted:
+		r.Deleted++
+	case BatchItemInvalid:
+		r.Invalid++
+	case BatchItemNotFound:
+		r.NotFound++
+	case BatchItemConflict:
+		r.Conflicts++
+	case BatchItemFailed:
+		r.Failed++
+	}
+	r.Items = append(r.Items, item)
+}
+
+// RatingSummary is derived from the rating aggregate maintained by reviews.
+type RatingSummary struct {
+	Average float64 `json:"average"`
+	Count   int     `json:"count"`
+}
+
+// SummarizeRating fills Rating from the stored aggregate, rounded to two decimals.
+func (b *Book) SummarizeRating() {
+	if b.RatingCount <= 0 {
+		b.Rating = nil
+		return
+	}
+	average := float64(b.RatingTotal) / float64(b.RatingCount)
+	b.Rating = &RatingSummary{
+		Average: math.Round(average*100) / 100,
+		Count:   b.RatingCount,
+	}
+}
+
+// ImageKey returns the object key of the book's image under bucketKey, or an
+// empty string when the image is not stored in the books bucket.
+func (b *Book) ImageKey(bucketKey string) string {
+	if bucketKey == "" {
+		return ""
+	}
+	_, fileName, found := strings.Cut(b.ImgURL, "/"+bucketKey)
+	if !found || fileName == "" {
+		return ""
+	}
+	return bucketKey + fileName
 }
 
 func (b *Book) Validate() *appError.Error {
-	if err := lib.ValidateUUID(b.ID); err != nil {
-		return err
+	if errs := b.ValidateAll(); len(errs) > 0 {
+		return errs[0]
 	}
-	if err := lib.ValidateStringNotEmpty(b.Name); err != nil {
-		return err
+	return nil
+}
+
+// ValidateAll reports every field error instead of stopping at the first one,
+// so batch callers can tell the client everything wrong with an item.
+func (b *Book) ValidateAll() []*appError.Error {
+	checks := []*appError.Error{
+		lib.ValidateUUID(b.ID),
+		lib.ValidateStringNotEmpty(b.Name),
+		lib.ValidateMaxStringCharacteres(b.Description, 200),
+		validateImgURL(b.ImgURL),
+		validateISBN(b.ISBN),
 	}
-	if err := lib.ValidateMaxStringCharacteres(b.Description, 200); err != nil {
-		return err
+	var errs []*appError.Error
+	for _, err := range checks {
+		if err != nil {
+			errs = append(errs, err)
+		}
 	}
-	if !strings.HasPrefix(b.ImgURL, "http://") && !strings.HasPrefix(b.ImgURL, "https://") {
+	return errs
+}
+
+func validateImgURL(url string) *appError.Error {
+	if !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://") {
 		return appError.NewValidationError("Image URL must start with 'http://' or 'https://'.")
 	}
 	return nil
 }
+
+// NormalizeISBN strips hyphens and spaces so equivalent ISBNs compare equal.
+func NormalizeISBN(isbn string) string {
+	return strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(isbn))
+}
+
+// validateISBN accepts an empty value or a valid ISBN-10 or ISBN-13.
+func validateISBN(isbn string) *appError.Error {
+	if isbn == "" {
+		return nil
+	}
+	digits := NormalizeISBN(isbn)
+	sum := 0
+	switch len(digits) {
+	case 10:
+		for i, c := range digits {
+			value := int(c - '0')
+			if c == 'X' && i == 9 {
+				value = 10
+			} else if c < '0' || c > '9' {
+				return appError.NewValidationError("ISBN must contain only digits.")
+			}
+			sum += value * (10 - i)
+		}
+		if sum%11 == 0 {
+			return nil
+		}
+	case 13:
+		for i, c := range digits {
+			if c < '0' || c > '9' {
+				return appError.NewValidationError("ISBN must contain only digits.")
+			}
+			weight := 1
+			if i%2 == 1 {
+				weight = 3
+			}
+			sum += int(c-'0') * weight
+		}
+		if sum%10 == 0 {
+			return nil
+		}
+	default:
+		return appError.NewValidationError("ISBN must have 10 or 13 digits.")
+	}
+	return appError.NewValidationError("ISBN check digit is invalid.")
+}
@@ -0,0 +1,131 @@
+package model
+
+import (
+	"fmt"
+	"time"
+
+	appError "main/utils/error"
+)
+
+// BookChangeType names a fact of the event stream of a book.
+type BookChangeType string
+
+const (
+	// BookChangeCreated puts Book in place of whatever was there.
+	BookChangeCreated BookChangeType = "created"
+	// BookChangeEdited sets the name, description, image and update time of
+	// Book, creating the book when there is none.
+	BookChangeEdited BookChangeType = "edited"
+	// BookChangeReplaced sets every catalog field of Book and keeps the
+	// record reference, creation time and rating of the book.
+	BookChangeReplaced BookChangeType = "replaced"
+	// BookChangeReverted brings the book back to Book, out of the trash,
+	// keeping only its rating.
+	BookChangeReverted  BookChangeType = "reverted"
+	BookChangeTrashed   BookChangeType = "trashed"
+	BookChangeUntrashed BookChangeType = "untrashed"
+	BookChangePurged    BookChangeType = "purged"
+)
+
+// BookChange is an immutable entry of the event stream of a book. The
+// entries of a book are numbered from 1 without gaps.
+type BookChange struct {
+	BookID   string         `json:"book_id" dynamodbav:"book_id"`
+	Sequence int            `json:"sequence" dynamodbav:"sequence"`
+	Type     BookChangeType `json:"type" dynamodbav:"type"`
+	At       time.Time      `json:"at" dynamodbav:"at"`
+	// Book holds the fields the change sets; trashing, untrashing and
+	// purging carry none.
+	Book *Book `json:"book,omitempty" dynamodbav:"book,omitempty"`
+}
+
+// BookAggregate is a book as folded from its event stream, and the snapshot
+// the fold can resume from.
+type BookAggregate struct {
+	ID string `json:"book_id" dynamodbav:"book_id"`
+	// Version is the sequence of the last change applied, 0 before any.
+	Version int `json:"sequence" dynamodbav:"sequence"`
+	// Book is nil while the book does not exist.
+	Book *Book `json:"book,omitempty" dynamodbav:"book,omitempty"`
+}
+
+// Apply folds changes into the aggregate in order. A change out of sequence,
+// or one the state of the book does not allow, leaves the aggregate as it
+// was at that change and fails. The book is copied on every change, so
+// books handed out earlier are never modified.
+func (a *BookAggregate) Apply(changes ...BookChange) *appError.Error {
+	for _, change := range changes {
+		if change.BookID != a.ID || change.Sequence != a.Version+1 {
+			return appError.NewUnexpectedError(fmt.Sprintf("Change %d of book %s does not follow version %d of book %s.", change.Sequence, change.BookID, a.Version, a.ID))
+		}
+		book, err := a.next(change)
+		if err != nil {
+			return err
+		}
+		if book != nil {
+			book.ID = a.ID
+			book.Revision = change.Sequence
+		}
+		a.Book = book
+		a.Version = change.Sequence
+	}
+	return nil
+}
+
+// next is the book after change.
+func (a *BookAggregate) next(change BookChange) (*Book, *appError.Error) {
+	if change.Book == nil && (change.Type == BookChangeCreated || change.Type == BookChangeEdited ||
+		change.Type == BookChangeReplaced || change.Type == BookChangeReverted) {
+		return nil, appError.NewUnexpectedError(fmt.Sprintf("Change %d of book %s carries no book.", change.Sequence, a.ID))
+	}
+
+	switch change.Type {
+	case BookChangeCreated:
+		book := *change.Book
+		book.DeletedAt = nil
+		return &book, nil
+	case BookChangeEdited:
+		book := Book{ID: a.ID}
+		if a.Book != nil {
+			book = *a.Book
+		}
+		book.Name = change.Book.Name
+		book.Description = change.Book.Description
+		book.ImgURL = change.Book.ImgURL
+		if change.Book.UpdatedAt != nil {
+			book.UpdatedAt = change.Book.UpdatedAt
+		}
+		return &book, nil
+	case BookChangeReverted:
+		book := *change.Book
+		book.DeletedAt = nil
+		book.RatingCount, book.RatingTotal = 0, 0
+		if a.Book != nil {
+			book.RatingCount, book.RatingTotal = a.Book.RatingCount, a.Book.RatingTotal
+		}
+		return &book, nil
+	case BookChangePurged:
+		return nil, nil
+	}
+
+	if a.Book == nil {
+		return nil, appError.NewUnexpectedError(fmt.Sprintf("Change %d of book %s applies to no book.", change.Sequence, a.ID))
+	}
+	book := *a.Book
+	switch change.Type {
+	case BookChangeReplaced:
+		book = *change.Book
+		book.RecordReference = a.Book.RecordReference
+		book.CreatedAt = a.Book.CreatedAt
+		book.RatingCount, book.RatingTotal = a.Book.RatingCount, a.Book.RatingTotal
+		book.DeletedAt = a.Book.DeletedAt
+	case BookChangeTrashed:
+		deletedAt := change.At
+		book.DeletedAt = &deletedAt
+	case BookChangeUntrashed:
+		book.DeletedAt = nil
+	default:
+		return nil, appError.NewUnexpectedError(fmt.Sprintf("Change %d of book %s has unknown type %q.", change.Sequence, a.ID, change.Type))
+	}
+	return &book, nil
+}
@@ -0,0 +1,66 @@
+package model
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type BookEventType string
+
+const (
+	BookCreated      BookEventType = "BookCreated"
+	BookUpdated      BookEventType = "BookUpdated"
+	BookDeleted      BookEventType = "BookDeleted"
+	BookImageChanged BookEventType = "BookImageChanged"
+)
+
+// BookEvent is a change to a book, published for other services. Events are
+// delivered at least once: consumers drop the ones whose ID they have seen.
+type BookEvent struct {
+	ID         string        `json:"id" dynamodbav:"ID"`
+	Type       BookEventType `json:"type" dynamodbav:"type"`
+	BookID     string        `json:"book_id" dynamodbav:"book_id"`
+	OccurredAt time.Time     `json:"occurred_at" dynamodbav:"occurred_at"`
+	// Book is the book after the change; deletions carry none.
+	Book *Book `json:"book,omitempty" dynamodbav:"book,omitempty"`
+	// PreviousImgURL is the image a BookImageChanged event replaced.
+	PreviousImgURL string `json:"previous_img_url,omitempty" dynamodbav:"previous_img_url,omitempty"`
+	// Sequence orders the events of the outbox, including the ones written in
+	// the same transaction.
+	Sequence string `json:"-" dynamodbav:"sequence"`
+	// Attempts counts the failed deliveries of the event.
+	Attempts int `json:"-" dynamodbav:"attempts,omitempty"`
+}
+
+// NewBookEvents returns the events of a change from previous to book: nil
+// previous is a creation and nil book a deletion. An update that replaces
+// the image also raises BookImageChanged.
+func NewBookEvents(bookID string, previous, book *Book) []BookEvent {
+	at := time.Now().UTC()
+	event := func(eventType BookEventType, offset int) BookEvent {
+		return BookEvent{
+			ID:         uuid.NewString(),
+			Type:       eventType,
+			BookID:     bookID,
+			OccurredAt: at,
+			Book:       book,
+			Sequence:   fmt.Sprintf("%020d", at.UnixNano()+int64(offset)),
+		}
+	}
+
+	switch {
+	case book == nil:
+		return []BookEvent{event(BookDeleted, 0)}
+	case previous == nil:
+		return []BookEvent{event(BookCreated, 0)}
+	}
+	events := []BookEvent{event(BookUpdated, 0)}
+	if previous.ImgURL != book.ImgURL {
+		changed := event(BookImageChanged, 1)
+		changed.PreviousImgURL = previous.ImgURL
+		events = append(events, changed)
+	}
+	return events
+}
@@ -0,0 +1,19 @@
+package model
+
+import "time"
+
+// BookFilter narrows a listing of books. Empty fields match every book.
+type BookFilter struct {
+	// Name matches the books whose name contains it, ignoring case.
+	Name         string     `json:"name,omitempty"`
+	Publisher    string     `json:"publisher,omitempty"`
+	Availability string     `json:"availability,omitempty"`
+	UpdatedSince *time.Time `json:"updated_since,omitempty"`
+}
+
+// BookPage is a page of a listing of books ordered by name. Next is the
+// opaque cursor of the following page, empty on the last one.
+type BookPage struct {
+	Books []Book `json:"books"`
+	Next  string `json:"next,omitempty"`
+}
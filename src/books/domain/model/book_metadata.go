@@ -0,0 +1,24 @@
+package model
+
+// Contributor is a person or organization credited on a book.
+type Contributor struct {
+	Name string `json:"name" dynamodbav:"name"`
+	Role string `json:"role,omitempty" dynamodbav:"role,omitempty"`
+}
+
+// Subject classifies a book under a subject scheme such as BISAC or Thema.
+type Subject struct {
+	Scheme  string `json:"scheme" dynamodbav:"scheme"`
+	Code    string `json:"code,omitempty" dynamodbav:"code,omitempty"`
+	Heading string `json:"heading,omitempty" dynamodbav:"heading,omitempty"`
+	Main    bool   `json:"main,omitempty" dynamodbav:"main,omitempty"`
+}
+
+// Price is kept as the decimal string the publisher sent, so amounts are
+// never altered by floating point conversion.
+type Price struct {
+	Type      string `json:"type,omitempty" dynamodbav:"type,omitempty"`
+	Amount    string `json:"amount" dynamodbav:"amount"`
+	Currency  string `json:"currency" dynamodbav:"currency"`
+	Territory string `json:"territory,omitempty" dynamodbav:"territory,omitempty"`
+}
@@ -0,0 +1,121 @@
+package model
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"time"
+)
+
+type BookOperation string
+
+const (
+	BookOperationCreated  BookOperation = "created"
+	BookOperationUpdated  BookOperation = "updated"
+	BookOperationDeleted  BookOperation = "deleted"
+	BookOperationRestored BookOperation = "restored"
+)
+
+// SystemActor is recorded for changes made without a known user, such as
+// scheduled imports.
+const SystemActor = "system"
+
+// BookRevision is an entry of the append-only history of a book. Revisions
+// are numbered from 1 per book and written in the same transaction as the
+// change they record.
+type BookRevision struct {
+	BookID    string        `json:"book_id" dynamodbav:"book_id"`
+	Revision  int           `json:"revision" dynamodbav:"revision"`
+	Operation BookOperation `json:"operation" dynamodbav:"operation"`
+	Actor     string        `json:"actor" dynamodbav:"actor"`
+	At        time.Time     `json:"at" dynamodbav:"at"`
+	Changes   []FieldChange `json:"changes" dynamodbav:"changes"`
+	// ImageChanged flags the revisions that replaced or removed the image.
+	ImageChanged bool `json:"image_changed,omitempty" dynamodbav:"image_changed,omitempty"`
+	// Book is the book after the change, which restoring the revision brings
+	// back; deletions carry none.
+	Book *Book `json:"book,omitempty" dynamodbav:"book,omitempty"`
+}
+
+// FieldChange is the value of a book field before and after a change, using
+// the JSON names of the fields. A missing value means the field was empty.
+type FieldChange struct {
+	Field  string      `json:"field" dynamodbav:"field"`
+	Before interface{} `json:"before,omitempty" dynamodbav:"before,omitempty"`
+	After  interface{} `json:"after,omitempty" dynamodbav:"after,omitempty"`
+}
+
+// untrackedFields change with every write or are maintained elsewhere, so
+// they are left out of the diffs.
+var untrackedFields = map[string]bool{
+	"ID":         true,
+	"revision":   true,
+	"created_at": true,
+	"updated_at": true,
+	"rating":     true,
+}
+
+// NewBookRevision records the change from previous to book: nil previous is a
+// creation and nil book a deletion, unless operation says otherwise.
+func NewBookRevision(bookID string, revision int, operation BookOperation, actor string, previous, book *Book) BookRevision {
+	if actor == "" {
+		actor = SystemActor
+	}
+	changes := DiffBooks(previous, book)
+	imageChanged := false
+	for _, change := range changes {
+		if change.Field == "img_url" {
+			imageChanged = true
+		}
+	}
+	return BookRevision{
+		BookID:       bookID,
+		Revision:     revision,
+		Operation:    operation,
+		Actor:        actor,
+		At:           time.Now().UTC(),
+		Changes:      changes,
+		ImageChanged: imageChanged,
+		Book:         book,
+	}
+}
+
+// DiffBooks lists the fields that differ between before and after, sorted by
+// name. Either book may be nil.
+func DiffBooks(before, after *Book) []FieldChange {
+	beforeFields, afterFields := bookFields(before), bookFields(after)
+	names := make([]string, 0, len(beforeFields)+len(afterFields))
+	for name := range beforeFields {
+		names = append(names, name)
+	}
+	for name := range afterFields {
+		if _, ok := beforeFields[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	changes := []FieldChange{}
+	for _, name := range names {
+		if untrackedFields[name] || reflect.DeepEqual(beforeFields[name], afterFields[name]) {
+			continue
+		}
+		changes = append(changes, FieldChange{Field: name, Before: beforeFields[name], After: afterFields[name]})
+	}
+	return changes
+}
+
+// bookFields is the book as generic JSON values, so every field, nested ones
+// included, compares the same way.
+func bookFields(book *Book) map[string]interface{} {
+	fields := map[string]interface{}{}
+	if book == nil {
+		return fields
+	}
+	data, err := json.Marshal(book)
+	if err != nil {
+		return fields
+	}
+	json.Unmarshal(data, &fields)
+	return fields
+}
@@ -4,6 +4,7 @@ import (
 	"main/src/books/domain/model"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/suite"
 )
@@ -39,6 +40,219 @@ func (s *BookModelSuite) TestValidate() {
 	}
 }
 
+func (s *BookModelSuite) TestValidateAll() {
+	book := model.Book{ID: "invalid-uuid", Name: " ", Description: "A classic novel.", ImgURL: "ftp://example.com/image.jpg"}
+	errs := book.ValidateAll()
+	s.Len(errs, 3)
+	s.Equal(errs[0], book.Validate())
+
+	book = model.Book{ID: "123e4567-e89b-12d3-a456-426614174000", Name: "The Great Gatsby", ImgURL: "https://example.com/image.jpg"}
+	s.Empty(book.ValidateAll())
+}
+
+func (s *BookModelSuite) TestValidateISBN() {
+	var tests = []struct {
+		isbn     string
+		expected bool
+	}{
+		{"", true},
+		{"978-0-7432-7356-5", true},
+		{"0-7432-7356-7", true},
+		{"080442957X", true},
+		{"978-0-7432-7356-4", false},
+		{"12345", false},
+		{"97807432A3565", false},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.isbn, func() {
+			book := model.Book{ID: "123e4567-e89b-12d3-a456-426614174000", Name: "The Great Gatsby", ImgURL: "https://example.com/image.jpg", ISBN: tt.isbn}
+			if tt.expected {
+				s.Nil(book.Validate())
+			} else {
+				s.NotNil(book.Validate())
+			}
+		})
+	}
+}
+
+func (s *BookModelSuite) TestImageKey() {
+	book := model.Book{ImgURL: "https://books-bucket.s3.amazonaws.com/images/123.jpg"}
+	s.Equal("images/123.jpg", book.ImageKey("images/"))
+	s.Equal("", book.ImageKey("covers/"))
+	s.Equal("", book.ImageKey(""))
+}
+
+func (s *BookModelSuite) TestTrashed() {
+	book := model.Book{ID: "1"}
+	s.False(book.Trashed())
+	deletedAt := time.Now().UTC()
+	book.DeletedAt = &deletedAt
+	s.True(book.Trashed())
+}
+
+func (s *BookModelSuite) TestSummarizeRating() {
+	book := model.Book{RatingCount: 3, RatingTotal: 11}
+	book.SummarizeRating()
+	s.NotNil(book.Rating)
+	s.Equal(3, book.Rating.Count)
+	s.Equal(3.67, book.Rating.Average)
+
+	book = model.Book{}
+	book.SummarizeRating()
+	s.Nil(book.Rating)
+}
+
+func (s *BookModelSuite) TestNewBookEvents() {
+	previous := &model.Book{ID: "1", Name: "Dune", ImgURL: "https://example.com/old.jpg"}
+	renamed := &model.Book{ID: "1", Name: "Dune Messiah", ImgURL: previous.ImgURL}
+	reimaged := &model.Book{ID: "1", Name: "Dune", ImgURL: "https://example.com/new.jpg"}
+
+	created := model.NewBookEvents("1", nil, previous)
+	s.Len(created, 1)
+	s.Equal(model.BookCreated, created[0].Type)
+	s.Equal(previous, created[0].Book)
+
+	updated := model.NewBookEvents("1", previous, renamed)
+	s.Len(updated, 1)
+	s.Equal(model.BookUpdated, updated[0].Type)
+
+	changed := model.NewBookEvents("1", previous, reimaged)
+	s.Len(changed, 2)
+	s.Equal(model.BookImageChanged, changed[1].Type)
+	s.Equal(previous.ImgURL, changed[1].PreviousImgURL)
+	s.NotEqual(changed[0].ID, changed[1].ID)
+	s.Less(changed[0].Sequence, changed[1].Sequence)
+
+	deleted := model.NewBookEvents("1", nil, nil)
+	s.Equal(model.BookDeleted, deleted[0].Type)
+	s.Nil(deleted[0].Book)
+}
+
+func (s *BookModelSuite) TestDiffBooks() {
+	before := &model.Book{ID: "1", Name: "Dune", Description: "Desert planet.", Revision: 1,
+		Subjects: []model.Subject{{Scheme: "BISAC", Code: "FIC028000"}}}
+	after := &model.Book{ID: "1", Name: "Dune", Description: "vandalized", ISBN: "9780441172719", Revision: 2}
+
+	changes := model.DiffBooks(before, after)
+	s.Equal([]model.FieldChange{
+		{Field: "description", Before: "Desert planet.", After: "vandalized"},
+		{Field: "isbn", After: "9780441172719"},
+		{Field: "subjects", Before: []interface{}{map[string]interface{}{"scheme": "BISAC", "code": "FIC028000"}}},
+	}, changes)
+	s.Empty(model.DiffBooks(before, before))
+	s.Len(model.DiffBooks(nil, after), 3, "A creation lists every field set")
+}
+
+func (s *BookModelSuite) TestNewBookRevision() {
+	previous := &model.Book{ID: "1", Name: "Dune", ImgURL: "https://example.com/old.jpg"}
+	reimaged := &model.Book{ID: "1", Name: "Dune", ImgURL: "https://example.com/new.jpg"}
+
+	revision := model.NewBookRevision("1", 2, model.BookOperationUpdated, "editor-7", previous, reimaged)
+	s.Equal(2, revision.Revision)
+	s.Equal("editor-7", revision.Actor)
+	s.True(revision.ImageChanged)
+	s.Equal(reimaged, revision.Book)
+
+	deleted := model.NewBookRevision("1", 3, model.BookOperationDeleted, "", reimaged, nil)
+	s.Equal(model.SystemActor, deleted.Actor)
+	s.Nil(deleted.Book)
+	s.Len(deleted.Changes, 2)
+}
+
+func (s *BookModelSuite) TestBookAggregateApply() {
+	at := time.Date(2024, 5, 3, 9, 0, 0, 0, time.UTC)
+	change := func(sequence int, changeType model.BookChangeType, book *model.Book) model.BookChange {
+		return model.BookChange{BookID: "1", Sequence: sequence, Type: changeType, At: at, Book: book}
+	}
+	created := &model.Book{Name: "Dune", Description: "Desert planet", RecordReference: "ref-1", CreatedAt: &at, RatingCount: 2, RatingTotal: 9}
+
+	var tests = []struct {
+		name     string
+		changes  []model.BookChange
+		expected *model.Book
+		fails    bool
+	}{
+		{"created", []model.BookChange{change(1, model.BookChangeCreated, created)},
+			&model.Book{ID: "1", Name: "Dune", Description: "Desert planet", RecordReference: "ref-1", CreatedAt: &at, RatingCount: 2, RatingTotal: 9, Revision: 1}, false},
+		{"edited without book", []model.BookChange{change(1, model.BookChangeEdited, &model.Book{Name: "Emma"})},
+			&model.Book{ID: "1", Name: "Emma", Revision: 1}, false},
+		{"edited keeps other fields", []model.BookChange{change(1, model.BookChangeCreated, created), change(2, model.BookChangeEdited, &model.Book{Name: "Dune Messiah"})},
+			&model.Book{ID: "1", Name: "Dune Messiah", RecordReference: "ref-1", CreatedAt: &at, RatingCount: 2, RatingTotal: 9, Revision: 2}, false},
+		{"replaced keeps reference, creation and rating", []model.BookChange{change(1, model.BookChangeCreated, created), change(2, model.BookChangeReplaced, &model.Book{Name: "Dune", ISBN: "9780441013593"})},
+			&model.Book{ID: "1", Name: "Dune", ISBN: "9780441013593", RecordReference: "ref-1", CreatedAt: &at, RatingCount: 2, RatingTotal: 9, Revision: 2}, false},
+		{"trashed", []model.BookChange{change(1, model.BookChangeCreated, created), change(2, model.BookChangeTrashed, nil)},
+			&model.Book{ID: "1", Name: "Dune", Description: "Desert planet", RecordReference: "ref-1", CreatedAt: &at, RatingCount: 2, RatingTotal: 9, Revision: 2, DeletedAt: &at}, false},
+		{"untrashed", []model.BookChange{change(1, model.BookChangeCreated, created), change(2, model.BookChangeTrashed, nil), change(3, model.BookChangeUntrashed, nil)},
+			&model.Book{ID: "1", Name: "Dune", Description: "Desert planet", RecordReference: "ref-1", CreatedAt: &at, RatingCount: 2, RatingTotal: 9, Revision: 3}, false},
+		{"reverted keeps rating only", []model.BookChange{change(1, model.BookChangeCreated, created), change(2, model.BookChangeTrashed, nil), change(3, model.BookChangeReverted, &model.Book{Name: "Dune"})},
+			&model.Book{ID: "1", Name: "Dune", RatingCount: 2, RatingTotal: 9, Revision: 3}, false},
+		{"purged", []model.BookChange{change(1, model.BookChangeCreated, created), change(2, model.BookChangePurged, nil)}, nil, false},
+		{"out of sequence", []model.BookChange{change(2, model.BookChangeCreated, created)}, nil, true},
+		{"replaced without book", []model.BookChange{change(1, model.BookChangeReplaced, created)}, nil, true},
+		{"created without payload", []model.BookChange{change(1, model.BookChangeCreated, nil)}, nil, true},
+		{"unknown type", []model.BookChange{change(1, model.BookChangeCreated, created), change(2, "renamed", nil)}, nil, true},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			aggregate := &model.BookAggregate{ID: "1"}
+			err := aggregate.Apply(tt.changes...)
+			if tt.fails {
+				s.NotNil(err)
+				return
+			}
+			s.Nil(err)
+			s.Equal(len(tt.changes), aggregate.Version)
+			s.Equal(tt.expected, aggregate.Book)
+		})
+	}
+}
+
+func (s *BookModelSuite) TestBookAggregateDoesNotModifyBooks() {
+	created := &model.Book{Name: "Dune"}
+	aggregate := &model.BookAggregate{ID: "1"}
+	s.Nil(aggregate.Apply(model.BookChange{BookID: "1", Sequence: 1, Type: model.BookChangeCreated, Book: created}))
+	before := aggregate.Book
+
+	s.Nil(aggregate.Apply(model.BookChange{BookID: "1", Sequence: 2, Type: model.BookChangeTrashed, At: time.Now()}))
+	s.Nil(before.DeletedAt)
+	s.Equal("", created.ID)
+	s.NotNil(aggregate.Book.DeletedAt)
+
+	// Resuming from a snapshot folds the remaining changes only.
+	snapshot := model.BookAggregate{ID: "1", Version: aggregate.Version, Book: aggregate.Book}
+	s.Nil(snapshot.Apply(model.BookChange{BookID: "1", Sequence: 3, Type: model.BookChangeUntrashed}))
+	s.Nil(snapshot.Book.DeletedAt)
+	s.Equal(3, snapshot.Book.Revision)
+}
+
+func (s *BookModelSuite) TestCloneSharesNothing() {
+	createdAt := time.Now()
+	book := &model.Book{
+		Name:         "Dune",
+		Contributors: []model.Contributor{{Name: "Frank Herbert", Role: "author"}},
+		Subjects:     []model.Subject{{Scheme: "BISAC", Code: "FIC028000"}},
+		Prices:       []model.Price{{Amount: "9.99", Currency: "USD"}},
+		CreatedAt:    &createdAt,
+		Rating:       &model.RatingSummary{Average: 4.5, Count: 2},
+	}
+	clone := book.Clone()
+	s.Equal(book, clone)
+
+	clone.Contributors[0].Name = "Brian Herbert"
+	clone.Subjects[0].Code = "FIC009000"
+	clone.Prices[0].Amount = "0.00"
+	*clone.CreatedAt = createdAt.Add(time.Hour)
+	clone.Rating.Count = 3
+	s.Equal("Frank Herbert", book.Contributors[0].Name)
+	s.Equal("FIC028000", book.Subjects[0].Code)
+	s.Equal("9.99", book.Prices[0].Amount)
+	s.Equal(createdAt, *book.CreatedAt)
+	s.Equal(2, book.Rating.Count)
+	s.Nil(clone.UpdatedAt)
+}
+
 func TestBookModelSuite(t *testing.T) {
 	suite.Run(t, new(BookModelSuite))
 }
@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"time"
+
+	"main/src/books/domain/model"
+	appError "main/utils/error"
+)
+
+// BookCacheRepository is a cache of books shared by every Lambda container,
+// such as ElastiCache, behind the in-process cache of the book service. A
+// nil book is the cached answer that the book does not exist.
+type BookCacheRepository interface {
+	GetBook(id string) (book *model.Book, found bool, err *appError.Error)
+	SetBook(id string, book *model.Book, ttl time.Duration) *appError.Error
+	DeleteBook(id string) *appError.Error
+}
@@ -2,10 +2,22 @@ package repository
 
 import (
 	"bytes"
+	"io"
 	appError "main/utils/error"
 )
 
 type BookFileRepository interface {
 	DeleteBookFile(string) *appError.Error
+	GetBookFile(string) ([]byte, *appError.Error)
 	SaveBookFile(*bytes.Reader, string, string) *appError.Error
+	// GetBookFileVersion also returns the version of the stored file, for a
+	// later SaveBookFileVersion.
+	GetBookFileVersion(string) ([]byte, string, *appError.Error)
+	// SaveBookFileVersion only replaces the file while it is still at the
+	// given version, or creates it when the version is empty. A file another
+	// writer saved first gives a conflict. It returns the new version.
+	SaveBookFileVersion(*bytes.Reader, string, string, string) (string, *appError.Error)
+	// UploadBookFile stores a file of unknown size as it is read, for the
+	// files too large to hold in memory.
+	UploadBookFile(io.Reader, string, string) *appError.Error
 }
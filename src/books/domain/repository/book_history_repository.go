@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"main/src/books/domain/model"
+	appError "main/utils/error"
+)
+
+// BookHistoryRepository reads the revisions the book repository records with
+// every change. GetBookRevisions pages newest first through the revisions
+// older than the given one, 0 starting from the latest.
+type BookHistoryRepository interface {
+	GetBookRevisions(string, int, int) ([]model.BookRevision, *appError.Error)
+	GetBookRevision(string, int) (*model.BookRevision, *appError.Error)
+}
@@ -0,0 +1,12 @@
+package repository
+
+import (
+	"main/src/books/domain/model"
+	appError "main/utils/error"
+)
+
+// BookListingRepository pages through the books matching a filter. The
+// cursor is the Next of the previous page, empty for the first one.
+type BookListingRepository interface {
+	ListBooks(model.BookFilter, string, int) (*model.BookPage, *appError.Error)
+}
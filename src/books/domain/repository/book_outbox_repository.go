@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"main/src/books/domain/model"
+	appError "main/utils/error"
+)
+
+// BookOutboxRepository reads the book events written alongside the book
+// changes and records their delivery.
+type BookOutboxRepository interface {
+	GetPendingEvents(int) ([]model.BookEvent, *appError.Error)
+	MarkEventPublished(string) *appError.Error
+	RecordEventFailure(string, string) *appError.Error
+}
@@ -7,9 +7,18 @@ import (
 
 type BookRepository interface {
 	GetAllBooks() ([]model.Book, *appError.Error)
+	ScanBooks(int, func([]model.Book) *appError.Error) *appError.Error
 	CreateBook(*model.Book) (*model.Book, *appError.Error)
-	CreateBatchBooks([]model.Book) *appError.Error
+	CreateBatchBooks([]model.Book) map[string]*appError.Error
 	GetBookByID(string) (*model.Book, *appError.Error)
+	GetBooksByIDs([]string) (*model.BooksByIDs, *appError.Error)
 	UpdateBookByID(string, *model.Book) (*model.Book, *appError.Error)
 	DeleteBookByID(string) *appError.Error
+	UpdateBooks([]model.Book) map[string]*appError.Error
+	DeleteBooksByIDs([]string) map[string]*appError.Error
+	GetRecentBooks(int) ([]model.Book, *appError.Error)
+	RestoreBook(*model.Book) *appError.Error
+	GetTrashedBooks(int) ([]model.Book, *appError.Error)
+	RestoreTrashedBook(string) *appError.Error
+	PurgeBook(string) *appError.Error
 }
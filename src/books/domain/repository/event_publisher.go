@@ -0,0 +1,11 @@
+package repository
+
+import (
+	"main/src/books/domain/model"
+	appError "main/utils/error"
+)
+
+// EventPublisher delivers book events to other services.
+type EventPublisher interface {
+	Publish(*model.BookEvent) *appError.Error
+}
@@ -0,0 +1,66 @@
+package adapter
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/eventbridge"
+	"github.com/aws/aws-sdk-go-v2/service/eventbridge/types"
+	"main/src/books/domain/model"
+	"main/src/books/domain/repository"
+	appError "main/utils/error"
+)
+
+// EventSource is the source of the book events put on EventBridge.
+const EventSource = "books"
+
+type BookEventPublisherEventBridge struct {
+	ctx     context.Context
+	client  *eventbridge.Client
+	busName string
+}
+
+func NewBookEventPublisherEventBridge(ctx context.Context, client *eventbridge.Client, busName string) repository.EventPublisher {
+	return &BookEventPublisherEventBridge{
+		ctx:     ctx,
+		client:  client,
+		busName: busName,
+	}
+}
+
+// Publish puts the event on the bus with its type as detail type. EventBridge
+// has no deduplication, so rules match on the type and consumers drop the
+// detail IDs they have seen.
+func (p *BookEventPublisherEventBridge) Publish(event *model.BookEvent) *appError.Error {
+	detail, err := json.Marshal(event)
+	if err != nil {
+		log.Printf("Error marshaling event: %v, ID: %s", err, event.ID)
+		return appError.NewUnexpectedError(err.Error())
+	}
+
+	result, err := p.client.PutEvents(p.ctx, &eventbridge.PutEventsInput{
+		Entries: []types.PutEventsRequestEntry{
+			{
+				EventBusName: aws.String(p.busName),
+				Source:       aws.String(EventSource),
+				DetailType:   aws.String(string(event.Type)),
+				Detail:       aws.String(string(detail)),
+				Time:         aws.Time(event.OccurredAt),
+			},
+		},
+	})
+	if err != nil {
+		log.Printf("Error putting event on EventBridge: %v, ID: %s, bus: %s", err, event.ID, p.busName)
+		return appError.NewUnexpectedError(err.Error())
+	}
+	if result.FailedEntryCount > 0 {
+		entry := result.Entries[0]
+		log.Printf("EventBridge rejected event: %s %s, ID: %s", aws.ToString(entry.ErrorCode), aws.ToString(entry.ErrorMessage), event.ID)
+		return appError.NewUnexpectedError(aws.ToString(entry.ErrorMessage))
+	}
+
+	log.Printf("Published event to EventBridge, ID: %s, type: %s", event.ID, event.Type)
+	return nil
+}
@@ -0,0 +1,60 @@
+package adapter
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sns"
+	"github.com/aws/aws-sdk-go-v2/service/sns/types"
+	"main/src/books/domain/model"
+	"main/src/books/domain/repository"
+	appError "main/utils/error"
+)
+
+type BookEventPublisherSNS struct {
+	ctx      context.Context
+	client   *sns.Client
+	topicARN string
+}
+
+func NewBookEventPublisherSNS(ctx context.Context, client *sns.Client, topicARN string) repository.EventPublisher {
+	return &BookEventPublisherSNS{
+		ctx:      ctx,
+		client:   client,
+		topicARN: topicARN,
+	}
+}
+
+// Publish sends the event with its type as message attribute, for
+// subscription filters. On a FIFO topic the event ID is the deduplication ID
+// and the events of a book share a message group, so they stay in order.
+func (p *BookEventPublisherSNS) Publish(event *model.BookEvent) *appError.Error {
+	message, err := json.Marshal(event)
+	if err != nil {
+		log.Printf("Error marshaling event: %v, ID: %s", err, event.ID)
+		return appError.NewUnexpectedError(err.Error())
+	}
+
+	input := &sns.PublishInput{
+		TopicArn: aws.String(p.topicARN),
+		Message:  aws.String(string(message)),
+		MessageAttributes: map[string]types.MessageAttributeValue{
+			"event_type": {DataType: aws.String("String"), StringValue: aws.String(string(event.Type))},
+			"event_id":   {DataType: aws.String("String"), StringValue: aws.String(event.ID)},
+		},
+	}
+	if strings.HasSuffix(p.topicARN, ".fifo") {
+		input.MessageDeduplicationId = aws.String(event.ID)
+		input.MessageGroupId = aws.String(event.BookID)
+	}
+	if _, err := p.client.Publish(p.ctx, input); err != nil {
+		log.Printf("Error publishing event to SNS: %v, ID: %s, topic: %s", err, event.ID, p.topicARN)
+		return appError.NewUnexpectedError(err.Error())
+	}
+
+	log.Printf("Published event to SNS, ID: %s, type: %s", event.ID, event.Type)
+	return nil
+}
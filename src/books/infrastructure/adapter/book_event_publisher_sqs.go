@@ -0,0 +1,60 @@
+package adapter
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+	"main/src/books/domain/model"
+	"main/src/books/domain/repository"
+	appError "main/utils/error"
+)
+
+type BookEventPublisherSQS struct {
+	ctx      context.Context
+	client   *sqs.Client
+	queueURL string
+}
+
+func NewBookEventPublisherSQS(ctx context.Context, client *sqs.Client, queueURL string) repository.EventPublisher {
+	return &BookEventPublisherSQS{
+		ctx:      ctx,
+		client:   client,
+		queueURL: queueURL,
+	}
+}
+
+// Publish sends the event with its type as message attribute. On a FIFO
+// queue the event ID is the deduplication ID and the events of a book share
+// a message group, so they stay in order.
+func (p *BookEventPublisherSQS) Publish(event *model.BookEvent) *appError.Error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		log.Printf("Error marshaling event: %v, ID: %s", err, event.ID)
+		return appError.NewUnexpectedError(err.Error())
+	}
+
+	input := &sqs.SendMessageInput{
+		QueueUrl:    aws.String(p.queueURL),
+		MessageBody: aws.String(string(body)),
+		MessageAttributes: map[string]types.MessageAttributeValue{
+			"event_type": {DataType: aws.String("String"), StringValue: aws.String(string(event.Type))},
+			"event_id":   {DataType: aws.String("String"), StringValue: aws.String(event.ID)},
+		},
+	}
+	if strings.HasSuffix(p.queueURL, ".fifo") {
+		input.MessageDeduplicationId = aws.String(event.ID)
+		input.MessageGroupId = aws.String(event.BookID)
+	}
+	if _, err := p.client.SendMessage(p.ctx, input); err != nil {
+		log.Printf("Error sending event to SQS: %v, ID: %s, queue: %s", err, event.ID, p.queueURL)
+		return appError.NewUnexpectedError(err.Error())
+	}
+
+	log.Printf("Published event to SQS, ID: %s, type: %s", event.ID, event.Type)
+	return nil
+}
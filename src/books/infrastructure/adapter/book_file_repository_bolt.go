@@ -0,0 +1,145 @@
+package adapter
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"log"
+	"main/src/books/domain/repository"
+
+	appError "main/utils/error"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// boltFilesBucket holds the book files by key, next to the books.
+var boltFilesBucket = []byte("files")
+
+// BookFileRepositoryBolt keeps the book files in the bolt file of the books,
+// so a backup of the store carries the images with it.
+type BookFileRepositoryBolt struct {
+	ctx context.Context
+	db  *bolt.DB
+}
+
+func NewBookFileRepositoryBolt(ctx context.Context, db *bolt.DB) repository.BookFileRepository {
+	return &BookFileRepositoryBolt{
+		ctx: ctx,
+		db:  db,
+	}
+}
+
+func (r *BookFileRepositoryBolt) SaveBookFile(file *bytes.Reader, bucketKey, fileExt string) *appError.Error {
+	return r.UploadBookFile(file, bucketKey, fileExt)
+}
+
+// UploadBookFile reads the whole file first: a bolt value is written at once.
+func (r *BookFileRepositoryBolt) UploadBookFile(file io.Reader, bucketKey, fileExt string) *appError.Error {
+	content, err := io.ReadAll(file)
+	if err != nil {
+		log.Printf("Error while reading book file: %v\n", err)
+		return appError.NewBadRequestError("Error while reading book file")
+	}
+
+	err = r.db.Update(func(tx *bolt.Tx) error {
+		bucket, err := tx.CreateBucketIfNotExists(boltFilesBucket)
+		if err != nil {
+			return err
+		}
+		return bucket.Put([]byte(bucketKey), content)
+	})
+	if err != nil {
+		log.Printf("Error while putting book file in bolt: %v\n", err)
+		return appError.NewUnexpectedError("Error while putting book file in bolt")
+	}
+
+	log.Printf("Book file creation completed successfully, book: %+v", bucketKey)
+	return nil
+}
+
+// fileVersion is the version of a stored file, a hash of its content.
+func fileVersion(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+// SaveBookFileVersion compares and writes in one bolt transaction, which
+// runs alone.
+func (r *BookFileRepositoryBolt) SaveBookFileVersion(file *bytes.Reader, bucketKey, fileExt, version string) (string, *appError.Error) {
+	content, err := io.ReadAll(file)
+	if err != nil {
+		log.Printf("Error while reading book file: %v\n", err)
+		return "", appError.NewBadRequestError("Error while reading book file")
+	}
+
+	changed := false
+	err = r.db.Update(func(tx *bolt.Tx) error {
+		bucket, err := tx.CreateBucketIfNotExists(boltFilesBucket)
+		if err != nil {
+			return err
+		}
+		stored := ""
+		if value := bucket.Get([]byte(bucketKey)); value != nil {
+			stored = fileVersion(value)
+		}
+		if stored != version {
+			changed = true
+			return nil
+		}
+		return bucket.Put([]byte(bucketKey), content)
+	})
+	if err != nil {
+		log.Printf("Error while putting book file in bolt: %v\n", err)
+		return "", appError.NewUnexpectedError("Error while putting book file in bolt")
+	}
+	if changed {
+		return "", appError.NewConflictError("File " + bucketKey + " was changed concurrently.")
+	}
+
+	log.Printf("Book file creation completed successfully, book: %+v", bucketKey)
+	return fileVersion(content), nil
+}
+
+func (r *BookFileRepositoryBolt) GetBookFile(bucketKey string) ([]byte, *appError.Error) {
+	content, _, err := r.GetBookFileVersion(bucketKey)
+	return content, err
+}
+
+func (r *BookFileRepositoryBolt) GetBookFileVersion(bucketKey string) ([]byte, string, *appError.Error) {
+	var content []byte
+	err := r.db.View(func(tx *bolt.Tx) error {
+		if bucket := tx.Bucket(boltFilesBucket); bucket != nil {
+			// The value is only valid during the transaction.
+			if value := bucket.Get([]byte(bucketKey)); value != nil {
+				content = append([]byte{}, value...)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		log.Printf("Error while getting book file from bolt: %v\n", err)
+		return nil, "", appError.NewUnexpectedError("Error while getting book file from bolt")
+	}
+	if content == nil {
+		return nil, "", appError.NewNotFoundError("File " + bucketKey + " not found.")
+	}
+	return content, fileVersion(content), nil
+}
+
+func (r *BookFileRepositoryBolt) DeleteBookFile(bucketKey string) *appError.Error {
+	err := r.db.Update(func(tx *bolt.Tx) error {
+		if bucket := tx.Bucket(boltFilesBucket); bucket != nil {
+			return bucket.Delete([]byte(bucketKey))
+		}
+		return nil
+	})
+	if err != nil {
+		log.Printf("Error while deleting book file from bolt: %v\n", err)
+		return appError.NewUnexpectedError("Error while deleting book file from bolt")
+	}
+
+	log.Printf("Book file %s deleted successfully", bucketKey)
+	return nil
+}
@@ -3,14 +3,20 @@ package adapter
 import (
 	"bytes"
 	"context"
+	"errors"
+	"io"
 	"log"
 	"main/src/books/domain/repository"
 	"mime"
+	"net/http"
 
 	appError "main/utils/error"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/s3/manager"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
 )
 
 type BookFileRepositoryS3 struct {
@@ -47,6 +53,87 @@ func (r *BookFileRepositoryS3) SaveBookFile(file *bytes.Reader, bucketKey, fileE
 	return nil
 }
 
+// UploadBookFile streams the file to S3 in a multipart upload, one part in
+// memory at a time.
+func (r *BookFileRepositoryS3) UploadBookFile(file io.Reader, bucketKey, fileExt string) *appError.Error {
+	input := &s3.PutObjectInput{
+		Bucket:      aws.String(r.BucketName),
+		Key:         aws.String(bucketKey),
+		Body:        file,
+		ContentType: aws.String(mime.TypeByExtension(fileExt)),
+	}
+
+	_, errS3 := manager.NewUploader(r.client).Upload(r.ctx, input)
+	if errS3 != nil {
+		log.Printf("Error while uploading object to S3: %v\n", errS3)
+		return appError.NewUnexpectedError("Error while uploading object to S3")
+	}
+
+	log.Printf("Book file upload completed successfully, book: %+v", bucketKey)
+	return nil
+}
+
+// SaveBookFileVersion puts the file with an If-Match on the ETag it was read
+// at, or an If-None-Match when it is new, so S3 refuses a stale write.
+func (r *BookFileRepositoryS3) SaveBookFileVersion(file *bytes.Reader, bucketKey, fileExt, version string) (string, *appError.Error) {
+	input := &s3.PutObjectInput{
+		Bucket:      aws.String(r.BucketName),
+		Key:         aws.String(bucketKey),
+		Body:        file,
+		ContentType: aws.String(mime.TypeByExtension(fileExt)),
+	}
+	condition := smithyhttp.AddHeaderValue("If-Match", version)
+	if version == "" {
+		condition = smithyhttp.AddHeaderValue("If-None-Match", "*")
+	}
+
+	output, errS3 := r.client.PutObject(r.ctx, input, s3.WithAPIOptions(condition))
+	if errS3 != nil {
+		// 412 when the ETag moved on, 409 when another conditional write
+		// to the key was in flight.
+		var responseError *smithyhttp.ResponseError
+		if errors.As(errS3, &responseError) &&
+			(responseError.HTTPStatusCode() == http.StatusPreconditionFailed || responseError.HTTPStatusCode() == http.StatusConflict) {
+			return "", appError.NewConflictError("File " + bucketKey + " was changed concurrently.")
+		}
+		log.Printf("Error while putting object to S3: %v\n", errS3)
+		return "", appError.NewUnexpectedError("Error while putting object to S3")
+	}
+
+	log.Printf("Book file creation completed successfully, book: %+v", bucketKey)
+	return aws.ToString(output.ETag), nil
+}
+
+func (r *BookFileRepositoryS3) GetBookFile(bucketKey string) ([]byte, *appError.Error) {
+	content, _, err := r.GetBookFileVersion(bucketKey)
+	return content, err
+}
+
+// GetBookFileVersion returns the file with its ETag.
+func (r *BookFileRepositoryS3) GetBookFileVersion(bucketKey string) ([]byte, string, *appError.Error) {
+	input := &s3.GetObjectInput{
+		Bucket: aws.String(r.BucketName),
+		Key:    aws.String(bucketKey),
+	}
+
+	output, errS3 := r.client.GetObject(r.ctx, input)
+	if errS3 != nil {
+		var noSuchKey *types.NoSuchKey
+		if errors.As(errS3, &noSuchKey) {
+			return nil, "", appError.NewNotFoundError("File " + bucketKey + " not found.")
+		}
+		log.Printf("Error while getting object from S3: %v\n", errS3)
+		return nil, "", appError.NewUnexpectedError("Error while getting object from S3")
+	}
+	defer output.Body.Close()
+
+	content, err := io.ReadAll(output.Body)
+	if err != nil {
+		log.Printf("Error while reading object from S3: %v\n", err)
+		return nil, "", appError.NewUnexpectedError("Error while reading object from S3")
+	}
+	return content, aws.ToString(output.ETag), nil
+}
 
 func (r *BookFileRepositoryS3) DeleteBookFile(bucketKey string) (*appError.Error) {
 	input := &s3.DeleteObjectInput{
@@ -0,0 +1,121 @@
+package adapter
+
+import (
+	"context"
+	"log"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"main/src/books/domain/model"
+	appError "main/utils/error"
+)
+
+// The history table is keyed by book_id and revision, so the revisions of a
+// book are a single Query in either order.
+type BookHistoryDynamoDBRepository struct {
+	ctx    context.Context
+	client *dynamodb.Client
+	table  string
+}
+
+func NewBookHistoryDynamoDBRepository(ctx context.Context, client *dynamodb.Client, table string) *BookHistoryDynamoDBRepository {
+	return &BookHistoryDynamoDBRepository{
+		ctx:    ctx,
+		client: client,
+		table:  table,
+	}
+}
+
+func (r *BookHistoryDynamoDBRepository) GetBookRevisions(bookID string, before, limit int) ([]model.BookRevision, *appError.Error) {
+	keyCond := expression.Key("book_id").Equal(expression.Value(bookID))
+	if before > 0 {
+		keyCond = keyCond.And(expression.Key("revision").LessThan(expression.Value(before)))
+	}
+	expr, err := expression.NewBuilder().WithKeyCondition(keyCond).Build()
+	if err != nil {
+		log.Printf("Error building expression for history query: %v, ID: %s", err, bookID)
+		return nil, appError.NewUnexpectedError(err.Error())
+	}
+
+	result, err := r.client.Query(r.ctx, &dynamodb.QueryInput{
+		TableName:                 aws.String(r.table),
+		KeyConditionExpression:    expr.KeyCondition(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		ScanIndexForward:          aws.Bool(false),
+		Limit:                     aws.Int32(int32(limit)),
+	})
+	if err != nil {
+		log.Printf("Error querying book history: %v, table: %s", err, r.table)
+		return nil, appError.NewUnexpectedError(err.Error())
+	}
+
+	revisions := []model.BookRevision{}
+	if err := attributevalue.UnmarshalListOfMaps(result.Items, &revisions); err != nil {
+		log.Printf("Error unmarshaling book history from DynamoDB: %v", err)
+		return nil, appError.NewUnexpectedError(err.Error())
+	}
+	log.Printf("Retrieved %d revisions of book %s", len(revisions), bookID)
+	return revisions, nil
+}
+
+func (r *BookHistoryDynamoDBRepository) GetBookRevision(bookID string, revision int) (*model.BookRevision, *appError.Error) {
+	result, err := r.client.GetItem(r.ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(r.table),
+		Key:       revisionKey(bookID, revision),
+	})
+	if err != nil {
+		log.Printf("Error getting revision from DynamoDB: %v, table: %s", err, r.table)
+		return nil, appError.NewUnexpectedError(err.Error())
+	}
+	if result.Item == nil {
+		return nil, appError.NewNotFoundError("Revision " + strconv.Itoa(revision) + " of book " + bookID + " not found.")
+	}
+	var bookRevision model.BookRevision
+	if err := attributevalue.UnmarshalMap(result.Item, &bookRevision); err != nil {
+		log.Printf("Error unmarshaling revision from DynamoDB: %v, ID: %s", err, bookID)
+		return nil, appError.NewUnexpectedError(err.Error())
+	}
+	return &bookRevision, nil
+}
+
+func revisionKey(bookID string, revision int) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		"book_id":  &types.AttributeValueMemberS{Value: bookID},
+		"revision": &types.AttributeValueMemberN{Value: strconv.Itoa(revision)},
+	}
+}
+
+// latestRevision is the number of the newest revision of a book, 0 when it
+// has none.
+func latestRevision(ctx context.Context, client *dynamodb.Client, table, bookID string) (int, error) {
+	expr, err := expression.NewBuilder().
+		WithKeyCondition(expression.Key("book_id").Equal(expression.Value(bookID))).
+		WithProjection(expression.NamesList(expression.Name("revision"))).
+		Build()
+	if err != nil {
+		return 0, err
+	}
+	result, err := client.Query(ctx, &dynamodb.QueryInput{
+		TableName:                 aws.String(table),
+		KeyConditionExpression:    expr.KeyCondition(),
+		ProjectionExpression:      expr.Projection(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		ScanIndexForward:          aws.Bool(false),
+		Limit:                     aws.Int32(1),
+		ConsistentRead:            aws.Bool(true),
+	})
+	if err != nil || len(result.Items) == 0 {
+		return 0, err
+	}
+	var latest model.BookRevision
+	if err := attributevalue.UnmarshalMap(result.Items[0], &latest); err != nil {
+		return 0, err
+	}
+	return latest.Revision, nil
+}
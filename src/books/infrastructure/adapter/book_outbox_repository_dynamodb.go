@@ -0,0 +1,128 @@
+package adapter
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"main/src/books/domain/model"
+	appError "main/utils/error"
+)
+
+// PendingIndex orders the unpublished events of the outbox. Pending events
+// share the pending partition, which publishing removes, so the sparse index
+// only ever holds the backlog.
+const (
+	PendingIndex     = "pending-sequence-index"
+	pendingAttribute = "pending"
+	pendingPartition = "outbox"
+)
+
+// publishedRetention is how long published events are kept before the table
+// TTL removes them.
+const publishedRetention = 7 * 24 * time.Hour
+
+type BookOutboxDynamoDBRepository struct {
+	ctx    context.Context
+	client *dynamodb.Client
+	table  string
+}
+
+func NewBookOutboxDynamoDBRepository(ctx context.Context, client *dynamodb.Client, table string) *BookOutboxDynamoDBRepository {
+	return &BookOutboxDynamoDBRepository{
+		ctx:    ctx,
+		client: client,
+		table:  table,
+	}
+}
+
+// marshalEvent adds the pending index key to a new outbox event.
+func marshalEvent(event *model.BookEvent) (map[string]types.AttributeValue, error) {
+	av, err := attributevalue.MarshalMap(event)
+	if err != nil {
+		return nil, err
+	}
+	av[pendingAttribute] = &types.AttributeValueMemberS{Value: pendingPartition}
+	return av, nil
+}
+
+// GetPendingEvents returns up to limit unpublished events, oldest first. The
+// index is eventually consistent, so an event published moments ago may
+// still be returned; delivery is at least once anyway.
+func (r *BookOutboxDynamoDBRepository) GetPendingEvents(limit int) ([]model.BookEvent, *appError.Error) {
+	keyCond := expression.Key(pendingAttribute).Equal(expression.Value(pendingPartition))
+	expr, err := expression.NewBuilder().WithKeyCondition(keyCond).Build()
+	if err != nil {
+		log.Printf("Error building expression for pending events query: %v", err)
+		return nil, appError.NewUnexpectedError(err.Error())
+	}
+
+	result, err := r.client.Query(r.ctx, &dynamodb.QueryInput{
+		TableName:                 aws.String(r.table),
+		IndexName:                 aws.String(PendingIndex),
+		KeyConditionExpression:    expr.KeyCondition(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		ScanIndexForward:          aws.Bool(true),
+		Limit:                     aws.Int32(int32(limit)),
+	})
+	if err != nil {
+		log.Printf("Error querying pending events: %v, table: %s", err, r.table)
+		return nil, appError.NewUnexpectedError(err.Error())
+	}
+
+	events := []model.BookEvent{}
+	if err := attributevalue.UnmarshalListOfMaps(result.Items, &events); err != nil {
+		log.Printf("Error unmarshaling pending events from DynamoDB: %v", err)
+		return nil, appError.NewUnexpectedError(err.Error())
+	}
+	log.Printf("Retrieved %d pending events", len(events))
+	return events, nil
+}
+
+// MarkEventPublished takes the event out of the pending index and lets the
+// TTL remove it after the retention period.
+func (r *BookOutboxDynamoDBRepository) MarkEventPublished(id string) *appError.Error {
+	now := time.Now().UTC()
+	update := expression.Remove(expression.Name(pendingAttribute)).
+		Set(expression.Name("published_at"), expression.Value(now)).
+		Set(expression.Name("expires_at"), expression.Value(now.Add(publishedRetention).Unix()))
+	return r.updateEvent(id, update)
+}
+
+// RecordEventFailure counts a failed delivery; the event stays pending.
+func (r *BookOutboxDynamoDBRepository) RecordEventFailure(id, reason string) *appError.Error {
+	update := expression.Add(expression.Name("attempts"), expression.Value(1)).
+		Set(expression.Name("last_error"), expression.Value(reason))
+	return r.updateEvent(id, update)
+}
+
+func (r *BookOutboxDynamoDBRepository) updateEvent(id string, update expression.UpdateBuilder) *appError.Error {
+	expr, err := expression.NewBuilder().
+		WithCondition(expression.AttributeExists(expression.Name("ID"))).
+		WithUpdate(update).
+		Build()
+	if err != nil {
+		log.Printf("Error building expression for event update: %v, ID: %s", err, id)
+		return appError.NewUnexpectedError(err.Error())
+	}
+
+	_, err = r.client.UpdateItem(r.ctx, &dynamodb.UpdateItemInput{
+		TableName:                 aws.String(r.table),
+		Key:                       map[string]types.AttributeValue{"ID": &types.AttributeValueMemberS{Value: id}},
+		UpdateExpression:          expr.Update(),
+		ConditionExpression:       expr.Condition(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+	})
+	if err != nil {
+		log.Printf("Error updating event in DynamoDB: %v, ID: %s, table: %s", err, id, r.table)
+		return appError.NewUnexpectedError(err.Error())
+	}
+	return nil
+}
@@ -0,0 +1,744 @@
+package adapter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"time"
+
+	"main/src/books/domain/model"
+	appError "main/utils/error"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// Buckets of the bolt store. The books bucket holds the records by ID; the
+// others are secondary indexes whose keys end with a zero byte and the ID,
+// kept in the same transaction as the record.
+var (
+	boltBooksBucket = []byte("books")
+	// boltNameIndex orders the books out of the trash by name, for listings.
+	boltNameIndex = []byte("books_by_name")
+	// boltRecentIndex orders the timestamped books out of the trash by
+	// update time, for the recent books feed.
+	boltRecentIndex = []byte("books_by_updated_at")
+	// boltTrashIndex orders the books in the trash by deletion time.
+	boltTrashIndex = []byte("trash_by_deleted_at")
+)
+
+// boltScanPageSize is the number of books ScanBooks reads per transaction.
+const boltScanPageSize = 500
+
+// boltRecord is a book as stored in bolt, with the rating aggregate the JSON
+// of the book leaves out and the expiry of the trash.
+type boltRecord struct {
+	model.Book
+	RatingCount int        `json:"rating_count,omitempty"`
+	RatingTotal int        `json:"rating_total,omitempty"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+}
+
+func (record *boltRecord) book() model.Book {
+	book := record.Book
+	book.RatingCount, book.RatingTotal = record.RatingCount, record.RatingTotal
+	return book
+}
+
+// expired tells whether the record was in the trash longer than its
+// retention, after which it only waits to be purged.
+func (record *boltRecord) expired(now time.Time) bool {
+	return record.Trashed() && record.ExpiresAt != nil && !record.ExpiresAt.After(now)
+}
+
+// BookBoltRepository keeps the books in a bolt file, so a single binary can
+// serve them without a database process. Every change runs in one bolt
+// transaction that also maintains the indexes. Changes record neither events
+// nor history, and the trash is purged by PurgeExpiredBooks.
+type BookBoltRepository struct {
+	ctx context.Context
+	db  *bolt.DB
+	// trashRetention is how long deleted books stay in the trash,
+	// DefaultTrashRetention when zero.
+	trashRetention time.Duration
+}
+
+func NewBookBoltRepository(ctx context.Context, db *bolt.DB) *BookBoltRepository {
+	return &BookBoltRepository{
+		ctx: ctx,
+		db:  db,
+	}
+}
+
+// WithTrashRetention keeps deleted books in the trash for retention before
+// they expire.
+func (r *BookBoltRepository) WithTrashRetention(retention time.Duration) *BookBoltRepository {
+	r.trashRetention = retention
+	return r
+}
+
+// CreateBuckets creates the buckets of the books and their indexes the file
+// does not have yet.
+func (r *BookBoltRepository) CreateBuckets() *appError.Error {
+	err := r.db.Update(func(tx *bolt.Tx) error {
+		for _, name := range [][]byte{boltBooksBucket, boltNameIndex, boltRecentIndex, boltTrashIndex} {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		log.Printf("Error creating bolt buckets: %v", err)
+		return appError.NewUnexpectedError(err.Error())
+	}
+	return nil
+}
+
+// Backup writes a consistent copy of the whole bolt file, books and files, to
+// w while the store keeps serving reads and writes, and returns its size.
+func (r *BookBoltRepository) Backup(w io.Writer) (int64, *appError.Error) {
+	var size int64
+	err := r.db.View(func(tx *bolt.Tx) error {
+		var err error
+		size, err = tx.WriteTo(w)
+		return err
+	})
+	if err != nil {
+		log.Printf("Error writing bolt backup: %v", err)
+		return size, appError.NewUnexpectedError(err.Error())
+	}
+	log.Printf("Wrote bolt backup of %d bytes", size)
+	return size, nil
+}
+
+func (r *BookBoltRepository) GetAllBooks() ([]model.Book, *appError.Error) {
+	books := []model.Book{}
+	err := r.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(boltBooksBucket).ForEach(func(_, value []byte) error {
+			var record boltRecord
+			if err := json.Unmarshal(value, &record); err != nil {
+				return err
+			}
+			if !record.Trashed() {
+				books = append(books, record.book())
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		log.Printf("Error reading books from bolt: %v", err)
+		return nil, appError.NewUnexpectedError(err.Error())
+	}
+	log.Println("Retrieved all books successfully")
+	return books, nil
+}
+
+// ScanBooks reads the books in pages ordered by ID, one read transaction per
+// page, handing each page to visit in turn. segments is ignored. Books in
+// the trash are left out.
+func (r *BookBoltRepository) ScanBooks(segments int, visit func([]model.Book) *appError.Error) *appError.Error {
+	var after []byte
+	for {
+		var books []model.Book
+		err := r.db.View(func(tx *bolt.Tx) error {
+			cursor := tx.Bucket(boltBooksBucket).Cursor()
+			key, value := cursor.First()
+			if after != nil {
+				key, value = cursor.Seek(after)
+				if bytes.Equal(key, after) {
+					key, value = cursor.Next()
+				}
+			}
+			for ; key != nil && len(books) < boltScanPageSize; key, value = cursor.Next() {
+				after = append([]byte(nil), key...)
+				var record boltRecord
+				if err := json.Unmarshal(value, &record); err != nil {
+					return err
+				}
+				if !record.Trashed() {
+					books = append(books, record.book())
+				}
+			}
+			if key == nil {
+				after = nil
+			}
+			return nil
+		})
+		if err != nil {
+			log.Printf("Error scanning books from bolt: %v", err)
+			return appError.NewUnexpectedError(err.Error())
+		}
+		if len(books) > 0 {
+			if err := visit(books); err != nil {
+				return err
+			}
+		}
+		if after == nil {
+			return nil
+		}
+	}
+}
+
+func (r *BookBoltRepository) CreateBook(book *model.Book) (*model.Book, *appError.Error) {
+	inserted := false
+	err := r.db.Update(func(tx *bolt.Tx) error {
+		var err error
+		inserted, err = insertBook(tx, book)
+		return err
+	})
+	if err != nil {
+		log.Printf("Error putting book in bolt: %v, ID: %s", err, book.ID)
+		return &model.Book{}, appError.NewUnexpectedError(err.Error())
+	}
+	if !inserted {
+		return &model.Book{}, bookExists(book.ID)
+	}
+
+	log.Printf("Book creation completed successfully, book: %+v", book)
+	return book, nil
+}
+
+// CreateBatchBooks writes the books in a single transaction: either every
+// book whose ID is free is written, or all of them are returned with the
+// reason the transaction failed.
+func (r *BookBoltRepository) CreateBatchBooks(books []model.Book) map[string]*appError.Error {
+	failed := make(map[string]*appError.Error)
+	err := r.db.Update(func(tx *bolt.Tx) error {
+		for i := range books {
+			inserted, err := insertBook(tx, &books[i])
+			if err != nil {
+				return err
+			}
+			if !inserted {
+				failed[books[i].ID] = bookExists(books[i].ID)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		log.Printf("Error putting books in bolt: %v", err)
+		for _, book := range books {
+			failed[book.ID] = appError.NewUnexpectedError(err.Error())
+		}
+	}
+
+	log.Printf("Batch books creation completed, requested: %d, failed: %d", len(books), len(failed))
+	return failed
+}
+
+// insertBook writes a new book, reporting false when its ID is already
+// taken, trashed books included.
+func insertBook(tx *bolt.Tx, book *model.Book) (bool, error) {
+	previous, err := getRecord(tx, book.ID)
+	if err != nil || previous != nil {
+		return false, err
+	}
+	record := &boltRecord{Book: *book, RatingCount: book.RatingCount, RatingTotal: book.RatingTotal}
+	return true, putRecord(tx, nil, record)
+}
+
+func (r *BookBoltRepository) GetBookByID(id string) (*model.Book, *appError.Error) {
+	var record *boltRecord
+	err := r.db.View(func(tx *bolt.Tx) error {
+		var err error
+		record, err = getRecord(tx, id)
+		return err
+	})
+	if err != nil {
+		log.Printf("Error getting book from bolt: %v, ID: %s", err, id)
+		return &model.Book{}, appError.NewUnexpectedError(err.Error())
+	}
+	if record == nil || record.Trashed() {
+		log.Println("No book found with ID:", id)
+		return &model.Book{}, nil
+	}
+
+	book := record.book()
+	log.Printf("Retrieved book successfully, ID: %s, book: %+v", id, book)
+	return &book, nil
+}
+
+func (r *BookBoltRepository) GetBooksByIDs(ids []string) (*model.BooksByIDs, *appError.Error) {
+	result := &model.BooksByIDs{
+		Books:      []model.Book{},
+		MissingIDs: []string{},
+	}
+	seen := make(map[string]bool, len(ids))
+	err := r.db.View(func(tx *bolt.Tx) error {
+		for _, id := range ids {
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
+			record, err := getRecord(tx, id)
+			if err != nil {
+				return err
+			}
+			if record == nil || record.Trashed() {
+				result.MissingIDs = append(result.MissingIDs, id)
+			} else {
+				result.Books = append(result.Books, record.book())
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		log.Printf("Error getting books from bolt: %v", err)
+		return nil, appError.NewUnexpectedError(err.Error())
+	}
+	log.Printf("Retrieved %d of %d requested books, missing: %v", len(result.Books), len(seen), result.MissingIDs)
+	return result, nil
+}
+
+// UpdateBookByID sets the name, description and image of a book, creating
+// it when there is none, as the DynamoDB repository does. A book in the
+// trash is not found.
+func (r *BookBoltRepository) UpdateBookByID(id string, book *model.Book) (*model.Book, *appError.Error) {
+	var updatedBook model.Book
+	errUpdate := r.change(id, func(previous *boltRecord) (*boltRecord, *appError.Error) {
+		record := &boltRecord{Book: model.Book{ID: id}}
+		if previous != nil {
+			if previous.Trashed() {
+				return nil, appError.NewNotFoundError("Book " + id + " not found.")
+			}
+			copied := *previous
+			record = &copied
+		}
+		record.Name = book.Name
+		record.Description = book.Description
+		record.ImgURL = book.ImgURL
+		if book.UpdatedAt != nil {
+			record.UpdatedAt = book.UpdatedAt
+		}
+		updatedBook = record.book()
+		return record, nil
+	})
+	if errUpdate != nil {
+		return &model.Book{}, errUpdate
+	}
+
+	log.Printf("Updated book successfully, ID: %s, book: %+v", id, updatedBook)
+	return &updatedBook, nil
+}
+
+// DeleteBookByID moves a book to the trash until it is restored or expires.
+// Deleting a book that does not exist or is already in the trash does
+// nothing.
+func (r *BookBoltRepository) DeleteBookByID(id string) *appError.Error {
+	retention := r.trashRetention
+	if retention <= 0 {
+		retention = DefaultTrashRetention
+	}
+	now := time.Now().UTC()
+	expiresAt := now.Add(retention)
+	errDelete := r.change(id, func(previous *boltRecord) (*boltRecord, *appError.Error) {
+		if previous == nil || previous.Trashed() {
+			return nil, nil
+		}
+		record := *previous
+		record.DeletedAt = &now
+		record.ExpiresAt = &expiresAt
+		return &record, nil
+	})
+	if errDelete != nil {
+		return errDelete
+	}
+
+	log.Printf("Moved book to the trash, book_id: %s", id)
+	return nil
+}
+
+// DeleteBooksByIDs moves each book to the trash and returns the IDs that
+// could not be deleted with the reason.
+func (r *BookBoltRepository) DeleteBooksByIDs(ids []string) map[string]*appError.Error {
+	failed := make(map[string]*appError.Error)
+	for _, id := range ids {
+		if err := r.DeleteBookByID(id); err != nil {
+			failed[id] = err
+		}
+	}
+
+	log.Printf("Batch books deletion completed, requested: %d, failed: %d", len(ids), len(failed))
+	return failed
+}
+
+// UpdateBooks replaces the catalog fields of each book. A book that does
+// not exist, or sits in the trash, is reported as not found instead of being
+// recreated.
+func (r *BookBoltRepository) UpdateBooks(books []model.Book) map[string]*appError.Error {
+	failed := make(map[string]*appError.Error)
+	for i := range books {
+		book := &books[i]
+		err := r.change(book.ID, func(previous *boltRecord) (*boltRecord, *appError.Error) {
+			if previous == nil || previous.Trashed() {
+				return nil, appError.NewNotFoundError("Book " + book.ID + " not found.")
+			}
+			record := *previous
+			setCatalog(&record.Book, book)
+			return &record, nil
+		})
+		if err != nil {
+			failed[book.ID] = err
+		}
+	}
+
+	log.Printf("Batch books update completed, requested: %d, failed: %d", len(books), len(failed))
+	return failed
+}
+
+// setCatalog sets the fields a batch update replaces, as catalogAttributes
+// are for DynamoDB.
+func setCatalog(book *model.Book, catalog *model.Book) {
+	book.Name = catalog.Name
+	book.Description = catalog.Description
+	book.ImgURL = catalog.ImgURL
+	book.ISBN = catalog.ISBN
+	book.Subtitle = catalog.Subtitle
+	book.Publisher = catalog.Publisher
+	book.PublishedOn = catalog.PublishedOn
+	book.Contributors = catalog.Contributors
+	book.Subjects = catalog.Subjects
+	book.Prices = catalog.Prices
+	book.Availability = catalog.Availability
+	book.UpdatedAt = catalog.UpdatedAt
+}
+
+// GetRecentBooks returns up to limit books, most recently updated first.
+func (r *BookBoltRepository) GetRecentBooks(limit int) ([]model.Book, *appError.Error) {
+	books, err := r.readIndexBackwards(boltRecentIndex, limit, func(record *boltRecord) bool {
+		return true
+	})
+	if err != nil {
+		log.Printf("Error reading recent books from bolt: %v", err)
+		return nil, appError.NewUnexpectedError(err.Error())
+	}
+	log.Printf("Retrieved %d recent books", len(books))
+	return books, nil
+}
+
+// GetTrashedBooks returns up to limit books of the trash that have not
+// expired, most recently deleted first.
+func (r *BookBoltRepository) GetTrashedBooks(limit int) ([]model.Book, *appError.Error) {
+	now := time.Now()
+	books, err := r.readIndexBackwards(boltTrashIndex, limit, func(record *boltRecord) bool {
+		return !record.expired(now)
+	})
+	if err != nil {
+		log.Printf("Error reading trashed books from bolt: %v", err)
+		return nil, appError.NewUnexpectedError(err.Error())
+	}
+	log.Printf("Retrieved %d trashed books", len(books))
+	return books, nil
+}
+
+// readIndexBackwards reads up to limit books of index, last key first, that
+// keep holds for.
+func (r *BookBoltRepository) readIndexBackwards(index []byte, limit int, keep func(*boltRecord) bool) ([]model.Book, error) {
+	books := []model.Book{}
+	err := r.db.View(func(tx *bolt.Tx) error {
+		cursor := tx.Bucket(index).Cursor()
+		for key, _ := cursor.Last(); key != nil && len(books) < limit; key, _ = cursor.Prev() {
+			record, err := getRecord(tx, indexedID(key))
+			if err != nil {
+				return err
+			}
+			if record != nil && keep(record) {
+				books = append(books, record.book())
+			}
+		}
+		return nil
+	})
+	return books, err
+}
+
+// RestoreTrashedBook takes a book out of the trash as it was deleted, unless
+// it expired.
+func (r *BookBoltRepository) RestoreTrashedBook(id string) *appError.Error {
+	now := time.Now()
+	errRestore := r.change(id, func(previous *boltRecord) (*boltRecord, *appError.Error) {
+		if previous == nil || !previous.Trashed() || previous.expired(now) {
+			return nil, appError.NewNotFoundError("Book " + id + " not found in the trash.")
+		}
+		record := *previous
+		record.DeletedAt, record.ExpiresAt = nil, nil
+		return &record, nil
+	})
+	if errRestore != nil {
+		return errRestore
+	}
+	log.Printf("Restored book from the trash, ID: %s", id)
+	return nil
+}
+
+// PurgeBook deletes a book of the trash for good.
+func (r *BookBoltRepository) PurgeBook(id string) *appError.Error {
+	var notInTrash bool
+	err := r.db.Update(func(tx *bolt.Tx) error {
+		previous, err := getRecord(tx, id)
+		if err != nil {
+			return err
+		}
+		if previous == nil || !previous.Trashed() {
+			notInTrash = true
+			return nil
+		}
+		return deleteRecord(tx, previous)
+	})
+	if err != nil {
+		log.Printf("Error purging book from bolt: %v, ID: %s", err, id)
+		return appError.NewUnexpectedError(err.Error())
+	}
+	if notInTrash {
+		return appError.NewNotFoundError("Book " + id + " not found in the trash.")
+	}
+	log.Printf("Purged book from the trash, ID: %s", id)
+	return nil
+}
+
+// PurgeExpiredBooks deletes the books whose retention in the trash ran out
+// and returns how many there were. It is meant to run periodically.
+func (r *BookBoltRepository) PurgeExpiredBooks() (int, *appError.Error) {
+	now := time.Now()
+	purged := 0
+	err := r.db.Update(func(tx *bolt.Tx) error {
+		var expired []*boltRecord
+		cursor := tx.Bucket(boltTrashIndex).Cursor()
+		for key, _ := cursor.First(); key != nil; key, _ = cursor.Next() {
+			record, err := getRecord(tx, indexedID(key))
+			if err != nil {
+				return err
+			}
+			if record != nil && record.expired(now) {
+				expired = append(expired, record)
+			}
+		}
+		for _, record := range expired {
+			if err := deleteRecord(tx, record); err != nil {
+				return err
+			}
+		}
+		purged = len(expired)
+		return nil
+	})
+	if err != nil {
+		log.Printf("Error purging expired books from bolt: %v", err)
+		return 0, appError.NewUnexpectedError(err.Error())
+	}
+	log.Printf("Purged %d expired books from the trash", purged)
+	return purged, nil
+}
+
+// RestoreBook replaces the catalog fields of a book with those of book,
+// recreating it when it was deleted. The rating aggregate maintained by
+// reviews is kept.
+func (r *BookBoltRepository) RestoreBook(book *model.Book) *appError.Error {
+	errRestore := r.change(book.ID, func(previous *boltRecord) (*boltRecord, *appError.Error) {
+		record := &boltRecord{Book: model.Book{ID: book.ID}}
+		if previous != nil {
+			copied := *previous
+			record = &copied
+		}
+		setCatalog(&record.Book, book)
+		if book.CreatedAt != nil {
+			record.CreatedAt = book.CreatedAt
+		}
+		if book.RecordReference != "" {
+			record.RecordReference = book.RecordReference
+		}
+		record.DeletedAt, record.ExpiresAt = nil, nil
+		return record, nil
+	})
+	if errRestore != nil {
+		return errRestore
+	}
+	log.Printf("Restored book successfully, ID: %s", book.ID)
+	return nil
+}
+
+// ListBooks returns up to limit books matching filter, ordered by name and
+// ID, starting after cursor. It walks the name index from the cursor, so a
+// page costs as many reads as the books it skips for not matching.
+func (r *BookBoltRepository) ListBooks(filter model.BookFilter, cursor string, limit int) (*model.BookPage, *appError.Error) {
+	if limit < 1 || limit > MaxListLimit {
+		return nil, appError.NewValidationError(fmt.Sprintf("The limit must be between 1 and %d.", MaxListLimit))
+	}
+	var start []byte
+	if cursor != "" {
+		after, errCursor := decodeBookCursor(cursor)
+		if errCursor != nil {
+			return nil, errCursor
+		}
+		start = indexKey(after.Name, after.ID)
+	}
+	name := strings.ToLower(filter.Name)
+
+	page := &model.BookPage{Books: []model.Book{}}
+	err := r.db.View(func(tx *bolt.Tx) error {
+		index := tx.Bucket(boltNameIndex).Cursor()
+		key, _ := index.First()
+		if start != nil {
+			key, _ = index.Seek(start)
+			if bytes.Equal(key, start) {
+				key, _ = index.Next()
+			}
+		}
+		for ; key != nil; key, _ = index.Next() {
+			record, err := getRecord(tx, indexedID(key))
+			if err != nil {
+				return err
+			}
+			if record == nil || !matches(&record.Book, filter, name) {
+				continue
+			}
+			if len(page.Books) == limit {
+				last := page.Books[limit-1]
+				page.Next = encodeBookCursor(bookCursor{Name: last.Name, ID: last.ID})
+				return nil
+			}
+			page.Books = append(page.Books, record.book())
+		}
+		return nil
+	})
+	if err != nil {
+		log.Printf("Error listing books from bolt: %v, filter: %+v", err, filter)
+		return nil, appError.NewUnexpectedError(err.Error())
+	}
+	log.Printf("Listed %d books, filter: %+v", len(page.Books), filter)
+	return page, nil
+}
+
+// matches tells whether book passes filter; name is the lowercase name of
+// the filter.
+func matches(book *model.Book, filter model.BookFilter, name string) bool {
+	if name != "" && !strings.Contains(strings.ToLower(book.Name), name) {
+		return false
+	}
+	if filter.Publisher != "" && book.Publisher != filter.Publisher {
+		return false
+	}
+	if filter.Availability != "" && book.Availability != filter.Availability {
+		return false
+	}
+	if filter.UpdatedSince != nil && (book.UpdatedAt == nil || book.UpdatedAt.Before(*filter.UpdatedSince)) {
+		return false
+	}
+	return true
+}
+
+// change applies decide to the stored record of a book in one transaction.
+// decide returns the new record, or nil to leave the book as it is.
+func (r *BookBoltRepository) change(id string, decide func(previous *boltRecord) (*boltRecord, *appError.Error)) *appError.Error {
+	var decision *appError.Error
+	err := r.db.Update(func(tx *bolt.Tx) error {
+		previous, err := getRecord(tx, id)
+		if err != nil {
+			return err
+		}
+		record, errDecide := decide(previous)
+		if errDecide != nil {
+			decision = errDecide
+			return errAbort
+		}
+		if record == nil {
+			return nil
+		}
+		return putRecord(tx, previous, record)
+	})
+	if decision != nil {
+		return decision
+	}
+	if err != nil {
+		log.Printf("Error updating book in bolt: %v, ID: %s", err, id)
+		return appError.NewUnexpectedError(err.Error())
+	}
+	return nil
+}
+
+// errAbort rolls back a transaction whose change was refused.
+var errAbort = errors.New("change refused")
+
+func getRecord(tx *bolt.Tx, id string) (*boltRecord, error) {
+	value := tx.Bucket(boltBooksBucket).Get([]byte(id))
+	if value == nil {
+		return nil, nil
+	}
+	var record boltRecord
+	if err := json.Unmarshal(value, &record); err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+// putRecord stores record in place of previous, nil for a new book, moving
+// its index entries.
+func putRecord(tx *bolt.Tx, previous, record *boltRecord) error {
+	if previous != nil {
+		if err := unindex(tx, previous); err != nil {
+			return err
+		}
+	}
+	// The rating aggregate is kept by the record, not the book.
+	record.Book.RatingCount, record.Book.RatingTotal = 0, 0
+	record.Book.Rating = nil
+	value, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	if err := tx.Bucket(boltBooksBucket).Put([]byte(record.ID), value); err != nil {
+		return err
+	}
+	for index, key := range indexKeys(record) {
+		if err := tx.Bucket([]byte(index)).Put(key, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func deleteRecord(tx *bolt.Tx, record *boltRecord) error {
+	if err := unindex(tx, record); err != nil {
+		return err
+	}
+	return tx.Bucket(boltBooksBucket).Delete([]byte(record.ID))
+}
+
+func unindex(tx *bolt.Tx, record *boltRecord) error {
+	for index, key := range indexKeys(record) {
+		if err := tx.Bucket([]byte(index)).Delete(key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// indexKeys are the entries of record in the indexes, by index name.
+// Timestamps use the fixed-width layout of the SQL store so keys sort as the
+// times they hold.
+func indexKeys(record *boltRecord) map[string][]byte {
+	if record.Trashed() {
+		return map[string][]byte{
+			string(boltTrashIndex): indexKey(sqlTime(*record.DeletedAt), record.ID),
+		}
+	}
+	keys := map[string][]byte{
+		string(boltNameIndex): indexKey(record.Name, record.ID),
+	}
+	if record.UpdatedAt != nil {
+		keys[string(boltRecentIndex)] = indexKey(sqlTime(*record.UpdatedAt), record.ID)
+	}
+	return keys
+}
+
+func indexKey(value, id string) []byte {
+	return []byte(value + "\x00" + id)
+}
+
+// indexedID is the book ID at the end of an index key.
+func indexedID(key []byte) string {
+	return string(key[bytes.LastIndexByte(key, 0)+1:])
+}
@@ -0,0 +1,217 @@
+package adapter_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"main/src/books/domain/model"
+	"main/src/books/domain/repository"
+	"main/src/books/infrastructure/adapter"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	_ repository.BookRepository        = (*adapter.BookBoltRepository)(nil)
+	_ repository.BookListingRepository = (*adapter.BookBoltRepository)(nil)
+)
+
+type BookBoltSuite struct {
+	suite.Suite
+	ctx            context.Context
+	db             *bolt.DB
+	initBooks      []model.Book
+	bookRepository *adapter.BookBoltRepository
+}
+
+func (suite *BookBoltSuite) SetupTest() {
+	suite.ctx = context.TODO()
+	db, err := bolt.Open(filepath.Join(suite.T().TempDir(), "books.bolt"), 0600, nil)
+	suite.Require().NoError(err)
+	suite.db = db
+
+	suite.bookRepository = adapter.NewBookBoltRepository(suite.ctx, db)
+	suite.Require().Nil(suite.bookRepository.CreateBuckets())
+
+	updatedAt := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	suite.initBooks = []model.Book{
+		{ID: uuid.NewString(), Name: "Book One", Description: "A first book", ImgURL: "url1", Publisher: "Ace",
+			Contributors: []model.Contributor{{Name: "Frank Herbert", Role: "A01"}}, UpdatedAt: &updatedAt},
+		{ID: uuid.NewString(), Name: "Book Two", Description: "A second book", ImgURL: "url2", Publisher: "Tor"},
+	}
+	suite.Require().Empty(suite.bookRepository.CreateBatchBooks(suite.initBooks))
+}
+
+func (suite *BookBoltSuite) TearDownTest() {
+	suite.db.Close()
+}
+
+func (suite *BookBoltSuite) TestCreateBookRoundTrips() {
+	createdAt := time.Date(2024, 1, 2, 3, 4, 5, 600, time.UTC)
+	newBook := model.Book{
+		ID: uuid.NewString(), Name: "Book Three", ISBN: "9780441013593",
+		Subjects:    []model.Subject{{Scheme: "BISAC", Code: "FIC028000", Main: true}},
+		Prices:      []model.Price{{Amount: "9.99", Currency: "USD"}},
+		RatingCount: 2, RatingTotal: 9,
+		CreatedAt: &createdAt,
+	}
+	_, err := suite.bookRepository.CreateBook(&newBook)
+	suite.Nil(err)
+
+	stored, err := suite.bookRepository.GetBookByID(newBook.ID)
+	suite.Nil(err)
+	suite.Equal(newBook, *stored)
+}
+
+func (suite *BookBoltSuite) TestUpdateBookByIDMovesTheIndexes() {
+	bookID := suite.initBooks[0].ID
+	updatedAt := time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC)
+	updatedBook, err := suite.bookRepository.UpdateBookByID(bookID, &model.Book{Name: "A Renamed Book", UpdatedAt: &updatedAt})
+	suite.Nil(err)
+	suite.Equal("Ace", updatedBook.Publisher)
+
+	page, _ := suite.bookRepository.ListBooks(model.BookFilter{}, "", 10)
+	suite.Equal([]string{bookID, suite.initBooks[1].ID}, bookIDs(page.Books))
+	page, _ = suite.bookRepository.ListBooks(model.BookFilter{Name: "Book One"}, "", 10)
+	suite.Empty(page.Books)
+	recent, _ := suite.bookRepository.GetRecentBooks(10)
+	suite.Len(recent, 1)
+	suite.Equal(&updatedAt, recent[0].UpdatedAt)
+
+	suite.Nil(suite.bookRepository.DeleteBookByID(bookID))
+	_, err = suite.bookRepository.UpdateBookByID(bookID, &model.Book{Name: "Edited"})
+	suite.Equal(http.StatusNotFound, err.Code)
+}
+
+func (suite *BookBoltSuite) TestCreateBatchBooksIsAtomic() {
+	books := []model.Book{
+		{ID: uuid.NewString(), Name: "Batch One"},
+		// bolt refuses empty keys, which fails the whole transaction.
+		{ID: "", Name: "Batch Two"},
+	}
+
+	failed := suite.bookRepository.CreateBatchBooks(books)
+	suite.Len(failed, 2)
+	stored, _ := suite.bookRepository.GetBookByID(books[0].ID)
+	suite.Empty(stored.ID)
+	page, _ := suite.bookRepository.ListBooks(model.BookFilter{Name: "Batch"}, "", 10)
+	suite.Empty(page.Books)
+}
+
+func (suite *BookBoltSuite) TestExpiredTrashIsPurged() {
+	bookRepository := adapter.NewBookBoltRepository(suite.ctx, suite.db).WithTrashRetention(time.Nanosecond)
+	suite.Nil(bookRepository.DeleteBookByID(suite.initBooks[1].ID))
+	time.Sleep(time.Millisecond)
+
+	trashed, _ := bookRepository.GetTrashedBooks(100)
+	suite.Empty(trashed)
+	suite.Equal(http.StatusNotFound, bookRepository.RestoreTrashedBook(suite.initBooks[1].ID).Code)
+	purged, err := bookRepository.PurgeExpiredBooks()
+	suite.Nil(err)
+	suite.Equal(1, purged)
+}
+
+func (suite *BookBoltSuite) TestRestoreBookKeepsRating() {
+	rated := model.Book{ID: uuid.NewString(), Name: "Rated", RatingCount: 2, RatingTotal: 9}
+	_, err := suite.bookRepository.CreateBook(&rated)
+	suite.Nil(err)
+	suite.Nil(suite.bookRepository.DeleteBookByID(rated.ID))
+
+	restored := model.Book{ID: rated.ID, Name: "Restored"}
+	suite.Nil(suite.bookRepository.RestoreBook(&restored))
+	stored, _ := suite.bookRepository.GetBookByID(restored.ID)
+	suite.Equal("Restored", stored.Name)
+	suite.Equal(2, stored.RatingCount)
+	suite.Equal(9, stored.RatingTotal)
+}
+
+func (suite *BookBoltSuite) TestListBooksPagesThroughMatches() {
+	var books []model.Book
+	for _, name := range []string{"Dune", "Dune Messiah", "Children of Dune", "Hyperion", "dune 100%"} {
+		books = append(books, model.Book{ID: uuid.NewString(), Name: name, Publisher: "Ace"})
+	}
+	suite.Require().Empty(suite.bookRepository.CreateBatchBooks(books))
+
+	filter := model.BookFilter{Name: "DUNE", Publisher: "Ace"}
+	var names []string
+	cursor := ""
+	for pages := 0; pages < 10; pages++ {
+		page, err := suite.bookRepository.ListBooks(filter, cursor, 2)
+		suite.Require().Nil(err)
+		for _, book := range page.Books {
+			names = append(names, book.Name)
+		}
+		if cursor = page.Next; cursor == "" {
+			break
+		}
+	}
+	suite.Equal([]string{"Children of Dune", "Dune", "Dune Messiah", "dune 100%"}, names)
+
+	_, err := suite.bookRepository.ListBooks(model.BookFilter{}, "not a cursor", 10)
+	suite.Equal(http.StatusBadRequest, err.Code)
+	_, err = suite.bookRepository.ListBooks(model.BookFilter{}, "", adapter.MaxListLimit+1)
+	suite.Equal(http.StatusUnprocessableEntity, err.Code)
+}
+
+func (suite *BookBoltSuite) TestBackupIsAStore() {
+	files := adapter.NewBookFileRepositoryBolt(suite.ctx, suite.db)
+	suite.Nil(files.SaveBookFile(bytes.NewReader([]byte("cover")), "images/cover.png", ".png"))
+
+	var backup bytes.Buffer
+	size, err := suite.bookRepository.Backup(&backup)
+	suite.Nil(err)
+	suite.Equal(int64(backup.Len()), size)
+
+	path := filepath.Join(suite.T().TempDir(), "backup.bolt")
+	suite.Require().NoError(os.WriteFile(path, backup.Bytes(), 0600))
+	db, errOpen := bolt.Open(path, 0600, nil)
+	suite.Require().NoError(errOpen)
+	defer db.Close()
+	books, _ := adapter.NewBookBoltRepository(suite.ctx, db).GetAllBooks()
+	suite.Len(books, 2)
+	content, _ := adapter.NewBookFileRepositoryBolt(suite.ctx, db).GetBookFile("images/cover.png")
+	suite.Equal("cover", string(content))
+}
+
+func (suite *BookBoltSuite) TestBookFiles() {
+	files := adapter.NewBookFileRepositoryBolt(suite.ctx, suite.db)
+	_, err := files.GetBookFile("images/missing.png")
+	suite.Equal(http.StatusNotFound, err.Code)
+
+	suite.Nil(files.SaveBookFile(bytes.NewReader([]byte("cover")), "images/cover.png", ".png"))
+	suite.Nil(files.DeleteBookFile("images/cover.png"))
+	_, err = files.GetBookFile("images/cover.png")
+	suite.Equal(http.StatusNotFound, err.Code)
+}
+
+func (suite *BookBoltSuite) TestBookFileVersions() {
+	files := adapter.NewBookFileRepositoryBolt(suite.ctx, suite.db)
+	first, err := files.SaveBookFileVersion(bytes.NewReader([]byte("v1")), "search/index", ".gz", "")
+	suite.Nil(err)
+	_, err = files.SaveBookFileVersion(bytes.NewReader([]byte("again")), "search/index", ".gz", "")
+	suite.Equal(http.StatusConflict, err.Code, "the file exists already")
+
+	content, version, err := files.GetBookFileVersion("search/index")
+	suite.Nil(err)
+	suite.Equal("v1", string(content))
+	suite.Equal(first, version)
+
+	second, err := files.SaveBookFileVersion(bytes.NewReader([]byte("v2")), "search/index", ".gz", version)
+	suite.Nil(err)
+	_, err = files.SaveBookFileVersion(bytes.NewReader([]byte("stale")), "search/index", ".gz", first)
+	suite.Equal(http.StatusConflict, err.Code, "the file moved past the version")
+	content, version, _ = files.GetBookFileVersion("search/index")
+	suite.Equal("v2", string(content))
+	suite.Equal(second, version)
+}
+
+func TestBookBoltSuite(t *testing.T) {
+	suite.Run(t, new(BookBoltSuite))
+}
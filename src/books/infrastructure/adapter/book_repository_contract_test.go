@@ -0,0 +1,279 @@
+package adapter_test
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"main/src/books/domain/model"
+	"main/src/books/domain/repository"
+	"main/src/books/infrastructure/adapter"
+	"main/src/books/infrastructure/configuration"
+	appError "main/utils/error"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	bolt "go.etcd.io/bbolt"
+)
+
+var _ repository.BookRepository = (*adapter.BookDynamoDBRepository)(nil)
+
+// BookRepositoryContractSuite is what every BookRepository does, whatever
+// keeps the books. Each adapter runs it with newRepository giving every test
+// an empty store of its own.
+type BookRepositoryContractSuite struct {
+	suite.Suite
+	newRepository  func(t *testing.T) repository.BookRepository
+	initBooks      []model.Book
+	bookRepository repository.BookRepository
+}
+
+func (suite *BookRepositoryContractSuite) SetupTest() {
+	suite.bookRepository = suite.newRepository(suite.T())
+
+	updatedAt := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	suite.initBooks = []model.Book{
+		{ID: uuid.NewString(), Name: "Book One", Description: "A first book", ImgURL: "url1", Publisher: "Ace",
+			Contributors: []model.Contributor{{Name: "Frank Herbert", Role: "A01"}}, UpdatedAt: &updatedAt},
+		{ID: uuid.NewString(), Name: "Book Two", Description: "A second book", ImgURL: "url2", Publisher: "Tor"},
+	}
+	suite.Require().Empty(suite.bookRepository.CreateBatchBooks(suite.initBooks))
+}
+
+func (suite *BookRepositoryContractSuite) TestGetAllBooks() {
+	books, err := suite.bookRepository.GetAllBooks()
+	suite.Nil(err)
+	suite.ElementsMatch([]string{suite.initBooks[0].ID, suite.initBooks[1].ID}, bookIDs(books))
+}
+
+func (suite *BookRepositoryContractSuite) TestCreateBook() {
+	newBook := model.Book{
+		ID: uuid.NewString(), Name: "Book Three", ISBN: "9780441013593",
+		Subjects: []model.Subject{{Scheme: "BISAC", Code: "FIC028000", Main: true}},
+		Prices:   []model.Price{{Amount: "9.99", Currency: "USD"}},
+	}
+	createdBook, err := suite.bookRepository.CreateBook(&newBook)
+	suite.Nil(err)
+	suite.Equal(newBook.Name, createdBook.Name)
+
+	stored, err := suite.bookRepository.GetBookByID(newBook.ID)
+	suite.Nil(err)
+	suite.Equal(newBook.Name, stored.Name)
+	suite.Equal(newBook.ISBN, stored.ISBN)
+	suite.Equal(newBook.Subjects, stored.Subjects)
+	suite.Equal(newBook.Prices, stored.Prices)
+}
+
+func (suite *BookRepositoryContractSuite) TestCreateNeverReplacesAStoredBook() {
+	stored := suite.initBooks[0]
+	_, err := suite.bookRepository.CreateBook(&model.Book{ID: stored.ID, Name: "Replacement"})
+	suite.Equal(http.StatusConflict, err.Code)
+
+	trashedID := suite.initBooks[1].ID
+	suite.Nil(suite.bookRepository.DeleteBookByID(trashedID))
+	newBook := model.Book{ID: uuid.NewString(), Name: "Book Three"}
+	failed := suite.bookRepository.CreateBatchBooks([]model.Book{
+		{ID: stored.ID, Name: "Replacement"}, {ID: trashedID, Name: "Replacement"}, newBook,
+	})
+	suite.Len(failed, 2)
+	suite.Equal(http.StatusConflict, failed[stored.ID].Code)
+	suite.Equal(http.StatusConflict, failed[trashedID].Code)
+
+	book, _ := suite.bookRepository.GetBookByID(stored.ID)
+	suite.Equal(stored.Name, book.Name)
+	book, _ = suite.bookRepository.GetBookByID(trashedID)
+	suite.Empty(book.ID)
+	book, _ = suite.bookRepository.GetBookByID(newBook.ID)
+	suite.Equal(newBook.Name, book.Name)
+}
+
+func (suite *BookRepositoryContractSuite) TestGetBookByID() {
+	book, err := suite.bookRepository.GetBookByID(suite.initBooks[0].ID)
+	suite.Nil(err)
+	suite.Equal(suite.initBooks[0].Contributors, book.Contributors)
+
+	book, err = suite.bookRepository.GetBookByID(uuid.NewString())
+	suite.Nil(err)
+	suite.Empty(book.ID)
+}
+
+func (suite *BookRepositoryContractSuite) TestGetBooksByIDs() {
+	missingID := uuid.NewString()
+	result, err := suite.bookRepository.GetBooksByIDs([]string{suite.initBooks[1].ID, missingID, suite.initBooks[1].ID})
+	suite.Nil(err)
+	suite.Len(result.Books, 1)
+	suite.Equal([]string{missingID}, result.MissingIDs)
+}
+
+func (suite *BookRepositoryContractSuite) TestUpdateBookByID() {
+	bookID := suite.initBooks[0].ID
+	updatedBook, err := suite.bookRepository.UpdateBookByID(bookID, &model.Book{Name: "Updated Book One", ImgURL: "updated_url1"})
+	suite.Nil(err)
+	suite.Equal("Updated Book One", updatedBook.Name)
+
+	stored, err := suite.bookRepository.GetBookByID(bookID)
+	suite.Nil(err)
+	suite.Equal("updated_url1", stored.ImgURL)
+	suite.Equal("Ace", stored.Publisher)
+
+	suite.Nil(suite.bookRepository.DeleteBookByID(bookID))
+	_, err = suite.bookRepository.UpdateBookByID(bookID, &model.Book{Name: "Edited"})
+	suite.Equal(http.StatusNotFound, err.Code)
+}
+
+func (suite *BookRepositoryContractSuite) TestUpdateBooksReportsMissing() {
+	missing := model.Book{ID: uuid.NewString(), Name: "Missing"}
+	replaced := suite.initBooks[1]
+	replaced.ISBN = "9780441013593"
+	replaced.Publisher = ""
+
+	failed := suite.bookRepository.UpdateBooks([]model.Book{replaced, missing})
+	suite.Len(failed, 1)
+	suite.Equal(http.StatusNotFound, failed[missing.ID].Code)
+	stored, _ := suite.bookRepository.GetBookByID(replaced.ID)
+	suite.Equal("9780441013593", stored.ISBN)
+	suite.Empty(stored.Publisher)
+}
+
+func (suite *BookRepositoryContractSuite) TestDeleteBooksByIDs() {
+	failed := suite.bookRepository.DeleteBooksByIDs([]string{suite.initBooks[0].ID, uuid.NewString()})
+	suite.Empty(failed)
+
+	books, _ := suite.bookRepository.GetAllBooks()
+	suite.Equal([]string{suite.initBooks[1].ID}, bookIDs(books))
+}
+
+func (suite *BookRepositoryContractSuite) TestTrashRestoreAndPurge() {
+	book := model.Book{ID: uuid.NewString(), Name: "Trashed", ImgURL: "url"}
+	_, err := suite.bookRepository.CreateBook(&book)
+	suite.Nil(err)
+
+	suite.Nil(suite.bookRepository.DeleteBookByID(book.ID))
+	suite.Nil(suite.bookRepository.DeleteBookByID(book.ID))
+	stored, _ := suite.bookRepository.GetBookByID(book.ID)
+	suite.Empty(stored.ID)
+	trashed, err := suite.bookRepository.GetTrashedBooks(100)
+	suite.Nil(err)
+	suite.Equal([]string{book.ID}, bookIDs(trashed))
+	suite.NotNil(trashed[0].DeletedAt)
+
+	suite.Nil(suite.bookRepository.RestoreTrashedBook(book.ID))
+	stored, _ = suite.bookRepository.GetBookByID(book.ID)
+	suite.Equal("Trashed", stored.Name)
+	suite.Equal(http.StatusNotFound, suite.bookRepository.PurgeBook(book.ID).Code)
+
+	suite.Nil(suite.bookRepository.DeleteBookByID(book.ID))
+	suite.Nil(suite.bookRepository.PurgeBook(book.ID))
+	trashed, _ = suite.bookRepository.GetTrashedBooks(100)
+	suite.Empty(trashed)
+	suite.Equal(http.StatusNotFound, suite.bookRepository.RestoreTrashedBook(book.ID).Code)
+}
+
+func (suite *BookRepositoryContractSuite) TestRestoreBook() {
+	bookID := suite.initBooks[1].ID
+	suite.Nil(suite.bookRepository.DeleteBookByID(bookID))
+
+	suite.Nil(suite.bookRepository.RestoreBook(&model.Book{ID: bookID, Name: "Restored"}))
+	stored, _ := suite.bookRepository.GetBookByID(bookID)
+	suite.Equal("Restored", stored.Name)
+	trashed, _ := suite.bookRepository.GetTrashedBooks(100)
+	suite.Empty(trashed)
+}
+
+func (suite *BookRepositoryContractSuite) TestGetRecentBooks() {
+	books, err := suite.bookRepository.GetRecentBooks(10)
+	suite.Nil(err)
+	suite.Equal([]string{suite.initBooks[0].ID}, bookIDs(books))
+}
+
+func (suite *BookRepositoryContractSuite) TestScanBooks() {
+	suite.Nil(suite.bookRepository.DeleteBookByID(suite.initBooks[1].ID))
+
+	var scanned []string
+	err := suite.bookRepository.ScanBooks(4, func(books []model.Book) *appError.Error {
+		scanned = append(scanned, bookIDs(books)...)
+		return nil
+	})
+	suite.Nil(err)
+	suite.Equal([]string{suite.initBooks[0].ID}, scanned)
+}
+
+func bookIDs(books []model.Book) []string {
+	ids := make([]string, len(books))
+	for i, book := range books {
+		ids[i] = book.ID
+	}
+	return ids
+}
+
+func TestBookSQLContract(t *testing.T) {
+	suite.Run(t, &BookRepositoryContractSuite{newRepository: func(t *testing.T) repository.BookRepository {
+		db, err := sql.Open("sqlite", "file::memory:")
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Every connection to :memory: is a database of its own.
+		db.SetMaxOpenConns(1)
+		t.Cleanup(func() { db.Close() })
+		bookRepository := adapter.NewBookSQLRepository(context.TODO(), db)
+		if err := bookRepository.Migrate(); err != nil {
+			t.Fatal(err.ToString())
+		}
+		return bookRepository
+	}})
+}
+
+func TestBookBoltContract(t *testing.T) {
+	suite.Run(t, &BookRepositoryContractSuite{newRepository: func(t *testing.T) repository.BookRepository {
+		db, err := bolt.Open(filepath.Join(t.TempDir(), "books.bolt"), 0600, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { db.Close() })
+		bookRepository := adapter.NewBookBoltRepository(context.TODO(), db)
+		if err := bookRepository.CreateBuckets(); err != nil {
+			t.Fatal(err.ToString())
+		}
+		return bookRepository
+	}})
+}
+
+func TestBookDynamoDBContract(t *testing.T) {
+	suite.Run(t, &BookRepositoryContractSuite{newRepository: func(t *testing.T) repository.BookRepository {
+		ctx := context.TODO()
+		client, err := configuration.GetLocalDynamoDBClient(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		table := "Test_Book_Contract_" + uuid.NewString()
+		if err := configuration.CreateLocalDynamoDBBookTable(ctx, client, table); err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { configuration.DeleteLocalDynamoDBBookTable(ctx, client, table) })
+		return adapter.NewBookDynamoDBRepository(ctx, client, table)
+	}})
+}
+
+func TestBookEventSourcedContract(t *testing.T) {
+	suite.Run(t, &BookRepositoryContractSuite{newRepository: func(t *testing.T) repository.BookRepository {
+		ctx := context.TODO()
+		client, err := configuration.GetLocalDynamoDBClient(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		eventsTable := "Test_Book_Contract_Events_" + uuid.NewString()
+		snapshotsTable := "Test_Book_Contract_Snapshots_" + uuid.NewString()
+		if err := configuration.CreateLocalDynamoDBBookEventsTable(ctx, client, eventsTable); err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { configuration.DeleteLocalDynamoDBBookTable(ctx, client, eventsTable) })
+		if err := configuration.CreateLocalDynamoDBBookSnapshotsTable(ctx, client, snapshotsTable); err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { configuration.DeleteLocalDynamoDBBookTable(ctx, client, snapshotsTable) })
+		return adapter.NewBookEventSourcedRepository(ctx, client, eventsTable, snapshotsTable).WithSnapshotEvery(3)
+	}})
+}
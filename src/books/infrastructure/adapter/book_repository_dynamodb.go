@@ -2,7 +2,11 @@ package adapter
 
 import (
 	"context"
+	"errors"
 	"log"
+	"net/http"
+	"sync"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
@@ -11,12 +15,56 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 	"main/src/books/domain/model"
 	appError "main/utils/error"
+	"main/utils/lib"
 )
 
+const (
+	maxBatchWriteSize = 25
+	batchWriteWorkers = 4
+	maxBatchAttempts  = 8
+	batchBackoffBase  = 50 * time.Millisecond
+	batchBackoffMax   = 2 * time.Second
+)
+
+// RecentIndex orders books by updated_at. Every timestamped book shares the
+// same recent partition, so the newest books are a single Query away; books
+// without timestamps are left out of the sparse index.
+const (
+	RecentIndex     = "recent-updated_at-index"
+	recentAttribute = "recent"
+	recentPartition = "books"
+)
+
+// TrashIndex orders the books in the trash, most recently deleted first.
+// Deleting a book puts it in the trash partition and restoring it takes it
+// out, so the sparse index only holds the trash.
+const (
+	TrashIndex     = "trash-deleted_at-index"
+	trashAttribute = "trash"
+	trashPartition = "books"
+)
+
+// DefaultTrashRetention is how long a deleted book stays in the trash before
+// the table TTL purges it.
+const DefaultTrashRetention = 30 * 24 * time.Hour
+
+// maxOutboxAttempts bounds the read-modify-write retries of a change whose
+// events or revision depend on the stored book.
+const maxOutboxAttempts = 3
+
 type BookDynamoDBRepository struct {
 	ctx    context.Context
 	client *dynamodb.Client
 	table  string
+	// outboxTable receives the events of every change, when set.
+	outboxTable string
+	// historyTable receives a revision of every change made by actor, when
+	// set.
+	historyTable string
+	actor        string
+	// trashRetention is how long deleted books stay in the trash,
+	// DefaultTrashRetention when zero.
+	trashRetention time.Duration
 }
 
 func NewBookDynamoDBRepository(ctx context.Context, client *dynamodb.Client, table string) *BookDynamoDBRepository {
@@ -27,43 +75,205 @@ func NewBookDynamoDBRepository(ctx context.Context, client *dynamodb.Client, tab
 	}
 }
 
-func (r *BookDynamoDBRepository) GetAllBooks() ([]model.Book, *appError.Error) {
-	input := &dynamodb.ScanInput{
-		TableName: aws.String(r.table),
+// NewBookDynamoDBRepositoryWithOutbox also writes the events of every change
+// to outboxTable, in the same transaction as the change.
+func NewBookDynamoDBRepositoryWithOutbox(ctx context.Context, client *dynamodb.Client, table, outboxTable string) *BookDynamoDBRepository {
+	return &BookDynamoDBRepository{
+		ctx:         ctx,
+		client:      client,
+		table:       table,
+		outboxTable: outboxTable,
 	}
-	result, err := r.client.Scan(r.ctx, input)
+}
+
+// WithHistory also records a revision of every change, attributed to actor,
+// in historyTable, in the same transaction as the change.
+func (r *BookDynamoDBRepository) WithHistory(historyTable, actor string) *BookDynamoDBRepository {
+	r.historyTable = historyTable
+	r.actor = actor
+	return r
+}
+
+// WithTrashRetention keeps deleted books in the trash for retention before
+// they are purged.
+func (r *BookDynamoDBRepository) WithTrashRetention(retention time.Duration) *BookDynamoDBRepository {
+	r.trashRetention = retention
+	return r
+}
+
+// tracked tells whether changes go through transactions recording their
+// events or revisions.
+func (r *BookDynamoDBRepository) tracked() bool {
+	return r.outboxTable != "" || r.historyTable != ""
+}
+
+// notTrashed holds for books that are not in the trash.
+func notTrashed() expression.ConditionBuilder {
+	return expression.AttributeNotExists(expression.Name("deleted_at"))
+}
+
+// bookExists is the error of a creation whose ID is already taken, trashed
+// books included: creating never replaces a stored book.
+func bookExists(id string) *appError.Error {
+	return appError.NewConflictError("Book " + id + " already exists.")
+}
+
+// newBook holds when no book has the ID of the one being put.
+func newBook() expression.ConditionBuilder {
+	return expression.AttributeNotExists(expression.Name("ID"))
+}
+
+func (r *BookDynamoDBRepository) GetAllBooks() ([]model.Book, *appError.Error) {
+	expr, err := expression.NewBuilder().WithFilter(notTrashed()).Build()
 	if err != nil {
-		log.Printf("Error scanning DynamoDB table: %v, table: %s", err, r.table)
+		log.Printf("Error building expression for scan: %v", err)
 		return nil, appError.NewUnexpectedError(err.Error())
 	}
+	// A scan page stops at 1 MB, filtered out items included.
+	paginator := dynamodb.NewScanPaginator(r.client, &dynamodb.ScanInput{
+		TableName:                aws.String(r.table),
+		FilterExpression:         expr.Filter(),
+		ExpressionAttributeNames: expr.Names(),
+	})
 
 	var books []model.Book
-	for _, item := range result.Items {
-		var book model.Book
-		err := attributevalue.UnmarshalMap(item, &book)
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(r.ctx)
 		if err != nil {
-			log.Printf("Error unmarshaling item from DynamoDB: %v, item: %+v", err, item)
+			log.Printf("Error scanning DynamoDB table: %v, table: %s", err, r.table)
 			return nil, appError.NewUnexpectedError(err.Error())
 		}
-		books = append(books, book)
+		for _, item := range page.Items {
+			var book model.Book
+			err := attributevalue.UnmarshalMap(item, &book)
+			if err != nil {
+				log.Printf("Error unmarshaling item from DynamoDB: %v, item: %+v", err, item)
+				return nil, appError.NewUnexpectedError(err.Error())
+			}
+			books = append(books, book)
+		}
 	}
 	log.Println("Retrieved all books successfully")
 	return books, nil
 }
 
-func (r *BookDynamoDBRepository) CreateBook(book *model.Book) (*model.Book, *appError.Error) {
+// ScanBooks reads the whole table with one paginated scan per segment running
+// in parallel, handing each page to visit as it arrives. visit may be called
+// concurrently. The first error stops the remaining segments. Books in the
+// trash are left out.
+func (r *BookDynamoDBRepository) ScanBooks(segments int, visit func([]model.Book) *appError.Error) *appError.Error {
+	if segments < 1 {
+		segments = 1
+	}
+	expr, err := expression.NewBuilder().WithFilter(notTrashed()).Build()
+	if err != nil {
+		log.Printf("Error building expression for scan: %v", err)
+		return appError.NewUnexpectedError(err.Error())
+	}
+	ctx, cancel := context.WithCancel(r.ctx)
+	defer cancel()
+
+	var once sync.Once
+	var scanErr *appError.Error
+	fail := func(err *appError.Error) {
+		once.Do(func() {
+			scanErr = err
+			cancel()
+		})
+	}
+
+	var wg sync.WaitGroup
+	for segment := 0; segment < segments; segment++ {
+		wg.Add(1)
+		go func(segment int) {
+			defer wg.Done()
+			paginator := dynamodb.NewScanPaginator(r.client, &dynamodb.ScanInput{
+				TableName:                aws.String(r.table),
+				Segment:                  aws.Int32(int32(segment)),
+				TotalSegments:            aws.Int32(int32(segments)),
+				FilterExpression:         expr.Filter(),
+				ExpressionAttributeNames: expr.Names(),
+			})
+			for paginator.HasMorePages() && ctx.Err() == nil {
+				page, err := paginator.NextPage(ctx)
+				if err != nil {
+					log.Printf("Error scanning DynamoDB table: %v, table: %s, segment: %d", err, r.table, segment)
+					fail(appError.NewUnexpectedError(err.Error()))
+					return
+				}
+				var books []model.Book
+				if err := attributevalue.UnmarshalListOfMaps(page.Items, &books); err != nil {
+					log.Printf("Error unmarshaling items from DynamoDB: %v", err)
+					fail(appError.NewUnexpectedError(err.Error()))
+					return
+				}
+				if err := visit(books); err != nil {
+					fail(err)
+					return
+				}
+			}
+			// A context done before the last page leaves the scan partial.
+			if err := ctx.Err(); err != nil {
+				fail(appError.NewUnexpectedError(err.Error()))
+			}
+		}(segment)
+	}
+	wg.Wait()
+
+	if scanErr == nil {
+		log.Printf("Scanned table %s with %d segments", r.table, segments)
+	}
+	return scanErr
+}
+
+// marshalBook adds the recent index key to timestamped books.
+func marshalBook(book *model.Book) (map[string]types.AttributeValue, error) {
 	av, err := attributevalue.MarshalMap(book)
+	if err != nil {
+		return nil, err
+	}
+	if book.UpdatedAt != nil {
+		av[recentAttribute] = &types.AttributeValueMemberS{Value: recentPartition}
+	}
+	return av, nil
+}
+
+func (r *BookDynamoDBRepository) CreateBook(book *model.Book) (*model.Book, *appError.Error) {
+	if r.tracked() {
+		if err := r.createBookTracked(book); err != nil {
+			if conditionFailed(err) {
+				return &model.Book{}, bookExists(book.ID)
+			}
+			log.Printf("Error putting item with its events in DynamoDB: %v, table: %s", err, r.table)
+			return &model.Book{}, appError.NewUnexpectedError(err.Error())
+		}
+		log.Printf("Book creation completed successfully, book: %+v", book)
+		return book, nil
+	}
+
+	av, err := marshalBook(book)
 	if err != nil {
 		log.Printf("Error marshaling book: %v, book: %+v", err, book)
 		return &model.Book{}, appError.NewUnexpectedError(err.Error())
 	}
 
+	expr, err := expression.NewBuilder().WithCondition(newBook()).Build()
+	if err != nil {
+		log.Printf("Error building expression for create: %v, ID: %s", err, book.ID)
+		return &model.Book{}, appError.NewUnexpectedError(err.Error())
+	}
 	input := &dynamodb.PutItemInput{
-		Item:      av,
-		TableName: aws.String(r.table),
+		Item:                     av,
+		TableName:                aws.String(r.table),
+		ConditionExpression:      expr.Condition(),
+		ExpressionAttributeNames: expr.Names(),
 	}
 	_, err = r.client.PutItem(r.ctx, input)
 	if err != nil {
+		var conditional *types.ConditionalCheckFailedException
+		if errors.As(err, &conditional) {
+			return &model.Book{}, bookExists(book.ID)
+		}
 		log.Printf("Error putting item in DynamoDB: %v, table: %s", err, r.table)
 		return &model.Book{}, appError.NewUnexpectedError(err.Error())
 	}
@@ -72,43 +282,137 @@ func (r *BookDynamoDBRepository) CreateBook(book *model.Book) (*model.Book, *app
 	return book, nil
 }
 
-func (r *BookDynamoDBRepository) CreateBatchBooks(books []model.Book) *appError.Error {
-	var writeRequests []types.WriteRequest
+// CreateBatchBooks writes books in chunks of 25, the BatchWriteItem limit,
+// using a bounded pool of workers. BatchWriteItem takes no condition, so the
+// IDs already stored are looked up first and those books are returned as
+// conflicts instead of written. Items DynamoDB leaves unprocessed are retried
+// with backoff; the ones that still could not be written are returned with
+// the reason, keyed by book ID.
+func (r *BookDynamoDBRepository) CreateBatchBooks(books []model.Book) map[string]*appError.Error {
+	if r.tracked() {
+		return r.createBooksWithEvents(books)
+	}
+	failed := make(map[string]*appError.Error)
+	ids := make([]string, len(books))
+	for i, book := range books {
+		ids[i] = book.ID
+	}
+	stored, errStored := r.getBooks(ids)
+	if errStored != nil {
+		for _, id := range ids {
+			failed[id] = errStored
+		}
+		return failed
+	}
+	var chunks [][]types.WriteRequest
+	var chunk []types.WriteRequest
 	for _, book := range books {
-		av, err := attributevalue.MarshalMap(book)
+		if _, ok := stored[book.ID]; ok {
+			failed[book.ID] = bookExists(book.ID)
+			continue
+		}
+		av, err := marshalBook(&book)
 		if err != nil {
 			log.Printf("Error while marshalling book: %s, book: %+v", err, book)
-			return appError.NewUnexpectedError(err.Error())
+			failed[book.ID] = appError.NewUnexpectedError(err.Error())
+			continue
 		}
-		writeRequests = append(writeRequests, types.WriteRequest{
+		chunk = append(chunk, types.WriteRequest{
 			PutRequest: &types.PutRequest{
 				Item: av,
 			},
 		})
+		if len(chunk) == maxBatchWriteSize {
+			chunks = append(chunks, chunk)
+			chunk = nil
+		}
+	}
+	if len(chunk) > 0 {
+		chunks = append(chunks, chunk)
 	}
 
-	const maxBatchSize = 25
-	for i := 0; i < len(writeRequests); i += maxBatchSize {
-		end := i + maxBatchSize
-		if end > len(writeRequests) {
-			end = len(writeRequests)
+	r.writeChunks(chunks, failed)
+
+	log.Printf("Batch books creation completed, requested: %d, failed: %d", len(books), len(failed))
+	return failed
+}
+
+// writeChunks writes the chunks concurrently, recording every request that
+// could not be written in failed.
+func (r *BookDynamoDBRepository) writeChunks(chunks [][]types.WriteRequest, failed map[string]*appError.Error) {
+	var mu sync.Mutex
+	forEachConcurrently(len(chunks), func(i int) {
+		unwritten, reason := r.writeBatch(chunks[i])
+		mu.Lock()
+		defer mu.Unlock()
+		for _, request := range unwritten {
+			failed[writeRequestID(request)] = reason
 		}
-		batch := writeRequests[i:end]
+	})
+}
 
-		input := &dynamodb.BatchWriteItemInput{
-			RequestItems: map[string][]types.WriteRequest{
-				r.table: batch,
-			},
+// forEachConcurrently calls fn for every index in [0, n) from at most
+// batchWriteWorkers goroutines, returning once all calls are done.
+func forEachConcurrently(n int, fn func(int)) {
+	var wg sync.WaitGroup
+	work := make(chan int)
+	for w := 0; w < batchWriteWorkers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range work {
+				fn(i)
+			}
+		}()
+	}
+	for i := 0; i < n; i++ {
+		work <- i
+	}
+	close(work)
+	wg.Wait()
+}
+
+// writeBatch sends one BatchWriteItem and retries its unprocessed items,
+// returning whatever is left unwritten and why.
+func (r *BookDynamoDBRepository) writeBatch(batch []types.WriteRequest) ([]types.WriteRequest, *appError.Error) {
+	requestItems := map[string][]types.WriteRequest{
+		r.table: batch,
+	}
+	for attempt := 0; len(requestItems) > 0; attempt++ {
+		if attempt == maxBatchAttempts {
+			log.Printf("Items still unprocessed after %d attempts, table: %s", attempt, r.table)
+			return requestItems[r.table], appError.NewUnexpectedError("DynamoDB is throttling requests, item was not written.")
+		}
+		if attempt > 0 {
+			if err := lib.Sleep(r.ctx, lib.Backoff(attempt-1, batchBackoffBase, batchBackoffMax)); err != nil {
+				log.Printf("Gave up retrying unprocessed items: %s, table: %s", err, r.table)
+				return requestItems[r.table], appError.NewUnexpectedError(err.Error())
+			}
 		}
 
-		_, err := r.client.BatchWriteItem(r.ctx, input)
+		result, err := r.client.BatchWriteItem(r.ctx, &dynamodb.BatchWriteItemInput{
+			RequestItems: requestItems,
+		})
 		if err != nil {
-			log.Printf("Error while batch writing items: %s, batch size: %d", err, len(batch))
-			return appError.NewUnexpectedError(err.Error())
+			log.Printf("Error while batch writing items: %s, batch size: %d", err, len(requestItems[r.table]))
+			return requestItems[r.table], appError.NewUnexpectedError(err.Error())
 		}
+		requestItems = result.UnprocessedItems
 	}
-	log.Println("Batch books creation completed successfully")
-	return nil
+	return nil, nil
+}
+
+func writeRequestID(request types.WriteRequest) string {
+	item := map[string]types.AttributeValue{}
+	if request.PutRequest != nil {
+		item = request.PutRequest.Item
+	} else if request.DeleteRequest != nil {
+		item = request.DeleteRequest.Key
+	}
+	if id, ok := item["ID"].(*types.AttributeValueMemberS); ok {
+		return id.Value
+	}
+	return ""
 }
 
 func (r *BookDynamoDBRepository) GetBookByID(id string) (*model.Book, *appError.Error) {
@@ -137,25 +441,118 @@ func (r *BookDynamoDBRepository) GetBookByID(id string) (*model.Book, *appError.
 		log.Printf("Error unmarshaling item from DynamoDB: %v, item: %+v", err, result.Item)
 		return &model.Book{}, appError.NewUnexpectedError(err.Error())
 	}
+	if book.Trashed() {
+		log.Println("Book is in the trash, ID:", id)
+		return &model.Book{}, nil
+	}
 
 	log.Printf("Retrieved book successfully, ID: %s, book: %+v", id, book)
 	return &book, nil
 }
 
+// GetBooksByIDs reads books in chunks of 100 keys, the BatchGetItem limit.
+func (r *BookDynamoDBRepository) GetBooksByIDs(ids []string) (*model.BooksByIDs, *appError.Error) {
+	// BatchGetItem rejects duplicate keys, so each ID is requested once.
+	var uniqueIDs []string
+	seen := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			uniqueIDs = append(uniqueIDs, id)
+		}
+	}
+
+	found, err := r.getBooks(uniqueIDs)
+	if err != nil {
+		return nil, err
+	}
+	for id, book := range found {
+		if book.Trashed() {
+			delete(found, id)
+		}
+	}
+
+	result := &model.BooksByIDs{
+		Books:      make([]model.Book, 0, len(found)),
+		MissingIDs: []string{},
+	}
+	for _, id := range uniqueIDs {
+		if book, ok := found[id]; ok {
+			result.Books = append(result.Books, book)
+		} else {
+			result.MissingIDs = append(result.MissingIDs, id)
+		}
+	}
+	log.Printf("Retrieved %d of %d requested books, missing: %v", len(result.Books), len(uniqueIDs), result.MissingIDs)
+	return result, nil
+}
+
+// getBooks reads the stored books with the given unique IDs, trashed ones
+// included, keyed by ID. Keys DynamoDB leaves unprocessed under throttling
+// are retried with exponential backoff and jitter before giving up.
+func (r *BookDynamoDBRepository) getBooks(ids []string) (map[string]model.Book, *appError.Error) {
+	found := make(map[string]model.Book, len(ids))
+	const maxBatchSize = 100
+	for i := 0; i < len(ids); i += maxBatchSize {
+		end := i + maxBatchSize
+		if end > len(ids) {
+			end = len(ids)
+		}
+
+		keys := make([]map[string]types.AttributeValue, 0, end-i)
+		for _, id := range ids[i:end] {
+			keys = append(keys, map[string]types.AttributeValue{
+				"ID": &types.AttributeValueMemberS{Value: id},
+			})
+		}
+
+		requestItems := map[string]types.KeysAndAttributes{
+			r.table: {Keys: keys},
+		}
+		for attempt := 0; len(requestItems) > 0; attempt++ {
+			if attempt == maxBatchAttempts {
+				log.Printf("Keys still unprocessed after %d attempts, table: %s", attempt, r.table)
+				return nil, appError.NewUnexpectedError("Books could not be read, DynamoDB is throttling requests")
+			}
+			if attempt > 0 {
+				if err := lib.Sleep(r.ctx, lib.Backoff(attempt-1, batchBackoffBase, batchBackoffMax)); err != nil {
+					log.Printf("Gave up retrying unprocessed keys: %s, table: %s", err, r.table)
+					return nil, appError.NewUnexpectedError(err.Error())
+				}
+			}
+
+			result, err := r.client.BatchGetItem(r.ctx, &dynamodb.BatchGetItemInput{
+				RequestItems: requestItems,
+			})
+			if err != nil {
+				log.Printf("Error while batch getting items: %s, batch size: %d", err, len(keys))
+				return nil, appError.NewUnexpectedError(err.Error())
+			}
+
+			var batchBooks []model.Book
+			err = attributevalue.UnmarshalListOfMaps(result.Responses[r.table], &batchBooks)
+			if err != nil {
+				log.Printf("Error unmarshaling items from DynamoDB: %v", err)
+				return nil, appError.NewUnexpectedError(err.Error())
+			}
+			for _, book := range batchBooks {
+				found[book.ID] = book
+			}
+			requestItems = result.UnprocessedKeys
+		}
+	}
+	return found, nil
+}
+
 func (r *BookDynamoDBRepository) UpdateBookByID(id string, book *model.Book) (*model.Book, *appError.Error) {
+	if r.tracked() {
+		return r.updateBookWithEvents(id, book)
+	}
 	keyCond := map[string]types.AttributeValue{
 		"ID": &types.AttributeValueMemberS{Value: id},
 	}
-	
-	update := expression.Set(
-		expression.Name("name"), expression.Value(book.Name),
-	).Set(
-		expression.Name("description"), expression.Value(book.Description),
-	).Set(
-		expression.Name("img_url"), expression.Value(book.ImgURL),
-	)
 
-	expr, err := expression.NewBuilder().WithUpdate(update).Build()
+	expr, err := expression.NewBuilder().WithCondition(notTrashed()).WithUpdate(bookUpdate(book)).Build()
 	if err != nil {
 		log.Printf("Error building expression for update: %v, ID: %s", err, id)
 		return &model.Book{}, appError.NewUnexpectedError(err.Error())
@@ -165,6 +562,7 @@ func (r *BookDynamoDBRepository) UpdateBookByID(id string, book *model.Book) (*m
 		TableName:                 aws.String(r.table),
 		Key:                       keyCond,
 		UpdateExpression:          expr.Update(),
+		ConditionExpression:       expr.Condition(),
 		ExpressionAttributeNames:  expr.Names(),
 		ExpressionAttributeValues: expr.Values(),
 		ReturnValues:              types.ReturnValueUpdatedNew,
@@ -172,6 +570,10 @@ func (r *BookDynamoDBRepository) UpdateBookByID(id string, book *model.Book) (*m
 
 	result, err := r.client.UpdateItem(r.ctx, input)
 	if err != nil {
+		var conditional *types.ConditionalCheckFailedException
+		if errors.As(err, &conditional) {
+			return &model.Book{}, appError.NewNotFoundError("Book " + id + " not found.")
+		}
 		log.Printf("Error updating item in DynamoDB: %v, table: %s", err, r.table)
 		return &model.Book{}, appError.NewUnexpectedError(err.Error())
 	}
@@ -187,31 +589,790 @@ func (r *BookDynamoDBRepository) UpdateBookByID(id string, book *model.Book) (*m
 	return &updatedBook, nil
 }
 
+// bookUpdate sets the fields UpdateBookByID replaces.
+func bookUpdate(book *model.Book) expression.UpdateBuilder {
+	update := expression.Set(
+		expression.Name("name"), expression.Value(book.Name),
+	).Set(
+		expression.Name("description"), expression.Value(book.Description),
+	).Set(
+		expression.Name("img_url"), expression.Value(book.ImgURL),
+	)
+	if book.UpdatedAt != nil {
+		update = update.Set(
+			expression.Name("updated_at"), expression.Value(book.UpdatedAt),
+		).Set(
+			expression.Name(recentAttribute), expression.Value(recentPartition),
+		)
+	}
+	return update
+}
+
+// DeleteBookByID moves a book to the trash, where it stays until it is
+// restored or the retention period runs out. Deleting a book that does not
+// exist or is already in the trash does nothing.
 func (r *BookDynamoDBRepository) DeleteBookByID(id string) *appError.Error {
-	key := map[string]types.AttributeValue{
-		"ID": &types.AttributeValueMemberS{Value: id},
+	if r.tracked() {
+		return r.deleteBookWithEvents(id)
+	}
+	expr, err := expression.NewBuilder().
+		WithCondition(expression.AttributeExists(expression.Name("ID")).And(notTrashed())).
+		WithUpdate(r.trashUpdate()).
+		Build()
+	if err != nil {
+		log.Printf("Error building expression for delete: %v, ID: %s", err, id)
+		return appError.NewUnexpectedError(err.Error())
 	}
 
-	input := &dynamodb.DeleteItemInput{
-		Key:       key,
+	_, err = r.client.UpdateItem(r.ctx, &dynamodb.UpdateItemInput{
+		TableName:                 aws.String(r.table),
+		Key:                       map[string]types.AttributeValue{"ID": &types.AttributeValueMemberS{Value: id}},
+		UpdateExpression:          expr.Update(),
+		ConditionExpression:       expr.Condition(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+	})
+	if err != nil {
+		var conditional *types.ConditionalCheckFailedException
+		if errors.As(err, &conditional) {
+			log.Println("No book found with ID:", id)
+			return nil
+		}
+		log.Printf("Error deleting item from DynamoDB: %v, table: %s", err, r.table)
+		return appError.NewUnexpectedError(err.Error())
+	}
+
+	log.Printf("Moved book to the trash, book_id: %s", id)
+	return nil
+}
+
+// trashUpdate stamps the tombstone of a deleted book and the time the table
+// TTL purges it, and moves it from the recent index to the trash index.
+func (r *BookDynamoDBRepository) trashUpdate() expression.UpdateBuilder {
+	retention := r.trashRetention
+	if retention <= 0 {
+		retention = DefaultTrashRetention
+	}
+	now := time.Now().UTC()
+	return expression.Set(
+		expression.Name("deleted_at"), expression.Value(now),
+	).Set(
+		expression.Name("expires_at"), expression.Value(now.Add(retention).Unix()),
+	).Set(
+		expression.Name(trashAttribute), expression.Value(trashPartition),
+	).Remove(
+		expression.Name(recentAttribute),
+	)
+}
+
+// untrashUpdate clears the tombstone of book and puts it back in the recent
+// index.
+func untrashUpdate(book *model.Book) expression.UpdateBuilder {
+	update := expression.Remove(expression.Name("deleted_at")).
+		Remove(expression.Name("expires_at")).
+		Remove(expression.Name(trashAttribute))
+	if book.UpdatedAt != nil {
+		update = update.Set(expression.Name(recentAttribute), expression.Value(recentPartition))
+	}
+	return update
+}
+
+// DeleteBooksByIDs moves each book to the trash with a conditional UpdateItem
+// and returns the IDs that could not be deleted with the reason.
+func (r *BookDynamoDBRepository) DeleteBooksByIDs(ids []string) map[string]*appError.Error {
+	if r.tracked() {
+		return r.deleteBooksWithEvents(ids)
+	}
+	failed := make(map[string]*appError.Error)
+	var mu sync.Mutex
+	forEachConcurrently(len(ids), func(i int) {
+		if err := r.DeleteBookByID(ids[i]); err != nil {
+			mu.Lock()
+			defer mu.Unlock()
+			failed[ids[i]] = err
+		}
+	})
+
+	log.Printf("Batch books deletion completed, requested: %d, failed: %d", len(ids), len(failed))
+	return failed
+}
+
+// catalogAttributes are the attributes a batch update replaces. The ID, the
+// record reference, the creation time and the rating aggregate maintained by
+// reviews are kept.
+var catalogAttributes = []string{
+	"name", "description", "img_url", "isbn", "subtitle", "publisher", "published_on",
+	"contributors", "subjects", "prices", "availability", "updated_at",
+}
+
+// catalogUpdate sets every catalog attribute of book and removes the ones it
+// leaves empty, so the stored record matches the book exactly.
+func catalogUpdate(book *model.Book) (expression.UpdateBuilder, error) {
+	av, err := attributevalue.MarshalMap(book)
+	if err != nil {
+		return expression.UpdateBuilder{}, err
+	}
+	var update expression.UpdateBuilder
+	for _, name := range catalogAttributes {
+		if value, ok := av[name]; ok {
+			update = update.Set(expression.Name(name), expression.Value(value))
+		} else {
+			update = update.Remove(expression.Name(name))
+		}
+	}
+	if book.UpdatedAt != nil {
+		update = update.Set(expression.Name(recentAttribute), expression.Value(recentPartition))
+	}
+	return update, nil
+}
+
+// UpdateBooks updates each book with a conditional UpdateItem so a book that
+// was deleted meanwhile, or sits in the trash, is reported as not found
+// instead of being recreated.
+func (r *BookDynamoDBRepository) UpdateBooks(books []model.Book) map[string]*appError.Error {
+	failed := make(map[string]*appError.Error)
+	var mu sync.Mutex
+	forEachConcurrently(len(books), func(i int) {
+		if err := r.updateExistingBook(&books[i]); err != nil {
+			mu.Lock()
+			defer mu.Unlock()
+			failed[books[i].ID] = err
+		}
+	})
+
+	log.Printf("Batch books update completed, requested: %d, failed: %d", len(books), len(failed))
+	return failed
+}
+
+func (r *BookDynamoDBRepository) updateExistingBook(book *model.Book) *appError.Error {
+	if r.tracked() {
+		return r.updateExistingBookWithEvents(book)
+	}
+	update, err := catalogUpdate(book)
+	if err != nil {
+		log.Printf("Error marshaling book: %v, book: %+v", err, book)
+		return appError.NewUnexpectedError(err.Error())
+	}
+	expr, err := expression.NewBuilder().
+		WithCondition(expression.AttributeExists(expression.Name("ID")).And(notTrashed())).
+		WithUpdate(update).
+		Build()
+	if err != nil {
+		log.Printf("Error building expression for update: %v, ID: %s", err, book.ID)
+		return appError.NewUnexpectedError(err.Error())
+	}
+
+	_, err = r.client.UpdateItem(r.ctx, &dynamodb.UpdateItemInput{
 		TableName: aws.String(r.table),
+		Key: map[string]types.AttributeValue{
+			"ID": &types.AttributeValueMemberS{Value: book.ID},
+		},
+		UpdateExpression:          expr.Update(),
+		ConditionExpression:       expr.Condition(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+	})
+	if err != nil {
+		var conditional *types.ConditionalCheckFailedException
+		if errors.As(err, &conditional) {
+			return appError.NewNotFoundError("Book " + book.ID + " not found.")
+		}
+		log.Printf("Error updating item in DynamoDB: %v, table: %s", err, r.table)
+		return appError.NewUnexpectedError(err.Error())
+	}
+	return nil
+}
+
+// GetRecentBooks returns up to limit books, most recently updated first.
+func (r *BookDynamoDBRepository) GetRecentBooks(limit int) ([]model.Book, *appError.Error) {
+	keyCond := expression.Key(recentAttribute).Equal(expression.Value(recentPartition))
+	expr, err := expression.NewBuilder().WithKeyCondition(keyCond).Build()
+	if err != nil {
+		log.Printf("Error building expression for recent books query: %v", err)
+		return nil, appError.NewUnexpectedError(err.Error())
+	}
+
+	result, err := r.client.Query(r.ctx, &dynamodb.QueryInput{
+		TableName:                 aws.String(r.table),
+		IndexName:                 aws.String(RecentIndex),
+		KeyConditionExpression:    expr.KeyCondition(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		ScanIndexForward:          aws.Bool(false),
+		Limit:                     aws.Int32(int32(limit)),
+	})
+	if err != nil {
+		log.Printf("Error querying recent books: %v, table: %s", err, r.table)
+		return nil, appError.NewUnexpectedError(err.Error())
+	}
+
+	books := []model.Book{}
+	if err := attributevalue.UnmarshalListOfMaps(result.Items, &books); err != nil {
+		log.Printf("Error unmarshaling recent books from DynamoDB: %v", err)
+		return nil, appError.NewUnexpectedError(err.Error())
+	}
+	log.Printf("Retrieved %d recent books", len(books))
+	return books, nil
+}
+
+// GetTrashedBooks returns up to limit books of the trash, most recently
+// deleted first.
+func (r *BookDynamoDBRepository) GetTrashedBooks(limit int) ([]model.Book, *appError.Error) {
+	keyCond := expression.Key(trashAttribute).Equal(expression.Value(trashPartition))
+	expr, err := expression.NewBuilder().WithKeyCondition(keyCond).Build()
+	if err != nil {
+		log.Printf("Error building expression for trash query: %v", err)
+		return nil, appError.NewUnexpectedError(err.Error())
+	}
+
+	result, err := r.client.Query(r.ctx, &dynamodb.QueryInput{
+		TableName:                 aws.String(r.table),
+		IndexName:                 aws.String(TrashIndex),
+		KeyConditionExpression:    expr.KeyCondition(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		ScanIndexForward:          aws.Bool(false),
+		Limit:                     aws.Int32(int32(limit)),
+	})
+	if err != nil {
+		log.Printf("Error querying trashed books: %v, table: %s", err, r.table)
+		return nil, appError.NewUnexpectedError(err.Error())
+	}
+
+	books := []model.Book{}
+	if err := attributevalue.UnmarshalListOfMaps(result.Items, &books); err != nil {
+		log.Printf("Error unmarshaling trashed books from DynamoDB: %v", err)
+		return nil, appError.NewUnexpectedError(err.Error())
+	}
+	log.Printf("Retrieved %d trashed books", len(books))
+	return books, nil
+}
+
+// RestoreTrashedBook takes a book out of the trash as it was deleted.
+func (r *BookDynamoDBRepository) RestoreTrashedBook(id string) *appError.Error {
+	notInTrash := appError.NewNotFoundError("Book " + id + " not found in the trash.")
+	if r.tracked() {
+		err := r.trackChange(id, func(previous *model.Book, revision int) (types.TransactWriteItem, bookChange, *appError.Error) {
+			if previous == nil || !previous.Trashed() {
+				return types.TransactWriteItem{}, bookChange{}, notInTrash
+			}
+			restored := *previous
+			restored.DeletedAt = nil
+			update := untrashUpdate(previous)
+			if revision > 0 {
+				restored.Revision = revision
+				update = update.Set(expression.Name("revision"), expression.Value(revision))
+			}
+			expr, err := expression.NewBuilder().WithCondition(unchanged(previous)).WithUpdate(update).Build()
+			if err != nil {
+				log.Printf("Error building expression for restore: %v, ID: %s", err, id)
+				return types.TransactWriteItem{}, bookChange{}, appError.NewUnexpectedError(err.Error())
+			}
+			return types.TransactWriteItem{
+				Update: &types.Update{
+					TableName:                 aws.String(r.table),
+					Key:                       map[string]types.AttributeValue{"ID": &types.AttributeValueMemberS{Value: id}},
+					UpdateExpression:          expr.Update(),
+					ConditionExpression:       expr.Condition(),
+					ExpressionAttributeNames:  expr.Names(),
+					ExpressionAttributeValues: expr.Values(),
+				},
+			}, bookChange{id: id, operation: model.BookOperationRestored, previous: previous, book: &restored, revision: revision}, nil
+		})
+		if err != nil {
+			return err
+		}
+		log.Printf("Restored book from the trash, ID: %s", id)
+		return nil
 	}
 
-	result, err := r.client.DeleteItem(r.ctx, input)
+	previous, err := r.currentBook(id)
+	if err != nil {
+		log.Printf("Error getting item from DynamoDB: %v, table: %s", err, r.table)
+		return appError.NewUnexpectedError(err.Error())
+	}
+	if previous == nil || !previous.Trashed() {
+		return notInTrash
+	}
+	expr, err := expression.NewBuilder().
+		WithCondition(expression.AttributeExists(expression.Name("deleted_at"))).
+		WithUpdate(untrashUpdate(previous)).
+		Build()
 	if err != nil {
+		log.Printf("Error building expression for restore: %v, ID: %s", err, id)
+		return appError.NewUnexpectedError(err.Error())
+	}
+	_, err = r.client.UpdateItem(r.ctx, &dynamodb.UpdateItemInput{
+		TableName:                 aws.String(r.table),
+		Key:                       map[string]types.AttributeValue{"ID": &types.AttributeValueMemberS{Value: id}},
+		UpdateExpression:          expr.Update(),
+		ConditionExpression:       expr.Condition(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+	})
+	if err != nil {
+		var conditional *types.ConditionalCheckFailedException
+		if errors.As(err, &conditional) {
+			return notInTrash
+		}
+		log.Printf("Error updating item in DynamoDB: %v, table: %s", err, r.table)
+		return appError.NewUnexpectedError(err.Error())
+	}
+	log.Printf("Restored book from the trash, ID: %s", id)
+	return nil
+}
+
+// PurgeBook deletes a book of the trash for good, as the table TTL does once
+// the retention period runs out. Its deletion was recorded when it was
+// trashed, so the purge records nothing; the stream handlers remove its
+// files.
+func (r *BookDynamoDBRepository) PurgeBook(id string) *appError.Error {
+	expr, err := expression.NewBuilder().WithCondition(expression.AttributeExists(expression.Name("deleted_at"))).Build()
+	if err != nil {
+		log.Printf("Error building expression for purge: %v, ID: %s", err, id)
+		return appError.NewUnexpectedError(err.Error())
+	}
+	_, err = r.client.DeleteItem(r.ctx, &dynamodb.DeleteItemInput{
+		TableName:                aws.String(r.table),
+		Key:                      map[string]types.AttributeValue{"ID": &types.AttributeValueMemberS{Value: id}},
+		ConditionExpression:      expr.Condition(),
+		ExpressionAttributeNames: expr.Names(),
+	})
+	if err != nil {
+		var conditional *types.ConditionalCheckFailedException
+		if errors.As(err, &conditional) {
+			return appError.NewNotFoundError("Book " + id + " not found in the trash.")
+		}
 		log.Printf("Error deleting item from DynamoDB: %v, table: %s", err, r.table)
 		return appError.NewUnexpectedError(err.Error())
 	}
+	log.Printf("Purged book from the trash, ID: %s", id)
+	return nil
+}
+
+// bookChange is a tracked write of a book: previous is nil for creations and
+// book nil for deletions. revision is 0 when no history is kept.
+type bookChange struct {
+	id        string
+	operation model.BookOperation
+	previous  *model.Book
+	book      *model.Book
+	revision  int
+}
+
+// writeChange applies a book write and puts the events of the change in the
+// outbox and its revision in the history in one transaction, so they are
+// recorded if and only if the change is.
+func (r *BookDynamoDBRepository) writeChange(write types.TransactWriteItem, change bookChange) error {
+	items := []types.TransactWriteItem{write}
+	if r.outboxTable != "" {
+		// Consumers saw a trashed book deleted, so taking it out of the trash
+		// creates it again.
+		previous := change.previous
+		if previous != nil && previous.Trashed() {
+			previous = nil
+		}
+		events := model.NewBookEvents(change.id, previous, change.book)
+		for i := range events {
+			av, err := marshalEvent(&events[i])
+			if err != nil {
+				return err
+			}
+			items = append(items, types.TransactWriteItem{
+				Put: &types.Put{
+					TableName: aws.String(r.outboxTable),
+					Item:      av,
+				},
+			})
+		}
+	}
+	if r.historyTable != "" {
+		revision := model.NewBookRevision(change.id, change.revision, change.operation, r.actor, change.previous, change.book)
+		av, err := attributevalue.MarshalMap(revision)
+		if err != nil {
+			return err
+		}
+		// Two writers numbering their change the same fail instead of
+		// overwriting each other's revision.
+		expr, err := expression.NewBuilder().WithCondition(expression.AttributeNotExists(expression.Name("revision"))).Build()
+		if err != nil {
+			return err
+		}
+		items = append(items, types.TransactWriteItem{
+			Put: &types.Put{
+				TableName:                aws.String(r.historyTable),
+				Item:                     av,
+				ConditionExpression:      expr.Condition(),
+				ExpressionAttributeNames: expr.Names(),
+			},
+		})
+	}
+	_, err := r.client.TransactWriteItems(r.ctx, &dynamodb.TransactWriteItemsInput{TransactItems: items})
+	return err
+}
+
+// conditionFailed tells whether a transaction was rejected by the condition
+// of one of its writes.
+func conditionFailed(err error) bool {
+	var canceled *types.TransactionCanceledException
+	if !errors.As(err, &canceled) {
+		return false
+	}
+	for _, reason := range canceled.CancellationReasons {
+		if aws.ToString(reason.Code) == "ConditionalCheckFailed" {
+			return true
+		}
+	}
+	return false
+}
+
+// currentBook reads a book with a consistent read, returning nil when it does
+// not exist.
+func (r *BookDynamoDBRepository) currentBook(id string) (*model.Book, error) {
+	result, err := r.client.GetItem(r.ctx, &dynamodb.GetItemInput{
+		TableName:      aws.String(r.table),
+		Key:            map[string]types.AttributeValue{"ID": &types.AttributeValueMemberS{Value: id}},
+		ConsistentRead: aws.Bool(true),
+	})
+	if err != nil || result.Item == nil {
+		return nil, err
+	}
+	var book model.Book
+	if err := attributevalue.UnmarshalMap(result.Item, &book); err != nil {
+		return nil, err
+	}
+	return &book, nil
+}
+
+// nextRevision numbers the next change of a book, 0 without a history. A
+// book that does not exist anymore continues the numbering of its history.
+func (r *BookDynamoDBRepository) nextRevision(id string, previous *model.Book) (int, error) {
+	if r.historyTable == "" {
+		return 0, nil
+	}
+	if previous != nil && previous.Revision > 0 {
+		return previous.Revision + 1, nil
+	}
+	latest, err := latestRevision(r.ctx, r.client, r.historyTable, id)
+	return latest + 1, err
+}
+
+// unchanged holds while the stored book is still previous as far as its
+// events and history are concerned: same image, update time, revision and
+// tombstone.
+func unchanged(previous *model.Book) expression.ConditionBuilder {
+	if previous == nil {
+		return expression.AttributeNotExists(expression.Name("ID"))
+	}
+	image := expression.AttributeNotExists(expression.Name("img_url"))
+	if previous.ImgURL != "" {
+		image = expression.Name("img_url").Equal(expression.Value(previous.ImgURL))
+	}
+	updated := expression.AttributeNotExists(expression.Name("updated_at"))
+	if previous.UpdatedAt != nil {
+		updated = expression.Name("updated_at").Equal(expression.Value(previous.UpdatedAt))
+	}
+	revision := expression.AttributeNotExists(expression.Name("revision"))
+	if previous.Revision > 0 {
+		revision = expression.Name("revision").Equal(expression.Value(previous.Revision))
+	}
+	deleted := notTrashed()
+	if previous.DeletedAt != nil {
+		deleted = expression.Name("deleted_at").Equal(expression.Value(previous.DeletedAt))
+	}
+	return expression.AttributeExists(expression.Name("ID")).And(image, updated, revision, deleted)
+}
 
-	var deletedBook model.Book
-	if result.Attributes != nil {
-		err = attributevalue.UnmarshalMap(result.Attributes, &deletedBook)
+// trackChange reads the stored book and writes the change build makes of it,
+// with its events and revision, unless the book changed meanwhile. It
+// retries a few times when it loses a race.
+func (r *BookDynamoDBRepository) trackChange(id string, build func(previous *model.Book, revision int) (types.TransactWriteItem, bookChange, *appError.Error)) *appError.Error {
+	for attempt := 0; attempt < maxOutboxAttempts; attempt++ {
+		previous, err := r.currentBook(id)
+		if err != nil {
+			log.Printf("Error getting item from DynamoDB: %v, table: %s", err, r.table)
+			return appError.NewUnexpectedError(err.Error())
+		}
+		revision, err := r.nextRevision(id, previous)
 		if err != nil {
-			log.Printf("Error unmarshaling deleted item from DynamoDB: %v, item: %+v", err, result.Attributes)
+			log.Printf("Error reading book history: %v, table: %s", err, r.historyTable)
+			return appError.NewUnexpectedError(err.Error())
+		}
+		write, change, errBuild := build(previous, revision)
+		if errBuild != nil {
+			return errBuild
+		}
+
+		err = r.writeChange(write, change)
+		if err == nil {
+			return nil
+		}
+		if !conditionFailed(err) {
+			log.Printf("Error writing item with its events in DynamoDB: %v, table: %s", err, r.table)
 			return appError.NewUnexpectedError(err.Error())
 		}
+		log.Printf("Book %s changed while being written, attempt: %d", id, attempt+1)
+	}
+	return appError.NewConflictError("Book " + id + " changed concurrently, please retry.")
+}
+
+// updateWithEvents applies the update unless the book changed meanwhile and
+// records the change. snapshot builds the book after the update from the
+// stored one, nil when there is none. update is rebuilt for every attempt
+// since the revision is added to it.
+func (r *BookDynamoDBRepository) updateWithEvents(id string, update func() (expression.UpdateBuilder, error), operation model.BookOperation, snapshot func(previous *model.Book) *model.Book, mustExist bool) *appError.Error {
+	return r.trackChange(id, func(previous *model.Book, revision int) (types.TransactWriteItem, bookChange, *appError.Error) {
+		if previous == nil && mustExist {
+			return types.TransactWriteItem{}, bookChange{}, appError.NewNotFoundError("Book " + id + " not found.")
+		}
+		// Only a restore brings a trashed book back; other updates treat it as
+		// gone.
+		if previous != nil && previous.Trashed() && operation != model.BookOperationRestored {
+			return types.TransactWriteItem{}, bookChange{}, appError.NewNotFoundError("Book " + id + " not found.")
+		}
+		change := bookChange{id: id, operation: operation, previous: previous, book: snapshot(previous), revision: revision}
+		if previous == nil && operation == model.BookOperationUpdated {
+			change.operation = model.BookOperationCreated
+		}
+
+		write, err := update()
+		if err != nil {
+			log.Printf("Error marshaling book: %v, ID: %s", err, id)
+			return types.TransactWriteItem{}, bookChange{}, appError.NewUnexpectedError(err.Error())
+		}
+		if revision > 0 {
+			change.book.Revision = revision
+			write = write.Set(expression.Name("revision"), expression.Value(revision))
+		}
+		expr, err := expression.NewBuilder().WithCondition(unchanged(previous)).WithUpdate(write).Build()
+		if err != nil {
+			log.Printf("Error building expression for update: %v, ID: %s", err, id)
+			return types.TransactWriteItem{}, bookChange{}, appError.NewUnexpectedError(err.Error())
+		}
+		return types.TransactWriteItem{
+			Update: &types.Update{
+				TableName:                 aws.String(r.table),
+				Key:                       map[string]types.AttributeValue{"ID": &types.AttributeValueMemberS{Value: id}},
+				UpdateExpression:          expr.Update(),
+				ConditionExpression:       expr.Condition(),
+				ExpressionAttributeNames:  expr.Names(),
+				ExpressionAttributeValues: expr.Values(),
+			},
+		}, change, nil
+	})
+}
+
+func (r *BookDynamoDBRepository) updateBookWithEvents(id string, book *model.Book) (*model.Book, *appError.Error) {
+	update := func() (expression.UpdateBuilder, error) {
+		return bookUpdate(book), nil
+	}
+	err := r.updateWithEvents(id, update, model.BookOperationUpdated, func(previous *model.Book) *model.Book {
+		updated := model.Book{ID: id}
+		if previous != nil {
+			updated = *previous
+		}
+		updated.Name = book.Name
+		updated.Description = book.Description
+		updated.ImgURL = book.ImgURL
+		if book.UpdatedAt != nil {
+			updated.UpdatedAt = book.UpdatedAt
+		}
+		return &updated
+	}, false)
+	if err != nil {
+		return &model.Book{}, err
+	}
+
+	// Same fields as the UPDATED_NEW values returned without an outbox.
+	updatedBook := model.Book{
+		Name:        book.Name,
+		Description: book.Description,
+		ImgURL:      book.ImgURL,
+		UpdatedAt:   book.UpdatedAt,
 	}
+	log.Printf("Updated book successfully, ID: %s, book: %+v", id, updatedBook)
+	return &updatedBook, nil
+}
+
+func (r *BookDynamoDBRepository) updateExistingBookWithEvents(book *model.Book) *appError.Error {
+	update := func() (expression.UpdateBuilder, error) {
+		return catalogUpdate(book)
+	}
+	return r.updateWithEvents(book.ID, update, model.BookOperationUpdated, func(previous *model.Book) *model.Book {
+		updated := *book
+		updated.RecordReference = previous.RecordReference
+		updated.CreatedAt = previous.CreatedAt
+		updated.RatingCount = previous.RatingCount
+		updated.RatingTotal = previous.RatingTotal
+		return &updated
+	}, true)
+}
+
+// restoreUpdate sets every catalog attribute of book along with its creation
+// time and record reference, so a deleted book comes back as it was, out of
+// the trash.
+func restoreUpdate(book *model.Book) (expression.UpdateBuilder, error) {
+	update, err := catalogUpdate(book)
+	if err != nil {
+		return update, err
+	}
+	update = update.Remove(expression.Name("deleted_at")).
+		Remove(expression.Name("expires_at")).
+		Remove(expression.Name(trashAttribute))
+	if book.CreatedAt != nil {
+		update = update.Set(expression.Name("created_at"), expression.Value(book.CreatedAt))
+	}
+	if book.RecordReference != "" {
+		update = update.Set(expression.Name("record_reference"), expression.Value(book.RecordReference))
+	}
+	return update, nil
+}
 
-	log.Printf("Deleted book successfully, book_id: %s, book: %+v", id, deletedBook)
+// RestoreBook replaces the catalog fields of a book with those of book,
+// recreating it when it was deleted. The rating aggregate maintained by
+// reviews is kept.
+func (r *BookDynamoDBRepository) RestoreBook(book *model.Book) *appError.Error {
+	if r.tracked() {
+		update := func() (expression.UpdateBuilder, error) {
+			return restoreUpdate(book)
+		}
+		return r.updateWithEvents(book.ID, update, model.BookOperationRestored, func(previous *model.Book) *model.Book {
+			restored := *book
+			restored.DeletedAt = nil
+			restored.RatingCount, restored.RatingTotal = 0, 0
+			if previous != nil {
+				restored.RatingCount = previous.RatingCount
+				restored.RatingTotal = previous.RatingTotal
+			}
+			return &restored
+		}, false)
+	}
+
+	update, err := restoreUpdate(book)
+	if err != nil {
+		log.Printf("Error marshaling book: %v, book: %+v", err, book)
+		return appError.NewUnexpectedError(err.Error())
+	}
+	expr, err := expression.NewBuilder().WithUpdate(update).Build()
+	if err != nil {
+		log.Printf("Error building expression for restore: %v, ID: %s", err, book.ID)
+		return appError.NewUnexpectedError(err.Error())
+	}
+	_, err = r.client.UpdateItem(r.ctx, &dynamodb.UpdateItemInput{
+		TableName:                 aws.String(r.table),
+		Key:                       map[string]types.AttributeValue{"ID": &types.AttributeValueMemberS{Value: book.ID}},
+		UpdateExpression:          expr.Update(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+	})
+	if err != nil {
+		log.Printf("Error updating item in DynamoDB: %v, table: %s", err, r.table)
+		return appError.NewUnexpectedError(err.Error())
+	}
+	log.Printf("Restored book successfully, ID: %s", book.ID)
 	return nil
 }
+
+// deleteBookWithEvents moves a book to the trash and records the deletion.
+// Deleting a book that does not exist or is already in the trash records
+// nothing.
+func (r *BookDynamoDBRepository) deleteBookWithEvents(id string) *appError.Error {
+	err := r.trackChange(id, func(previous *model.Book, revision int) (types.TransactWriteItem, bookChange, *appError.Error) {
+		if previous == nil || previous.Trashed() {
+			return types.TransactWriteItem{}, bookChange{}, appError.NewNotFoundError("Book " + id + " not found.")
+		}
+		update := r.trashUpdate()
+		if revision > 0 {
+			update = update.Set(expression.Name("revision"), expression.Value(revision))
+		}
+		expr, err := expression.NewBuilder().WithCondition(unchanged(previous)).WithUpdate(update).Build()
+		if err != nil {
+			log.Printf("Error building expression for delete: %v, ID: %s", err, id)
+			return types.TransactWriteItem{}, bookChange{}, appError.NewUnexpectedError(err.Error())
+		}
+		return types.TransactWriteItem{
+			Update: &types.Update{
+				TableName:                 aws.String(r.table),
+				Key:                       map[string]types.AttributeValue{"ID": &types.AttributeValueMemberS{Value: id}},
+				UpdateExpression:          expr.Update(),
+				ConditionExpression:       expr.Condition(),
+				ExpressionAttributeNames:  expr.Names(),
+				ExpressionAttributeValues: expr.Values(),
+			},
+		}, bookChange{id: id, operation: model.BookOperationDeleted, previous: previous, revision: revision}, nil
+	})
+	if err != nil {
+		if err.Code == http.StatusNotFound {
+			log.Println("No book found with ID:", id)
+			return nil
+		}
+		return err
+	}
+
+	log.Printf("Moved book to the trash, book_id: %s", id)
+	return nil
+}
+
+// createBookTracked puts a new book with its events and first revision. The
+// transaction is canceled when the ID is already taken.
+func (r *BookDynamoDBRepository) createBookTracked(book *model.Book) error {
+	revision, err := r.nextRevision(book.ID, nil)
+	if err != nil {
+		return err
+	}
+	book.Revision = revision
+	av, err := marshalBook(book)
+	if err != nil {
+		return err
+	}
+	expr, err := expression.NewBuilder().WithCondition(newBook()).Build()
+	if err != nil {
+		return err
+	}
+	return r.writeChange(types.TransactWriteItem{
+		Put: &types.Put{
+			Item:                     av,
+			TableName:                aws.String(r.table),
+			ConditionExpression:      expr.Condition(),
+			ExpressionAttributeNames: expr.Names(),
+		},
+	}, bookChange{id: book.ID, operation: model.BookOperationCreated, book: book, revision: revision})
+}
+
+// createBooksWithEvents writes each book in its own transaction with its
+// event, since a transaction fails as a whole.
+func (r *BookDynamoDBRepository) createBooksWithEvents(books []model.Book) map[string]*appError.Error {
+	failed := make(map[string]*appError.Error)
+	var mu sync.Mutex
+	forEachConcurrently(len(books), func(i int) {
+		book := &books[i]
+		if err := r.createBookTracked(book); err != nil {
+			reason := bookExists(book.ID)
+			if !conditionFailed(err) {
+				log.Printf("Error creating book with its events: %v, ID: %s", err, book.ID)
+				reason = appError.NewUnexpectedError(err.Error())
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			failed[book.ID] = reason
+		}
+	})
+
+	log.Printf("Batch books creation completed, requested: %d, failed: %d", len(books), len(failed))
+	return failed
+}
+
+func (r *BookDynamoDBRepository) deleteBooksWithEvents(ids []string) map[string]*appError.Error {
+	failed := make(map[string]*appError.Error)
+	var mu sync.Mutex
+	forEachConcurrently(len(ids), func(i int) {
+		if err := r.deleteBookWithEvents(ids[i]); err != nil {
+			mu.Lock()
+			defer mu.Unlock()
+			failed[ids[i]] = err
+		}
+	})
+
+	log.Printf("Batch books deletion completed, requested: %d, failed: %d", len(ids), len(failed))
+	return failed
+}
@@ -39,7 +39,7 @@ func (suite *BookDynamoDBSuite) SetupSuite() {
 		{ID: uuid.NewString(), Name: "Book Two", Description: "A second book", ImgURL: "url2"},
 	}
 
-	suite.NotNil(suite.bookRepository.CreateBatchBooks(suite.initBooks))
+	suite.Empty(suite.bookRepository.CreateBatchBooks(suite.initBooks))
 }
 
 func (suite *BookDynamoDBSuite) TearDownSuite() {
@@ -0,0 +1,649 @@
+package adapter
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"main/src/books/domain/model"
+	appError "main/utils/error"
+)
+
+// DefaultSnapshotEvery is how many changes of a book are appended between
+// two snapshots of it.
+const DefaultSnapshotEvery = 50
+
+// BookEventSourcedRepository derives every book from an append-only stream
+// of changes instead of storing its state. A change is written with the next
+// sequence number of its book on condition that the number is free, so two
+// writers never fork a stream; the loser reloads the book and tries again.
+// A book is folded from its latest snapshot and the changes after it. The
+// first change of a stream is written with a snapshot, so the snapshots
+// table lists every book and listings never read whole streams.
+//
+// The stream is the book history, so no outbox or history table is written.
+// Ratings are kept only as written with a book: reviews, which count them on
+// the books table, are refused with this store. Books
+// stay in the trash for the retention period and are then read as purged,
+// without a change recording it; their files are not removed.
+type BookEventSourcedRepository struct {
+	ctx            context.Context
+	client         *dynamodb.Client
+	eventsTable    string
+	snapshotsTable string
+	snapshotEvery  int
+	trashRetention time.Duration
+	now            func() time.Time
+}
+
+func NewBookEventSourcedRepository(ctx context.Context, client *dynamodb.Client, eventsTable, snapshotsTable string) *BookEventSourcedRepository {
+	return &BookEventSourcedRepository{
+		ctx:            ctx,
+		client:         client,
+		eventsTable:    eventsTable,
+		snapshotsTable: snapshotsTable,
+		snapshotEvery:  DefaultSnapshotEvery,
+		trashRetention: DefaultTrashRetention,
+		now:            func() time.Time { return time.Now().UTC() },
+	}
+}
+
+// WithSnapshotEvery snapshots a book every n changes.
+func (r *BookEventSourcedRepository) WithSnapshotEvery(n int) *BookEventSourcedRepository {
+	if n > 0 {
+		r.snapshotEvery = n
+	}
+	return r
+}
+
+// WithTrashRetention keeps deleted books in the trash for retention.
+func (r *BookEventSourcedRepository) WithTrashRetention(retention time.Duration) *BookEventSourcedRepository {
+	if retention > 0 {
+		r.trashRetention = retention
+	}
+	return r
+}
+
+// current is the book of aggregate as reads see it: nil when it does not
+// exist or stayed in the trash past the retention period.
+func (r *BookEventSourcedRepository) current(aggregate *model.BookAggregate) *model.Book {
+	book := aggregate.Book
+	if book == nil || (book.Trashed() && r.now().Sub(*book.DeletedAt) > r.trashRetention) {
+		return nil
+	}
+	return book
+}
+
+// live is the book of aggregate when it exists out of the trash.
+func (r *BookEventSourcedRepository) live(aggregate *model.BookAggregate) *model.Book {
+	book := r.current(aggregate)
+	if book == nil || book.Trashed() {
+		return nil
+	}
+	return book
+}
+
+// load folds a book from its latest snapshot and the changes after it, with
+// consistent reads.
+func (r *BookEventSourcedRepository) load(id string) (*model.BookAggregate, *appError.Error) {
+	aggregate := &model.BookAggregate{ID: id}
+	snapshot, err := r.client.GetItem(r.ctx, &dynamodb.GetItemInput{
+		TableName:      aws.String(r.snapshotsTable),
+		Key:            map[string]types.AttributeValue{"book_id": &types.AttributeValueMemberS{Value: id}},
+		ConsistentRead: aws.Bool(true),
+	})
+	if err != nil {
+		log.Printf("Error getting snapshot from DynamoDB: %v, table: %s", err, r.snapshotsTable)
+		return nil, appError.NewUnexpectedError(err.Error())
+	}
+	if snapshot.Item != nil {
+		if err := attributevalue.UnmarshalMap(snapshot.Item, aggregate); err != nil {
+			log.Printf("Error unmarshaling snapshot from DynamoDB: %v, ID: %s", err, id)
+			return nil, appError.NewUnexpectedError(err.Error())
+		}
+	}
+	if err := r.catchUp(r.ctx, aggregate); err != nil {
+		return nil, err
+	}
+	return aggregate, nil
+}
+
+// catchUp applies to aggregate the changes of its book after its version.
+func (r *BookEventSourcedRepository) catchUp(ctx context.Context, aggregate *model.BookAggregate) *appError.Error {
+	keyCond := expression.Key("book_id").Equal(expression.Value(aggregate.ID)).
+		And(expression.Key("sequence").GreaterThan(expression.Value(aggregate.Version)))
+	expr, err := expression.NewBuilder().WithKeyCondition(keyCond).Build()
+	if err != nil {
+		log.Printf("Error building expression for changes query: %v, ID: %s", err, aggregate.ID)
+		return appError.NewUnexpectedError(err.Error())
+	}
+	paginator := dynamodb.NewQueryPaginator(r.client, &dynamodb.QueryInput{
+		TableName:                 aws.String(r.eventsTable),
+		KeyConditionExpression:    expr.KeyCondition(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		ConsistentRead:            aws.Bool(true),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			log.Printf("Error querying changes: %v, table: %s, ID: %s", err, r.eventsTable, aggregate.ID)
+			return appError.NewUnexpectedError(err.Error())
+		}
+		var changes []model.BookChange
+		if err := attributevalue.UnmarshalListOfMaps(page.Items, &changes); err != nil {
+			log.Printf("Error unmarshaling changes from DynamoDB: %v, ID: %s", err, aggregate.ID)
+			return appError.NewUnexpectedError(err.Error())
+		}
+		if errApply := aggregate.Apply(changes...); errApply != nil {
+			return errApply
+		}
+	}
+	return nil
+}
+
+// append writes the next change of aggregate and applies it. It fails with
+// a conflict when another writer took the sequence number first.
+func (r *BookEventSourcedRepository) append(aggregate *model.BookAggregate, changeType model.BookChangeType, book *model.Book) *appError.Error {
+	change := model.BookChange{
+		BookID:   aggregate.ID,
+		Sequence: aggregate.Version + 1,
+		Type:     changeType,
+		At:       r.now(),
+		Book:     book,
+	}
+	next := *aggregate
+	if err := next.Apply(change); err != nil {
+		return err
+	}
+
+	av, err := attributevalue.MarshalMap(change)
+	if err != nil {
+		log.Printf("Error marshaling change: %v, ID: %s", err, aggregate.ID)
+		return appError.NewUnexpectedError(err.Error())
+	}
+	expr, err := expression.NewBuilder().WithCondition(expression.AttributeNotExists(expression.Name("sequence"))).Build()
+	if err != nil {
+		log.Printf("Error building expression for change: %v, ID: %s", err, aggregate.ID)
+		return appError.NewUnexpectedError(err.Error())
+	}
+	put := &types.Put{
+		TableName:                aws.String(r.eventsTable),
+		Item:                     av,
+		ConditionExpression:      expr.Condition(),
+		ExpressionAttributeNames: expr.Names(),
+	}
+	if aggregate.Version == 0 {
+		err = r.startStream(put, &next)
+	} else {
+		_, err = r.client.PutItem(r.ctx, &dynamodb.PutItemInput{
+			TableName:                put.TableName,
+			Item:                     put.Item,
+			ConditionExpression:      put.ConditionExpression,
+			ExpressionAttributeNames: put.ExpressionAttributeNames,
+		})
+	}
+	if err != nil {
+		var conditional *types.ConditionalCheckFailedException
+		if errors.As(err, &conditional) || conditionFailed(err) {
+			return appError.NewConflictError("Book " + aggregate.ID + " was changed concurrently.")
+		}
+		log.Printf("Error putting change in DynamoDB: %v, table: %s", err, r.eventsTable)
+		return appError.NewUnexpectedError(err.Error())
+	}
+	*aggregate = next
+
+	if aggregate.Version > 1 && aggregate.Version%r.snapshotEvery == 0 {
+		r.saveSnapshot(aggregate)
+	}
+	return nil
+}
+
+// startStream writes the first change of a book with its first snapshot, in
+// one transaction, so that listings find the book from the snapshots.
+func (r *BookEventSourcedRepository) startStream(put *types.Put, aggregate *model.BookAggregate) error {
+	av, err := attributevalue.MarshalMap(aggregate)
+	if err != nil {
+		return err
+	}
+	expr, err := expression.NewBuilder().WithCondition(expression.AttributeNotExists(expression.Name("book_id"))).Build()
+	if err != nil {
+		return err
+	}
+	_, err = r.client.TransactWriteItems(r.ctx, &dynamodb.TransactWriteItemsInput{
+		TransactItems: []types.TransactWriteItem{
+			{Put: put},
+			{Put: &types.Put{
+				TableName:                aws.String(r.snapshotsTable),
+				Item:                     av,
+				ConditionExpression:      expr.Condition(),
+				ExpressionAttributeNames: expr.Names(),
+			}},
+		},
+	})
+	return err
+}
+
+// saveSnapshot stores the aggregate unless a later snapshot exists. A failed
+// snapshot only makes the next loads read more changes.
+func (r *BookEventSourcedRepository) saveSnapshot(aggregate *model.BookAggregate) {
+	av, err := attributevalue.MarshalMap(aggregate)
+	if err != nil {
+		log.Printf("Error marshaling snapshot: %v, ID: %s", err, aggregate.ID)
+		return
+	}
+	cond := expression.AttributeNotExists(expression.Name("book_id")).
+		Or(expression.Name("sequence").LessThan(expression.Value(aggregate.Version)))
+	expr, err := expression.NewBuilder().WithCondition(cond).Build()
+	if err != nil {
+		log.Printf("Error building expression for snapshot: %v, ID: %s", err, aggregate.ID)
+		return
+	}
+	_, err = r.client.PutItem(r.ctx, &dynamodb.PutItemInput{
+		TableName:                 aws.String(r.snapshotsTable),
+		Item:                      av,
+		ConditionExpression:       expr.Condition(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+	})
+	if err != nil {
+		var conditional *types.ConditionalCheckFailedException
+		if !errors.As(err, &conditional) {
+			log.Printf("Error putting snapshot in DynamoDB: %v, table: %s", err, r.snapshotsTable)
+		}
+		return
+	}
+	log.Printf("Snapshotted book %s at version %d", aggregate.ID, aggregate.Version)
+}
+
+// change loads a book, lets decide pick the change to make of it and appends
+// that change, starting over when another writer appended first. decide gets
+// the current book, nil when there is none, and returns an empty type to
+// leave the book as it is.
+func (r *BookEventSourcedRepository) change(id string, decide func(current *model.Book) (model.BookChangeType, *model.Book, *appError.Error)) (*model.BookAggregate, *appError.Error) {
+	for attempt := 0; attempt < maxOutboxAttempts; attempt++ {
+		aggregate, err := r.load(id)
+		if err != nil {
+			return nil, err
+		}
+		changeType, book, err := decide(r.current(aggregate))
+		if err != nil {
+			return nil, err
+		}
+		if changeType == "" {
+			return aggregate, nil
+		}
+		err = r.append(aggregate, changeType, book)
+		if err == nil {
+			return aggregate, nil
+		}
+		if err.Code != http.StatusConflict {
+			return nil, err
+		}
+		log.Printf("Book %s changed while being written, attempt: %d", id, attempt+1)
+	}
+	return nil, appError.NewConflictError("Book " + id + " changed concurrently, please retry.")
+}
+
+// replay folds every book with one paginated scan of the snapshots per
+// segment running in parallel, each book from its snapshot and the changes
+// after it, and hands the aggregates of each page to visit.
+func (r *BookEventSourcedRepository) replay(segments int, visit func([]model.BookAggregate) *appError.Error) *appError.Error {
+	if segments < 1 {
+		segments = 1
+	}
+	ctx, cancel := context.WithCancel(r.ctx)
+	defer cancel()
+
+	var once sync.Once
+	var replayErr *appError.Error
+	fail := func(err *appError.Error) {
+		once.Do(func() {
+			replayErr = err
+			cancel()
+		})
+	}
+
+	var wg sync.WaitGroup
+	for segment := 0; segment < segments; segment++ {
+		wg.Add(1)
+		go func(segment int) {
+			defer wg.Done()
+			paginator := dynamodb.NewScanPaginator(r.client, &dynamodb.ScanInput{
+				TableName:      aws.String(r.snapshotsTable),
+				Segment:        aws.Int32(int32(segment)),
+				TotalSegments:  aws.Int32(int32(segments)),
+				ConsistentRead: aws.Bool(true),
+			})
+			for paginator.HasMorePages() && ctx.Err() == nil {
+				page, err := paginator.NextPage(ctx)
+				if err != nil {
+					log.Printf("Error scanning snapshots: %v, table: %s, segment: %d", err, r.snapshotsTable, segment)
+					fail(appError.NewUnexpectedError(err.Error()))
+					return
+				}
+				var aggregates []model.BookAggregate
+				if err := attributevalue.UnmarshalListOfMaps(page.Items, &aggregates); err != nil {
+					log.Printf("Error unmarshaling snapshots from DynamoDB: %v", err)
+					fail(appError.NewUnexpectedError(err.Error()))
+					return
+				}
+				forEachConcurrently(len(aggregates), func(i int) {
+					if err := r.catchUp(ctx, &aggregates[i]); err != nil {
+						fail(err)
+					}
+				})
+				if ctx.Err() != nil {
+					break
+				}
+				if err := visit(aggregates); err != nil {
+					fail(err)
+					return
+				}
+			}
+			// A context done before the last page leaves the replay partial.
+			if err := ctx.Err(); err != nil {
+				fail(appError.NewUnexpectedError(err.Error()))
+			}
+		}(segment)
+	}
+	wg.Wait()
+
+	if replayErr == nil {
+		log.Printf("Replayed table %s with %d segments", r.snapshotsTable, segments)
+	}
+	return replayErr
+}
+
+// collect replays the stream in one segment and returns the books pick
+// keeps.
+func (r *BookEventSourcedRepository) collect(pick func(*model.BookAggregate) *model.Book) ([]model.Book, *appError.Error) {
+	var books []model.Book
+	err := r.replay(1, func(aggregates []model.BookAggregate) *appError.Error {
+		for i := range aggregates {
+			if book := pick(&aggregates[i]); book != nil {
+				books = append(books, *book)
+			}
+		}
+		return nil
+	})
+	return books, err
+}
+
+func (r *BookEventSourcedRepository) GetAllBooks() ([]model.Book, *appError.Error) {
+	books, err := r.collect(r.live)
+	if err != nil {
+		return nil, err
+	}
+	log.Println("Retrieved all books successfully")
+	return books, nil
+}
+
+// ScanBooks folds every book in parallel segments and hands the books out
+// of the trash to visit, a page of snapshots at a time. visit may be called
+// concurrently.
+func (r *BookEventSourcedRepository) ScanBooks(segments int, visit func([]model.Book) *appError.Error) *appError.Error {
+	return r.replay(segments, func(aggregates []model.BookAggregate) *appError.Error {
+		books := make([]model.Book, 0, len(aggregates))
+		for i := range aggregates {
+			if book := r.live(&aggregates[i]); book != nil {
+				books = append(books, *book)
+			}
+		}
+		return visit(books)
+	})
+}
+
+func (r *BookEventSourcedRepository) CreateBook(book *model.Book) (*model.Book, *appError.Error) {
+	aggregate, err := r.change(book.ID, func(current *model.Book) (model.BookChangeType, *model.Book, *appError.Error) {
+		if current != nil {
+			return "", nil, bookExists(book.ID)
+		}
+		return model.BookChangeCreated, book, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	book.Revision = aggregate.Version
+	log.Printf("Created book successfully, ID: %s, version: %d", book.ID, aggregate.Version)
+	return book, nil
+}
+
+func (r *BookEventSourcedRepository) CreateBatchBooks(books []model.Book) map[string]*appError.Error {
+	failed := make(map[string]*appError.Error)
+	var mu sync.Mutex
+	forEachConcurrently(len(books), func(i int) {
+		if _, err := r.CreateBook(&books[i]); err != nil {
+			mu.Lock()
+			defer mu.Unlock()
+			failed[books[i].ID] = err
+		}
+	})
+
+	log.Printf("Batch books creation completed, requested: %d, failed: %d", len(books), len(failed))
+	return failed
+}
+
+func (r *BookEventSourcedRepository) GetBookByID(id string) (*model.Book, *appError.Error) {
+	aggregate, err := r.load(id)
+	if err != nil {
+		return &model.Book{}, err
+	}
+	book := r.live(aggregate)
+	if book == nil {
+		log.Println("No book found with ID:", id)
+		return &model.Book{}, nil
+	}
+	log.Printf("Retrieved book successfully, ID: %s, version: %d", id, aggregate.Version)
+	return book, nil
+}
+
+// GetBooksByIDs folds the requested books concurrently.
+func (r *BookEventSourcedRepository) GetBooksByIDs(ids []string) (*model.BooksByIDs, *appError.Error) {
+	var uniqueIDs []string
+	seen := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			uniqueIDs = append(uniqueIDs, id)
+		}
+	}
+
+	books := make([]*model.Book, len(uniqueIDs))
+	var once sync.Once
+	var loadErr *appError.Error
+	forEachConcurrently(len(uniqueIDs), func(i int) {
+		aggregate, err := r.load(uniqueIDs[i])
+		if err != nil {
+			once.Do(func() { loadErr = err })
+			return
+		}
+		books[i] = r.live(aggregate)
+	})
+	if loadErr != nil {
+		return nil, loadErr
+	}
+
+	result := &model.BooksByIDs{
+		Books:      make([]model.Book, 0, len(uniqueIDs)),
+		MissingIDs: []string{},
+	}
+	for i, id := range uniqueIDs {
+		if books[i] != nil {
+			result.Books = append(result.Books, *books[i])
+		} else {
+			result.MissingIDs = append(result.MissingIDs, id)
+		}
+	}
+	log.Printf("Retrieved %d of %d requested books, missing: %v", len(result.Books), len(uniqueIDs), result.MissingIDs)
+	return result, nil
+}
+
+// UpdateBookByID sets the name, description, image and update time of a
+// book, creating it when it does not exist.
+func (r *BookEventSourcedRepository) UpdateBookByID(id string, book *model.Book) (*model.Book, *appError.Error) {
+	edit := &model.Book{
+		Name:        book.Name,
+		Description: book.Description,
+		ImgURL:      book.ImgURL,
+		UpdatedAt:   book.UpdatedAt,
+	}
+	_, err := r.change(id, func(current *model.Book) (model.BookChangeType, *model.Book, *appError.Error) {
+		switch {
+		case current == nil:
+			// Whatever was there before is gone, so the book starts anew.
+			return model.BookChangeCreated, edit, nil
+		case current.Trashed():
+			return "", nil, appError.NewNotFoundError("Book " + id + " not found.")
+		}
+		return model.BookChangeEdited, edit, nil
+	})
+	if err != nil {
+		return &model.Book{}, err
+	}
+	log.Printf("Updated book successfully, ID: %s, book: %+v", id, edit)
+	return edit, nil
+}
+
+// DeleteBookByID moves a book to the trash. Deleting a book that does not
+// exist or is already in the trash does nothing.
+func (r *BookEventSourcedRepository) DeleteBookByID(id string) *appError.Error {
+	_, err := r.change(id, func(current *model.Book) (model.BookChangeType, *model.Book, *appError.Error) {
+		if current == nil || current.Trashed() {
+			log.Println("No book found with ID:", id)
+			return "", nil, nil
+		}
+		return model.BookChangeTrashed, nil, nil
+	})
+	if err != nil {
+		return err
+	}
+	log.Printf("Moved book to the trash, book_id: %s", id)
+	return nil
+}
+
+// UpdateBooks replaces the catalog fields of each book, reporting the books
+// that do not exist or are in the trash as not found.
+func (r *BookEventSourcedRepository) UpdateBooks(books []model.Book) map[string]*appError.Error {
+	failed := make(map[string]*appError.Error)
+	var mu sync.Mutex
+	forEachConcurrently(len(books), func(i int) {
+		book := &books[i]
+		_, err := r.change(book.ID, func(current *model.Book) (model.BookChangeType, *model.Book, *appError.Error) {
+			if current == nil || current.Trashed() {
+				return "", nil, appError.NewNotFoundError("Book " + book.ID + " not found.")
+			}
+			return model.BookChangeReplaced, book, nil
+		})
+		if err != nil {
+			mu.Lock()
+			defer mu.Unlock()
+			failed[book.ID] = err
+		}
+	})
+
+	log.Printf("Batch books update completed, requested: %d, failed: %d", len(books), len(failed))
+	return failed
+}
+
+func (r *BookEventSourcedRepository) DeleteBooksByIDs(ids []string) map[string]*appError.Error {
+	failed := make(map[string]*appError.Error)
+	var mu sync.Mutex
+	forEachConcurrently(len(ids), func(i int) {
+		if err := r.DeleteBookByID(ids[i]); err != nil {
+			mu.Lock()
+			defer mu.Unlock()
+			failed[ids[i]] = err
+		}
+	})
+
+	log.Printf("Batch books deletion completed, requested: %d, failed: %d", len(ids), len(failed))
+	return failed
+}
+
+// GetRecentBooks returns up to limit books, most recently updated first.
+// Books without an update time are left out, as in the recent index.
+func (r *BookEventSourcedRepository) GetRecentBooks(limit int) ([]model.Book, *appError.Error) {
+	books, err := r.collect(func(aggregate *model.BookAggregate) *model.Book {
+		if book := r.live(aggregate); book != nil && book.UpdatedAt != nil {
+			return book
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(books, func(i, j int) bool { return books[i].UpdatedAt.After(*books[j].UpdatedAt) })
+	if len(books) > limit {
+		books = books[:limit]
+	}
+	log.Printf("Retrieved %d recent books", len(books))
+	return append([]model.Book{}, books...), nil
+}
+
+// RestoreBook brings a book back to book, out of the trash, recreating it
+// when it was purged. The rating is kept.
+func (r *BookEventSourcedRepository) RestoreBook(book *model.Book) *appError.Error {
+	_, err := r.change(book.ID, func(*model.Book) (model.BookChangeType, *model.Book, *appError.Error) {
+		return model.BookChangeReverted, book, nil
+	})
+	if err != nil {
+		return err
+	}
+	log.Printf("Restored book successfully, ID: %s", book.ID)
+	return nil
+}
+
+// GetTrashedBooks returns up to limit books of the trash, most recently
+// deleted first.
+func (r *BookEventSourcedRepository) GetTrashedBooks(limit int) ([]model.Book, *appError.Error) {
+	books, err := r.collect(func(aggregate *model.BookAggregate) *model.Book {
+		if book := r.current(aggregate); book != nil && book.Trashed() {
+			return book
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(books, func(i, j int) bool { return books[i].DeletedAt.After(*books[j].DeletedAt) })
+	if len(books) > limit {
+		books = books[:limit]
+	}
+	log.Printf("Retrieved %d trashed books", len(books))
+	return append([]model.Book{}, books...), nil
+}
+
+// RestoreTrashedBook takes a book out of the trash as it was deleted.
+func (r *BookEventSourcedRepository) RestoreTrashedBook(id string) *appError.Error {
+	return r.changeTrashed(id, model.BookChangeUntrashed, "Restored book from the trash")
+}
+
+// PurgeBook ends the stream of a book of the trash. Its files are not
+// removed.
+func (r *BookEventSourcedRepository) PurgeBook(id string) *appError.Error {
+	return r.changeTrashed(id, model.BookChangePurged, "Purged book from the trash")
+}
+
+func (r *BookEventSourcedRepository) changeTrashed(id string, changeType model.BookChangeType, done string) *appError.Error {
+	_, err := r.change(id, func(current *model.Book) (model.BookChangeType, *model.Book, *appError.Error) {
+		if current == nil || !current.Trashed() {
+			return "", nil, appError.NewNotFoundError("Book " + id + " not found in the trash.")
+		}
+		return changeType, nil, nil
+	})
+	if err != nil {
+		return err
+	}
+	log.Printf("%s, ID: %s", done, id)
+	return nil
+}
@@ -0,0 +1,104 @@
+package adapter_test
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"main/src/books/domain/model"
+	"main/src/books/domain/repository"
+	"main/src/books/infrastructure/adapter"
+	"main/src/books/infrastructure/configuration"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+)
+
+var _ repository.BookRepository = (*adapter.BookEventSourcedRepository)(nil)
+
+type BookEventSourcedSuite struct {
+	suite.Suite
+	ctx            context.Context
+	eventsTable    string
+	snapshotsTable string
+	dynamoClient   *dynamodb.Client
+	bookRepository repository.BookRepository
+}
+
+func (suite *BookEventSourcedSuite) SetupSuite() {
+	suite.ctx = context.TODO()
+	client, err := configuration.GetLocalDynamoDBClient(suite.ctx)
+	suite.Require().NoError(err)
+	suite.dynamoClient = client
+
+	suite.eventsTable = "Test_Book_Events_Table"
+	suite.snapshotsTable = "Test_Book_Snapshots_Table"
+	suite.Require().NoError(configuration.CreateLocalDynamoDBBookEventsTable(suite.ctx, client, suite.eventsTable))
+	suite.Require().NoError(configuration.CreateLocalDynamoDBBookSnapshotsTable(suite.ctx, client, suite.snapshotsTable))
+
+	suite.bookRepository = adapter.NewBookEventSourcedRepository(suite.ctx, client, suite.eventsTable, suite.snapshotsTable).
+		WithSnapshotEvery(3)
+}
+
+func (suite *BookEventSourcedSuite) TearDownSuite() {
+	configuration.DeleteLocalDynamoDBBookTable(suite.ctx, suite.dynamoClient, suite.eventsTable)
+	configuration.DeleteLocalDynamoDBBookTable(suite.ctx, suite.dynamoClient, suite.snapshotsTable)
+}
+
+func (suite *BookEventSourcedSuite) TestSnapshotsEveryNChanges() {
+	book := model.Book{ID: uuid.NewString(), Name: "Version 1"}
+	_, err := suite.bookRepository.CreateBook(&book)
+	suite.Nil(err)
+	for _, name := range []string{"Version 2", "Version 3", "Version 4"} {
+		_, err := suite.bookRepository.UpdateBookByID(book.ID, &model.Book{Name: name})
+		suite.Nil(err)
+	}
+
+	snapshot, errGet := suite.dynamoClient.GetItem(suite.ctx, &dynamodb.GetItemInput{
+		TableName:      aws.String(suite.snapshotsTable),
+		Key:            map[string]types.AttributeValue{"book_id": &types.AttributeValueMemberS{Value: book.ID}},
+		ConsistentRead: aws.Bool(true),
+	})
+	suite.NoError(errGet)
+	suite.Equal(&types.AttributeValueMemberN{Value: "3"}, snapshot.Item["sequence"])
+
+	stored, _ := suite.bookRepository.GetBookByID(book.ID)
+	suite.Equal("Version 4", stored.Name)
+	suite.Equal(4, stored.Revision)
+}
+
+func (suite *BookEventSourcedSuite) TestConcurrentWritersDoNotForkTheStream() {
+	book := model.Book{ID: uuid.NewString(), Name: "Contended"}
+	_, err := suite.bookRepository.CreateBook(&book)
+	suite.Nil(err)
+
+	var wg sync.WaitGroup
+	conflicts := 0
+	var mu sync.Mutex
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			at := time.Now().UTC()
+			if _, err := suite.bookRepository.UpdateBookByID(book.ID, &model.Book{Name: "Edited", UpdatedAt: &at}); err != nil {
+				mu.Lock()
+				defer mu.Unlock()
+				suite.Equal(http.StatusConflict, err.Code)
+				conflicts++
+			}
+		}()
+	}
+	wg.Wait()
+
+	stored, _ := suite.bookRepository.GetBookByID(book.ID)
+	suite.Equal(1+5-conflicts, stored.Revision)
+}
+
+func TestBookEventSourcedSuite(t *testing.T) {
+	suite.Run(t, new(BookEventSourcedSuite))
+}
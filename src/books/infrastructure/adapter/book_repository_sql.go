@@ -0,0 +1,777 @@
+package adapter
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"main/src/books/domain/model"
+	appError "main/utils/error"
+)
+
+// bookMigrations are applied in file name order by Migrate.
+//
+//go:embed migrations/*.sql
+var bookMigrations embed.FS
+
+const (
+	// sqlScanPageSize is the number of books ScanBooks reads per query.
+	sqlScanPageSize = 500
+	// maxSQLBatchIDs bounds the IN list of a GetBooksByIDs query.
+	maxSQLBatchIDs = 500
+	// sqlTimeLayout writes UTC timestamps with a fixed width, so the text
+	// columns sort as the times they hold.
+	sqlTimeLayout = "2006-01-02T15:04:05.000000000Z07:00"
+)
+
+// MaxListLimit is the largest page ListBooks returns.
+const MaxListLimit = 1000
+
+// bookColumnList are the columns a book is read from and written to. The
+// expiry of the trash is only written by the trash operations.
+var bookColumnList = []string{
+	"id", "name", "description", "img_url", "isbn", "subtitle", "publisher", "published_on",
+	"availability", "record_reference", "contributors", "subjects", "prices",
+	"rating_count", "rating_total", "revision", "created_at", "updated_at", "deleted_at",
+}
+
+// sqlCatalogColumns are the columns a batch update replaces, as
+// catalogAttributes are for DynamoDB.
+var sqlCatalogColumns = []string{
+	"name", "description", "img_url", "isbn", "subtitle", "publisher", "published_on",
+	"contributors", "subjects", "prices", "availability", "updated_at",
+}
+
+var (
+	bookColumns = strings.Join(bookColumnList, ", ")
+
+	// insertBookSQL writes a new book and leaves alone whatever already has
+	// its ID, trashed books included.
+	insertBookSQL = "INSERT INTO books (" + bookColumns + ") VALUES (" + sqlPlaceholders(1, len(bookColumnList)) + ")" +
+		" ON CONFLICT (id) DO NOTHING"
+
+	updateCatalogSQL = "UPDATE books SET " + sqlAssignments(sqlCatalogColumns) +
+		" WHERE id = $" + strconv.Itoa(len(sqlCatalogColumns)+1) + " AND deleted_at IS NULL"
+
+	// restoreBookSQL replaces the catalog columns of a book, recreating it
+	// when it was deleted, and keeps its rating aggregate.
+	restoreBookSQL = "INSERT INTO books (id, " + strings.Join(sqlCatalogColumns, ", ") + ", created_at, record_reference)" +
+		" VALUES (" + sqlPlaceholders(1, len(sqlCatalogColumns)+3) + ")" +
+		" ON CONFLICT (id) DO UPDATE SET " + sqlExcluded(sqlCatalogColumns) +
+		", created_at = COALESCE(excluded.created_at, books.created_at)" +
+		", record_reference = CASE WHEN excluded.record_reference = '' THEN books.record_reference ELSE excluded.record_reference END" +
+		", deleted_at = NULL, expires_at = NULL"
+)
+
+// BookSQLRepository keeps the books in a SQL database through database/sql.
+// The statements only use what PostgreSQL and SQLite have in common, with
+// $N placeholders. Changes record neither events nor history, and the trash
+// is purged by PurgeExpiredBooks rather than a TTL.
+type BookSQLRepository struct {
+	ctx context.Context
+	db  *sql.DB
+	// trashRetention is how long deleted books stay in the trash,
+	// DefaultTrashRetention when zero.
+	trashRetention time.Duration
+}
+
+func NewBookSQLRepository(ctx context.Context, db *sql.DB) *BookSQLRepository {
+	return &BookSQLRepository{
+		ctx: ctx,
+		db:  db,
+	}
+}
+
+// WithTrashRetention keeps deleted books in the trash for retention before
+// they expire.
+func (r *BookSQLRepository) WithTrashRetention(retention time.Duration) *BookSQLRepository {
+	r.trashRetention = retention
+	return r
+}
+
+// Migrate applies the embedded migrations the database has not recorded in
+// schema_migrations yet, each in its own transaction. A migration another
+// instance applied meanwhile is skipped.
+func (r *BookSQLRepository) Migrate() *appError.Error {
+	_, err := r.db.ExecContext(r.ctx, "CREATE TABLE IF NOT EXISTS schema_migrations (version TEXT PRIMARY KEY, applied_at TEXT NOT NULL)")
+	if err != nil {
+		log.Printf("Error creating the migrations table: %v", err)
+		return appError.NewUnexpectedError(err.Error())
+	}
+	applied, err := r.appliedMigrations()
+	if err != nil {
+		log.Printf("Error reading the applied migrations: %v", err)
+		return appError.NewUnexpectedError(err.Error())
+	}
+	names, err := fs.Glob(bookMigrations, "migrations/*.sql")
+	if err != nil {
+		return appError.NewUnexpectedError(err.Error())
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		version := strings.TrimSuffix(path.Base(name), ".sql")
+		if applied[version] {
+			continue
+		}
+		if err := r.applyMigration(version, name); err != nil {
+			if applied, errApplied := r.appliedMigrations(); errApplied == nil && applied[version] {
+				continue
+			}
+			log.Printf("Error applying migration %s: %v", version, err)
+			return appError.NewUnexpectedError(err.Error())
+		}
+		log.Printf("Applied migration %s", version)
+	}
+	return nil
+}
+
+func (r *BookSQLRepository) appliedMigrations() (map[string]bool, error) {
+	rows, err := r.db.QueryContext(r.ctx, "SELECT version FROM schema_migrations")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	applied := make(map[string]bool)
+	for rows.Next() {
+		var version string
+		if err := rows.Scan(&version); err != nil {
+			return nil, err
+		}
+		applied[version] = true
+	}
+	return applied, rows.Err()
+}
+
+func (r *BookSQLRepository) applyMigration(version, name string) error {
+	content, err := bookMigrations.ReadFile(name)
+	if err != nil {
+		return err
+	}
+	tx, err := r.db.BeginTx(r.ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if _, err := tx.ExecContext(r.ctx, string(content)); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(r.ctx, "INSERT INTO schema_migrations (version, applied_at) VALUES ($1, $2)", version, sqlTime(time.Now())); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *BookSQLRepository) GetAllBooks() ([]model.Book, *appError.Error) {
+	books, err := r.queryBooks("SELECT " + bookColumns + " FROM books WHERE deleted_at IS NULL ORDER BY id")
+	if err != nil {
+		log.Printf("Error querying books: %v", err)
+		return nil, appError.NewUnexpectedError(err.Error())
+	}
+	log.Println("Retrieved all books successfully")
+	return books, nil
+}
+
+// ScanBooks reads the books in pages ordered by ID, handing each page to
+// visit in turn. The database orders the whole table, so segments, which
+// parallelizes DynamoDB scans, is ignored. Books in the trash are left out.
+func (r *BookSQLRepository) ScanBooks(segments int, visit func([]model.Book) *appError.Error) *appError.Error {
+	after := ""
+	for {
+		books, err := r.queryBooks("SELECT "+bookColumns+" FROM books WHERE deleted_at IS NULL AND id > $1 ORDER BY id LIMIT $2", after, sqlScanPageSize)
+		if err != nil {
+			log.Printf("Error scanning books after %q: %v", after, err)
+			return appError.NewUnexpectedError(err.Error())
+		}
+		if len(books) == 0 {
+			return nil
+		}
+		if err := visit(books); err != nil {
+			return err
+		}
+		if len(books) < sqlScanPageSize {
+			return nil
+		}
+		after = books[len(books)-1].ID
+	}
+}
+
+func (r *BookSQLRepository) CreateBook(book *model.Book) (*model.Book, *appError.Error) {
+	values, err := bookValues(book)
+	if err != nil {
+		log.Printf("Error marshaling book: %v, book: %+v", err, book)
+		return &model.Book{}, appError.NewUnexpectedError(err.Error())
+	}
+	result, err := r.db.ExecContext(r.ctx, insertBookSQL, values...)
+	if err != nil {
+		log.Printf("Error inserting book: %v, ID: %s", err, book.ID)
+		return &model.Book{}, appError.NewUnexpectedError(err.Error())
+	}
+	if inserted, err := result.RowsAffected(); err == nil && inserted == 0 {
+		return &model.Book{}, bookExists(book.ID)
+	}
+
+	log.Printf("Book creation completed successfully, book: %+v", book)
+	return book, nil
+}
+
+// CreateBatchBooks writes the books in a single transaction: either every
+// book that could be marshaled and whose ID is free is written, or all of
+// them are returned with the reason the transaction failed.
+func (r *BookSQLRepository) CreateBatchBooks(books []model.Book) map[string]*appError.Error {
+	failed := make(map[string]*appError.Error)
+	failAll := func(err error) map[string]*appError.Error {
+		for _, book := range books {
+			if failed[book.ID] == nil {
+				failed[book.ID] = appError.NewUnexpectedError(err.Error())
+			}
+		}
+		return failed
+	}
+
+	tx, err := r.db.BeginTx(r.ctx, nil)
+	if err != nil {
+		log.Printf("Error beginning batch transaction: %v", err)
+		return failAll(err)
+	}
+	defer tx.Rollback()
+	stmt, err := tx.PrepareContext(r.ctx, insertBookSQL)
+	if err != nil {
+		log.Printf("Error preparing batch insert: %v", err)
+		return failAll(err)
+	}
+	defer stmt.Close()
+
+	for i := range books {
+		values, err := bookValues(&books[i])
+		if err != nil {
+			log.Printf("Error while marshalling book: %s, book: %+v", err, books[i])
+			failed[books[i].ID] = appError.NewUnexpectedError(err.Error())
+			continue
+		}
+		result, err := stmt.ExecContext(r.ctx, values...)
+		if err != nil {
+			log.Printf("Error inserting book: %v, ID: %s", err, books[i].ID)
+			return failAll(err)
+		}
+		if inserted, err := result.RowsAffected(); err == nil && inserted == 0 {
+			failed[books[i].ID] = bookExists(books[i].ID)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		log.Printf("Error committing batch transaction: %v", err)
+		return failAll(err)
+	}
+
+	log.Printf("Batch books creation completed, requested: %d, failed: %d", len(books), len(failed))
+	return failed
+}
+
+func (r *BookSQLRepository) GetBookByID(id string) (*model.Book, *appError.Error) {
+	row := r.db.QueryRowContext(r.ctx, "SELECT "+bookColumns+" FROM books WHERE id = $1", id)
+	book, err := scanBook(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		log.Println("No book found with ID:", id)
+		return &model.Book{}, nil
+	}
+	if err != nil {
+		log.Printf("Error getting book: %v, ID: %s", err, id)
+		return &model.Book{}, appError.NewUnexpectedError(err.Error())
+	}
+	if book.Trashed() {
+		log.Println("Book is in the trash, ID:", id)
+		return &model.Book{}, nil
+	}
+
+	log.Printf("Retrieved book successfully, ID: %s, book: %+v", id, book)
+	return &book, nil
+}
+
+// GetBooksByIDs reads the books with IN lists of up to 500 IDs.
+func (r *BookSQLRepository) GetBooksByIDs(ids []string) (*model.BooksByIDs, *appError.Error) {
+	var uniqueIDs []string
+	seen := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			uniqueIDs = append(uniqueIDs, id)
+		}
+	}
+
+	found := make(map[string]model.Book, len(uniqueIDs))
+	for i := 0; i < len(uniqueIDs); i += maxSQLBatchIDs {
+		end := i + maxSQLBatchIDs
+		if end > len(uniqueIDs) {
+			end = len(uniqueIDs)
+		}
+		args := make([]interface{}, 0, end-i)
+		for _, id := range uniqueIDs[i:end] {
+			args = append(args, id)
+		}
+		books, err := r.queryBooks("SELECT "+bookColumns+" FROM books WHERE deleted_at IS NULL AND id IN ("+sqlPlaceholders(1, len(args))+")", args...)
+		if err != nil {
+			log.Printf("Error querying books by IDs: %v, batch size: %d", err, len(args))
+			return nil, appError.NewUnexpectedError(err.Error())
+		}
+		for _, book := range books {
+			found[book.ID] = book
+		}
+	}
+
+	result := &model.BooksByIDs{
+		Books:      make([]model.Book, 0, len(found)),
+		MissingIDs: []string{},
+	}
+	for _, id := range uniqueIDs {
+		if book, ok := found[id]; ok {
+			result.Books = append(result.Books, book)
+		} else {
+			result.MissingIDs = append(result.MissingIDs, id)
+		}
+	}
+	log.Printf("Retrieved %d of %d requested books, missing: %v", len(result.Books), len(uniqueIDs), result.MissingIDs)
+	return result, nil
+}
+
+// UpdateBookByID sets the name, description and image of a book, creating
+// it when there is none, as the DynamoDB repository does. A book in the
+// trash is not found.
+func (r *BookSQLRepository) UpdateBookByID(id string, book *model.Book) (*model.Book, *appError.Error) {
+	row := r.db.QueryRowContext(r.ctx,
+		"INSERT INTO books (id, name, description, img_url, updated_at) VALUES ($1, $2, $3, $4, $5)"+
+			" ON CONFLICT (id) DO UPDATE SET name = excluded.name, description = excluded.description, img_url = excluded.img_url,"+
+			" updated_at = COALESCE(excluded.updated_at, books.updated_at)"+
+			" WHERE books.deleted_at IS NULL RETURNING "+bookColumns,
+		id, book.Name, book.Description, book.ImgURL, sqlTimeValue(book.UpdatedAt))
+	updatedBook, err := scanBook(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return &model.Book{}, appError.NewNotFoundError("Book " + id + " not found.")
+	}
+	if err != nil {
+		log.Printf("Error updating book: %v, ID: %s", err, id)
+		return &model.Book{}, appError.NewUnexpectedError(err.Error())
+	}
+
+	log.Printf("Updated book successfully, ID: %s, book: %+v", id, updatedBook)
+	return &updatedBook, nil
+}
+
+// DeleteBookByID moves a book to the trash until it is restored or expires.
+// Deleting a book that does not exist or is already in the trash does
+// nothing.
+func (r *BookSQLRepository) DeleteBookByID(id string) *appError.Error {
+	retention := r.trashRetention
+	if retention <= 0 {
+		retention = DefaultTrashRetention
+	}
+	now := time.Now()
+	result, err := r.db.ExecContext(r.ctx, "UPDATE books SET deleted_at = $1, expires_at = $2 WHERE id = $3 AND deleted_at IS NULL",
+		sqlTime(now), sqlTime(now.Add(retention)), id)
+	if err != nil {
+		log.Printf("Error deleting book: %v, ID: %s", err, id)
+		return appError.NewUnexpectedError(err.Error())
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		log.Println("No book found with ID:", id)
+		return nil
+	}
+
+	log.Printf("Moved book to the trash, book_id: %s", id)
+	return nil
+}
+
+// DeleteBooksByIDs moves each book to the trash and returns the IDs that
+// could not be deleted with the reason.
+func (r *BookSQLRepository) DeleteBooksByIDs(ids []string) map[string]*appError.Error {
+	failed := make(map[string]*appError.Error)
+	for _, id := range ids {
+		if err := r.DeleteBookByID(id); err != nil {
+			failed[id] = err
+		}
+	}
+
+	log.Printf("Batch books deletion completed, requested: %d, failed: %d", len(ids), len(failed))
+	return failed
+}
+
+// UpdateBooks replaces the catalog columns of each book. A book that does
+// not exist, or sits in the trash, is reported as not found instead of being
+// recreated.
+func (r *BookSQLRepository) UpdateBooks(books []model.Book) map[string]*appError.Error {
+	failed := make(map[string]*appError.Error)
+	for i := range books {
+		if err := r.updateExistingBook(&books[i]); err != nil {
+			failed[books[i].ID] = err
+		}
+	}
+
+	log.Printf("Batch books update completed, requested: %d, failed: %d", len(books), len(failed))
+	return failed
+}
+
+func (r *BookSQLRepository) updateExistingBook(book *model.Book) *appError.Error {
+	values, err := catalogValues(book)
+	if err != nil {
+		log.Printf("Error marshaling book: %v, book: %+v", err, book)
+		return appError.NewUnexpectedError(err.Error())
+	}
+	result, err := r.db.ExecContext(r.ctx, updateCatalogSQL, append(values, book.ID)...)
+	if err != nil {
+		log.Printf("Error updating book: %v, ID: %s", err, book.ID)
+		return appError.NewUnexpectedError(err.Error())
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return appError.NewNotFoundError("Book " + book.ID + " not found.")
+	}
+	return nil
+}
+
+// GetRecentBooks returns up to limit books, most recently updated first.
+func (r *BookSQLRepository) GetRecentBooks(limit int) ([]model.Book, *appError.Error) {
+	books, err := r.queryBooks("SELECT "+bookColumns+" FROM books WHERE deleted_at IS NULL AND updated_at IS NOT NULL ORDER BY updated_at DESC LIMIT $1", limit)
+	if err != nil {
+		log.Printf("Error querying recent books: %v", err)
+		return nil, appError.NewUnexpectedError(err.Error())
+	}
+	log.Printf("Retrieved %d recent books", len(books))
+	return books, nil
+}
+
+// GetTrashedBooks returns up to limit books of the trash that have not
+// expired, most recently deleted first.
+func (r *BookSQLRepository) GetTrashedBooks(limit int) ([]model.Book, *appError.Error) {
+	books, err := r.queryBooks("SELECT "+bookColumns+" FROM books WHERE deleted_at IS NOT NULL AND (expires_at IS NULL OR expires_at > $1)"+
+		" ORDER BY deleted_at DESC LIMIT $2", sqlTime(time.Now()), limit)
+	if err != nil {
+		log.Printf("Error querying trashed books: %v", err)
+		return nil, appError.NewUnexpectedError(err.Error())
+	}
+	log.Printf("Retrieved %d trashed books", len(books))
+	return books, nil
+}
+
+// RestoreTrashedBook takes a book out of the trash as it was deleted, unless
+// it expired.
+func (r *BookSQLRepository) RestoreTrashedBook(id string) *appError.Error {
+	result, err := r.db.ExecContext(r.ctx, "UPDATE books SET deleted_at = NULL, expires_at = NULL"+
+		" WHERE id = $1 AND deleted_at IS NOT NULL AND (expires_at IS NULL OR expires_at > $2)", id, sqlTime(time.Now()))
+	if err != nil {
+		log.Printf("Error restoring book: %v, ID: %s", err, id)
+		return appError.NewUnexpectedError(err.Error())
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return appError.NewNotFoundError("Book " + id + " not found in the trash.")
+	}
+	log.Printf("Restored book from the trash, ID: %s", id)
+	return nil
+}
+
+// PurgeBook deletes a book of the trash for good.
+func (r *BookSQLRepository) PurgeBook(id string) *appError.Error {
+	result, err := r.db.ExecContext(r.ctx, "DELETE FROM books WHERE id = $1 AND deleted_at IS NOT NULL", id)
+	if err != nil {
+		log.Printf("Error purging book: %v, ID: %s", err, id)
+		return appError.NewUnexpectedError(err.Error())
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return appError.NewNotFoundError("Book " + id + " not found in the trash.")
+	}
+	log.Printf("Purged book from the trash, ID: %s", id)
+	return nil
+}
+
+// PurgeExpiredBooks deletes the books whose retention in the trash ran out
+// and returns how many there were. It stands in for the TTL of the DynamoDB
+// table and is meant to run periodically.
+func (r *BookSQLRepository) PurgeExpiredBooks() (int, *appError.Error) {
+	result, err := r.db.ExecContext(r.ctx, "DELETE FROM books WHERE deleted_at IS NOT NULL AND expires_at <= $1", sqlTime(time.Now()))
+	if err != nil {
+		log.Printf("Error purging expired books: %v", err)
+		return 0, appError.NewUnexpectedError(err.Error())
+	}
+	purged, _ := result.RowsAffected()
+	log.Printf("Purged %d expired books from the trash", purged)
+	return int(purged), nil
+}
+
+// RestoreBook replaces the catalog fields of a book with those of book,
+// recreating it when it was deleted. The rating aggregate maintained by
+// reviews is kept.
+func (r *BookSQLRepository) RestoreBook(book *model.Book) *appError.Error {
+	values, err := catalogValues(book)
+	if err != nil {
+		log.Printf("Error marshaling book: %v, book: %+v", err, book)
+		return appError.NewUnexpectedError(err.Error())
+	}
+	args := append([]interface{}{book.ID}, values...)
+	args = append(args, sqlTimeValue(book.CreatedAt), book.RecordReference)
+	if _, err := r.db.ExecContext(r.ctx, restoreBookSQL, args...); err != nil {
+		log.Printf("Error restoring book: %v, ID: %s", err, book.ID)
+		return appError.NewUnexpectedError(err.Error())
+	}
+	log.Printf("Restored book successfully, ID: %s", book.ID)
+	return nil
+}
+
+// bookCursor is the position after the last book of a page, in the
+// (name, id) order of the listing.
+type bookCursor struct {
+	Name string `json:"n"`
+	ID   string `json:"i"`
+}
+
+// ListBooks returns up to limit books matching filter, ordered by name and
+// ID, starting after cursor. Pages are read with a keyset condition on the
+// index of the listing, so reading far into the catalog costs as much as
+// reading its first page.
+func (r *BookSQLRepository) ListBooks(filter model.BookFilter, cursor string, limit int) (*model.BookPage, *appError.Error) {
+	if limit < 1 || limit > MaxListLimit {
+		return nil, appError.NewValidationError(fmt.Sprintf("The limit must be between 1 and %d.", MaxListLimit))
+	}
+	query := &sqlConditions{}
+	query.add("deleted_at IS NULL")
+	if filter.Name != "" {
+		query.add("LOWER(name) LIKE " + query.arg("%"+escapeLike(strings.ToLower(filter.Name))+"%") + ` ESCAPE '\'`)
+	}
+	if filter.Publisher != "" {
+		query.add("publisher = " + query.arg(filter.Publisher))
+	}
+	if filter.Availability != "" {
+		query.add("availability = " + query.arg(filter.Availability))
+	}
+	if filter.UpdatedSince != nil {
+		query.add("updated_at >= " + query.arg(sqlTime(*filter.UpdatedSince)))
+	}
+	if cursor != "" {
+		after, errCursor := decodeBookCursor(cursor)
+		if errCursor != nil {
+			return nil, errCursor
+		}
+		query.add("(name, id) > (" + query.arg(after.Name) + ", " + query.arg(after.ID) + ")")
+	}
+
+	books, err := r.queryBooks("SELECT "+bookColumns+" FROM books"+query.where()+" ORDER BY name, id LIMIT "+query.arg(limit+1), query.args...)
+	if err != nil {
+		log.Printf("Error listing books: %v, filter: %+v", err, filter)
+		return nil, appError.NewUnexpectedError(err.Error())
+	}
+
+	page := &model.BookPage{Books: books}
+	if len(books) > limit {
+		page.Books = books[:limit]
+		last := page.Books[limit-1]
+		page.Next = encodeBookCursor(bookCursor{Name: last.Name, ID: last.ID})
+	}
+	log.Printf("Listed %d books, filter: %+v", len(page.Books), filter)
+	return page, nil
+}
+
+func encodeBookCursor(cursor bookCursor) string {
+	content, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(content)
+}
+
+func decodeBookCursor(cursor string) (bookCursor, *appError.Error) {
+	var after bookCursor
+	content, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err == nil {
+		err = json.Unmarshal(content, &after)
+	}
+	if err != nil || after.ID == "" {
+		return after, appError.NewBadRequestError("Invalid cursor.")
+	}
+	return after, nil
+}
+
+// sqlConditions collects the conditions of a query and numbers the
+// placeholders of their arguments.
+type sqlConditions struct {
+	conditions []string
+	args       []interface{}
+}
+
+func (c *sqlConditions) add(condition string) {
+	c.conditions = append(c.conditions, condition)
+}
+
+// arg binds value and returns its placeholder.
+func (c *sqlConditions) arg(value interface{}) string {
+	c.args = append(c.args, value)
+	return "$" + strconv.Itoa(len(c.args))
+}
+
+func (c *sqlConditions) where() string {
+	if len(c.conditions) == 0 {
+		return ""
+	}
+	return " WHERE " + strings.Join(c.conditions, " AND ")
+}
+
+// escapeLike makes the wildcards of a LIKE pattern match themselves.
+func escapeLike(pattern string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(pattern)
+}
+
+func (r *BookSQLRepository) queryBooks(query string, args ...interface{}) ([]model.Book, error) {
+	rows, err := r.db.QueryContext(r.ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	books := []model.Book{}
+	for rows.Next() {
+		book, err := scanBook(rows)
+		if err != nil {
+			return nil, err
+		}
+		books = append(books, book)
+	}
+	return books, rows.Err()
+}
+
+// scanBook reads a row of bookColumns.
+func scanBook(row interface{ Scan(...interface{}) error }) (model.Book, error) {
+	var book model.Book
+	var contributors, subjects, prices, createdAt, updatedAt, deletedAt sql.NullString
+	err := row.Scan(
+		&book.ID, &book.Name, &book.Description, &book.ImgURL, &book.ISBN, &book.Subtitle, &book.Publisher, &book.PublishedOn,
+		&book.Availability, &book.RecordReference, &contributors, &subjects, &prices,
+		&book.RatingCount, &book.RatingTotal, &book.Revision, &createdAt, &updatedAt, &deletedAt,
+	)
+	if err != nil {
+		return book, err
+	}
+	for _, column := range []struct {
+		value  sql.NullString
+		target interface{}
+	}{
+		{contributors, &book.Contributors},
+		{subjects, &book.Subjects},
+		{prices, &book.Prices},
+	} {
+		if column.value.Valid {
+			if err := json.Unmarshal([]byte(column.value.String), column.target); err != nil {
+				return book, err
+			}
+		}
+	}
+	if book.CreatedAt, err = parseSQLTime(createdAt); err != nil {
+		return book, err
+	}
+	if book.UpdatedAt, err = parseSQLTime(updatedAt); err != nil {
+		return book, err
+	}
+	book.DeletedAt, err = parseSQLTime(deletedAt)
+	return book, err
+}
+
+// bookValues are the values of bookColumns for book.
+func bookValues(book *model.Book) ([]interface{}, error) {
+	contributors, subjects, prices, err := metadataValues(book)
+	if err != nil {
+		return nil, err
+	}
+	return []interface{}{
+		book.ID, book.Name, book.Description, book.ImgURL, book.ISBN, book.Subtitle, book.Publisher, book.PublishedOn,
+		book.Availability, book.RecordReference, contributors, subjects, prices,
+		book.RatingCount, book.RatingTotal, book.Revision,
+		sqlTimeValue(book.CreatedAt), sqlTimeValue(book.UpdatedAt), sqlTimeValue(book.DeletedAt),
+	}, nil
+}
+
+// catalogValues are the values of sqlCatalogColumns for book.
+func catalogValues(book *model.Book) ([]interface{}, error) {
+	contributors, subjects, prices, err := metadataValues(book)
+	if err != nil {
+		return nil, err
+	}
+	return []interface{}{
+		book.Name, book.Description, book.ImgURL, book.ISBN, book.Subtitle, book.Publisher, book.PublishedOn,
+		contributors, subjects, prices, book.Availability, sqlTimeValue(book.UpdatedAt),
+	}, nil
+}
+
+// metadataValues encodes the contributors, subjects and prices of book as
+// JSON, NULL when there are none.
+func metadataValues(book *model.Book) (contributors, subjects, prices interface{}, err error) {
+	if contributors, err = sqlJSONValue(book.Contributors, len(book.Contributors)); err != nil {
+		return
+	}
+	if subjects, err = sqlJSONValue(book.Subjects, len(book.Subjects)); err != nil {
+		return
+	}
+	prices, err = sqlJSONValue(book.Prices, len(book.Prices))
+	return
+}
+
+func sqlJSONValue(value interface{}, length int) (interface{}, error) {
+	if length == 0 {
+		return nil, nil
+	}
+	content, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	return string(content), nil
+}
+
+func sqlTime(t time.Time) string {
+	return t.UTC().Format(sqlTimeLayout)
+}
+
+func sqlTimeValue(t *time.Time) interface{} {
+	if t == nil {
+		return nil
+	}
+	return sqlTime(*t)
+}
+
+func parseSQLTime(value sql.NullString) (*time.Time, error) {
+	if !value.Valid {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339Nano, value.String)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// sqlPlaceholders lists n placeholders numbered from first.
+func sqlPlaceholders(first, n int) string {
+	placeholders := make([]string, n)
+	for i := range placeholders {
+		placeholders[i] = "$" + strconv.Itoa(first+i)
+	}
+	return strings.Join(placeholders, ", ")
+}
+
+// sqlExcluded sets columns to the values of the row an upsert tried to
+// insert.
+func sqlExcluded(columns []string) string {
+	assignments := make([]string, len(columns))
+	for i, column := range columns {
+		assignments[i] = column + " = excluded." + column
+	}
+	return strings.Join(assignments, ", ")
+}
+
+// sqlAssignments sets columns to placeholders numbered from 1.
+func sqlAssignments(columns []string) string {
+	assignments := make([]string, len(columns))
+	for i, column := range columns {
+		assignments[i] = column + " = $" + strconv.Itoa(i+1)
+	}
+	return strings.Join(assignments, ", ")
+}
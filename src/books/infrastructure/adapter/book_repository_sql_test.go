@@ -0,0 +1,167 @@
+package adapter_test
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"testing"
+	"time"
+
+	"main/src/books/domain/model"
+	"main/src/books/domain/repository"
+	"main/src/books/infrastructure/adapter"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	_ "modernc.org/sqlite"
+)
+
+var (
+	_ repository.BookRepository        = (*adapter.BookSQLRepository)(nil)
+	_ repository.BookListingRepository = (*adapter.BookSQLRepository)(nil)
+)
+
+type BookSQLSuite struct {
+	suite.Suite
+	ctx            context.Context
+	db             *sql.DB
+	initBooks      []model.Book
+	bookRepository *adapter.BookSQLRepository
+}
+
+func (suite *BookSQLSuite) SetupTest() {
+	suite.ctx = context.TODO()
+	db, err := sql.Open("sqlite", "file::memory:")
+	suite.Require().NoError(err)
+	// Every connection to :memory: is a database of its own.
+	db.SetMaxOpenConns(1)
+	suite.db = db
+
+	suite.bookRepository = adapter.NewBookSQLRepository(suite.ctx, db)
+	suite.Require().Nil(suite.bookRepository.Migrate())
+
+	updatedAt := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	suite.initBooks = []model.Book{
+		{ID: uuid.NewString(), Name: "Book One", Description: "A first book", ImgURL: "url1", Publisher: "Ace",
+			Contributors: []model.Contributor{{Name: "Frank Herbert", Role: "A01"}}, UpdatedAt: &updatedAt},
+		{ID: uuid.NewString(), Name: "Book Two", Description: "A second book", ImgURL: "url2", Publisher: "Tor"},
+	}
+	suite.Require().Empty(suite.bookRepository.CreateBatchBooks(suite.initBooks))
+}
+
+func (suite *BookSQLSuite) TearDownTest() {
+	suite.db.Close()
+}
+
+func (suite *BookSQLSuite) TestMigrateIsIdempotent() {
+	suite.Nil(suite.bookRepository.Migrate())
+
+	var applied int
+	suite.NoError(suite.db.QueryRow("SELECT COUNT(*) FROM schema_migrations").Scan(&applied))
+	suite.Equal(1, applied)
+}
+
+func (suite *BookSQLSuite) TestCreateBookRoundTrips() {
+	createdAt := time.Date(2024, 1, 2, 3, 4, 5, 600, time.UTC)
+	newBook := model.Book{
+		ID: uuid.NewString(), Name: "Book Three", ISBN: "9780441013593",
+		Subjects:  []model.Subject{{Scheme: "BISAC", Code: "FIC028000", Main: true}},
+		Prices:    []model.Price{{Amount: "9.99", Currency: "USD"}},
+		CreatedAt: &createdAt,
+	}
+	_, err := suite.bookRepository.CreateBook(&newBook)
+	suite.Nil(err)
+
+	stored, err := suite.bookRepository.GetBookByID(newBook.ID)
+	suite.Nil(err)
+	suite.Equal(newBook, *stored)
+}
+
+func (suite *BookSQLSuite) TestCreateBatchBooksIsAtomic() {
+	books := []model.Book{
+		{ID: uuid.NewString(), Name: "Batch One"},
+		{ID: uuid.NewString(), Name: "Batch Two"},
+	}
+	_, err := suite.db.Exec("CREATE TRIGGER reject_batch_two BEFORE INSERT ON books WHEN NEW.name = 'Batch Two' BEGIN SELECT RAISE(ABORT, 'rejected'); END")
+	suite.Require().NoError(err)
+
+	failed := suite.bookRepository.CreateBatchBooks(books)
+	suite.Len(failed, 2)
+	stored, _ := suite.bookRepository.GetBookByID(books[0].ID)
+	suite.Empty(stored.ID)
+}
+
+func (suite *BookSQLSuite) TestExpiredTrashIsPurged() {
+	bookRepository := adapter.NewBookSQLRepository(suite.ctx, suite.db).WithTrashRetention(time.Nanosecond)
+	suite.Nil(bookRepository.DeleteBookByID(suite.initBooks[1].ID))
+	time.Sleep(time.Millisecond)
+
+	trashed, _ := bookRepository.GetTrashedBooks(100)
+	suite.Empty(trashed)
+	suite.Equal(http.StatusNotFound, bookRepository.RestoreTrashedBook(suite.initBooks[1].ID).Code)
+	purged, err := bookRepository.PurgeExpiredBooks()
+	suite.Nil(err)
+	suite.Equal(1, purged)
+}
+
+func (suite *BookSQLSuite) TestRestoreBookKeepsRating() {
+	_, err := suite.db.Exec("UPDATE books SET rating_count = 2, rating_total = 9 WHERE id = $1", suite.initBooks[1].ID)
+	suite.Require().NoError(err)
+	suite.Nil(suite.bookRepository.DeleteBookByID(suite.initBooks[1].ID))
+
+	restored := model.Book{ID: suite.initBooks[1].ID, Name: "Restored"}
+	suite.Nil(suite.bookRepository.RestoreBook(&restored))
+	stored, _ := suite.bookRepository.GetBookByID(restored.ID)
+	suite.Equal("Restored", stored.Name)
+	suite.Equal(2, stored.RatingCount)
+	suite.Equal(9, stored.RatingTotal)
+}
+
+func (suite *BookSQLSuite) TestListBooksPagesThroughMatches() {
+	var books []model.Book
+	for _, name := range []string{"Dune", "Dune Messiah", "Children of Dune", "Hyperion", "dune 100%"} {
+		books = append(books, model.Book{ID: uuid.NewString(), Name: name, Publisher: "Ace"})
+	}
+	suite.Require().Empty(suite.bookRepository.CreateBatchBooks(books))
+
+	filter := model.BookFilter{Name: "DUNE", Publisher: "Ace"}
+	var names []string
+	cursor := ""
+	for pages := 0; pages < 10; pages++ {
+		page, err := suite.bookRepository.ListBooks(filter, cursor, 2)
+		suite.Require().Nil(err)
+		for _, book := range page.Books {
+			names = append(names, book.Name)
+		}
+		if cursor = page.Next; cursor == "" {
+			break
+		}
+	}
+	suite.Equal([]string{"Children of Dune", "Dune", "Dune Messiah", "dune 100%"}, names)
+
+	page, err := suite.bookRepository.ListBooks(model.BookFilter{Name: "100%"}, "", 10)
+	suite.Nil(err)
+	suite.Len(page.Books, 1)
+	page, err = suite.bookRepository.ListBooks(model.BookFilter{Name: "e 1_0"}, "", 10)
+	suite.Nil(err)
+	suite.Empty(page.Books)
+}
+
+func (suite *BookSQLSuite) TestListBooksFiltersUpdatedSince() {
+	since := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	page, err := suite.bookRepository.ListBooks(model.BookFilter{UpdatedSince: &since}, "", 10)
+	suite.Nil(err)
+	suite.Equal([]string{suite.initBooks[0].ID}, bookIDs(page.Books))
+	suite.Empty(page.Next)
+}
+
+func (suite *BookSQLSuite) TestListBooksRejectsInvalidRequests() {
+	_, err := suite.bookRepository.ListBooks(model.BookFilter{}, "not a cursor", 10)
+	suite.Equal(http.StatusBadRequest, err.Code)
+	_, err = suite.bookRepository.ListBooks(model.BookFilter{}, "", adapter.MaxListLimit+1)
+	suite.Equal(http.StatusUnprocessableEntity, err.Code)
+}
+
+func TestBookSQLSuite(t *testing.T) {
+	suite.Run(t, new(BookSQLSuite))
+}
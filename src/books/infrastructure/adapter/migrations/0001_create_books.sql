@@ -0,0 +1,30 @@
+-- Books of the SQL store. Timestamps are fixed-width UTC RFC 3339 text so
+-- they sort as they compare in both PostgreSQL and SQLite; contributors,
+-- subjects and prices are JSON arrays.
+CREATE TABLE books (
+    id               TEXT PRIMARY KEY,
+    name             TEXT NOT NULL DEFAULT '',
+    description      TEXT NOT NULL DEFAULT '',
+    img_url          TEXT NOT NULL DEFAULT '',
+    isbn             TEXT NOT NULL DEFAULT '',
+    subtitle         TEXT NOT NULL DEFAULT '',
+    publisher        TEXT NOT NULL DEFAULT '',
+    published_on     TEXT NOT NULL DEFAULT '',
+    availability     TEXT NOT NULL DEFAULT '',
+    record_reference TEXT NOT NULL DEFAULT '',
+    contributors     TEXT,
+    subjects         TEXT,
+    prices           TEXT,
+    rating_count     INTEGER NOT NULL DEFAULT 0,
+    rating_total     INTEGER NOT NULL DEFAULT 0,
+    revision         INTEGER NOT NULL DEFAULT 0,
+    created_at       TEXT,
+    updated_at       TEXT,
+    deleted_at       TEXT,
+    expires_at       TEXT
+);
+
+-- Keyset pagination of the listing, the recent books feed and the trash.
+CREATE INDEX books_name_id_index ON books (name, id);
+CREATE INDEX books_updated_at_index ON books (updated_at);
+CREATE INDEX books_deleted_at_index ON books (deleted_at);
@@ -0,0 +1,45 @@
+package configuration
+
+import (
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// GetBoltBookPath is the file of the bolt store, BOOKS_BOLT_PATH or
+// books.bolt in the working directory.
+func GetBoltBookPath() string {
+	path := os.Getenv("BOOKS_BOLT_PATH")
+	if path == "" {
+		return "books.bolt"
+	}
+	return path
+}
+
+var (
+	boltDBsMu sync.Mutex
+	boltDBs   = make(map[string]*bolt.DB)
+)
+
+// GetBoltDB opens the file of the bolt store. A bolt file is locked by the
+// process that opens it, so every caller shares the same handle.
+func GetBoltDB() (*bolt.DB, error) {
+	path := GetBoltBookPath()
+	boltDBsMu.Lock()
+	defer boltDBsMu.Unlock()
+	if db, ok := boltDBs[path]; ok {
+		return db, nil
+	}
+
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		log.Printf("Error opening bolt database %s: %v", path, err)
+		return nil, err
+	}
+	log.Printf("Bolt database %s opened successfully", path)
+	boltDBs[path] = db
+	return db, nil
+}
@@ -0,0 +1,47 @@
+package configuration
+
+import (
+	"os"
+	"strconv"
+	"time"
+)
+
+const (
+	// The cache is off unless sized: each Lambda keeps its own, and the
+	// invalidations of the Lambdas that change books do not reach it.
+	defaultBookCacheSize        = 0
+	defaultBookCacheTTL         = time.Minute
+	defaultBookCacheNegativeTTL = 10 * time.Second
+)
+
+// BookCacheConfig sizes the in-process cache of books by ID.
+type BookCacheConfig struct {
+	// Size is how many books are kept; zero disables the cache.
+	Size        int
+	TTL         time.Duration
+	NegativeTTL time.Duration
+}
+
+// GetBookCacheConfig reads BOOKS_CACHE_SIZE, and BOOKS_CACHE_TTL and
+// BOOKS_CACHE_NEGATIVE_TTL as durations such as 30s. Size it only where the
+// readers see the changes, in a single process or with a shared cache, or
+// where reads may lag the changes by the TTL.
+func GetBookCacheConfig() BookCacheConfig {
+	config := BookCacheConfig{
+		Size:        defaultBookCacheSize,
+		TTL:         getDurationEnv("BOOKS_CACHE_TTL", defaultBookCacheTTL),
+		NegativeTTL: getDurationEnv("BOOKS_CACHE_NEGATIVE_TTL", defaultBookCacheNegativeTTL),
+	}
+	if size, err := strconv.Atoi(os.Getenv("BOOKS_CACHE_SIZE")); err == nil && size >= 0 {
+		config.Size = size
+	}
+	return config
+}
+
+func getDurationEnv(env string, fallback time.Duration) time.Duration {
+	value, err := time.ParseDuration(os.Getenv(env))
+	if err != nil || value <= 0 {
+		return fallback
+	}
+	return value
+}
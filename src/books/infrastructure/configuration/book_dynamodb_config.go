@@ -6,12 +6,16 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"sync"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/aws-sdk-go-v2/service/eventbridge"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/sns"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
 )
 
 
@@ -25,6 +29,33 @@ func GetDynamoDBBookTable() string {
 	return tableName
 }
 
+func GetDynamoDBBookHistoryTable() string {
+	tableName := os.Getenv("BOOKS_HISTORY_TABLE")
+	if tableName == "" {
+		return "Test_Book_History_Table"
+	}
+	return tableName
+}
+
+// GetDynamoDBBookEventsTable is the table of the event-sourced book streams.
+func GetDynamoDBBookEventsTable() string {
+	tableName := os.Getenv("BOOKS_EVENTS_TABLE")
+	if tableName == "" {
+		return "Test_Book_Events_Table"
+	}
+	return tableName
+}
+
+// GetDynamoDBBookSnapshotsTable is the table of the snapshots of the
+// event-sourced book streams.
+func GetDynamoDBBookSnapshotsTable() string {
+	tableName := os.Getenv("BOOKS_SNAPSHOTS_TABLE")
+	if tableName == "" {
+		return "Test_Book_Snapshots_Table"
+	}
+	return tableName
+}
+
 func GetDynamoDBClient(ctx context.Context) (*dynamodb.Client, error) {
 	tableName := os.Getenv("BOOKS_TABLE")
 	if tableName == "" {
@@ -34,6 +65,124 @@ func GetDynamoDBClient(ctx context.Context) (*dynamodb.Client, error) {
 }
 
 func CreateLocalDynamoDBBookTable(ctx context.Context, client *dynamodb.Client, tableName string) error {
+	_, err := client.CreateTable(ctx, &dynamodb.CreateTableInput{
+		AttributeDefinitions: []types.AttributeDefinition{
+			{
+				AttributeName: aws.String("ID"),
+				AttributeType: types.ScalarAttributeTypeS,
+			},
+			{
+				AttributeName: aws.String("recent"),
+				AttributeType: types.ScalarAttributeTypeS,
+			},
+			{
+				AttributeName: aws.String("updated_at"),
+				AttributeType: types.ScalarAttributeTypeS,
+			},
+			{
+				AttributeName: aws.String("trash"),
+				AttributeType: types.ScalarAttributeTypeS,
+			},
+			{
+				AttributeName: aws.String("deleted_at"),
+				AttributeType: types.ScalarAttributeTypeS,
+			},
+		},
+		KeySchema: []types.KeySchemaElement{
+			{
+				AttributeName: aws.String("ID"),
+				KeyType:       types.KeyTypeHash,
+			},
+		},
+		// Same indexes as the deployed table, for the recent books feed and
+		// the trash.
+		GlobalSecondaryIndexes: []types.GlobalSecondaryIndex{
+			{
+				IndexName: aws.String("recent-updated_at-index"),
+				KeySchema: []types.KeySchemaElement{
+					{
+						AttributeName: aws.String("recent"),
+						KeyType:       types.KeyTypeHash,
+					},
+					{
+						AttributeName: aws.String("updated_at"),
+						KeyType:       types.KeyTypeRange,
+					},
+				},
+				Projection: &types.Projection{
+					ProjectionType: types.ProjectionTypeAll,
+				},
+			},
+			{
+				IndexName: aws.String("trash-deleted_at-index"),
+				KeySchema: []types.KeySchemaElement{
+					{
+						AttributeName: aws.String("trash"),
+						KeyType:       types.KeyTypeHash,
+					},
+					{
+						AttributeName: aws.String("deleted_at"),
+						KeyType:       types.KeyTypeRange,
+					},
+				},
+				Projection: &types.Projection{
+					ProjectionType: types.ProjectionTypeAll,
+				},
+			},
+		},
+		TableName:   aws.String(tableName),
+		BillingMode: types.BillingModePayPerRequest,
+	})
+
+	if err != nil {
+		log.Printf("Error creating table %s: %s", tableName, err)
+		return err
+	}
+
+	log.Printf("Table %s created successfully", tableName)
+	return nil
+}
+
+// CreateLocalDynamoDBBookEventsTable creates the table of the event-sourced
+// book streams: the changes of each book ordered by sequence number.
+func CreateLocalDynamoDBBookEventsTable(ctx context.Context, client *dynamodb.Client, tableName string) error {
+	_, err := client.CreateTable(ctx, &dynamodb.CreateTableInput{
+		AttributeDefinitions: []types.AttributeDefinition{
+			{
+				AttributeName: aws.String("book_id"),
+				AttributeType: types.ScalarAttributeTypeS,
+			},
+			{
+				AttributeName: aws.String("sequence"),
+				AttributeType: types.ScalarAttributeTypeN,
+			},
+		},
+		KeySchema: []types.KeySchemaElement{
+			{
+				AttributeName: aws.String("book_id"),
+				KeyType:       types.KeyTypeHash,
+			},
+			{
+				AttributeName: aws.String("sequence"),
+				KeyType:       types.KeyTypeRange,
+			},
+		},
+		TableName:   aws.String(tableName),
+		BillingMode: types.BillingModePayPerRequest,
+	})
+
+	if err != nil {
+		log.Printf("Error creating table %s: %s", tableName, err)
+		return err
+	}
+
+	log.Printf("Table %s created successfully", tableName)
+	return nil
+}
+
+// CreateLocalDynamoDBBookSnapshotsTable creates the table holding the latest
+// snapshot of each event-sourced book.
+func CreateLocalDynamoDBBookSnapshotsTable(ctx context.Context, client *dynamodb.Client, tableName string) error {
 	_, err := client.CreateTable(ctx, &dynamodb.CreateTableInput{
 		AttributeDefinitions: []types.AttributeDefinition{
 			{
@@ -46,10 +195,6 @@ func CreateLocalDynamoDBBookTable(ctx context.Context, client *dynamodb.Client,
 				AttributeName: aws.String("book_id"),
 				KeyType:       types.KeyTypeHash,
 			},
-			// {
-			// 	AttributeName: aws.String("date"),
-			// 	KeyType:       types.KeyTypeRange,
-			// },
 		},
 		TableName:   aws.String(tableName),
 		BillingMode: types.BillingModePayPerRequest,
@@ -100,41 +245,96 @@ func DeleteLocalDynamoDBBookTable(ctx context.Context, client *dynamodb.Client,
 }
 
 func GetAWSDynamoDBClient(ctx context.Context) (*dynamodb.Client, error) {
-	cfg, err := config.LoadDefaultConfig(ctx)
+	cfg, err := GetAWSConfig(ctx)
 	if err != nil {
-		log.Printf("Error setting AWS configuration: %v", err)
 		return nil, err
 	}
-	log.Printf("AWS Client connected successfully")
 	return dynamodb.NewFromConfig(cfg), nil
 }
 
 func GetAWSS3Client(ctx context.Context) (*s3.Client, error) {
-	cfg, err := config.LoadDefaultConfig(ctx)
+	cfg, err := GetAWSConfig(ctx)
 	if err != nil {
-		log.Printf("Error setting AWS configuration: %v", err)
 		return nil, err
 	}
-	log.Printf("AWS Client connected successfully")
 	return s3.NewFromConfig(cfg), nil
 }
 
+func GetAWSEventBridgeClient(ctx context.Context) (*eventbridge.Client, error) {
+	cfg, err := GetAWSConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return eventbridge.NewFromConfig(cfg), nil
+}
+
+func GetAWSSNSClient(ctx context.Context) (*sns.Client, error) {
+	cfg, err := GetAWSConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return sns.NewFromConfig(cfg), nil
+}
+
+func GetAWSSQSClient(ctx context.Context) (*sqs.Client, error) {
+	cfg, err := GetAWSConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return sqs.NewFromConfig(cfg), nil
+}
+
 func GetLocalEndpoint(service, region string, options ...interface{}) (aws.Endpoint, error) {
 	return aws.Endpoint{URL: "http://localhost:8000"}, nil
 }
 
 func GetLocalDynamoDBClient(ctx context.Context) (*dynamodb.Client, error) {
-	cfg, err := config.LoadDefaultConfig(ctx,
-		config.WithEndpointResolverWithOptions(
+	cfg, err := loadAWSConfig(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+	return dynamodb.NewFromConfig(cfg), nil
+}
+
+var (
+	awsConfigsMu sync.Mutex
+	awsConfigs   = make(map[bool]aws.Config)
+)
+
+// GetAWSConfig is the SDK configuration, loaded on first use and shared by
+// every client of the process, so warm Lambda invocations skip loading it
+// and reuse its HTTP connections.
+func GetAWSConfig(ctx context.Context) (aws.Config, error) {
+	return loadAWSConfig(ctx, false)
+}
+
+// loadAWSConfig loads the configuration of AWS, or of DynamoDB Local when
+// local is set, once.
+func loadAWSConfig(ctx context.Context, local bool) (aws.Config, error) {
+	awsConfigsMu.Lock()
+	defer awsConfigsMu.Unlock()
+	if cfg, ok := awsConfigs[local]; ok {
+		return cfg, nil
+	}
+
+	var options []func(*config.LoadOptions) error
+	if local {
+		options = append(options, config.WithEndpointResolverWithOptions(
 			aws.EndpointResolverWithOptionsFunc(
 				GetLocalEndpoint,
 			),
-		),
-	)
+		))
+	}
+	cfg, err := config.LoadDefaultConfig(ctx, options...)
 	if err != nil {
-		log.Printf("Error setting Local configurationb: %v", err)
-		return nil, err
+		log.Printf("Error setting AWS configuration: %v", err)
+		return aws.Config{}, err
 	}
-	log.Printf("Local Client connected successfully")
-	return dynamodb.NewFromConfig(cfg), nil
+	if local {
+		log.Printf("Local configuration loaded successfully")
+	} else {
+		log.Printf("AWS configuration loaded successfully")
+	}
+	awsConfigs[local] = cfg
+	return cfg, nil
 }
@@ -0,0 +1,75 @@
+package configuration
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"os"
+	"sync"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+	_ "modernc.org/sqlite"
+)
+
+// Drivers registered for the SQL store.
+const (
+	SQLDriverPostgres = "pgx"
+	SQLDriverSQLite   = "sqlite"
+)
+
+// GetSQLBookDSN is the data source of the SQL store. Without BOOKS_SQL_DSN
+// the books are kept in a local SQLite file.
+func GetSQLBookDSN() string {
+	dsn := os.Getenv("BOOKS_SQL_DSN")
+	if dsn == "" {
+		return "file:books.db?_pragma=busy_timeout(5000)"
+	}
+	return dsn
+}
+
+// GetSQLBookDriver is the database/sql driver of the SQL store: BOOKS_SQL_DRIVER
+// when set, else PostgreSQL for a configured data source and SQLite for the
+// local file.
+func GetSQLBookDriver() string {
+	if driver := os.Getenv("BOOKS_SQL_DRIVER"); driver != "" {
+		return driver
+	}
+	if os.Getenv("BOOKS_SQL_DSN") == "" {
+		return SQLDriverSQLite
+	}
+	return SQLDriverPostgres
+}
+
+var (
+	sqlDBsMu sync.Mutex
+	sqlDBs   = make(map[string]*sql.DB)
+)
+
+// GetSQLDB opens the SQL store. The connection pool is shared by every
+// caller of the same driver and data source, so a warm Lambda reuses it.
+func GetSQLDB(ctx context.Context) (*sql.DB, error) {
+	driver, dsn := GetSQLBookDriver(), GetSQLBookDSN()
+	sqlDBsMu.Lock()
+	defer sqlDBsMu.Unlock()
+	if db, ok := sqlDBs[driver+" "+dsn]; ok {
+		return db, nil
+	}
+
+	db, err := sql.Open(driver, dsn)
+	if err != nil {
+		log.Printf("Error opening %s database: %v", driver, err)
+		return nil, err
+	}
+	if driver == SQLDriverSQLite {
+		// SQLite serializes writers; one connection avoids busy errors.
+		db.SetMaxOpenConns(1)
+	}
+	if err := db.PingContext(ctx); err != nil {
+		log.Printf("Error connecting to %s database: %v", driver, err)
+		db.Close()
+		return nil, err
+	}
+	log.Printf("SQL %s database connected successfully", driver)
+	sqlDBs[driver+" "+dsn] = db
+	return db, nil
+}
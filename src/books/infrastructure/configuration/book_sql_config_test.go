@@ -0,0 +1,56 @@
+package configuration_test
+
+import (
+	"context"
+	"testing"
+
+	"main/src/books/infrastructure/configuration"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type SQLBookConfigSuite struct {
+	suite.Suite
+}
+
+func (suite *SQLBookConfigSuite) TestGetBookStore() {
+	suite.T().Setenv("BOOKS_STORE", "")
+	suite.Equal(configuration.BookStoreDynamoDB, configuration.GetBookStore())
+	suite.T().Setenv("BOOKS_STORE", "sql")
+	suite.Equal(configuration.BookStoreSQL, configuration.GetBookStore())
+	suite.T().Setenv("BOOKS_STORE", "bolt")
+	suite.Equal(configuration.BookStoreBolt, configuration.GetBookStore())
+	suite.T().Setenv("BOOKS_STORE", "eventsourced")
+	suite.Equal(configuration.BookStoreEventSourced, configuration.GetBookStore())
+}
+
+func (suite *SQLBookConfigSuite) TestGetSQLBookDriver() {
+	suite.T().Setenv("BOOKS_SQL_DRIVER", "")
+	suite.T().Setenv("BOOKS_SQL_DSN", "")
+	suite.Equal(configuration.SQLDriverSQLite, configuration.GetSQLBookDriver())
+	suite.T().Setenv("BOOKS_SQL_DSN", "postgres://books@localhost/books")
+	suite.Equal(configuration.SQLDriverPostgres, configuration.GetSQLBookDriver())
+	suite.T().Setenv("BOOKS_SQL_DRIVER", "sqlite")
+	suite.Equal(configuration.SQLDriverSQLite, configuration.GetSQLBookDriver())
+}
+
+func (suite *SQLBookConfigSuite) TestGetSQLDBSharesThePool() {
+	suite.T().Setenv("BOOKS_SQL_DRIVER", "sqlite")
+	suite.T().Setenv("BOOKS_SQL_DSN", "file::memory:")
+	db, err := configuration.GetSQLDB(context.TODO())
+	suite.Require().NoError(err)
+	again, err := configuration.GetSQLDB(context.TODO())
+	suite.NoError(err)
+	suite.Same(db, again)
+}
+
+func (suite *SQLBookConfigSuite) TestBookCacheIsOffByDefault() {
+	suite.T().Setenv("BOOKS_CACHE_SIZE", "")
+	suite.Zero(configuration.GetBookCacheConfig().Size)
+	suite.T().Setenv("BOOKS_CACHE_SIZE", "500")
+	suite.Equal(500, configuration.GetBookCacheConfig().Size)
+}
+
+func TestSQLBookConfigSuite(t *testing.T) {
+	suite.Run(t, new(SQLBookConfigSuite))
+}
@@ -0,0 +1,26 @@
+package configuration
+
+import "os"
+
+// Stores the books can be kept in, chosen with BOOKS_STORE.
+const (
+	BookStoreDynamoDB = "dynamodb"
+	BookStoreSQL      = "sql"
+	// BookStoreBolt keeps the books and their files in a single local file,
+	// for deployments without a database process.
+	BookStoreBolt = "bolt"
+	// BookStoreEventSourced derives the books from the streams of their
+	// changes kept in BOOKS_EVENTS_TABLE, snapshotted in
+	// BOOKS_SNAPSHOTS_TABLE.
+	BookStoreEventSourced = "eventsourced"
+)
+
+// GetBookStore is the store the books are kept in, DynamoDB unless
+// BOOKS_STORE names another one.
+func GetBookStore() string {
+	switch store := os.Getenv("BOOKS_STORE"); store {
+	case BookStoreSQL, BookStoreBolt, BookStoreEventSourced:
+		return store
+	}
+	return BookStoreDynamoDB
+}
@@ -0,0 +1,104 @@
+package handler
+
+import (
+	"context"
+
+	bookHandler "main/src/books/application/handler"
+	bookConfiguration "main/src/books/infrastructure/configuration"
+	"main/src/lists/application/service"
+	"main/src/lists/domain/model"
+	"main/src/lists/infrastructure/adapter"
+	"main/src/lists/infrastructure/configuration"
+	appError "main/utils/error"
+)
+
+type MicroAWSListDynamoDB struct {
+	Ctx        context.Context
+	ListsTable string
+	BooksTable string
+}
+
+func (micro *MicroAWSListDynamoDB) listService() (service.ListService, *appError.Error) {
+	dynamoClient, err := bookHandler.DefaultBookContainer().DynamoDBClient(micro.Ctx)
+	if err != nil {
+		return nil, err
+	}
+	if micro.ListsTable == "" {
+		micro.ListsTable = configuration.GetDynamoDBListTable()
+	}
+	if micro.BooksTable == "" {
+		micro.BooksTable = bookConfiguration.GetDynamoDBBookTable()
+	}
+	listInfrastructure := adapter.NewListDynamoDBRepository(micro.Ctx, dynamoClient, micro.ListsTable)
+	// The entries are hydrated from the store chosen by BOOKS_STORE.
+	bookMicro := &bookHandler.MicroAWSBookDynamoDB{Ctx: micro.Ctx, TableName: micro.BooksTable}
+	bookInfrastructure, errBooks := bookHandler.DefaultBookContainer().BookRepository(bookMicro, false)
+	if errBooks != nil {
+		return nil, errBooks
+	}
+	return service.NewListServiceDynamoDB(listInfrastructure, bookInfrastructure), nil
+}
+
+func (micro *MicroAWSListDynamoDB) CreateList(list *model.ReadingList) (*model.ReadingList, *appError.Error) {
+	listService, err := micro.listService()
+	if err != nil {
+		return nil, err
+	}
+	return listService.CreateList(list)
+}
+
+func (micro *MicroAWSListDynamoDB) GetList(listID, viewerID string) (*model.ReadingListDetail, *appError.Error) {
+	listService, err := micro.listService()
+	if err != nil {
+		return nil, err
+	}
+	return listService.GetList(listID, viewerID)
+}
+
+func (micro *MicroAWSListDynamoDB) GetListsByOwnerID(ownerID, viewerID string) ([]model.ReadingList, *appError.Error) {
+	listService, err := micro.listService()
+	if err != nil {
+		return nil, err
+	}
+	return listService.GetListsByOwnerID(ownerID, viewerID)
+}
+
+func (micro *MicroAWSListDynamoDB) UpdateList(listID, actorID string, list *model.ReadingList) (*model.ReadingList, *appError.Error) {
+	listService, err := micro.listService()
+	if err != nil {
+		return nil, err
+	}
+	return listService.UpdateList(listID, actorID, list)
+}
+
+func (micro *MicroAWSListDynamoDB) DeleteList(listID, actorID string) *appError.Error {
+	listService, err := micro.listService()
+	if err != nil {
+		return err
+	}
+	return listService.DeleteList(listID, actorID)
+}
+
+func (micro *MicroAWSListDynamoDB) AddBook(listID, actorID, bookID string) (*model.ReadingList, *appError.Error) {
+	listService, err := micro.listService()
+	if err != nil {
+		return nil, err
+	}
+	return listService.AddBook(listID, actorID, bookID)
+}
+
+func (micro *MicroAWSListDynamoDB) RemoveBook(listID, actorID, bookID string) (*model.ReadingList, *appError.Error) {
+	listService, err := micro.listService()
+	if err != nil {
+		return nil, err
+	}
+	return listService.RemoveBook(listID, actorID, bookID)
+}
+
+func (micro *MicroAWSListDynamoDB) ReorderBooks(listID, actorID string, bookIDs []string) (*model.ReadingList, *appError.Error) {
+	listService, err := micro.listService()
+	if err != nil {
+		return nil, err
+	}
+	return listService.ReorderBooks(listID, actorID, bookIDs)
+}
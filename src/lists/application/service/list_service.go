@@ -0,0 +1,17 @@
+package service
+
+import (
+	"main/src/lists/domain/model"
+	appError "main/utils/error"
+)
+
+type ListService interface {
+	CreateList(*model.ReadingList) (*model.ReadingList, *appError.Error)
+	GetList(string, string) (*model.ReadingListDetail, *appError.Error)
+	GetListsByOwnerID(string, string) ([]model.ReadingList, *appError.Error)
+	UpdateList(string, string, *model.ReadingList) (*model.ReadingList, *appError.Error)
+	DeleteList(string, string) *appError.Error
+	AddBook(string, string, string) (*model.ReadingList, *appError.Error)
+	RemoveBook(string, string, string) (*model.ReadingList, *appError.Error)
+	ReorderBooks(string, string, []string) (*model.ReadingList, *appError.Error)
+}
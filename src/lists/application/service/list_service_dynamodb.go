@@ -0,0 +1,194 @@
+package service
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	bookModel "main/src/books/domain/model"
+	bookRepository "main/src/books/domain/repository"
+	"main/src/lists/domain/model"
+	"main/src/lists/domain/repository"
+	appError "main/utils/error"
+	"main/utils/lib"
+)
+
+type ListServiceDynamoDB struct {
+	repo     repository.ListRepository
+	bookRepo bookRepository.BookRepository
+}
+
+func NewListServiceDynamoDB(repo repository.ListRepository, bookRepo bookRepository.BookRepository) ListService {
+	return &ListServiceDynamoDB{
+		repo:     repo,
+		bookRepo: bookRepo,
+	}
+}
+
+func now() time.Time {
+	return time.Now().UTC().Truncate(time.Second)
+}
+
+func (service *ListServiceDynamoDB) CreateList(list *model.ReadingList) (*model.ReadingList, *appError.Error) {
+	createdAt := now()
+	list.ID = uuid.NewString()
+	list.Version = 1
+	list.CreatedAt = createdAt
+	list.UpdatedAt = createdAt
+	if list.Visibility == "" {
+		list.Visibility = model.VisibilityPrivate
+	}
+	if list.Entries == nil {
+		list.Entries = []model.ListEntry{}
+	}
+	if err := list.Validate(); err != nil {
+		return nil, err
+	}
+	if err := service.repo.CreateList(list); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// GetList returns the list with every entry hydrated from the book catalog in
+// a single batch read, keeping the list order.
+func (service *ListServiceDynamoDB) GetList(listID, viewerID string) (*model.ReadingListDetail, *appError.Error) {
+	list, err := service.getList(listID)
+	if err != nil {
+		return nil, err
+	}
+	if !list.CanView(viewerID) {
+		// Private lists are reported as missing so their existence is not leaked.
+		return nil, appError.NewNotFoundError("List " + listID + " not found.")
+	}
+
+	books, err := service.bookRepo.GetBooksByIDs(list.BookIDs())
+	if err != nil {
+		return nil, err
+	}
+	booksByID := make(map[string]bookModel.Book, len(books.Books))
+	for _, book := range books.Books {
+		book.SummarizeRating()
+		booksByID[book.ID] = book
+	}
+
+	detail := &model.ReadingListDetail{
+		ReadingList: *list,
+		Entries:     make([]model.HydratedEntry, len(list.Entries)),
+	}
+	for i, entry := range list.Entries {
+		detail.Entries[i] = model.HydratedEntry{ListEntry: entry}
+		if book, ok := booksByID[entry.BookID]; ok {
+			detail.Entries[i].Book = &book
+		}
+	}
+	return detail, nil
+}
+
+func (service *ListServiceDynamoDB) GetListsByOwnerID(ownerID, viewerID string) ([]model.ReadingList, *appError.Error) {
+	lists, err := service.repo.GetListsByOwnerID(ownerID)
+	if err != nil {
+		return nil, err
+	}
+	visible := make([]model.ReadingList, 0, len(lists))
+	for _, list := range lists {
+		if list.CanView(viewerID) {
+			visible = append(visible, list)
+		}
+	}
+	return visible, nil
+}
+
+func (service *ListServiceDynamoDB) UpdateList(listID, actorID string, changes *model.ReadingList) (*model.ReadingList, *appError.Error) {
+	return service.modify(listID, actorID, func(list *model.ReadingList) *appError.Error {
+		if changes.Name != "" {
+			list.Name = changes.Name
+		}
+		if changes.Visibility != "" {
+			list.Visibility = changes.Visibility
+		}
+		return nil
+	})
+}
+
+func (service *ListServiceDynamoDB) DeleteList(listID, actorID string) *appError.Error {
+	list, err := service.getOwnedList(listID, actorID)
+	if err != nil {
+		return err
+	}
+	return service.repo.DeleteList(list.ID)
+}
+
+func (service *ListServiceDynamoDB) AddBook(listID, actorID, bookID string) (*model.ReadingList, *appError.Error) {
+	if err := lib.ValidateUUID(bookID); err != nil {
+		return nil, err
+	}
+	book, err := service.bookRepo.GetBookByID(bookID)
+	if err != nil {
+		return nil, err
+	}
+	if book.ID == "" {
+		return nil, appError.NewNotFoundError("Book " + bookID + " not found.")
+	}
+	return service.modify(listID, actorID, func(list *model.ReadingList) *appError.Error {
+		return list.AddEntry(bookID, now())
+	})
+}
+
+func (service *ListServiceDynamoDB) RemoveBook(listID, actorID, bookID string) (*model.ReadingList, *appError.Error) {
+	return service.modify(listID, actorID, func(list *model.ReadingList) *appError.Error {
+		return list.RemoveEntry(bookID)
+	})
+}
+
+func (service *ListServiceDynamoDB) ReorderBooks(listID, actorID string, bookIDs []string) (*model.ReadingList, *appError.Error) {
+	return service.modify(listID, actorID, func(list *model.ReadingList) *appError.Error {
+		return list.Reorder(bookIDs)
+	})
+}
+
+// modify applies change to the owner's list and saves it guarded by the
+// version that was read, so concurrent edits fail instead of overwriting.
+func (service *ListServiceDynamoDB) modify(listID, actorID string, change func(*model.ReadingList) *appError.Error) (*model.ReadingList, *appError.Error) {
+	list, err := service.getOwnedList(listID, actorID)
+	if err != nil {
+		return nil, err
+	}
+	expectedVersion := list.Version
+	if err := change(list); err != nil {
+		return nil, err
+	}
+	list.Version = expectedVersion + 1
+	list.UpdatedAt = now()
+	if err := list.Validate(); err != nil {
+		return nil, err
+	}
+	if err := service.repo.UpdateList(list, expectedVersion); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (service *ListServiceDynamoDB) getOwnedList(listID, actorID string) (*model.ReadingList, *appError.Error) {
+	list, err := service.getList(listID)
+	if err != nil {
+		return nil, err
+	}
+	if list.OwnerID != actorID {
+		return nil, appError.NewForbiddenError("Only the owner can modify this list.")
+	}
+	return list, nil
+}
+
+func (service *ListServiceDynamoDB) getList(listID string) (*model.ReadingList, *appError.Error) {
+	if err := lib.ValidateUUID(listID); err != nil {
+		return nil, err
+	}
+	list, err := service.repo.GetListByID(listID)
+	if err != nil {
+		return nil, err
+	}
+	if list.ID == "" {
+		return nil, appError.NewNotFoundError("List " + listID + " not found.")
+	}
+	return list, nil
+}
@@ -0,0 +1,161 @@
+package service_test
+
+import (
+	"testing"
+	"time"
+
+	bookModel "main/src/books/domain/model"
+	"main/src/lists/application/service"
+	"main/src/lists/domain/model"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	repoMock "main/mocks"
+)
+
+type ListServiceDynamoDBSuite struct {
+	suite.Suite
+	listRepository *repoMock.ListRepository
+	bookRepository *repoMock.BookRepository
+	listService    service.ListService
+	testList       *model.ReadingList
+	bookIDs        []string
+}
+
+const (
+	MethodCreateList        = "CreateList"
+	MethodGetListByID       = "GetListByID"
+	MethodGetListsByOwnerID = "GetListsByOwnerID"
+	MethodUpdateList        = "UpdateList"
+	MethodDeleteList        = "DeleteList"
+	MethodGetBookByID       = "GetBookByID"
+	MethodGetBooksByIDs     = "GetBooksByIDs"
+)
+
+func (suite *ListServiceDynamoDBSuite) SetupTest() {
+	suite.listRepository = new(repoMock.ListRepository)
+	suite.bookRepository = new(repoMock.BookRepository)
+	suite.listService = service.NewListServiceDynamoDB(suite.listRepository, suite.bookRepository)
+	suite.bookIDs = []string{uuid.NewString(), uuid.NewString(), uuid.NewString()}
+	suite.testList = &model.ReadingList{
+		ID:         uuid.NewString(),
+		OwnerID:    "user-1",
+		Name:       "Favorites",
+		Visibility: model.VisibilityPrivate,
+		Version:    3,
+	}
+	for _, id := range suite.bookIDs {
+		suite.testList.Entries = append(suite.testList.Entries, model.ListEntry{BookID: id, AddedAt: time.Now()})
+	}
+}
+
+func (suite *ListServiceDynamoDBSuite) TestCreateList() {
+	list := &model.ReadingList{OwnerID: "user-1", Name: "To read"}
+	suite.listRepository.On(MethodCreateList, list).Return(nil)
+	created, err := suite.listService.CreateList(list)
+	suite.Nil(err)
+	suite.NotEmpty(created.ID)
+	suite.Equal(model.VisibilityPrivate, created.Visibility)
+	suite.NotNil(created.Entries)
+	suite.listRepository.AssertExpectations(suite.T())
+}
+
+func (suite *ListServiceDynamoDBSuite) TestGetListHydratesInOrder() {
+	// Hydration must not rely on the repository's ordering.
+	books := &bookModel.BooksByIDs{
+		Books: []bookModel.Book{
+			{ID: suite.bookIDs[2], Name: "Third"},
+			{ID: suite.bookIDs[0], Name: "First", RatingCount: 2, RatingTotal: 9},
+		},
+		MissingIDs: []string{suite.bookIDs[1]},
+	}
+	suite.listRepository.On(MethodGetListByID, suite.testList.ID).Return(suite.testList, nil)
+	suite.bookRepository.On(MethodGetBooksByIDs, suite.bookIDs).Return(books, nil)
+
+	detail, err := suite.listService.GetList(suite.testList.ID, "user-1")
+	suite.Nil(err)
+	suite.Len(detail.Entries, 3)
+	suite.Equal("First", detail.Entries[0].Book.Name)
+	suite.Equal(4.5, detail.Entries[0].Book.Rating.Average)
+	suite.Nil(detail.Entries[1].Book)
+	suite.Equal("Third", detail.Entries[2].Book.Name)
+	suite.bookRepository.AssertNumberOfCalls(suite.T(), MethodGetBooksByIDs, 1)
+	suite.bookRepository.AssertNotCalled(suite.T(), MethodGetBookByID, mock.Anything)
+}
+
+func (suite *ListServiceDynamoDBSuite) TestGetPrivateListAsStranger() {
+	suite.listRepository.On(MethodGetListByID, suite.testList.ID).Return(suite.testList, nil)
+	_, err := suite.listService.GetList(suite.testList.ID, "user-2")
+	suite.NotNil(err)
+	suite.Equal(404, err.Code)
+}
+
+func (suite *ListServiceDynamoDBSuite) TestGetListsByOwnerIDFiltersPrivate() {
+	public := *suite.testList
+	public.ID = uuid.NewString()
+	public.Visibility = model.VisibilityPublic
+	suite.listRepository.On(MethodGetListsByOwnerID, "user-1").Return([]model.ReadingList{*suite.testList, public}, nil)
+
+	lists, err := suite.listService.GetListsByOwnerID("user-1", "user-2")
+	suite.Nil(err)
+	suite.Len(lists, 1)
+	suite.Equal(public.ID, lists[0].ID)
+
+	lists, err = suite.listService.GetListsByOwnerID("user-1", "user-1")
+	suite.Nil(err)
+	suite.Len(lists, 2)
+}
+
+func (suite *ListServiceDynamoDBSuite) TestAddBook() {
+	bookID := uuid.NewString()
+	suite.bookRepository.On(MethodGetBookByID, bookID).Return(&bookModel.Book{ID: bookID}, nil)
+	suite.listRepository.On(MethodGetListByID, suite.testList.ID).Return(suite.testList, nil)
+	suite.listRepository.On(MethodUpdateList, mock.MatchedBy(func(l *model.ReadingList) bool {
+		return len(l.Entries) == 4 && l.Version == 4
+	}), 3).Return(nil)
+
+	list, err := suite.listService.AddBook(suite.testList.ID, "user-1", bookID)
+	suite.Nil(err)
+	suite.Equal(bookID, list.Entries[3].BookID)
+	suite.listRepository.AssertExpectations(suite.T())
+}
+
+func (suite *ListServiceDynamoDBSuite) TestAddMissingBook() {
+	bookID := uuid.NewString()
+	suite.bookRepository.On(MethodGetBookByID, bookID).Return(&bookModel.Book{}, nil)
+	_, err := suite.listService.AddBook(suite.testList.ID, "user-1", bookID)
+	suite.NotNil(err)
+	suite.Equal(404, err.Code)
+	suite.listRepository.AssertNotCalled(suite.T(), MethodUpdateList, mock.Anything, mock.Anything)
+}
+
+func (suite *ListServiceDynamoDBSuite) TestReorderBooksByStranger() {
+	suite.listRepository.On(MethodGetListByID, suite.testList.ID).Return(suite.testList, nil)
+	_, err := suite.listService.ReorderBooks(suite.testList.ID, "user-2", suite.bookIDs)
+	suite.NotNil(err)
+	suite.Equal(403, err.Code)
+	suite.listRepository.AssertNotCalled(suite.T(), MethodUpdateList, mock.Anything, mock.Anything)
+}
+
+func (suite *ListServiceDynamoDBSuite) TestUpdateListVisibility() {
+	suite.listRepository.On(MethodGetListByID, suite.testList.ID).Return(suite.testList, nil)
+	suite.listRepository.On(MethodUpdateList, mock.AnythingOfType("*model.ReadingList"), 3).Return(nil)
+
+	list, err := suite.listService.UpdateList(suite.testList.ID, "user-1", &model.ReadingList{Visibility: model.VisibilityPublic})
+	suite.Nil(err)
+	suite.Equal(model.VisibilityPublic, list.Visibility)
+	suite.Equal("Favorites", list.Name)
+}
+
+func (suite *ListServiceDynamoDBSuite) TestDeleteList() {
+	suite.listRepository.On(MethodGetListByID, suite.testList.ID).Return(suite.testList, nil)
+	suite.listRepository.On(MethodDeleteList, suite.testList.ID).Return(nil)
+	suite.Nil(suite.listService.DeleteList(suite.testList.ID, "user-1"))
+	suite.listRepository.AssertExpectations(suite.T())
+}
+
+func TestListServiceDynamoDBSuite(t *testing.T) {
+	suite.Run(t, new(ListServiceDynamoDBSuite))
+}
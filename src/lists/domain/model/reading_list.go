@@ -0,0 +1,132 @@
+package model
+
+import (
+	bookModel "main/src/books/domain/model"
+	appError "main/utils/error"
+	"main/utils/lib"
+	"time"
+)
+
+type Visibility string
+
+const (
+	VisibilityPrivate Visibility = "private"
+	VisibilityPublic  Visibility = "public"
+)
+
+const (
+	MaxListNameCharacters = 100
+	MaxListEntries        = 500
+)
+
+type ListEntry struct {
+	BookID  string    `json:"book_id" dynamodbav:"book_id"`
+	AddedAt time.Time `json:"added_at" dynamodbav:"added_at"`
+}
+
+// ReadingList is a named, ordered collection of books owned by one user.
+type ReadingList struct {
+	ID         string      `json:"ID,omitempty" dynamodbav:"ID,omitempty"`
+	OwnerID    string      `json:"owner_id,omitempty" dynamodbav:"owner_id,omitempty"`
+	Name       string      `json:"name,omitempty" dynamodbav:"name,omitempty"`
+	Visibility Visibility  `json:"visibility,omitempty" dynamodbav:"visibility,omitempty"`
+	Entries    []ListEntry `json:"entries" dynamodbav:"entries"`
+	Version    int         `json:"version" dynamodbav:"version"`
+	CreatedAt  time.Time   `json:"created_at" dynamodbav:"created_at"`
+	UpdatedAt  time.Time   `json:"updated_at" dynamodbav:"updated_at"`
+}
+
+// HydratedEntry pairs a list entry with the current book record. Book is nil
+// when the book no longer exists in the catalog.
+type HydratedEntry struct {
+	ListEntry
+	Book *bookModel.Book `json:"book,omitempty"`
+}
+
+type ReadingListDetail struct {
+	ReadingList
+	Entries []HydratedEntry `json:"entries"`
+}
+
+func (l *ReadingList) Validate() *appError.Error {
+	if err := lib.ValidateUUID(l.ID); err != nil {
+		return err
+	}
+	if err := lib.ValidateStringNotEmpty(l.OwnerID); err != nil {
+		return appError.NewValidationError("Owner ID cannot be empty.")
+	}
+	if err := lib.ValidateStringNotEmpty(l.Name); err != nil {
+		return err
+	}
+	if len(l.Name) > MaxListNameCharacters {
+		return appError.NewValidationError("Name cannot exceed 100 characters.")
+	}
+	if l.Visibility != VisibilityPrivate && l.Visibility != VisibilityPublic {
+		return appError.NewValidationError("Visibility must be 'private' or 'public'.")
+	}
+	if len(l.Entries) > MaxListEntries {
+		return appError.NewValidationError("A list cannot hold more than 500 books.")
+	}
+	return nil
+}
+
+func (l *ReadingList) CanView(viewerID string) bool {
+	return l.Visibility == VisibilityPublic || l.OwnerID == viewerID
+}
+
+func (l *ReadingList) BookIDs() []string {
+	ids := make([]string, len(l.Entries))
+	for i, entry := range l.Entries {
+		ids[i] = entry.BookID
+	}
+	return ids
+}
+
+func (l *ReadingList) indexOf(bookID string) int {
+	for i, entry := range l.Entries {
+		if entry.BookID == bookID {
+			return i
+		}
+	}
+	return -1
+}
+
+func (l *ReadingList) AddEntry(bookID string, addedAt time.Time) *appError.Error {
+	if l.indexOf(bookID) >= 0 {
+		return appError.NewConflictError("Book is already in the list.")
+	}
+	if len(l.Entries) >= MaxListEntries {
+		return appError.NewValidationError("A list cannot hold more than 500 books.")
+	}
+	l.Entries = append(l.Entries, ListEntry{BookID: bookID, AddedAt: addedAt})
+	return nil
+}
+
+func (l *ReadingList) RemoveEntry(bookID string) *appError.Error {
+	i := l.indexOf(bookID)
+	if i < 0 {
+		return appError.NewNotFoundError("Book is not in the list.")
+	}
+	l.Entries = append(l.Entries[:i], l.Entries[i+1:]...)
+	return nil
+}
+
+// Reorder rearranges the entries to follow bookIDs, which must name every
+// book in the list exactly once.
+func (l *ReadingList) Reorder(bookIDs []string) *appError.Error {
+	if len(bookIDs) != len(l.Entries) {
+		return appError.NewValidationError("Reorder must include every book in the list exactly once.")
+	}
+	reordered := make([]ListEntry, 0, len(bookIDs))
+	seen := make(map[string]bool, len(bookIDs))
+	for _, bookID := range bookIDs {
+		i := l.indexOf(bookID)
+		if i < 0 || seen[bookID] {
+			return appError.NewValidationError("Reorder must include every book in the list exactly once.")
+		}
+		seen[bookID] = true
+		reordered = append(reordered, l.Entries[i])
+	}
+	l.Entries = reordered
+	return nil
+}
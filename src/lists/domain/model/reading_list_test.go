@@ -0,0 +1,76 @@
+package model_test
+
+import (
+	"main/src/lists/domain/model"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type ReadingListModelSuite struct {
+	suite.Suite
+	list model.ReadingList
+}
+
+func (s *ReadingListModelSuite) SetupTest() {
+	added := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	s.list = model.ReadingList{
+		ID:         "123e4567-e89b-12d3-a456-426614174000",
+		OwnerID:    "user-1",
+		Name:       "To read",
+		Visibility: model.VisibilityPrivate,
+		Entries: []model.ListEntry{
+			{BookID: "book-a", AddedAt: added},
+			{BookID: "book-b", AddedAt: added},
+			{BookID: "book-c", AddedAt: added},
+		},
+	}
+}
+
+func (s *ReadingListModelSuite) TestValidate() {
+	s.Nil(s.list.Validate())
+
+	invalid := s.list
+	invalid.Visibility = "friends"
+	s.NotNil(invalid.Validate())
+
+	invalid = s.list
+	invalid.Name = " "
+	s.NotNil(invalid.Validate())
+}
+
+func (s *ReadingListModelSuite) TestCanView() {
+	s.True(s.list.CanView("user-1"))
+	s.False(s.list.CanView("user-2"))
+	s.False(s.list.CanView(""))
+
+	s.list.Visibility = model.VisibilityPublic
+	s.True(s.list.CanView(""))
+}
+
+func (s *ReadingListModelSuite) TestAddEntry() {
+	s.NotNil(s.list.AddEntry("book-a", time.Now()))
+	s.Nil(s.list.AddEntry("book-d", time.Now()))
+	s.Equal([]string{"book-a", "book-b", "book-c", "book-d"}, s.list.BookIDs())
+}
+
+func (s *ReadingListModelSuite) TestRemoveEntry() {
+	s.Nil(s.list.RemoveEntry("book-b"))
+	s.Equal([]string{"book-a", "book-c"}, s.list.BookIDs())
+	s.NotNil(s.list.RemoveEntry("book-b"))
+}
+
+func (s *ReadingListModelSuite) TestReorder() {
+	s.Nil(s.list.Reorder([]string{"book-c", "book-a", "book-b"}))
+	s.Equal([]string{"book-c", "book-a", "book-b"}, s.list.BookIDs())
+
+	s.NotNil(s.list.Reorder([]string{"book-c", "book-a"}))
+	s.NotNil(s.list.Reorder([]string{"book-c", "book-c", "book-a"}))
+	s.NotNil(s.list.Reorder([]string{"book-c", "book-a", "book-x"}))
+	s.Equal([]string{"book-c", "book-a", "book-b"}, s.list.BookIDs(), "Failed reorders leave the list untouched")
+}
+
+func TestReadingListModelSuite(t *testing.T) {
+	suite.Run(t, new(ReadingListModelSuite))
+}
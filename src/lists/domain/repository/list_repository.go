@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"main/src/lists/domain/model"
+	appError "main/utils/error"
+)
+
+type ListRepository interface {
+	CreateList(*model.ReadingList) *appError.Error
+	GetListByID(string) (*model.ReadingList, *appError.Error)
+	GetListsByOwnerID(string) ([]model.ReadingList, *appError.Error)
+	UpdateList(*model.ReadingList, int) *appError.Error
+	DeleteList(string) *appError.Error
+}
@@ -0,0 +1,161 @@
+package adapter
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"main/src/lists/domain/model"
+	appError "main/utils/error"
+)
+
+const ListsOwnerIndex = "owner_id-index"
+
+type ListDynamoDBRepository struct {
+	ctx    context.Context
+	client *dynamodb.Client
+	table  string
+}
+
+func NewListDynamoDBRepository(ctx context.Context, client *dynamodb.Client, table string) *ListDynamoDBRepository {
+	return &ListDynamoDBRepository{
+		ctx:    ctx,
+		client: client,
+		table:  table,
+	}
+}
+
+func listKey(id string) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		"ID": &types.AttributeValueMemberS{Value: id},
+	}
+}
+
+func (r *ListDynamoDBRepository) CreateList(list *model.ReadingList) *appError.Error {
+	av, err := attributevalue.MarshalMap(list)
+	if err != nil {
+		log.Printf("Error marshaling list: %v, list: %+v", err, list)
+		return appError.NewUnexpectedError(err.Error())
+	}
+
+	_, err = r.client.PutItem(r.ctx, &dynamodb.PutItemInput{
+		Item:      av,
+		TableName: aws.String(r.table),
+	})
+	if err != nil {
+		log.Printf("Error putting list in DynamoDB: %v, table: %s", err, r.table)
+		return appError.NewUnexpectedError(err.Error())
+	}
+
+	log.Printf("List creation completed successfully, list: %+v", list)
+	return nil
+}
+
+func (r *ListDynamoDBRepository) GetListByID(id string) (*model.ReadingList, *appError.Error) {
+	result, err := r.client.GetItem(r.ctx, &dynamodb.GetItemInput{
+		Key:       listKey(id),
+		TableName: aws.String(r.table),
+	})
+	if err != nil {
+		log.Printf("Error getting list from DynamoDB: %v, table: %s", err, r.table)
+		return &model.ReadingList{}, appError.NewUnexpectedError(err.Error())
+	}
+	if result.Item == nil {
+		log.Println("No list found with ID:", id)
+		return &model.ReadingList{}, nil // No error but no data
+	}
+
+	var list model.ReadingList
+	if err := attributevalue.UnmarshalMap(result.Item, &list); err != nil {
+		log.Printf("Error unmarshaling list from DynamoDB: %v, item: %+v", err, result.Item)
+		return &model.ReadingList{}, appError.NewUnexpectedError(err.Error())
+	}
+	return &list, nil
+}
+
+func (r *ListDynamoDBRepository) GetListsByOwnerID(ownerID string) ([]model.ReadingList, *appError.Error) {
+	keyCond := expression.Key("owner_id").Equal(expression.Value(ownerID))
+	expr, err := expression.NewBuilder().WithKeyCondition(keyCond).Build()
+	if err != nil {
+		log.Printf("Error building expression for lists query: %v, owner_id: %s", err, ownerID)
+		return nil, appError.NewUnexpectedError(err.Error())
+	}
+
+	lists := []model.ReadingList{}
+	paginator := dynamodb.NewQueryPaginator(r.client, &dynamodb.QueryInput{
+		TableName:                 aws.String(r.table),
+		IndexName:                 aws.String(ListsOwnerIndex),
+		KeyConditionExpression:    expr.KeyCondition(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(r.ctx)
+		if err != nil {
+			log.Printf("Error querying lists: %v, table: %s", err, r.table)
+			return nil, appError.NewUnexpectedError(err.Error())
+		}
+		var pageLists []model.ReadingList
+		if err := attributevalue.UnmarshalListOfMaps(page.Items, &pageLists); err != nil {
+			log.Printf("Error unmarshaling lists from DynamoDB: %v", err)
+			return nil, appError.NewUnexpectedError(err.Error())
+		}
+		lists = append(lists, pageLists...)
+	}
+	log.Printf("Retrieved %d lists, owner_id: %s", len(lists), ownerID)
+	return lists, nil
+}
+
+func (r *ListDynamoDBRepository) UpdateList(list *model.ReadingList, expectedVersion int) *appError.Error {
+	av, err := attributevalue.MarshalMap(list)
+	if err != nil {
+		log.Printf("Error marshaling list: %v, list: %+v", err, list)
+		return appError.NewUnexpectedError(err.Error())
+	}
+	expr, err := expression.NewBuilder().
+		WithCondition(expression.Name("version").Equal(expression.Value(expectedVersion))).
+		Build()
+	if err != nil {
+		log.Printf("Error building expression for list update: %v, ID: %s", err, list.ID)
+		return appError.NewUnexpectedError(err.Error())
+	}
+
+	_, err = r.client.PutItem(r.ctx, &dynamodb.PutItemInput{
+		Item:                      av,
+		TableName:                 aws.String(r.table),
+		ConditionExpression:       expr.Condition(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+	})
+	if err != nil {
+		var conditional *types.ConditionalCheckFailedException
+		if errors.As(err, &conditional) {
+			log.Printf("List was modified concurrently, ID: %s, expected version: %d", list.ID, expectedVersion)
+			return appError.NewConflictError("List was modified concurrently, please retry.")
+		}
+		log.Printf("Error updating list in DynamoDB: %v, table: %s", err, r.table)
+		return appError.NewUnexpectedError(err.Error())
+	}
+
+	log.Printf("Updated list successfully, ID: %s, version: %d", list.ID, list.Version)
+	return nil
+}
+
+func (r *ListDynamoDBRepository) DeleteList(id string) *appError.Error {
+	_, err := r.client.DeleteItem(r.ctx, &dynamodb.DeleteItemInput{
+		Key:       listKey(id),
+		TableName: aws.String(r.table),
+	})
+	if err != nil {
+		log.Printf("Error deleting list from DynamoDB: %v, table: %s", err, r.table)
+		return appError.NewUnexpectedError(err.Error())
+	}
+
+	log.Printf("Deleted list successfully, ID: %s", id)
+	return nil
+}
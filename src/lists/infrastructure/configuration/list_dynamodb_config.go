@@ -0,0 +1,16 @@
+package configuration
+
+import (
+	"log"
+	"os"
+)
+
+func GetDynamoDBListTable() string {
+	tableName := os.Getenv("LISTS_TABLE")
+	if tableName == "" {
+		log.Printf("Local DynamoDB Database")
+		return "Test_List_Table"
+	}
+	log.Printf("AWS DynamoDB Database: %s", tableName)
+	return tableName
+}
@@ -0,0 +1,95 @@
+package handler
+
+import (
+	"context"
+
+	bookHandler "main/src/books/application/handler"
+	bookConfiguration "main/src/books/infrastructure/configuration"
+	"main/src/loans/application/service"
+	"main/src/loans/domain/model"
+	"main/src/loans/infrastructure/adapter"
+	"main/src/loans/infrastructure/configuration"
+	appError "main/utils/error"
+)
+
+type MicroAWSLoanDynamoDB struct {
+	Ctx         context.Context
+	CopiesTable string
+	LoansTable  string
+	HoldsTable  string
+	BooksTable  string
+}
+
+func (micro *MicroAWSLoanDynamoDB) loanService() (service.LoanService, *appError.Error) {
+	dynamoClient, err := bookHandler.DefaultBookContainer().DynamoDBClient(micro.Ctx)
+	if err != nil {
+		return nil, err
+	}
+	if micro.CopiesTable == "" {
+		micro.CopiesTable = configuration.GetDynamoDBCopyTable()
+	}
+	if micro.LoansTable == "" {
+		micro.LoansTable = configuration.GetDynamoDBLoanTable()
+	}
+	if micro.HoldsTable == "" {
+		micro.HoldsTable = configuration.GetDynamoDBHoldTable()
+	}
+	if micro.BooksTable == "" {
+		micro.BooksTable = bookConfiguration.GetDynamoDBBookTable()
+	}
+	loanInfrastructure := adapter.NewLoanDynamoDBRepository(micro.Ctx, dynamoClient, micro.CopiesTable, micro.LoansTable, micro.HoldsTable)
+	bookMicro := &bookHandler.MicroAWSBookDynamoDB{Ctx: micro.Ctx, TableName: micro.BooksTable}
+	bookInfrastructure, errBooks := bookHandler.DefaultBookContainer().BookRepository(bookMicro, false)
+	if errBooks != nil {
+		return nil, errBooks
+	}
+	return service.NewLoanServiceDynamoDB(loanInfrastructure, bookInfrastructure, configuration.GetLoanPolicy()), nil
+}
+
+func (micro *MicroAWSLoanDynamoDB) AddCopies(bookID string, count int) ([]model.BookCopy, *appError.Error) {
+	loanService, err := micro.loanService()
+	if err != nil {
+		return nil, err
+	}
+	return loanService.AddCopies(bookID, count)
+}
+
+func (micro *MicroAWSLoanDynamoDB) Checkout(bookID, patronID string) (*model.Loan, *appError.Error) {
+	loanService, err := micro.loanService()
+	if err != nil {
+		return nil, err
+	}
+	return loanService.Checkout(bookID, patronID)
+}
+
+func (micro *MicroAWSLoanDynamoDB) Renew(loanID string) (*model.Loan, *appError.Error) {
+	loanService, err := micro.loanService()
+	if err != nil {
+		return nil, err
+	}
+	return loanService.Renew(loanID)
+}
+
+func (micro *MicroAWSLoanDynamoDB) Return(loanID string) (*model.Loan, *appError.Error) {
+	loanService, err := micro.loanService()
+	if err != nil {
+		return nil, err
+	}
+	return loanService.Return(loanID)
+}
+
+func (micro *MicroAWSLoanDynamoDB) PlaceHold(bookID, patronID string) (*model.Hold, *appError.Error) {
+	loanService, err := micro.loanService()
+	if err != nil {
+		return nil, err
+	}
+	return loanService.PlaceHold(bookID, patronID)
+}
+
+func (micro *MicroAWSLoanDynamoDB) GetOverdueLoans() ([]model.Loan, *appError.Error) {
+	loanService, err := micro.loanService()
+	if err != nil {
+		return nil, err
+	}
+	return loanService.GetOverdueLoans()
+}
@@ -0,0 +1,15 @@
+package service
+
+import (
+	"main/src/loans/domain/model"
+	appError "main/utils/error"
+)
+
+type LoanService interface {
+	AddCopies(string, int) ([]model.BookCopy, *appError.Error)
+	Checkout(string, string) (*model.Loan, *appError.Error)
+	Renew(string) (*model.Loan, *appError.Error)
+	Return(string) (*model.Loan, *appError.Error)
+	PlaceHold(string, string) (*model.Hold, *appError.Error)
+	GetOverdueLoans() ([]model.Loan, *appError.Error)
+}
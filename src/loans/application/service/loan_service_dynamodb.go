@@ -0,0 +1,257 @@
+package service
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	bookRepository "main/src/books/domain/repository"
+	"main/src/loans/domain/model"
+	"main/src/loans/domain/repository"
+	appError "main/utils/error"
+	"main/utils/lib"
+)
+
+const maxCopiesPerRequest = 100
+
+type LoanServiceDynamoDB struct {
+	repo     repository.LoanRepository
+	bookRepo bookRepository.BookRepository
+	policy   model.LoanPolicy
+}
+
+func NewLoanServiceDynamoDB(repo repository.LoanRepository, bookRepo bookRepository.BookRepository, policy model.LoanPolicy) LoanService {
+	return &LoanServiceDynamoDB{
+		repo:     repo,
+		bookRepo: bookRepo,
+		policy:   policy,
+	}
+}
+
+// now is truncated to whole seconds so stored timestamps sort lexicographically.
+func now() time.Time {
+	return time.Now().UTC().Truncate(time.Second)
+}
+
+func (service *LoanServiceDynamoDB) AddCopies(bookID string, count int) ([]model.BookCopy, *appError.Error) {
+	if err := lib.ValidateUUID(bookID); err != nil {
+		return nil, err
+	}
+	if count < 1 || count > maxCopiesPerRequest {
+		return nil, appError.NewValidationError("Copies count must be between 1 and 100.")
+	}
+	book, err := service.bookRepo.GetBookByID(bookID)
+	if err != nil {
+		return nil, err
+	}
+	if book.ID == "" {
+		return nil, appError.NewNotFoundError("Book " + bookID + " not found.")
+	}
+	holds, err := service.repo.GetWaitingHoldsByBookID(bookID)
+	if err != nil {
+		return nil, err
+	}
+
+	copies := make([]model.BookCopy, count)
+	for i := range copies {
+		copies[i] = model.BookCopy{
+			ID:     uuid.NewString(),
+			BookID: bookID,
+			Status: model.CopyStatusAvailable,
+		}
+	}
+
+	// New copies serve the holds queue first, oldest hold first. Only the
+	// copies left over once nobody is waiting go on the shelf.
+	var shelved []model.BookCopy
+	for i := range copies {
+		held, err := service.offerCopy(&copies[i], &holds)
+		if err != nil {
+			return nil, err
+		}
+		if !held {
+			shelved = append(shelved, copies[i])
+		}
+	}
+	if len(shelved) > 0 {
+		if err := service.repo.CreateCopies(shelved); err != nil {
+			return nil, err
+		}
+	}
+	return copies, nil
+}
+
+// offerCopy stores bookCopy on hold for the oldest hold still waiting in
+// holds, moving past holds another request has already served. It reports
+// false when no hold is left and the copy was not stored.
+func (service *LoanServiceDynamoDB) offerCopy(bookCopy *model.BookCopy, holds *[]model.Hold) (bool, *appError.Error) {
+	for len(*holds) > 0 {
+		next := (*holds)[0]
+		*holds = (*holds)[1:]
+
+		readyAt := now()
+		next.Status = model.HoldStatusReady
+		next.CopyID = bookCopy.ID
+		next.ReadyAt = &readyAt
+		held := *bookCopy
+		held.Status = model.CopyStatusOnHold
+		held.HeldFor = next.PatronID
+		held.HoldID = next.ID
+
+		err := service.repo.CreateHeldCopy(&held, &next)
+		if err == nil {
+			*bookCopy = held
+			return true, nil
+		}
+		// The hold is no longer waiting, offer the copy to the next one.
+		if err.Code != http.StatusConflict {
+			return false, err
+		}
+	}
+	return false, nil
+}
+
+func (service *LoanServiceDynamoDB) Checkout(bookID, patronID string) (*model.Loan, *appError.Error) {
+	if err := lib.ValidateUUID(bookID); err != nil {
+		return nil, err
+	}
+	if err := model.ValidatePatronID(patronID); err != nil {
+		return nil, err
+	}
+	copies, err := service.repo.GetCopiesByBookID(bookID)
+	if err != nil {
+		return nil, err
+	}
+
+	// A copy held for the patron takes precedence over any available copy.
+	var candidates []model.BookCopy
+	for _, c := range copies {
+		if c.Status == model.CopyStatusOnHold && c.IsAvailableFor(patronID) {
+			candidates = append([]model.BookCopy{c}, candidates...)
+		} else if c.Status == model.CopyStatusAvailable {
+			candidates = append(candidates, c)
+		}
+	}
+
+	checkedOutAt := now()
+	for i := range candidates {
+		loan := &model.Loan{
+			ID:           uuid.NewString(),
+			BookID:       bookID,
+			CopyID:       candidates[i].ID,
+			PatronID:     patronID,
+			Status:       model.LoanStatusActive,
+			CheckedOutAt: checkedOutAt,
+			DueAt:        checkedOutAt.Add(service.policy.LoanPeriod),
+		}
+		if err := loan.Validate(); err != nil {
+			return nil, err
+		}
+		err := service.repo.CheckoutCopy(&candidates[i], loan)
+		if err == nil {
+			return loan, nil
+		}
+		// Another patron took this copy first, try the next one.
+		if err.Code != http.StatusConflict {
+			return nil, err
+		}
+	}
+	return nil, appError.NewConflictError("No copies available, place a hold instead.")
+}
+
+func (service *LoanServiceDynamoDB) Renew(loanID string) (*model.Loan, *appError.Error) {
+	loan, err := service.getLoan(loanID)
+	if err != nil {
+		return nil, err
+	}
+	if err := loan.CanRenew(service.policy, now()); err != nil {
+		return nil, err
+	}
+	return service.repo.RenewLoan(loanID, loan.DueAt.Add(service.policy.LoanPeriod), service.policy.MaxRenewals)
+}
+
+func (service *LoanServiceDynamoDB) Return(loanID string) (*model.Loan, *appError.Error) {
+	loan, err := service.getLoan(loanID)
+	if err != nil {
+		return nil, err
+	}
+	if loan.Status != model.LoanStatusActive {
+		return nil, appError.NewConflictError("Loan has already been returned.")
+	}
+	holds, err := service.repo.GetWaitingHoldsByBookID(loan.BookID)
+	if err != nil {
+		return nil, err
+	}
+	var next *model.Hold
+	if len(holds) > 0 {
+		next = &holds[0]
+	}
+
+	returnedAt := now()
+	loan.Status = model.LoanStatusReturned
+	loan.ReturnedAt = &returnedAt
+	if err := service.repo.ReturnLoan(loan, next); err != nil {
+		return nil, err
+	}
+	return loan, nil
+}
+
+func (service *LoanServiceDynamoDB) PlaceHold(bookID, patronID string) (*model.Hold, *appError.Error) {
+	if err := lib.ValidateUUID(bookID); err != nil {
+		return nil, err
+	}
+	if err := model.ValidatePatronID(patronID); err != nil {
+		return nil, err
+	}
+	copies, err := service.repo.GetCopiesByBookID(bookID)
+	if err != nil {
+		return nil, err
+	}
+	if len(copies) == 0 {
+		return nil, appError.NewNotFoundError("Book has no copies to hold.")
+	}
+	for _, c := range copies {
+		if c.IsAvailableFor(patronID) {
+			return nil, appError.NewConflictError("A copy is available, check it out instead.")
+		}
+	}
+	holds, err := service.repo.GetWaitingHoldsByBookID(bookID)
+	if err != nil {
+		return nil, err
+	}
+	for _, h := range holds {
+		if h.PatronID == patronID {
+			return nil, appError.NewConflictError("Patron already has a hold on this book.")
+		}
+	}
+
+	hold := &model.Hold{
+		ID:       model.HoldID(bookID, patronID),
+		BookID:   bookID,
+		PatronID: patronID,
+		Status:   model.HoldStatusWaiting,
+		PlacedAt: now(),
+	}
+	if err := service.repo.CreateHold(hold); err != nil {
+		return nil, err
+	}
+	return hold, nil
+}
+
+func (service *LoanServiceDynamoDB) GetOverdueLoans() ([]model.Loan, *appError.Error) {
+	return service.repo.GetOverdueLoans(now())
+}
+
+func (service *LoanServiceDynamoDB) getLoan(loanID string) (*model.Loan, *appError.Error) {
+	if err := lib.ValidateUUID(loanID); err != nil {
+		return nil, err
+	}
+	loan, err := service.repo.GetLoanByID(loanID)
+	if err != nil {
+		return nil, err
+	}
+	if loan.ID == "" {
+		return nil, appError.NewNotFoundError("Loan " + loanID + " not found.")
+	}
+	return loan, nil
+}
@@ -0,0 +1,243 @@
+package service_test
+
+import (
+	"testing"
+	"time"
+
+	bookModel "main/src/books/domain/model"
+	"main/src/loans/application/service"
+	"main/src/loans/domain/model"
+	appError "main/utils/error"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	repoMock "main/mocks"
+)
+
+type LoanServiceDynamoDBSuite struct {
+	suite.Suite
+	loanRepository *repoMock.LoanRepository
+	bookRepository *repoMock.BookRepository
+	loanService    service.LoanService
+	policy         model.LoanPolicy
+	bookID         string
+}
+
+const (
+	MethodCreateCopies            = "CreateCopies"
+	MethodCreateHeldCopy          = "CreateHeldCopy"
+	MethodGetCopiesByBookID       = "GetCopiesByBookID"
+	MethodCheckoutCopy            = "CheckoutCopy"
+	MethodGetLoanByID             = "GetLoanByID"
+	MethodRenewLoan               = "RenewLoan"
+	MethodReturnLoan              = "ReturnLoan"
+	MethodGetOverdueLoans         = "GetOverdueLoans"
+	MethodCreateHold              = "CreateHold"
+	MethodGetWaitingHoldsByBookID = "GetWaitingHoldsByBookID"
+	MethodGetBookByID             = "GetBookByID"
+)
+
+func (suite *LoanServiceDynamoDBSuite) SetupTest() {
+	suite.loanRepository = new(repoMock.LoanRepository)
+	suite.policy = model.LoanPolicy{LoanPeriod: 14 * 24 * time.Hour, MaxRenewals: 1}
+	suite.bookRepository = new(repoMock.BookRepository)
+	suite.loanService = service.NewLoanServiceDynamoDB(suite.loanRepository, suite.bookRepository, suite.policy)
+	suite.bookID = uuid.NewString()
+}
+
+func (suite *LoanServiceDynamoDBSuite) TestAddCopies() {
+	suite.bookRepository.On(MethodGetBookByID, suite.bookID).Return(&bookModel.Book{ID: suite.bookID}, nil)
+	suite.loanRepository.On(MethodGetWaitingHoldsByBookID, suite.bookID).Return([]model.Hold{}, nil)
+	suite.loanRepository.On(MethodCreateCopies, mock.AnythingOfType("[]model.BookCopy")).Return(nil)
+	copies, err := suite.loanService.AddCopies(suite.bookID, 3)
+	suite.Nil(err)
+	suite.Len(copies, 3)
+	suite.Equal(model.CopyStatusAvailable, copies[0].Status)
+	suite.loanRepository.AssertExpectations(suite.T())
+}
+
+func (suite *LoanServiceDynamoDBSuite) TestAddCopiesBookNotFound() {
+	suite.bookRepository.On(MethodGetBookByID, suite.bookID).Return(&bookModel.Book{}, nil)
+
+	_, err := suite.loanService.AddCopies(suite.bookID, 3)
+	suite.NotNil(err)
+	suite.Equal(404, err.Code)
+	suite.loanRepository.AssertNotCalled(suite.T(), MethodCreateCopies, mock.Anything)
+}
+
+func (suite *LoanServiceDynamoDBSuite) TestAddCopiesServesWaitingHolds() {
+	holds := []model.Hold{
+		{ID: uuid.NewString(), BookID: suite.bookID, PatronID: "patron-2", Status: model.HoldStatusWaiting},
+		{ID: uuid.NewString(), BookID: suite.bookID, PatronID: "patron-3", Status: model.HoldStatusWaiting},
+	}
+	suite.bookRepository.On(MethodGetBookByID, suite.bookID).Return(&bookModel.Book{ID: suite.bookID}, nil)
+	suite.loanRepository.On(MethodGetWaitingHoldsByBookID, suite.bookID).Return(holds, nil)
+	// A concurrent return already served the first hold.
+	suite.loanRepository.On(MethodCreateHeldCopy, mock.Anything, mock.MatchedBy(func(h *model.Hold) bool { return h.ID == holds[0].ID })).
+		Return(appError.NewConflictError("Loan state changed concurrently, please retry."))
+	suite.loanRepository.On(MethodCreateHeldCopy, mock.Anything, mock.MatchedBy(func(h *model.Hold) bool {
+		return h.ID == holds[1].ID && h.Status == model.HoldStatusReady && h.ReadyAt != nil
+	})).Return(nil)
+	suite.loanRepository.On(MethodCreateCopies, mock.MatchedBy(func(c []model.BookCopy) bool { return len(c) == 2 })).Return(nil)
+
+	copies, err := suite.loanService.AddCopies(suite.bookID, 3)
+	suite.Nil(err)
+	suite.Len(copies, 3)
+	suite.Equal(model.CopyStatusOnHold, copies[0].Status)
+	suite.Equal("patron-3", copies[0].HeldFor)
+	suite.Equal(holds[1].ID, copies[0].HoldID)
+	suite.Equal(model.CopyStatusAvailable, copies[1].Status)
+	suite.Equal(model.CopyStatusAvailable, copies[2].Status)
+	suite.loanRepository.AssertExpectations(suite.T())
+}
+
+func (suite *LoanServiceDynamoDBSuite) TestAddCopiesInvalidCount() {
+	_, err := suite.loanService.AddCopies(suite.bookID, 0)
+	suite.NotNil(err)
+	suite.loanRepository.AssertNotCalled(suite.T(), MethodCreateCopies, mock.Anything)
+}
+
+func (suite *LoanServiceDynamoDBSuite) TestCheckoutRetriesNextCopyOnConflict() {
+	copies := []model.BookCopy{
+		{ID: uuid.NewString(), BookID: suite.bookID, Status: model.CopyStatusAvailable},
+		{ID: uuid.NewString(), BookID: suite.bookID, Status: model.CopyStatusAvailable},
+	}
+	suite.loanRepository.On(MethodGetCopiesByBookID, suite.bookID).Return(copies, nil)
+	suite.loanRepository.On(MethodCheckoutCopy, mock.MatchedBy(func(c *model.BookCopy) bool { return c.ID == copies[0].ID }), mock.Anything).
+		Return(appError.NewConflictError("taken"))
+	suite.loanRepository.On(MethodCheckoutCopy, mock.MatchedBy(func(c *model.BookCopy) bool { return c.ID == copies[1].ID }), mock.Anything).
+		Return(nil)
+
+	loan, err := suite.loanService.Checkout(suite.bookID, "patron-1")
+	suite.Nil(err)
+	suite.Equal(copies[1].ID, loan.CopyID)
+	suite.Equal(model.LoanStatusActive, loan.Status)
+	suite.Equal(suite.policy.LoanPeriod, loan.DueAt.Sub(loan.CheckedOutAt))
+	suite.loanRepository.AssertExpectations(suite.T())
+}
+
+func (suite *LoanServiceDynamoDBSuite) TestCheckoutPrefersHeldCopy() {
+	held := model.BookCopy{ID: uuid.NewString(), BookID: suite.bookID, Status: model.CopyStatusOnHold, HeldFor: "patron-1", HoldID: uuid.NewString()}
+	copies := []model.BookCopy{
+		{ID: uuid.NewString(), BookID: suite.bookID, Status: model.CopyStatusAvailable},
+		held,
+	}
+	suite.loanRepository.On(MethodGetCopiesByBookID, suite.bookID).Return(copies, nil)
+	suite.loanRepository.On(MethodCheckoutCopy, mock.MatchedBy(func(c *model.BookCopy) bool { return c.ID == held.ID }), mock.Anything).Return(nil)
+
+	loan, err := suite.loanService.Checkout(suite.bookID, "patron-1")
+	suite.Nil(err)
+	suite.Equal(held.ID, loan.CopyID)
+	suite.loanRepository.AssertExpectations(suite.T())
+}
+
+func (suite *LoanServiceDynamoDBSuite) TestCheckoutNoCopiesAvailable() {
+	copies := []model.BookCopy{
+		{ID: uuid.NewString(), BookID: suite.bookID, Status: model.CopyStatusOnLoan},
+		{ID: uuid.NewString(), BookID: suite.bookID, Status: model.CopyStatusOnHold, HeldFor: "patron-2"},
+	}
+	suite.loanRepository.On(MethodGetCopiesByBookID, suite.bookID).Return(copies, nil)
+
+	_, err := suite.loanService.Checkout(suite.bookID, "patron-1")
+	suite.NotNil(err)
+	suite.Equal(409, err.Code)
+	suite.loanRepository.AssertNotCalled(suite.T(), MethodCheckoutCopy, mock.Anything, mock.Anything)
+}
+
+func (suite *LoanServiceDynamoDBSuite) TestRenew() {
+	loan := &model.Loan{ID: uuid.NewString(), Status: model.LoanStatusActive, DueAt: time.Now().Add(time.Hour)}
+	renewed := *loan
+	renewed.Renewals = 1
+	suite.loanRepository.On(MethodGetLoanByID, loan.ID).Return(loan, nil)
+	suite.loanRepository.On(MethodRenewLoan, loan.ID, loan.DueAt.Add(suite.policy.LoanPeriod), suite.policy.MaxRenewals).Return(&renewed, nil)
+
+	result, err := suite.loanService.Renew(loan.ID)
+	suite.Nil(err)
+	suite.Equal(1, result.Renewals)
+	suite.loanRepository.AssertExpectations(suite.T())
+}
+
+func (suite *LoanServiceDynamoDBSuite) TestRenewLimitReached() {
+	loan := &model.Loan{ID: uuid.NewString(), Status: model.LoanStatusActive, DueAt: time.Now().Add(time.Hour), Renewals: 1}
+	suite.loanRepository.On(MethodGetLoanByID, loan.ID).Return(loan, nil)
+
+	_, err := suite.loanService.Renew(loan.ID)
+	suite.NotNil(err)
+	suite.loanRepository.AssertNotCalled(suite.T(), MethodRenewLoan, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *LoanServiceDynamoDBSuite) TestReturnAssignsNextHold() {
+	loan := &model.Loan{ID: uuid.NewString(), BookID: suite.bookID, CopyID: uuid.NewString(), Status: model.LoanStatusActive}
+	holds := []model.Hold{
+		{ID: uuid.NewString(), BookID: suite.bookID, PatronID: "patron-2", Status: model.HoldStatusWaiting},
+		{ID: uuid.NewString(), BookID: suite.bookID, PatronID: "patron-3", Status: model.HoldStatusWaiting},
+	}
+	suite.loanRepository.On(MethodGetLoanByID, loan.ID).Return(loan, nil)
+	suite.loanRepository.On(MethodGetWaitingHoldsByBookID, suite.bookID).Return(holds, nil)
+	suite.loanRepository.On(MethodReturnLoan, loan, mock.MatchedBy(func(h *model.Hold) bool { return h.ID == holds[0].ID })).Return(nil)
+
+	result, err := suite.loanService.Return(loan.ID)
+	suite.Nil(err)
+	suite.Equal(model.LoanStatusReturned, result.Status)
+	suite.NotNil(result.ReturnedAt)
+	suite.loanRepository.AssertExpectations(suite.T())
+}
+
+func (suite *LoanServiceDynamoDBSuite) TestReturnNotFound() {
+	loanID := uuid.NewString()
+	suite.loanRepository.On(MethodGetLoanByID, loanID).Return(&model.Loan{}, nil)
+
+	_, err := suite.loanService.Return(loanID)
+	suite.NotNil(err)
+	suite.Equal(404, err.Code)
+}
+
+func (suite *LoanServiceDynamoDBSuite) TestPlaceHold() {
+	copies := []model.BookCopy{{ID: uuid.NewString(), BookID: suite.bookID, Status: model.CopyStatusOnLoan}}
+	suite.loanRepository.On(MethodGetCopiesByBookID, suite.bookID).Return(copies, nil)
+	suite.loanRepository.On(MethodGetWaitingHoldsByBookID, suite.bookID).Return([]model.Hold{}, nil)
+	suite.loanRepository.On(MethodCreateHold, mock.AnythingOfType("*model.Hold")).Return(nil)
+
+	hold, err := suite.loanService.PlaceHold(suite.bookID, "patron-1")
+	suite.Nil(err)
+	suite.Equal(model.HoldStatusWaiting, hold.Status)
+	suite.Equal(model.HoldID(suite.bookID, "patron-1"), hold.ID)
+	suite.loanRepository.AssertExpectations(suite.T())
+}
+
+func (suite *LoanServiceDynamoDBSuite) TestPlaceHoldLosesTheRace() {
+	copies := []model.BookCopy{{ID: uuid.NewString(), BookID: suite.bookID, Status: model.CopyStatusOnLoan}}
+	suite.loanRepository.On(MethodGetCopiesByBookID, suite.bookID).Return(copies, nil)
+	suite.loanRepository.On(MethodGetWaitingHoldsByBookID, suite.bookID).Return([]model.Hold{}, nil)
+	suite.loanRepository.On(MethodCreateHold, mock.AnythingOfType("*model.Hold")).
+		Return(appError.NewConflictError("Patron already has a hold on this book."))
+
+	_, err := suite.loanService.PlaceHold(suite.bookID, "patron-1")
+	suite.NotNil(err)
+	suite.Equal(409, err.Code)
+}
+
+func (suite *LoanServiceDynamoDBSuite) TestPlaceHoldDuplicate() {
+	copies := []model.BookCopy{{ID: uuid.NewString(), BookID: suite.bookID, Status: model.CopyStatusOnLoan}}
+	holds := []model.Hold{{ID: uuid.NewString(), BookID: suite.bookID, PatronID: "patron-1", Status: model.HoldStatusWaiting}}
+	suite.loanRepository.On(MethodGetCopiesByBookID, suite.bookID).Return(copies, nil)
+	suite.loanRepository.On(MethodGetWaitingHoldsByBookID, suite.bookID).Return(holds, nil)
+
+	_, err := suite.loanService.PlaceHold(suite.bookID, "patron-1")
+	suite.NotNil(err)
+	suite.loanRepository.AssertNotCalled(suite.T(), MethodCreateHold, mock.Anything)
+}
+
+func (suite *LoanServiceDynamoDBSuite) TestGetOverdueLoans() {
+	suite.loanRepository.On(MethodGetOverdueLoans, mock.AnythingOfType("time.Time")).Return([]model.Loan{{ID: uuid.NewString()}}, nil)
+	loans, err := suite.loanService.GetOverdueLoans()
+	suite.Nil(err)
+	suite.Len(loans, 1)
+	suite.loanRepository.AssertExpectations(suite.T())
+}
+
+func TestLoanServiceDynamoDBSuite(t *testing.T) {
+	suite.Run(t, new(LoanServiceDynamoDBSuite))
+}
@@ -0,0 +1,30 @@
+package model
+
+type CopyStatus string
+
+const (
+	CopyStatusAvailable CopyStatus = "available"
+	CopyStatusOnLoan    CopyStatus = "on_loan"
+	CopyStatusOnHold    CopyStatus = "on_hold"
+)
+
+// BookCopy is a physical copy of a catalog book. A copy that is on hold is
+// reserved for the patron at the head of the holds queue.
+type BookCopy struct {
+	ID      string     `json:"ID,omitempty" dynamodbav:"ID,omitempty"`
+	BookID  string     `json:"book_id,omitempty" dynamodbav:"book_id,omitempty"`
+	Status  CopyStatus `json:"status,omitempty" dynamodbav:"status,omitempty"`
+	LoanID  string     `json:"loan_id,omitempty" dynamodbav:"loan_id,omitempty"`
+	HoldID  string     `json:"hold_id,omitempty" dynamodbav:"hold_id,omitempty"`
+	HeldFor string     `json:"held_for,omitempty" dynamodbav:"held_for,omitempty"`
+}
+
+func (c *BookCopy) IsAvailableFor(patronID string) bool {
+	switch c.Status {
+	case CopyStatusAvailable:
+		return true
+	case CopyStatusOnHold:
+		return c.HeldFor == patronID
+	}
+	return false
+}
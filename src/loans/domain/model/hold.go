@@ -0,0 +1,33 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type HoldStatus string
+
+const (
+	HoldStatusWaiting   HoldStatus = "waiting"
+	HoldStatusReady     HoldStatus = "ready"
+	HoldStatusFulfilled HoldStatus = "fulfilled"
+)
+
+// Hold is a patron's place in the FIFO queue for a book. Holds are ordered by
+// PlacedAt; when a copy is returned it is assigned to the oldest waiting hold.
+type Hold struct {
+	ID       string     `json:"ID,omitempty" dynamodbav:"ID,omitempty"`
+	BookID   string     `json:"book_id,omitempty" dynamodbav:"book_id,omitempty"`
+	PatronID string     `json:"patron_id,omitempty" dynamodbav:"patron_id,omitempty"`
+	Status   HoldStatus `json:"status,omitempty" dynamodbav:"status,omitempty"`
+	CopyID   string     `json:"copy_id,omitempty" dynamodbav:"copy_id,omitempty"`
+	PlacedAt time.Time  `json:"placed_at" dynamodbav:"placed_at"`
+	ReadyAt  *time.Time `json:"ready_at,omitempty" dynamodbav:"ready_at,omitempty"`
+}
+
+// HoldID is derived from the book and the patron, so two holds placed at once
+// by the same patron land on the same item and only one of them is kept.
+func HoldID(bookID, patronID string) string {
+	return uuid.NewSHA1(uuid.NameSpaceURL, []byte(bookID+"/"+patronID)).String()
+}
@@ -0,0 +1,75 @@
+package model
+
+import (
+	appError "main/utils/error"
+	"main/utils/lib"
+	"time"
+)
+
+type LoanStatus string
+
+const (
+	LoanStatusActive   LoanStatus = "active"
+	LoanStatusReturned LoanStatus = "returned"
+)
+
+type Loan struct {
+	ID           string     `json:"ID,omitempty" dynamodbav:"ID,omitempty"`
+	BookID       string     `json:"book_id,omitempty" dynamodbav:"book_id,omitempty"`
+	CopyID       string     `json:"copy_id,omitempty" dynamodbav:"copy_id,omitempty"`
+	PatronID     string     `json:"patron_id,omitempty" dynamodbav:"patron_id,omitempty"`
+	Status       LoanStatus `json:"status,omitempty" dynamodbav:"status,omitempty"`
+	CheckedOutAt time.Time  `json:"checked_out_at" dynamodbav:"checked_out_at"`
+	DueAt        time.Time  `json:"due_at" dynamodbav:"due_at"`
+	ReturnedAt   *time.Time `json:"returned_at,omitempty" dynamodbav:"returned_at,omitempty"`
+	Renewals     int        `json:"renewals" dynamodbav:"renewals"`
+}
+
+// LoanPolicy holds the lending rules applied by the loan service.
+type LoanPolicy struct {
+	LoanPeriod  time.Duration
+	MaxRenewals int
+}
+
+func (l *Loan) Validate() *appError.Error {
+	if err := lib.ValidateUUID(l.ID); err != nil {
+		return err
+	}
+	if err := lib.ValidateUUID(l.BookID); err != nil {
+		return err
+	}
+	if err := lib.ValidateUUID(l.CopyID); err != nil {
+		return err
+	}
+	if err := ValidatePatronID(l.PatronID); err != nil {
+		return err
+	}
+	if !l.DueAt.After(l.CheckedOutAt) {
+		return appError.NewValidationError("Due date must be after the checkout date.")
+	}
+	return nil
+}
+
+func (l *Loan) IsOverdue(now time.Time) bool {
+	return l.Status == LoanStatusActive && now.After(l.DueAt)
+}
+
+func (l *Loan) CanRenew(policy LoanPolicy, now time.Time) *appError.Error {
+	if l.Status != LoanStatusActive {
+		return appError.NewConflictError("Only active loans can be renewed.")
+	}
+	if l.IsOverdue(now) {
+		return appError.NewConflictError("Overdue loans cannot be renewed.")
+	}
+	if l.Renewals >= policy.MaxRenewals {
+		return appError.NewConflictError("Loan has reached the renewal limit.")
+	}
+	return nil
+}
+
+func ValidatePatronID(patronID string) *appError.Error {
+	if err := lib.ValidateStringNotEmpty(patronID); err != nil {
+		return appError.NewValidationError("Patron ID cannot be empty.")
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package model_test
+
+import (
+	"main/src/loans/domain/model"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type LoanModelSuite struct {
+	suite.Suite
+	now    time.Time
+	policy model.LoanPolicy
+}
+
+func (s *LoanModelSuite) SetupTest() {
+	s.now = time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	s.policy = model.LoanPolicy{LoanPeriod: 21 * 24 * time.Hour, MaxRenewals: 2}
+}
+
+func (s *LoanModelSuite) TestValidate() {
+	var tests = []struct {
+		name     string
+		loan     model.Loan
+		expected bool // true if no error is expected, false otherwise
+	}{
+		{"valid", model.Loan{ID: "123e4567-e89b-12d3-a456-426614174000", BookID: "123e4567-e89b-12d3-a456-426614174001", CopyID: "123e4567-e89b-12d3-a456-426614174002", PatronID: "patron-1", CheckedOutAt: s.now, DueAt: s.now.Add(time.Hour)}, true},
+		{"invalid_book", model.Loan{ID: "123e4567-e89b-12d3-a456-426614174000", BookID: "invalid", CopyID: "123e4567-e89b-12d3-a456-426614174002", PatronID: "patron-1", CheckedOutAt: s.now, DueAt: s.now.Add(time.Hour)}, false},
+		{"empty_patron", model.Loan{ID: "123e4567-e89b-12d3-a456-426614174000", BookID: "123e4567-e89b-12d3-a456-426614174001", CopyID: "123e4567-e89b-12d3-a456-426614174002", PatronID: " ", CheckedOutAt: s.now, DueAt: s.now.Add(time.Hour)}, false},
+		{"due_before_checkout", model.Loan{ID: "123e4567-e89b-12d3-a456-426614174000", BookID: "123e4567-e89b-12d3-a456-426614174001", CopyID: "123e4567-e89b-12d3-a456-426614174002", PatronID: "patron-1", CheckedOutAt: s.now, DueAt: s.now}, false},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			err := tt.loan.Validate()
+			if tt.expected {
+				s.Nil(err)
+			} else {
+				s.NotNil(err)
+			}
+		})
+	}
+}
+
+func (s *LoanModelSuite) TestIsOverdue() {
+	loan := model.Loan{Status: model.LoanStatusActive, DueAt: s.now}
+	s.False(loan.IsOverdue(s.now))
+	s.True(loan.IsOverdue(s.now.Add(time.Second)))
+
+	loan.Status = model.LoanStatusReturned
+	s.False(loan.IsOverdue(s.now.Add(time.Second)))
+}
+
+func (s *LoanModelSuite) TestCanRenew() {
+	loan := model.Loan{Status: model.LoanStatusActive, DueAt: s.now.Add(time.Hour), Renewals: 1}
+	s.Nil(loan.CanRenew(s.policy, s.now))
+
+	loan.Renewals = 2
+	s.NotNil(loan.CanRenew(s.policy, s.now))
+
+	loan.Renewals = 0
+	s.NotNil(loan.CanRenew(s.policy, s.now.Add(2*time.Hour)))
+
+	loan.Status = model.LoanStatusReturned
+	s.NotNil(loan.CanRenew(s.policy, s.now))
+}
+
+func (s *LoanModelSuite) TestCopyIsAvailableFor() {
+	s.True((&model.BookCopy{Status: model.CopyStatusAvailable}).IsAvailableFor("patron-1"))
+	s.True((&model.BookCopy{Status: model.CopyStatusOnHold, HeldFor: "patron-1"}).IsAvailableFor("patron-1"))
+	s.False((&model.BookCopy{Status: model.CopyStatusOnHold, HeldFor: "patron-2"}).IsAvailableFor("patron-1"))
+	s.False((&model.BookCopy{Status: model.CopyStatusOnLoan}).IsAvailableFor("patron-1"))
+}
+
+func TestLoanModelSuite(t *testing.T) {
+	suite.Run(t, new(LoanModelSuite))
+}
@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"main/src/loans/domain/model"
+	appError "main/utils/error"
+	"time"
+)
+
+type LoanRepository interface {
+	CreateCopies([]model.BookCopy) *appError.Error
+	CreateHeldCopy(*model.BookCopy, *model.Hold) *appError.Error
+	GetCopiesByBookID(string) ([]model.BookCopy, *appError.Error)
+	CheckoutCopy(*model.BookCopy, *model.Loan) *appError.Error
+	GetLoanByID(string) (*model.Loan, *appError.Error)
+	RenewLoan(string, time.Time, int) (*model.Loan, *appError.Error)
+	ReturnLoan(*model.Loan, *model.Hold) *appError.Error
+	GetOverdueLoans(time.Time) ([]model.Loan, *appError.Error)
+	CreateHold(*model.Hold) *appError.Error
+	GetWaitingHoldsByBookID(string) ([]model.Hold, *appError.Error)
+}
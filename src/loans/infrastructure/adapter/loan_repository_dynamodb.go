@@ -0,0 +1,557 @@
+package adapter
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"main/src/loans/domain/model"
+	appError "main/utils/error"
+	"main/utils/lib"
+)
+
+const (
+	CopiesBookIndex     = "book_id-index"
+	LoansStatusDueIndex = "status-due_at-index"
+	HoldsBookIndex      = "book_id-placed_at-index"
+
+	// Unprocessed copies are retried with backoff this many times.
+	maxBatchAttempts = 8
+	batchBackoffBase = 50 * time.Millisecond
+	batchBackoffMax  = 2 * time.Second
+)
+
+type LoanDynamoDBRepository struct {
+	ctx         context.Context
+	client      *dynamodb.Client
+	copiesTable string
+	loansTable  string
+	holdsTable  string
+}
+
+func NewLoanDynamoDBRepository(ctx context.Context, client *dynamodb.Client, copiesTable, loansTable, holdsTable string) *LoanDynamoDBRepository {
+	return &LoanDynamoDBRepository{
+		ctx:         ctx,
+		client:      client,
+		copiesTable: copiesTable,
+		loansTable:  loansTable,
+		holdsTable:  holdsTable,
+	}
+}
+
+func idKey(id string) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		"ID": &types.AttributeValueMemberS{Value: id},
+	}
+}
+
+// transactionError maps failed conditions to a conflict so callers can tell
+// a lost race apart from an infrastructure failure.
+func transactionError(err error) *appError.Error {
+	var canceled *types.TransactionCanceledException
+	var conditional *types.ConditionalCheckFailedException
+	if errors.As(err, &canceled) || errors.As(err, &conditional) {
+		return appError.NewConflictError("Loan state changed concurrently, please retry.")
+	}
+	return appError.NewUnexpectedError(err.Error())
+}
+
+func (r *LoanDynamoDBRepository) CreateCopies(copies []model.BookCopy) *appError.Error {
+	var writeRequests []types.WriteRequest
+	for _, c := range copies {
+		av, err := attributevalue.MarshalMap(c)
+		if err != nil {
+			log.Printf("Error while marshalling copy: %s, copy: %+v", err, c)
+			return appError.NewUnexpectedError(err.Error())
+		}
+		writeRequests = append(writeRequests, types.WriteRequest{
+			PutRequest: &types.PutRequest{Item: av},
+		})
+	}
+
+	const maxBatchSize = 25
+	for i := 0; i < len(writeRequests); i += maxBatchSize {
+		end := i + maxBatchSize
+		if end > len(writeRequests) {
+			end = len(writeRequests)
+		}
+		if err := r.writeCopies(writeRequests[i:end]); err != nil {
+			return err
+		}
+	}
+	log.Printf("Created %d copies successfully", len(copies))
+	return nil
+}
+
+// writeCopies sends one BatchWriteItem and retries the items DynamoDB leaves
+// unprocessed under throttling, failing once the attempts run out.
+func (r *LoanDynamoDBRepository) writeCopies(batch []types.WriteRequest) *appError.Error {
+	requestItems := map[string][]types.WriteRequest{
+		r.copiesTable: batch,
+	}
+	for attempt := 0; len(requestItems) > 0; attempt++ {
+		if attempt == maxBatchAttempts {
+			log.Printf("Copies still unprocessed after %d attempts, table: %s", attempt, r.copiesTable)
+			return appError.NewUnexpectedError("DynamoDB is throttling requests, copies were not written.")
+		}
+		if attempt > 0 {
+			if err := lib.Sleep(r.ctx, lib.Backoff(attempt-1, batchBackoffBase, batchBackoffMax)); err != nil {
+				log.Printf("Gave up retrying unprocessed copies: %s, table: %s", err, r.copiesTable)
+				return appError.NewUnexpectedError(err.Error())
+			}
+		}
+
+		result, err := r.client.BatchWriteItem(r.ctx, &dynamodb.BatchWriteItemInput{
+			RequestItems: requestItems,
+		})
+		if err != nil {
+			log.Printf("Error while batch writing copies: %s, batch size: %d", err, len(requestItems[r.copiesTable]))
+			return appError.NewUnexpectedError(err.Error())
+		}
+		requestItems = result.UnprocessedItems
+	}
+	return nil
+}
+
+func (r *LoanDynamoDBRepository) CreateHeldCopy(bookCopy *model.BookCopy, hold *model.Hold) *appError.Error {
+	copyItem, err := attributevalue.MarshalMap(bookCopy)
+	if err != nil {
+		log.Printf("Error marshaling copy: %v, copy: %+v", err, bookCopy)
+		return appError.NewUnexpectedError(err.Error())
+	}
+	copyExpr, err := expression.NewBuilder().WithCondition(expression.AttributeNotExists(expression.Name("ID"))).Build()
+	if err != nil {
+		log.Printf("Error building expression for copy: %v, copy: %s", err, bookCopy.ID)
+		return appError.NewUnexpectedError(err.Error())
+	}
+
+	// The new copy is only stored together with the hold it is offered to,
+	// the same way a returned copy goes to the head of the queue.
+	holdExpr, err := expression.NewBuilder().
+		WithCondition(expression.Name("status").Equal(expression.Value(model.HoldStatusWaiting))).
+		WithUpdate(expression.Set(
+			expression.Name("status"), expression.Value(hold.Status),
+		).Set(
+			expression.Name("copy_id"), expression.Value(hold.CopyID),
+		).Set(
+			expression.Name("ready_at"), expression.Value(hold.ReadyAt),
+		)).
+		Build()
+	if err != nil {
+		log.Printf("Error building expression for hold: %v, hold: %s", err, hold.ID)
+		return appError.NewUnexpectedError(err.Error())
+	}
+
+	items := []types.TransactWriteItem{
+		{
+			Put: &types.Put{
+				TableName:                aws.String(r.copiesTable),
+				Item:                     copyItem,
+				ConditionExpression:      copyExpr.Condition(),
+				ExpressionAttributeNames: copyExpr.Names(),
+			},
+		},
+		{
+			Update: &types.Update{
+				TableName:                 aws.String(r.holdsTable),
+				Key:                       idKey(hold.ID),
+				UpdateExpression:          holdExpr.Update(),
+				ConditionExpression:       holdExpr.Condition(),
+				ExpressionAttributeNames:  holdExpr.Names(),
+				ExpressionAttributeValues: holdExpr.Values(),
+			},
+		},
+	}
+
+	_, err = r.client.TransactWriteItems(r.ctx, &dynamodb.TransactWriteItemsInput{TransactItems: items})
+	if err != nil {
+		log.Printf("Error creating held copy: %v, copy: %s, hold: %s", err, bookCopy.ID, hold.ID)
+		return transactionError(err)
+	}
+
+	log.Printf("Created held copy successfully, copy: %s, hold: %s", bookCopy.ID, hold.ID)
+	return nil
+}
+
+func (r *LoanDynamoDBRepository) GetCopiesByBookID(bookID string) ([]model.BookCopy, *appError.Error) {
+	keyCond := expression.Key("book_id").Equal(expression.Value(bookID))
+	expr, err := expression.NewBuilder().WithKeyCondition(keyCond).Build()
+	if err != nil {
+		log.Printf("Error building expression for copies query: %v, book_id: %s", err, bookID)
+		return nil, appError.NewUnexpectedError(err.Error())
+	}
+
+	var copies []model.BookCopy
+	paginator := dynamodb.NewQueryPaginator(r.client, &dynamodb.QueryInput{
+		TableName:                 aws.String(r.copiesTable),
+		IndexName:                 aws.String(CopiesBookIndex),
+		KeyConditionExpression:    expr.KeyCondition(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(r.ctx)
+		if err != nil {
+			log.Printf("Error querying copies: %v, table: %s", err, r.copiesTable)
+			return nil, appError.NewUnexpectedError(err.Error())
+		}
+		var pageCopies []model.BookCopy
+		if err := attributevalue.UnmarshalListOfMaps(page.Items, &pageCopies); err != nil {
+			log.Printf("Error unmarshaling copies from DynamoDB: %v", err)
+			return nil, appError.NewUnexpectedError(err.Error())
+		}
+		copies = append(copies, pageCopies...)
+	}
+	return copies, nil
+}
+
+func (r *LoanDynamoDBRepository) CheckoutCopy(bookCopy *model.BookCopy, loan *model.Loan) *appError.Error {
+	// The copy must still be free (or held for this patron) when the write
+	// lands, otherwise two patrons could take the last copy at once.
+	copyCond := expression.Name("status").Equal(expression.Value(model.CopyStatusAvailable)).Or(
+		expression.Name("status").Equal(expression.Value(model.CopyStatusOnHold)).And(
+			expression.Name("held_for").Equal(expression.Value(loan.PatronID)),
+		),
+	)
+	copyUpdate := expression.Set(
+		expression.Name("status"), expression.Value(model.CopyStatusOnLoan),
+	).Set(
+		expression.Name("loan_id"), expression.Value(loan.ID),
+	).Remove(expression.Name("held_for")).Remove(expression.Name("hold_id"))
+	copyExpr, err := expression.NewBuilder().WithCondition(copyCond).WithUpdate(copyUpdate).Build()
+	if err != nil {
+		log.Printf("Error building expression for checkout: %v, copy: %s", err, bookCopy.ID)
+		return appError.NewUnexpectedError(err.Error())
+	}
+
+	loanItem, err := attributevalue.MarshalMap(loan)
+	if err != nil {
+		log.Printf("Error marshaling loan: %v, loan: %+v", err, loan)
+		return appError.NewUnexpectedError(err.Error())
+	}
+	loanExpr, err := expression.NewBuilder().WithCondition(expression.AttributeNotExists(expression.Name("ID"))).Build()
+	if err != nil {
+		log.Printf("Error building expression for loan: %v, loan: %s", err, loan.ID)
+		return appError.NewUnexpectedError(err.Error())
+	}
+
+	items := []types.TransactWriteItem{
+		{
+			Update: &types.Update{
+				TableName:                 aws.String(r.copiesTable),
+				Key:                       idKey(bookCopy.ID),
+				UpdateExpression:          copyExpr.Update(),
+				ConditionExpression:       copyExpr.Condition(),
+				ExpressionAttributeNames:  copyExpr.Names(),
+				ExpressionAttributeValues: copyExpr.Values(),
+			},
+		},
+		{
+			Put: &types.Put{
+				TableName:                aws.String(r.loansTable),
+				Item:                     loanItem,
+				ConditionExpression:      loanExpr.Condition(),
+				ExpressionAttributeNames: loanExpr.Names(),
+			},
+		},
+	}
+
+	if bookCopy.HoldID != "" {
+		holdExpr, err := expression.NewBuilder().
+			WithCondition(expression.Name("status").Equal(expression.Value(model.HoldStatusReady))).
+			WithUpdate(expression.Set(expression.Name("status"), expression.Value(model.HoldStatusFulfilled))).
+			Build()
+		if err != nil {
+			log.Printf("Error building expression for hold: %v, hold: %s", err, bookCopy.HoldID)
+			return appError.NewUnexpectedError(err.Error())
+		}
+		items = append(items, types.TransactWriteItem{
+			Update: &types.Update{
+				TableName:                 aws.String(r.holdsTable),
+				Key:                       idKey(bookCopy.HoldID),
+				UpdateExpression:          holdExpr.Update(),
+				ConditionExpression:       holdExpr.Condition(),
+				ExpressionAttributeNames:  holdExpr.Names(),
+				ExpressionAttributeValues: holdExpr.Values(),
+			},
+		})
+	}
+
+	_, err = r.client.TransactWriteItems(r.ctx, &dynamodb.TransactWriteItemsInput{TransactItems: items})
+	if err != nil {
+		log.Printf("Error checking out copy: %v, copy: %s, loan: %s", err, bookCopy.ID, loan.ID)
+		return transactionError(err)
+	}
+
+	log.Printf("Checked out copy successfully, copy: %s, loan: %+v", bookCopy.ID, loan)
+	return nil
+}
+
+func (r *LoanDynamoDBRepository) GetLoanByID(id string) (*model.Loan, *appError.Error) {
+	result, err := r.client.GetItem(r.ctx, &dynamodb.GetItemInput{
+		Key:       idKey(id),
+		TableName: aws.String(r.loansTable),
+	})
+	if err != nil {
+		log.Printf("Error getting loan from DynamoDB: %v, table: %s", err, r.loansTable)
+		return &model.Loan{}, appError.NewUnexpectedError(err.Error())
+	}
+	if result.Item == nil {
+		log.Println("No loan found with ID:", id)
+		return &model.Loan{}, nil // No error but no data
+	}
+
+	var loan model.Loan
+	if err := attributevalue.UnmarshalMap(result.Item, &loan); err != nil {
+		log.Printf("Error unmarshaling loan from DynamoDB: %v, item: %+v", err, result.Item)
+		return &model.Loan{}, appError.NewUnexpectedError(err.Error())
+	}
+	return &loan, nil
+}
+
+func (r *LoanDynamoDBRepository) RenewLoan(id string, dueAt time.Time, maxRenewals int) (*model.Loan, *appError.Error) {
+	cond := expression.Name("status").Equal(expression.Value(model.LoanStatusActive)).And(
+		expression.Name("renewals").LessThan(expression.Value(maxRenewals)),
+	)
+	update := expression.Set(
+		expression.Name("due_at"), expression.Value(dueAt),
+	).Add(
+		expression.Name("renewals"), expression.Value(1),
+	)
+	expr, err := expression.NewBuilder().WithCondition(cond).WithUpdate(update).Build()
+	if err != nil {
+		log.Printf("Error building expression for renewal: %v, ID: %s", err, id)
+		return &model.Loan{}, appError.NewUnexpectedError(err.Error())
+	}
+
+	result, err := r.client.UpdateItem(r.ctx, &dynamodb.UpdateItemInput{
+		TableName:                 aws.String(r.loansTable),
+		Key:                       idKey(id),
+		UpdateExpression:          expr.Update(),
+		ConditionExpression:       expr.Condition(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		ReturnValues:              types.ReturnValueAllNew,
+	})
+	if err != nil {
+		log.Printf("Error renewing loan in DynamoDB: %v, table: %s", err, r.loansTable)
+		return &model.Loan{}, transactionError(err)
+	}
+
+	var loan model.Loan
+	if err := attributevalue.UnmarshalMap(result.Attributes, &loan); err != nil {
+		log.Printf("Error unmarshaling renewed loan from DynamoDB: %v, item: %+v", err, result.Attributes)
+		return &model.Loan{}, appError.NewUnexpectedError(err.Error())
+	}
+	log.Printf("Renewed loan successfully, ID: %s, due_at: %s", id, loan.DueAt)
+	return &loan, nil
+}
+
+func (r *LoanDynamoDBRepository) ReturnLoan(loan *model.Loan, next *model.Hold) *appError.Error {
+	loanExpr, err := expression.NewBuilder().
+		WithCondition(expression.Name("status").Equal(expression.Value(model.LoanStatusActive))).
+		WithUpdate(expression.Set(
+			expression.Name("status"), expression.Value(loan.Status),
+		).Set(
+			expression.Name("returned_at"), expression.Value(loan.ReturnedAt),
+		)).
+		Build()
+	if err != nil {
+		log.Printf("Error building expression for return: %v, loan: %s", err, loan.ID)
+		return appError.NewUnexpectedError(err.Error())
+	}
+
+	// The returned copy goes straight to the head of the holds queue if
+	// anyone is waiting, otherwise back on the shelf.
+	copyUpdate := expression.Set(
+		expression.Name("status"), expression.Value(model.CopyStatusAvailable),
+	).Remove(expression.Name("loan_id"))
+	if next != nil {
+		copyUpdate = expression.Set(
+			expression.Name("status"), expression.Value(model.CopyStatusOnHold),
+		).Set(
+			expression.Name("held_for"), expression.Value(next.PatronID),
+		).Set(
+			expression.Name("hold_id"), expression.Value(next.ID),
+		).Remove(expression.Name("loan_id"))
+	}
+	copyExpr, err := expression.NewBuilder().
+		WithCondition(expression.Name("loan_id").Equal(expression.Value(loan.ID))).
+		WithUpdate(copyUpdate).
+		Build()
+	if err != nil {
+		log.Printf("Error building expression for copy: %v, copy: %s", err, loan.CopyID)
+		return appError.NewUnexpectedError(err.Error())
+	}
+
+	items := []types.TransactWriteItem{
+		{
+			Update: &types.Update{
+				TableName:                 aws.String(r.loansTable),
+				Key:                       idKey(loan.ID),
+				UpdateExpression:          loanExpr.Update(),
+				ConditionExpression:       loanExpr.Condition(),
+				ExpressionAttributeNames:  loanExpr.Names(),
+				ExpressionAttributeValues: loanExpr.Values(),
+			},
+		},
+		{
+			Update: &types.Update{
+				TableName:                 aws.String(r.copiesTable),
+				Key:                       idKey(loan.CopyID),
+				UpdateExpression:          copyExpr.Update(),
+				ConditionExpression:       copyExpr.Condition(),
+				ExpressionAttributeNames:  copyExpr.Names(),
+				ExpressionAttributeValues: copyExpr.Values(),
+			},
+		},
+	}
+
+	if next != nil {
+		holdExpr, err := expression.NewBuilder().
+			WithCondition(expression.Name("status").Equal(expression.Value(model.HoldStatusWaiting))).
+			WithUpdate(expression.Set(
+				expression.Name("status"), expression.Value(model.HoldStatusReady),
+			).Set(
+				expression.Name("copy_id"), expression.Value(loan.CopyID),
+			).Set(
+				expression.Name("ready_at"), expression.Value(loan.ReturnedAt),
+			)).
+			Build()
+		if err != nil {
+			log.Printf("Error building expression for hold: %v, hold: %s", err, next.ID)
+			return appError.NewUnexpectedError(err.Error())
+		}
+		items = append(items, types.TransactWriteItem{
+			Update: &types.Update{
+				TableName:                 aws.String(r.holdsTable),
+				Key:                       idKey(next.ID),
+				UpdateExpression:          holdExpr.Update(),
+				ConditionExpression:       holdExpr.Condition(),
+				ExpressionAttributeNames:  holdExpr.Names(),
+				ExpressionAttributeValues: holdExpr.Values(),
+			},
+		})
+	}
+
+	_, err = r.client.TransactWriteItems(r.ctx, &dynamodb.TransactWriteItemsInput{TransactItems: items})
+	if err != nil {
+		log.Printf("Error returning loan: %v, loan: %s", err, loan.ID)
+		return transactionError(err)
+	}
+
+	log.Printf("Returned loan successfully, loan: %s, next hold: %+v", loan.ID, next)
+	return nil
+}
+
+func (r *LoanDynamoDBRepository) GetOverdueLoans(now time.Time) ([]model.Loan, *appError.Error) {
+	keyCond := expression.Key("status").Equal(expression.Value(model.LoanStatusActive)).And(
+		expression.Key("due_at").LessThan(expression.Value(now)),
+	)
+	expr, err := expression.NewBuilder().WithKeyCondition(keyCond).Build()
+	if err != nil {
+		log.Printf("Error building expression for overdue query: %v", err)
+		return nil, appError.NewUnexpectedError(err.Error())
+	}
+
+	var loans []model.Loan
+	paginator := dynamodb.NewQueryPaginator(r.client, &dynamodb.QueryInput{
+		TableName:                 aws.String(r.loansTable),
+		IndexName:                 aws.String(LoansStatusDueIndex),
+		KeyConditionExpression:    expr.KeyCondition(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(r.ctx)
+		if err != nil {
+			log.Printf("Error querying overdue loans: %v, table: %s", err, r.loansTable)
+			return nil, appError.NewUnexpectedError(err.Error())
+		}
+		var pageLoans []model.Loan
+		if err := attributevalue.UnmarshalListOfMaps(page.Items, &pageLoans); err != nil {
+			log.Printf("Error unmarshaling loans from DynamoDB: %v", err)
+			return nil, appError.NewUnexpectedError(err.Error())
+		}
+		loans = append(loans, pageLoans...)
+	}
+	log.Printf("Retrieved %d overdue loans", len(loans))
+	return loans, nil
+}
+
+func (r *LoanDynamoDBRepository) CreateHold(hold *model.Hold) *appError.Error {
+	av, err := attributevalue.MarshalMap(hold)
+	if err != nil {
+		log.Printf("Error marshaling hold: %v, hold: %+v", err, hold)
+		return appError.NewUnexpectedError(err.Error())
+	}
+	// The ID is the same for every hold of a patron on a book, so only a
+	// fulfilled one may be replaced.
+	expr, err := expression.NewBuilder().
+		WithCondition(expression.AttributeNotExists(expression.Name("ID")).Or(
+			expression.Name("status").Equal(expression.Value(model.HoldStatusFulfilled)))).
+		Build()
+	if err != nil {
+		log.Printf("Error building expression for hold: %v, hold: %+v", err, hold)
+		return appError.NewUnexpectedError(err.Error())
+	}
+	_, err = r.client.PutItem(r.ctx, &dynamodb.PutItemInput{
+		Item:                      av,
+		TableName:                 aws.String(r.holdsTable),
+		ConditionExpression:       expr.Condition(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+	})
+	if err != nil {
+		var conditional *types.ConditionalCheckFailedException
+		if errors.As(err, &conditional) {
+			return appError.NewConflictError("Patron already has a hold on this book.")
+		}
+		log.Printf("Error putting hold in DynamoDB: %v, table: %s", err, r.holdsTable)
+		return appError.NewUnexpectedError(err.Error())
+	}
+	log.Printf("Hold creation completed successfully, hold: %+v", hold)
+	return nil
+}
+
+func (r *LoanDynamoDBRepository) GetWaitingHoldsByBookID(bookID string) ([]model.Hold, *appError.Error) {
+	keyCond := expression.Key("book_id").Equal(expression.Value(bookID))
+	filter := expression.Name("status").Equal(expression.Value(model.HoldStatusWaiting))
+	expr, err := expression.NewBuilder().WithKeyCondition(keyCond).WithFilter(filter).Build()
+	if err != nil {
+		log.Printf("Error building expression for holds query: %v, book_id: %s", err, bookID)
+		return nil, appError.NewUnexpectedError(err.Error())
+	}
+
+	// The index is sorted by placed_at, so ascending order is FIFO order.
+	var holds []model.Hold
+	paginator := dynamodb.NewQueryPaginator(r.client, &dynamodb.QueryInput{
+		TableName:                 aws.String(r.holdsTable),
+		IndexName:                 aws.String(HoldsBookIndex),
+		KeyConditionExpression:    expr.KeyCondition(),
+		FilterExpression:          expr.Filter(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		ScanIndexForward:          aws.Bool(true),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(r.ctx)
+		if err != nil {
+			log.Printf("Error querying holds: %v, table: %s", err, r.holdsTable)
+			return nil, appError.NewUnexpectedError(err.Error())
+		}
+		var pageHolds []model.Hold
+		if err := attributevalue.UnmarshalListOfMaps(page.Items, &pageHolds); err != nil {
+			log.Printf("Error unmarshaling holds from DynamoDB: %v", err)
+			return nil, appError.NewUnexpectedError(err.Error())
+		}
+		holds = append(holds, pageHolds...)
+	}
+	return holds, nil
+}
@@ -0,0 +1,56 @@
+package configuration
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	"main/src/loans/domain/model"
+)
+
+const (
+	defaultLoanPeriodDays = 21
+	defaultMaxRenewals    = 2
+)
+
+func getTableName(env, localName string) string {
+	tableName := os.Getenv(env)
+	if tableName == "" {
+		log.Printf("Local DynamoDB Database")
+		return localName
+	}
+	log.Printf("AWS DynamoDB Database: %s", tableName)
+	return tableName
+}
+
+func GetDynamoDBCopyTable() string {
+	return getTableName("COPIES_TABLE", "Test_Copy_Table")
+}
+
+func GetDynamoDBLoanTable() string {
+	return getTableName("LOANS_TABLE", "Test_Loan_Table")
+}
+
+func GetDynamoDBHoldTable() string {
+	return getTableName("HOLDS_TABLE", "Test_Hold_Table")
+}
+
+func getIntEnv(env string, fallback int) int {
+	value, err := strconv.Atoi(os.Getenv(env))
+	if err != nil || value < 0 {
+		return fallback
+	}
+	return value
+}
+
+func GetLoanPolicy() model.LoanPolicy {
+	days := getIntEnv("LOAN_PERIOD_DAYS", defaultLoanPeriodDays)
+	if days == 0 {
+		days = defaultLoanPeriodDays
+	}
+	return model.LoanPolicy{
+		LoanPeriod:  time.Duration(days) * 24 * time.Hour,
+		MaxRenewals: getIntEnv("MAX_RENEWALS", defaultMaxRenewals),
+	}
+}
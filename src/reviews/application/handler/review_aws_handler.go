@@ -0,0 +1,80 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	bookHandler "main/src/books/application/handler"
+	bookConfiguration "main/src/books/infrastructure/configuration"
+	"main/src/reviews/application/service"
+	"main/src/reviews/domain/model"
+	"main/src/reviews/infrastructure/adapter"
+	"main/src/reviews/infrastructure/configuration"
+	appError "main/utils/error"
+)
+
+type MicroAWSReviewDynamoDB struct {
+	Ctx          context.Context
+	ReviewsTable string
+	BooksTable   string
+}
+
+func (micro *MicroAWSReviewDynamoDB) reviewService() (service.ReviewService, *appError.Error) {
+	// The rating of a book is kept on its DynamoDB item, in the same
+	// transaction as the review; the other stores have no such item.
+	if bookConfiguration.GetBookStore() != bookConfiguration.BookStoreDynamoDB {
+		return nil, appError.NewError(http.StatusNotImplemented, "Reviews are only available for the DynamoDB book store.")
+	}
+	dynamoClient, err := bookHandler.DefaultBookContainer().DynamoDBClient(micro.Ctx)
+	if err != nil {
+		return nil, err
+	}
+	if micro.ReviewsTable == "" {
+		micro.ReviewsTable = configuration.GetDynamoDBReviewTable()
+	}
+	if micro.BooksTable == "" {
+		micro.BooksTable = bookConfiguration.GetDynamoDBBookTable()
+	}
+	reviewInfrastructure := adapter.NewReviewDynamoDBRepository(micro.Ctx, dynamoClient, micro.ReviewsTable, micro.BooksTable)
+	return service.NewReviewServiceDynamoDB(reviewInfrastructure), nil
+}
+
+func (micro *MicroAWSReviewDynamoDB) CreateReview(review *model.Review) (*model.Review, *appError.Error) {
+	reviewService, err := micro.reviewService()
+	if err != nil {
+		return nil, err
+	}
+	return reviewService.CreateReview(review)
+}
+
+func (micro *MicroAWSReviewDynamoDB) GetReviewsByBookID(bookID string) ([]model.Review, *appError.Error) {
+	reviewService, err := micro.reviewService()
+	if err != nil {
+		return nil, err
+	}
+	return reviewService.GetReviewsByBookID(bookID)
+}
+
+func (micro *MicroAWSReviewDynamoDB) UpdateReview(bookID, userID string, review *model.Review) (*model.Review, *appError.Error) {
+	reviewService, err := micro.reviewService()
+	if err != nil {
+		return nil, err
+	}
+	return reviewService.UpdateReview(bookID, userID, review)
+}
+
+func (micro *MicroAWSReviewDynamoDB) DeleteReview(bookID, userID string) *appError.Error {
+	reviewService, err := micro.reviewService()
+	if err != nil {
+		return err
+	}
+	return reviewService.DeleteReview(bookID, userID)
+}
+
+func (micro *MicroAWSReviewDynamoDB) ModerateReview(bookID, userID string, status model.ReviewStatus) (*model.Review, *appError.Error) {
+	reviewService, err := micro.reviewService()
+	if err != nil {
+		return nil, err
+	}
+	return reviewService.ModerateReview(bookID, userID, status)
+}
@@ -0,0 +1,14 @@
+package service
+
+import (
+	"main/src/reviews/domain/model"
+	appError "main/utils/error"
+)
+
+type ReviewService interface {
+	CreateReview(*model.Review) (*model.Review, *appError.Error)
+	GetReviewsByBookID(string) ([]model.Review, *appError.Error)
+	UpdateReview(string, string, *model.Review) (*model.Review, *appError.Error)
+	DeleteReview(string, string) *appError.Error
+	ModerateReview(string, string, model.ReviewStatus) (*model.Review, *appError.Error)
+}
@@ -0,0 +1,109 @@
+package service
+
+import (
+	"time"
+
+	"main/src/reviews/domain/model"
+	"main/src/reviews/domain/repository"
+	appError "main/utils/error"
+	"main/utils/lib"
+)
+
+type ReviewServiceDynamoDB struct {
+	repo repository.ReviewRepository
+}
+
+func NewReviewServiceDynamoDB(repo repository.ReviewRepository) ReviewService {
+	return &ReviewServiceDynamoDB{
+		repo: repo,
+	}
+}
+
+func (service *ReviewServiceDynamoDB) CreateReview(review *model.Review) (*model.Review, *appError.Error) {
+	now := time.Now().UTC().Truncate(time.Second)
+	review.Status = model.ReviewStatusPublished
+	review.Version = 1
+	review.CreatedAt = now
+	review.UpdatedAt = now
+	if err := review.Validate(); err != nil {
+		return nil, err
+	}
+	if err := service.repo.CreateReview(review); err != nil {
+		return nil, err
+	}
+	return review, nil
+}
+
+// GetReviewsByBookID returns the reviews visible to readers, hiding rejected ones.
+func (service *ReviewServiceDynamoDB) GetReviewsByBookID(bookID string) ([]model.Review, *appError.Error) {
+	if err := lib.ValidateUUID(bookID); err != nil {
+		return nil, err
+	}
+	reviews, err := service.repo.GetReviewsByBookID(bookID)
+	if err != nil {
+		return nil, err
+	}
+	visible := make([]model.Review, 0, len(reviews))
+	for _, review := range reviews {
+		if review.Status != model.ReviewStatusRejected {
+			visible = append(visible, review)
+		}
+	}
+	return visible, nil
+}
+
+func (service *ReviewServiceDynamoDB) UpdateReview(bookID, userID string, review *model.Review) (*model.Review, *appError.Error) {
+	previous, err := service.getReview(bookID, userID)
+	if err != nil {
+		return nil, err
+	}
+	updated := *previous
+	updated.Rating = review.Rating
+	updated.Title = review.Title
+	updated.Body = review.Body
+	return service.save(previous, &updated)
+}
+
+func (service *ReviewServiceDynamoDB) DeleteReview(bookID, userID string) *appError.Error {
+	previous, err := service.getReview(bookID, userID)
+	if err != nil {
+		return err
+	}
+	return service.repo.DeleteReview(previous)
+}
+
+func (service *ReviewServiceDynamoDB) ModerateReview(bookID, userID string, status model.ReviewStatus) (*model.Review, *appError.Error) {
+	previous, err := service.getReview(bookID, userID)
+	if err != nil {
+		return nil, err
+	}
+	updated := *previous
+	updated.Status = status
+	return service.save(previous, &updated)
+}
+
+func (service *ReviewServiceDynamoDB) save(previous, updated *model.Review) (*model.Review, *appError.Error) {
+	updated.Version = previous.Version + 1
+	updated.UpdatedAt = time.Now().UTC().Truncate(time.Second)
+	if err := updated.Validate(); err != nil {
+		return nil, err
+	}
+	if err := service.repo.UpdateReview(previous, updated); err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+func (service *ReviewServiceDynamoDB) getReview(bookID, userID string) (*model.Review, *appError.Error) {
+	if err := lib.ValidateUUID(bookID); err != nil {
+		return nil, err
+	}
+	review, err := service.repo.GetReview(bookID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if review.BookID == "" {
+		return nil, appError.NewNotFoundError("Review not found for user " + userID + ".")
+	}
+	return review, nil
+}
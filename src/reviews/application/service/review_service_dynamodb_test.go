@@ -0,0 +1,122 @@
+package service_test
+
+import (
+	"testing"
+
+	"main/src/reviews/application/service"
+	"main/src/reviews/domain/model"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	repoMock "main/mocks"
+)
+
+type ReviewServiceDynamoDBSuite struct {
+	suite.Suite
+	reviewRepository *repoMock.ReviewRepository
+	reviewService    service.ReviewService
+	testReview       *model.Review
+}
+
+const (
+	MethodGetReview          = "GetReview"
+	MethodGetReviewsByBookID = "GetReviewsByBookID"
+	MethodCreateReview       = "CreateReview"
+	MethodUpdateReview       = "UpdateReview"
+	MethodDeleteReview       = "DeleteReview"
+)
+
+func (suite *ReviewServiceDynamoDBSuite) SetupTest() {
+	suite.reviewRepository = new(repoMock.ReviewRepository)
+	suite.reviewService = service.NewReviewServiceDynamoDB(suite.reviewRepository)
+	suite.testReview = &model.Review{
+		BookID:  uuid.NewString(),
+		UserID:  "user-1",
+		Rating:  4,
+		Body:    "A descriptive review for testing",
+		Status:  model.ReviewStatusPublished,
+		Version: 1,
+	}
+}
+
+func (suite *ReviewServiceDynamoDBSuite) TestCreateReview() {
+	newReview := &model.Review{BookID: suite.testReview.BookID, UserID: "user-2", Rating: 5, Status: model.ReviewStatusRejected}
+	suite.reviewRepository.On(MethodCreateReview, newReview).Return(nil)
+	created, err := suite.reviewService.CreateReview(newReview)
+	suite.Nil(err)
+	suite.Equal(model.ReviewStatusPublished, created.Status, "New reviews are always published")
+	suite.Equal(1, created.Version)
+	suite.reviewRepository.AssertExpectations(suite.T())
+}
+
+func (suite *ReviewServiceDynamoDBSuite) TestCreateReviewInvalidRating() {
+	_, err := suite.reviewService.CreateReview(&model.Review{BookID: suite.testReview.BookID, UserID: "user-2", Rating: 9})
+	suite.NotNil(err)
+	suite.reviewRepository.AssertNotCalled(suite.T(), MethodCreateReview, mock.Anything)
+}
+
+func (suite *ReviewServiceDynamoDBSuite) TestGetReviewsByBookIDHidesRejected() {
+	rejected := *suite.testReview
+	rejected.UserID = "user-2"
+	rejected.Status = model.ReviewStatusRejected
+	suite.reviewRepository.On(MethodGetReviewsByBookID, suite.testReview.BookID).Return([]model.Review{*suite.testReview, rejected}, nil)
+
+	reviews, err := suite.reviewService.GetReviewsByBookID(suite.testReview.BookID)
+	suite.Nil(err)
+	suite.Len(reviews, 1)
+	suite.Equal("user-1", reviews[0].UserID)
+}
+
+func (suite *ReviewServiceDynamoDBSuite) TestUpdateReview() {
+	suite.reviewRepository.On(MethodGetReview, suite.testReview.BookID, suite.testReview.UserID).Return(suite.testReview, nil)
+	suite.reviewRepository.On(MethodUpdateReview, suite.testReview, mock.MatchedBy(func(r *model.Review) bool {
+		return r.Rating == 2 && r.Version == 2
+	})).Return(nil)
+
+	updated, err := suite.reviewService.UpdateReview(suite.testReview.BookID, suite.testReview.UserID, &model.Review{Rating: 2, Body: "Changed my mind"})
+	suite.Nil(err)
+	suite.Equal(2, updated.Rating)
+	suite.Equal(model.ReviewStatusPublished, updated.Status)
+	suite.reviewRepository.AssertExpectations(suite.T())
+}
+
+func (suite *ReviewServiceDynamoDBSuite) TestModerateReview() {
+	suite.reviewRepository.On(MethodGetReview, suite.testReview.BookID, suite.testReview.UserID).Return(suite.testReview, nil)
+	suite.reviewRepository.On(MethodUpdateReview, suite.testReview, mock.MatchedBy(func(r *model.Review) bool {
+		return r.Status == model.ReviewStatusRejected
+	})).Return(nil)
+
+	moderated, err := suite.reviewService.ModerateReview(suite.testReview.BookID, suite.testReview.UserID, model.ReviewStatusRejected)
+	suite.Nil(err)
+	suite.Equal(model.ReviewStatusRejected, moderated.Status)
+	suite.reviewRepository.AssertExpectations(suite.T())
+}
+
+func (suite *ReviewServiceDynamoDBSuite) TestModerateReviewUnknownStatus() {
+	suite.reviewRepository.On(MethodGetReview, suite.testReview.BookID, suite.testReview.UserID).Return(suite.testReview, nil)
+	_, err := suite.reviewService.ModerateReview(suite.testReview.BookID, suite.testReview.UserID, "spam")
+	suite.NotNil(err)
+	suite.reviewRepository.AssertNotCalled(suite.T(), MethodUpdateReview, mock.Anything, mock.Anything)
+}
+
+func (suite *ReviewServiceDynamoDBSuite) TestDeleteReviewNotFound() {
+	suite.reviewRepository.On(MethodGetReview, suite.testReview.BookID, "user-9").Return(&model.Review{}, nil)
+	err := suite.reviewService.DeleteReview(suite.testReview.BookID, "user-9")
+	suite.NotNil(err)
+	suite.Equal(404, err.Code)
+	suite.reviewRepository.AssertNotCalled(suite.T(), MethodDeleteReview, mock.Anything)
+}
+
+func (suite *ReviewServiceDynamoDBSuite) TestDeleteReview() {
+	suite.reviewRepository.On(MethodGetReview, suite.testReview.BookID, suite.testReview.UserID).Return(suite.testReview, nil)
+	suite.reviewRepository.On(MethodDeleteReview, suite.testReview).Return(nil)
+	err := suite.reviewService.DeleteReview(suite.testReview.BookID, suite.testReview.UserID)
+	suite.Nil(err)
+	suite.reviewRepository.AssertExpectations(suite.T())
+}
+
+func TestReviewServiceDynamoDBSuite(t *testing.T) {
+	suite.Run(t, new(ReviewServiceDynamoDBSuite))
+}
@@ -0,0 +1,82 @@
+package model
+
+import (
+	appError "main/utils/error"
+	"main/utils/lib"
+	"time"
+)
+
+type ReviewStatus string
+
+const (
+	ReviewStatusPublished ReviewStatus = "published"
+	ReviewStatusFlagged   ReviewStatus = "flagged"
+	ReviewStatusRejected  ReviewStatus = "rejected"
+)
+
+const (
+	MinRating           = 1
+	MaxRating           = 5
+	MaxReviewCharacters = 2000
+)
+
+// Review is keyed by book and user, so each user has at most one review per book.
+type Review struct {
+	BookID    string       `json:"book_id,omitempty" dynamodbav:"book_id,omitempty"`
+	UserID    string       `json:"user_id,omitempty" dynamodbav:"user_id,omitempty"`
+	Rating    int          `json:"rating,omitempty" dynamodbav:"rating,omitempty"`
+	Title     string       `json:"title,omitempty" dynamodbav:"title,omitempty"`
+	Body      string       `json:"body,omitempty" dynamodbav:"body,omitempty"`
+	Status    ReviewStatus `json:"status,omitempty" dynamodbav:"status,omitempty"`
+	Version   int          `json:"version" dynamodbav:"version"`
+	CreatedAt time.Time    `json:"created_at" dynamodbav:"created_at"`
+	UpdatedAt time.Time    `json:"updated_at" dynamodbav:"updated_at"`
+}
+
+func (r *Review) Validate() *appError.Error {
+	if err := lib.ValidateUUID(r.BookID); err != nil {
+		return err
+	}
+	if err := lib.ValidateStringNotEmpty(r.UserID); err != nil {
+		return appError.NewValidationError("User ID cannot be empty.")
+	}
+	if r.Rating < MinRating || r.Rating > MaxRating {
+		return appError.NewValidationError("Rating must be between 1 and 5.")
+	}
+	if len(r.Body) > MaxReviewCharacters {
+		return appError.NewValidationError("Review cannot exceed 2000 characters.")
+	}
+	if !r.Status.IsValid() {
+		return appError.NewValidationError("Unknown review status: " + string(r.Status))
+	}
+	return nil
+}
+
+func (s ReviewStatus) IsValid() bool {
+	switch s {
+	case ReviewStatusPublished, ReviewStatusFlagged, ReviewStatusRejected:
+		return true
+	}
+	return false
+}
+
+// Counts reports whether the review takes part in the book's rating. Flagged
+// reviews keep counting until a moderator rejects them.
+func (r *Review) Counts() bool {
+	return r != nil && r.Status != ReviewStatusRejected
+}
+
+// RatingDelta returns how the book's rating count and total change when a
+// review moves from previous to next. Either side may be nil for creates and
+// deletes.
+func RatingDelta(previous, next *Review) (count int, total int) {
+	if previous.Counts() {
+		count--
+		total -= previous.Rating
+	}
+	if next.Counts() {
+		count++
+		total += next.Rating
+	}
+	return count, total
+}
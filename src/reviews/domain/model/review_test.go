@@ -0,0 +1,72 @@
+package model_test
+
+import (
+	"main/src/reviews/domain/model"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type ReviewModelSuite struct {
+	suite.Suite
+}
+
+func (s *ReviewModelSuite) TestValidate() {
+	const bookID = "123e4567-e89b-12d3-a456-426614174000"
+	var tests = []struct {
+		name     string
+		review   model.Review
+		expected bool // true if no error is expected, false otherwise
+	}{
+		{"valid", model.Review{BookID: bookID, UserID: "user-1", Rating: 4, Body: "Great read.", Status: model.ReviewStatusPublished}, true},
+		{"invalid_book", model.Review{BookID: "invalid", UserID: "user-1", Rating: 4, Status: model.ReviewStatusPublished}, false},
+		{"empty_user", model.Review{BookID: bookID, UserID: "", Rating: 4, Status: model.ReviewStatusPublished}, false},
+		{"rating_too_low", model.Review{BookID: bookID, UserID: "user-1", Rating: 0, Status: model.ReviewStatusPublished}, false},
+		{"rating_too_high", model.Review{BookID: bookID, UserID: "user-1", Rating: 6, Status: model.ReviewStatusPublished}, false},
+		{"body_too_long", model.Review{BookID: bookID, UserID: "user-1", Rating: 3, Body: strings.Repeat("A", 2001), Status: model.ReviewStatusPublished}, false},
+		{"unknown_status", model.Review{BookID: bookID, UserID: "user-1", Rating: 3, Status: "deleted"}, false},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			err := tt.review.Validate()
+			if tt.expected {
+				s.Nil(err)
+			} else {
+				s.NotNil(err)
+			}
+		})
+	}
+}
+
+func (s *ReviewModelSuite) TestRatingDelta() {
+	published := &model.Review{Rating: 4, Status: model.ReviewStatusPublished}
+	edited := &model.Review{Rating: 2, Status: model.ReviewStatusPublished}
+	rejected := &model.Review{Rating: 2, Status: model.ReviewStatusRejected}
+
+	var tests = []struct {
+		name           string
+		previous, next *model.Review
+		count, total   int
+	}{
+		{"create", nil, published, 1, 4},
+		{"delete", published, nil, -1, -4},
+		{"edit_rating", published, edited, 0, -2},
+		{"reject", edited, rejected, -1, -2},
+		{"restore", rejected, edited, 1, 2},
+		{"delete_rejected", rejected, nil, 0, 0},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			count, total := model.RatingDelta(tt.previous, tt.next)
+			s.Equal(tt.count, count)
+			s.Equal(tt.total, total)
+		})
+	}
+}
+
+func TestReviewModelSuite(t *testing.T) {
+	suite.Run(t, new(ReviewModelSuite))
+}
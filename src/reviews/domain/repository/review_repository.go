@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"main/src/reviews/domain/model"
+	appError "main/utils/error"
+)
+
+type ReviewRepository interface {
+	GetReview(string, string) (*model.Review, *appError.Error)
+	GetReviewsByBookID(string) ([]model.Review, *appError.Error)
+	CreateReview(*model.Review) *appError.Error
+	UpdateReview(*model.Review, *model.Review) *appError.Error
+	DeleteReview(*model.Review) *appError.Error
+}
@@ -0,0 +1,265 @@
+package adapter
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"main/src/reviews/domain/model"
+	appError "main/utils/error"
+)
+
+const conditionalCheckFailed = "ConditionalCheckFailed"
+
+type ReviewDynamoDBRepository struct {
+	ctx        context.Context
+	client     *dynamodb.Client
+	table      string
+	booksTable string
+}
+
+func NewReviewDynamoDBRepository(ctx context.Context, client *dynamodb.Client, table, booksTable string) *ReviewDynamoDBRepository {
+	return &ReviewDynamoDBRepository{
+		ctx:        ctx,
+		client:     client,
+		table:      table,
+		booksTable: booksTable,
+	}
+}
+
+func reviewKey(bookID, userID string) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		"book_id": &types.AttributeValueMemberS{Value: bookID},
+		"user_id": &types.AttributeValueMemberS{Value: userID},
+	}
+}
+
+// ratingUpdate adjusts the book's rating aggregate in the same transaction as
+// the review write, so the average never drifts from the stored reviews. A
+// book in the trash takes no reviews, the write fails as if it were missing.
+func (r *ReviewDynamoDBRepository) ratingUpdate(bookID string, previous, next *model.Review) (types.TransactWriteItem, error) {
+	count, total := model.RatingDelta(previous, next)
+	expr, err := expression.NewBuilder().
+		WithCondition(expression.AttributeExists(expression.Name("ID")).And(notTrashed())).
+		WithUpdate(expression.Add(
+			expression.Name("rating_count"), expression.Value(count),
+		).Add(
+			expression.Name("rating_total"), expression.Value(total),
+		)).
+		Build()
+	if err != nil {
+		return types.TransactWriteItem{}, err
+	}
+	return types.TransactWriteItem{
+		Update: &types.Update{
+			TableName: aws.String(r.booksTable),
+			Key: map[string]types.AttributeValue{
+				"ID": &types.AttributeValueMemberS{Value: bookID},
+			},
+			UpdateExpression:          expr.Update(),
+			ConditionExpression:       expr.Condition(),
+			ExpressionAttributeNames:  expr.Names(),
+			ExpressionAttributeValues: expr.Values(),
+		},
+	}, nil
+}
+
+// notTrashed holds for books that are not in the trash.
+func notTrashed() expression.ConditionBuilder {
+	return expression.AttributeNotExists(expression.Name("deleted_at"))
+}
+
+// transactionError reports which of the two writes lost its condition: the
+// review (index 0) or the book (index 1).
+func transactionError(err error, reviewMessage string) *appError.Error {
+	var canceled *types.TransactionCanceledException
+	if errors.As(err, &canceled) {
+		for i, reason := range canceled.CancellationReasons {
+			if aws.ToString(reason.Code) != conditionalCheckFailed {
+				continue
+			}
+			if i == 0 {
+				return appError.NewConflictError(reviewMessage)
+			}
+			return appError.NewNotFoundError("Book not found.")
+		}
+	}
+	return appError.NewUnexpectedError(err.Error())
+}
+
+func (r *ReviewDynamoDBRepository) GetReview(bookID, userID string) (*model.Review, *appError.Error) {
+	result, err := r.client.GetItem(r.ctx, &dynamodb.GetItemInput{
+		Key:       reviewKey(bookID, userID),
+		TableName: aws.String(r.table),
+	})
+	if err != nil {
+		log.Printf("Error getting review from DynamoDB: %v, table: %s", err, r.table)
+		return &model.Review{}, appError.NewUnexpectedError(err.Error())
+	}
+	if result.Item == nil {
+		log.Printf("No review found, book_id: %s, user_id: %s", bookID, userID)
+		return &model.Review{}, nil // No error but no data
+	}
+
+	var review model.Review
+	if err := attributevalue.UnmarshalMap(result.Item, &review); err != nil {
+		log.Printf("Error unmarshaling review from DynamoDB: %v, item: %+v", err, result.Item)
+		return &model.Review{}, appError.NewUnexpectedError(err.Error())
+	}
+	return &review, nil
+}
+
+func (r *ReviewDynamoDBRepository) GetReviewsByBookID(bookID string) ([]model.Review, *appError.Error) {
+	keyCond := expression.Key("book_id").Equal(expression.Value(bookID))
+	expr, err := expression.NewBuilder().WithKeyCondition(keyCond).Build()
+	if err != nil {
+		log.Printf("Error building expression for reviews query: %v, book_id: %s", err, bookID)
+		return nil, appError.NewUnexpectedError(err.Error())
+	}
+
+	reviews := []model.Review{}
+	paginator := dynamodb.NewQueryPaginator(r.client, &dynamodb.QueryInput{
+		TableName:                 aws.String(r.table),
+		KeyConditionExpression:    expr.KeyCondition(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(r.ctx)
+		if err != nil {
+			log.Printf("Error querying reviews: %v, table: %s", err, r.table)
+			return nil, appError.NewUnexpectedError(err.Error())
+		}
+		var pageReviews []model.Review
+		if err := attributevalue.UnmarshalListOfMaps(page.Items, &pageReviews); err != nil {
+			log.Printf("Error unmarshaling reviews from DynamoDB: %v", err)
+			return nil, appError.NewUnexpectedError(err.Error())
+		}
+		reviews = append(reviews, pageReviews...)
+	}
+	log.Printf("Retrieved %d reviews, book_id: %s", len(reviews), bookID)
+	return reviews, nil
+}
+
+func (r *ReviewDynamoDBRepository) CreateReview(review *model.Review) *appError.Error {
+	av, err := attributevalue.MarshalMap(review)
+	if err != nil {
+		log.Printf("Error marshaling review: %v, review: %+v", err, review)
+		return appError.NewUnexpectedError(err.Error())
+	}
+	expr, err := expression.NewBuilder().WithCondition(expression.AttributeNotExists(expression.Name("book_id"))).Build()
+	if err != nil {
+		log.Printf("Error building expression for review: %v", err)
+		return appError.NewUnexpectedError(err.Error())
+	}
+	bookUpdate, err := r.ratingUpdate(review.BookID, nil, review)
+	if err != nil {
+		log.Printf("Error building expression for rating: %v", err)
+		return appError.NewUnexpectedError(err.Error())
+	}
+
+	_, err = r.client.TransactWriteItems(r.ctx, &dynamodb.TransactWriteItemsInput{
+		TransactItems: []types.TransactWriteItem{
+			{
+				Put: &types.Put{
+					TableName:                aws.String(r.table),
+					Item:                     av,
+					ConditionExpression:      expr.Condition(),
+					ExpressionAttributeNames: expr.Names(),
+				},
+			},
+			bookUpdate,
+		},
+	})
+	if err != nil {
+		log.Printf("Error creating review: %v, review: %+v", err, review)
+		return transactionError(err, "User has already reviewed this book.")
+	}
+
+	log.Printf("Review creation completed successfully, review: %+v", review)
+	return nil
+}
+
+func (r *ReviewDynamoDBRepository) UpdateReview(previous, review *model.Review) *appError.Error {
+	av, err := attributevalue.MarshalMap(review)
+	if err != nil {
+		log.Printf("Error marshaling review: %v, review: %+v", err, review)
+		return appError.NewUnexpectedError(err.Error())
+	}
+	expr, err := expression.NewBuilder().
+		WithCondition(expression.Name("version").Equal(expression.Value(previous.Version))).
+		Build()
+	if err != nil {
+		log.Printf("Error building expression for review: %v", err)
+		return appError.NewUnexpectedError(err.Error())
+	}
+	bookUpdate, err := r.ratingUpdate(review.BookID, previous, review)
+	if err != nil {
+		log.Printf("Error building expression for rating: %v", err)
+		return appError.NewUnexpectedError(err.Error())
+	}
+
+	_, err = r.client.TransactWriteItems(r.ctx, &dynamodb.TransactWriteItemsInput{
+		TransactItems: []types.TransactWriteItem{
+			{
+				Put: &types.Put{
+					TableName:                 aws.String(r.table),
+					Item:                      av,
+					ConditionExpression:       expr.Condition(),
+					ExpressionAttributeNames:  expr.Names(),
+					ExpressionAttributeValues: expr.Values(),
+				},
+			},
+			bookUpdate,
+		},
+	})
+	if err != nil {
+		log.Printf("Error updating review: %v, review: %+v", err, review)
+		return transactionError(err, "Review was modified concurrently, please retry.")
+	}
+
+	log.Printf("Updated review successfully, review: %+v", review)
+	return nil
+}
+
+func (r *ReviewDynamoDBRepository) DeleteReview(review *model.Review) *appError.Error {
+	expr, err := expression.NewBuilder().
+		WithCondition(expression.Name("version").Equal(expression.Value(review.Version))).
+		Build()
+	if err != nil {
+		log.Printf("Error building expression for review: %v", err)
+		return appError.NewUnexpectedError(err.Error())
+	}
+	bookUpdate, err := r.ratingUpdate(review.BookID, review, nil)
+	if err != nil {
+		log.Printf("Error building expression for rating: %v", err)
+		return appError.NewUnexpectedError(err.Error())
+	}
+
+	_, err = r.client.TransactWriteItems(r.ctx, &dynamodb.TransactWriteItemsInput{
+		TransactItems: []types.TransactWriteItem{
+			{
+				Delete: &types.Delete{
+					TableName:                 aws.String(r.table),
+					Key:                       reviewKey(review.BookID, review.UserID),
+					ConditionExpression:       expr.Condition(),
+					ExpressionAttributeNames:  expr.Names(),
+					ExpressionAttributeValues: expr.Values(),
+				},
+			},
+			bookUpdate,
+		},
+	})
+	if err != nil {
+		log.Printf("Error deleting review: %v, review: %+v", err, review)
+		return transactionError(err, "Review was modified concurrently, please retry.")
+	}
+
+	log.Printf("Deleted review successfully, book_id: %s, user_id: %s", review.BookID, review.UserID)
+	return nil
+}
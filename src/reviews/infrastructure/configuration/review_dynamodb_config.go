@@ -0,0 +1,16 @@
+package configuration
+
+import (
+	"log"
+	"os"
+)
+
+func GetDynamoDBReviewTable() string {
+	tableName := os.Getenv("REVIEWS_TABLE")
+	if tableName == "" {
+		log.Printf("Local DynamoDB Database")
+		return "Test_Review_Table"
+	}
+	log.Printf("AWS DynamoDB Database: %s", tableName)
+	return tableName
+}
@@ -0,0 +1,291 @@
+// Package dispatcher delivers book events to the webhook subscriptions that
+// asked for them, signing each request and retrying failed deliveries with
+// exponential backoff until they run out of attempts and are dead-lettered.
+package dispatcher
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+
+	bookModel "main/src/books/domain/model"
+	"main/src/webhooks/domain/model"
+	"main/src/webhooks/domain/repository"
+	appError "main/utils/error"
+)
+
+const (
+	DefaultMaxAttempts = 8
+	DefaultBaseDelay   = time.Minute
+	DefaultMaxDelay    = 6 * time.Hour
+	DefaultTimeout     = 10 * time.Second
+	// DefaultRetryBatch is how many due deliveries a retry run sends at most.
+	DefaultRetryBatch = 100
+	// maxResponseBytes is how much of a response is read before the
+	// connection is dropped.
+	maxResponseBytes = 64 << 10
+	userAgent        = "books-webhooks/1.0"
+)
+
+// Options tune the retries: the n-th failed attempt waits BaseDelay * 2^(n-1),
+// capped at MaxDelay, and the delivery is dead after MaxAttempts attempts.
+type Options struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+	Timeout     time.Duration
+}
+
+func DefaultOptions() Options {
+	return Options{
+		MaxAttempts: DefaultMaxAttempts,
+		BaseDelay:   DefaultBaseDelay,
+		MaxDelay:    DefaultMaxDelay,
+		Timeout:     DefaultTimeout,
+	}
+}
+
+// RetryDelay is the wait after the given number of failed attempts.
+func (o Options) RetryDelay(failed int) time.Duration {
+	if failed < 1 {
+		return 0
+	}
+	if failed > 32 {
+		return o.MaxDelay
+	}
+	delay := o.BaseDelay << uint(failed-1)
+	if delay <= 0 || delay > o.MaxDelay {
+		return o.MaxDelay
+	}
+	return delay
+}
+
+type Report struct {
+	Delivered int `json:"delivered"`
+	Retrying  int `json:"retrying"`
+	Dead      int `json:"dead"`
+	// Skipped events were already dispatched to the subscription.
+	Skipped int `json:"skipped"`
+}
+
+func (r *Report) add(status model.DeliveryStatus) {
+	switch status {
+	case model.DeliveryDelivered:
+		r.Delivered++
+	case model.DeliveryRetrying:
+		r.Retrying++
+	case model.DeliveryDead:
+		r.Dead++
+	}
+}
+
+type Dispatcher struct {
+	repo    repository.WebhookRepository
+	client  *http.Client
+	options Options
+	now     func() time.Time
+}
+
+// NewDispatcher sends with client, or when client is nil with a client that
+// times out after options.Timeout, does not follow redirects and only
+// connects to public addresses.
+func NewDispatcher(repo repository.WebhookRepository, client *http.Client, options Options) *Dispatcher {
+	if client == nil {
+		dialer := &net.Dialer{Timeout: options.Timeout, Control: publicOnly}
+		client = &http.Client{
+			Timeout: options.Timeout,
+			// No proxy, so the addresses checked are those of the subscribers.
+			Transport: &http.Transport{
+				DialContext:         dialer.DialContext,
+				TLSHandshakeTimeout: options.Timeout,
+			},
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		}
+	}
+	return &Dispatcher{
+		repo:    repo,
+		client:  client,
+		options: options,
+		now:     func() time.Time { return time.Now().UTC().Truncate(time.Second) },
+	}
+}
+
+// publicOnly refuses connections to the addresses subscribers must not
+// reach. It runs on the resolved address of every connection, so a name
+// resolving to a private address is refused too, whenever it resolves so.
+func publicOnly(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	if ip := net.ParseIP(host); ip == nil || !model.PublicAddress(ip) {
+		return fmt.Errorf("address %s is not public", host)
+	}
+	return nil
+}
+
+// Dispatch delivers an event to every active subscription wanting its type.
+// Each delivery is stored before it is sent, so one interrupted by a crash is
+// picked up by the retries, and an event dispatched again is skipped.
+func (d *Dispatcher) Dispatch(event *bookModel.BookEvent) (*Report, *appError.Error) {
+	subscriptions, err := d.repo.GetSubscriptions()
+	if err != nil {
+		return nil, err
+	}
+	payload, errJSON := json.Marshal(event)
+	if errJSON != nil {
+		log.Printf("Error marshaling event: %v, ID: %s", errJSON, event.ID)
+		return nil, appError.NewUnexpectedError(errJSON.Error())
+	}
+
+	report := &Report{}
+	for i := range subscriptions {
+		subscription := &subscriptions[i]
+		if !subscription.Matches(event.Type) {
+			continue
+		}
+		now := d.now()
+		delivery := &model.Delivery{
+			ID:             model.DeliveryID(subscription.ID, event.ID),
+			SubscriptionID: subscription.ID,
+			EventID:        event.ID,
+			EventType:      string(event.Type),
+			URL:            subscription.URL,
+			Payload:        string(payload),
+			Status:         model.DeliveryPending,
+			Attempts:       []model.Attempt{},
+			NextAttemptAt:  &now,
+			CreatedAt:      now,
+			UpdatedAt:      now,
+		}
+		if errCreate := d.repo.CreateDelivery(delivery); errCreate != nil {
+			if errCreate.Code == http.StatusConflict {
+				report.Skipped++
+				continue
+			}
+			return nil, errCreate
+		}
+		if errAttempt := d.attempt(delivery, subscription); errAttempt != nil {
+			return nil, errAttempt
+		}
+		report.add(delivery.Status)
+	}
+
+	log.Printf("Dispatched event %s (%s): %+v", event.ID, event.Type, report)
+	return report, nil
+}
+
+// RetryDue sends up to limit deliveries whose next attempt is due. A
+// delivery whose subscription was deleted or deactivated is dead-lettered.
+func (d *Dispatcher) RetryDue(limit int) (*Report, *appError.Error) {
+	if limit < 1 {
+		limit = DefaultRetryBatch
+	}
+	deliveries, err := d.repo.GetDueDeliveries(d.now(), limit)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &Report{}
+	subscriptions := make(map[string]*model.Subscription)
+	for i := range deliveries {
+		delivery := &deliveries[i]
+		subscription, ok := subscriptions[delivery.SubscriptionID]
+		if !ok {
+			var errGet *appError.Error
+			subscription, errGet = d.repo.GetSubscriptionByID(delivery.SubscriptionID)
+			if errGet != nil && errGet.Code != http.StatusNotFound {
+				return nil, errGet
+			}
+			subscriptions[delivery.SubscriptionID] = subscription
+		}
+
+		if subscription == nil || !subscription.Active {
+			d.deadLetter(delivery, "Subscription was deleted or deactivated.")
+			if errSave := d.repo.SaveDelivery(delivery); errSave != nil {
+				return nil, errSave
+			}
+		} else if errAttempt := d.attempt(delivery, subscription); errAttempt != nil {
+			return nil, errAttempt
+		}
+		report.add(delivery.Status)
+	}
+
+	log.Printf("Retried %d due deliveries: %+v", len(deliveries), report)
+	return report, nil
+}
+
+// attempt sends the delivery once, logs the attempt and schedules the next
+// one or dead-letters it, then saves it.
+func (d *Dispatcher) attempt(delivery *model.Delivery, subscription *model.Subscription) *appError.Error {
+	attempt := d.send(delivery, subscription)
+	delivery.Attempts = append(delivery.Attempts, attempt)
+	delivery.UpdatedAt = attempt.At
+
+	switch {
+	case model.Succeeded(attempt.StatusCode):
+		delivery.Status = model.DeliveryDelivered
+		delivery.NextAttemptAt = nil
+	case len(delivery.Attempts) >= d.options.MaxAttempts:
+		d.deadLetter(delivery, fmt.Sprintf("Gave up after %d attempts.", len(delivery.Attempts)))
+	default:
+		next := attempt.At.Add(d.options.RetryDelay(len(delivery.Attempts)))
+		delivery.Status = model.DeliveryRetrying
+		delivery.NextAttemptAt = &next
+	}
+	return d.repo.SaveDelivery(delivery)
+}
+
+func (d *Dispatcher) deadLetter(delivery *model.Delivery, reason string) {
+	log.Printf("Dead-lettering delivery %s of event %s to %s: %s", delivery.ID, delivery.EventID, delivery.URL, reason)
+	delivery.Status = model.DeliveryDead
+	delivery.NextAttemptAt = nil
+	delivery.Reason = reason
+	delivery.UpdatedAt = d.now()
+}
+
+func (d *Dispatcher) send(delivery *model.Delivery, subscription *model.Subscription) model.Attempt {
+	at := d.now()
+	started := time.Now()
+	attempt := model.Attempt{At: at}
+
+	body := []byte(delivery.Payload)
+	request, err := http.NewRequest(http.MethodPost, subscription.URL, bytes.NewReader(body))
+	if err != nil {
+		attempt.Error = err.Error()
+		return attempt
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("User-Agent", userAgent)
+	request.Header.Set(HeaderDelivery, delivery.ID)
+	request.Header.Set(HeaderEvent, delivery.EventType)
+	request.Header.Set(HeaderEventID, delivery.EventID)
+	request.Header.Set(HeaderTimestamp, strconv.FormatInt(at.Unix(), 10))
+	request.Header.Set(HeaderSignature, Sign(subscription.Secret, at.Unix(), body))
+
+	response, err := d.client.Do(request)
+	attempt.DurationMs = time.Since(started).Milliseconds()
+	if err != nil {
+		log.Printf("Error delivering %s to %s: %v", delivery.ID, subscription.URL, err)
+		attempt.Error = err.Error()
+		return attempt
+	}
+	defer response.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(response.Body, maxResponseBytes))
+
+	attempt.StatusCode = response.StatusCode
+	if !model.Succeeded(response.StatusCode) {
+		attempt.Error = response.Status
+	}
+	log.Printf("Sent delivery %s to %s, status: %d, attempt: %d", delivery.ID, subscription.URL, response.StatusCode, len(delivery.Attempts)+1)
+	return attempt
+}
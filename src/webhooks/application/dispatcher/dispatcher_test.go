@@ -0,0 +1,238 @@
+package dispatcher_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	bookModel "main/src/books/domain/model"
+	"main/src/webhooks/application/dispatcher"
+	"main/src/webhooks/domain/model"
+	appError "main/utils/error"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	repoMock "main/mocks"
+)
+
+type DispatcherSuite struct {
+	suite.Suite
+	repo         *repoMock.WebhookRepository
+	receiver     *httptest.Server
+	mu           sync.Mutex
+	status       int
+	requests     []*http.Request
+	bodies       [][]byte
+	subscription model.Subscription
+	event        *bookModel.BookEvent
+	dispatcher   *dispatcher.Dispatcher
+	saved        []model.Delivery
+}
+
+const (
+	MethodGetSubscriptions    = "GetSubscriptions"
+	MethodGetSubscriptionByID = "GetSubscriptionByID"
+	MethodCreateDelivery      = "CreateDelivery"
+	MethodSaveDelivery        = "SaveDelivery"
+	MethodGetDueDeliveries    = "GetDueDeliveries"
+)
+
+const secret = "a-very-secret-signing-key"
+
+func (suite *DispatcherSuite) SetupTest() {
+	suite.status = http.StatusOK
+	suite.requests = nil
+	suite.bodies = nil
+	suite.saved = nil
+	suite.receiver = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		suite.mu.Lock()
+		defer suite.mu.Unlock()
+		suite.requests = append(suite.requests, r)
+		suite.bodies = append(suite.bodies, body)
+		w.WriteHeader(suite.status)
+	}))
+
+	suite.subscription = model.Subscription{
+		ID:         uuid.NewString(),
+		URL:        suite.receiver.URL + "/hooks/books",
+		EventTypes: []string{string(bookModel.BookCreated)},
+		Secret:     secret,
+		Active:     true,
+	}
+	suite.event = &bookModel.BookEvent{
+		ID:         uuid.NewString(),
+		Type:       bookModel.BookCreated,
+		BookID:     uuid.NewString(),
+		OccurredAt: time.Now().UTC(),
+		Book:       &bookModel.Book{Name: "Dune"},
+	}
+
+	suite.repo = new(repoMock.WebhookRepository)
+	suite.repo.On(MethodSaveDelivery, mock.Anything).Run(func(args mock.Arguments) {
+		suite.saved = append(suite.saved, *args.Get(0).(*model.Delivery))
+	}).Return(nil)
+	suite.dispatcher = dispatcher.NewDispatcher(suite.repo, suite.receiver.Client(), dispatcher.Options{
+		MaxAttempts: 3,
+		BaseDelay:   time.Minute,
+		MaxDelay:    time.Hour,
+	})
+}
+
+func (suite *DispatcherSuite) TearDownTest() {
+	suite.receiver.Close()
+}
+
+func (suite *DispatcherSuite) TestDispatchSignsAndDelivers() {
+	other := model.Subscription{ID: uuid.NewString(), URL: suite.receiver.URL, EventTypes: []string{string(bookModel.BookDeleted)}, Secret: secret, Active: true}
+	suite.repo.On(MethodGetSubscriptions).Return([]model.Subscription{suite.subscription, other}, nil)
+	suite.repo.On(MethodCreateDelivery, mock.Anything).Return(nil)
+
+	report, err := suite.dispatcher.Dispatch(suite.event)
+	suite.Nil(err)
+	suite.Equal(&dispatcher.Report{Delivered: 1}, report)
+
+	suite.Require().Len(suite.requests, 1)
+	request := suite.requests[0]
+	suite.Equal("/hooks/books", request.URL.Path)
+	suite.Equal(string(bookModel.BookCreated), request.Header.Get(dispatcher.HeaderEvent))
+	suite.Equal(suite.event.ID, request.Header.Get(dispatcher.HeaderEventID))
+	suite.Equal(model.DeliveryID(suite.subscription.ID, suite.event.ID), request.Header.Get(dispatcher.HeaderDelivery))
+	suite.True(dispatcher.Verify(secret, request.Header.Get(dispatcher.HeaderTimestamp), request.Header.Get(dispatcher.HeaderSignature), suite.bodies[0], 5*time.Minute, time.Now()))
+	suite.False(dispatcher.Verify("another-secret-entirely", request.Header.Get(dispatcher.HeaderTimestamp), request.Header.Get(dispatcher.HeaderSignature), suite.bodies[0], 5*time.Minute, time.Now()))
+	suite.Contains(string(suite.bodies[0]), `"name":"Dune"`)
+
+	suite.Require().Len(suite.saved, 1)
+	suite.Equal(model.DeliveryDelivered, suite.saved[0].Status)
+	suite.Equal(http.StatusOK, suite.saved[0].Attempts[0].StatusCode)
+	suite.Nil(suite.saved[0].NextAttemptAt)
+}
+
+func (suite *DispatcherSuite) TestDispatchSkipsDeliveredEvents() {
+	suite.repo.On(MethodGetSubscriptions).Return([]model.Subscription{suite.subscription}, nil)
+	suite.repo.On(MethodCreateDelivery, mock.Anything).Return(appError.NewConflictError("exists"))
+
+	report, err := suite.dispatcher.Dispatch(suite.event)
+	suite.Nil(err)
+	suite.Equal(&dispatcher.Report{Skipped: 1}, report)
+	suite.Empty(suite.requests)
+}
+
+func (suite *DispatcherSuite) TestDispatchSchedulesRetryWithBackoff() {
+	suite.status = http.StatusServiceUnavailable
+	suite.repo.On(MethodGetSubscriptions).Return([]model.Subscription{suite.subscription}, nil)
+	suite.repo.On(MethodCreateDelivery, mock.Anything).Return(nil)
+
+	report, err := suite.dispatcher.Dispatch(suite.event)
+	suite.Nil(err)
+	suite.Equal(&dispatcher.Report{Retrying: 1}, report)
+
+	delivery := suite.saved[0]
+	suite.Equal(model.DeliveryRetrying, delivery.Status)
+	suite.Equal(http.StatusServiceUnavailable, delivery.Attempts[0].StatusCode)
+	suite.NotEmpty(delivery.Attempts[0].Error)
+	suite.Equal(delivery.Attempts[0].At.Add(time.Minute), *delivery.NextAttemptAt)
+}
+
+func (suite *DispatcherSuite) TestRetryDeadLettersAfterMaxAttempts() {
+	suite.status = http.StatusInternalServerError
+	at := time.Now().UTC().Add(-time.Minute)
+	due := model.Delivery{
+		ID:             model.DeliveryID(suite.subscription.ID, suite.event.ID),
+		SubscriptionID: suite.subscription.ID,
+		EventID:        suite.event.ID,
+		Payload:        `{"id":"1"}`,
+		Status:         model.DeliveryRetrying,
+		Attempts:       []model.Attempt{{At: at, StatusCode: 500}},
+		NextAttemptAt:  &at,
+	}
+	suite.repo.On(MethodGetDueDeliveries, mock.Anything, dispatcher.DefaultRetryBatch).Return([]model.Delivery{due}, nil).Once()
+	suite.repo.On(MethodGetSubscriptionByID, suite.subscription.ID).Return(&suite.subscription, nil)
+
+	report, err := suite.dispatcher.RetryDue(0)
+	suite.Nil(err)
+	suite.Equal(&dispatcher.Report{Retrying: 1}, report)
+	second := suite.saved[0]
+	suite.Equal(second.Attempts[1].At.Add(2*time.Minute), *second.NextAttemptAt, "the wait doubles")
+
+	suite.repo.On(MethodGetDueDeliveries, mock.Anything, 10).Return([]model.Delivery{second}, nil).Once()
+	report, err = suite.dispatcher.RetryDue(10)
+	suite.Nil(err)
+	suite.Equal(&dispatcher.Report{Dead: 1}, report)
+	dead := suite.saved[1]
+	suite.Equal(model.DeliveryDead, dead.Status)
+	suite.Len(dead.Attempts, 3)
+	suite.Nil(dead.NextAttemptAt)
+	suite.NotEmpty(dead.Reason)
+	suite.Len(suite.requests, 2)
+}
+
+func (suite *DispatcherSuite) TestRetryRecoversAndDeadLettersRemovedSubscriptions() {
+	at := time.Now().UTC().Add(-time.Minute)
+	removedID := uuid.NewString()
+	deliveries := []model.Delivery{
+		{ID: "1", SubscriptionID: suite.subscription.ID, Payload: "{}", Status: model.DeliveryRetrying, NextAttemptAt: &at},
+		{ID: "2", SubscriptionID: removedID, Payload: "{}", Status: model.DeliveryRetrying, NextAttemptAt: &at},
+	}
+	suite.repo.On(MethodGetDueDeliveries, mock.Anything, 10).Return(deliveries, nil)
+	suite.repo.On(MethodGetSubscriptionByID, suite.subscription.ID).Return(&suite.subscription, nil)
+	suite.repo.On(MethodGetSubscriptionByID, removedID).Return(nil, appError.NewNotFoundError("missing"))
+
+	report, err := suite.dispatcher.RetryDue(10)
+	suite.Nil(err)
+	suite.Equal(&dispatcher.Report{Delivered: 1, Dead: 1}, report)
+	suite.Len(suite.requests, 1)
+	suite.Empty(suite.saved[1].Attempts)
+}
+
+func (suite *DispatcherSuite) TestUnreachableReceiver() {
+	suite.receiver.Close()
+	suite.repo.On(MethodGetSubscriptions).Return([]model.Subscription{suite.subscription}, nil)
+	suite.repo.On(MethodCreateDelivery, mock.Anything).Return(nil)
+
+	report, err := suite.dispatcher.Dispatch(suite.event)
+	suite.Nil(err)
+	suite.Equal(1, report.Retrying)
+	suite.Zero(suite.saved[0].Attempts[0].StatusCode)
+	suite.NotEmpty(suite.saved[0].Attempts[0].Error)
+}
+
+func (suite *DispatcherSuite) TestDefaultClientRefusesPrivateAddresses() {
+	suite.repo.On(MethodGetSubscriptions).Return([]model.Subscription{suite.subscription}, nil)
+	suite.repo.On(MethodCreateDelivery, mock.Anything).Return(nil)
+
+	// The receiver listens on the loopback address.
+	report, err := dispatcher.NewDispatcher(suite.repo, nil, dispatcher.DefaultOptions()).Dispatch(suite.event)
+	suite.Nil(err)
+	suite.Equal(1, report.Retrying)
+	suite.Empty(suite.requests)
+	suite.Contains(suite.saved[0].Attempts[0].Error, "not public")
+}
+
+func (suite *DispatcherSuite) TestRetryDelay() {
+	options := dispatcher.DefaultOptions()
+	suite.Equal(time.Minute, options.RetryDelay(1))
+	suite.Equal(8*time.Minute, options.RetryDelay(4))
+	suite.Equal(options.MaxDelay, options.RetryDelay(20))
+	suite.Equal(options.MaxDelay, options.RetryDelay(100))
+}
+
+func (suite *DispatcherSuite) TestVerifyRejectsStaleTimestamps() {
+	body := []byte(`{"id":"1"}`)
+	signedAt := time.Now().Add(-time.Hour)
+	signature := dispatcher.Sign(secret, signedAt.Unix(), body)
+	timestamp := strconv.FormatInt(signedAt.Unix(), 10)
+	suite.True(dispatcher.Verify(secret, timestamp, signature, body, 2*time.Hour, time.Now()))
+	suite.False(dispatcher.Verify(secret, timestamp, signature, body, 5*time.Minute, time.Now()))
+	suite.False(dispatcher.Verify(secret, "not-a-timestamp", signature, body, 2*time.Hour, time.Now()))
+}
+
+func TestDispatcherSuite(t *testing.T) {
+	suite.Run(t, new(DispatcherSuite))
+}
@@ -0,0 +1,92 @@
+package handler
+
+import (
+	"context"
+
+	bookHandler "main/src/books/application/handler"
+	bookModel "main/src/books/domain/model"
+	"main/src/webhooks/application/dispatcher"
+	"main/src/webhooks/application/service"
+	"main/src/webhooks/domain/model"
+	"main/src/webhooks/infrastructure/adapter"
+	"main/src/webhooks/infrastructure/configuration"
+	appError "main/utils/error"
+)
+
+type MicroAWSWebhookDynamoDB struct {
+	Ctx             context.Context
+	WebhooksTable   string
+	DeliveriesTable string
+}
+
+func (micro *MicroAWSWebhookDynamoDB) repository() (*adapter.WebhookDynamoDBRepository, *appError.Error) {
+	dynamoClient, err := bookHandler.DefaultBookContainer().DynamoDBClient(micro.Ctx)
+	if err != nil {
+		return nil, err
+	}
+	if micro.WebhooksTable == "" {
+		micro.WebhooksTable = configuration.GetDynamoDBWebhookTable()
+	}
+	if micro.DeliveriesTable == "" {
+		micro.DeliveriesTable = configuration.GetDynamoDBDeliveryTable()
+	}
+	return adapter.NewWebhookDynamoDBRepository(micro.Ctx, dynamoClient, micro.WebhooksTable, micro.DeliveriesTable), nil
+}
+
+func (micro *MicroAWSWebhookDynamoDB) webhookService() (service.WebhookService, *appError.Error) {
+	webhookInfrastructure, err := micro.repository()
+	if err != nil {
+		return nil, err
+	}
+	return service.NewWebhookServiceDynamoDB(webhookInfrastructure, configuration.IsLocal()), nil
+}
+
+func (micro *MicroAWSWebhookDynamoDB) CreateWebhook(subscription *model.Subscription) (*model.Subscription, *appError.Error) {
+	webhookService, err := micro.webhookService()
+	if err != nil {
+		return nil, err
+	}
+	return webhookService.CreateSubscription(subscription)
+}
+
+func (micro *MicroAWSWebhookDynamoDB) GetWebhooks() ([]model.Subscription, *appError.Error) {
+	webhookService, err := micro.webhookService()
+	if err != nil {
+		return nil, err
+	}
+	return webhookService.GetSubscriptions()
+}
+
+func (micro *MicroAWSWebhookDynamoDB) DeleteWebhook(webhookID string) *appError.Error {
+	webhookService, err := micro.webhookService()
+	if err != nil {
+		return err
+	}
+	return webhookService.DeleteSubscription(webhookID)
+}
+
+func (micro *MicroAWSWebhookDynamoDB) GetWebhookDeliveries(webhookID string, status model.DeliveryStatus, limit int) ([]model.Delivery, *appError.Error) {
+	webhookService, err := micro.webhookService()
+	if err != nil {
+		return nil, err
+	}
+	return webhookService.GetDeliveries(webhookID, status, limit)
+}
+
+// DispatchBookEvent delivers a book event to the subscriptions wanting it.
+func (micro *MicroAWSWebhookDynamoDB) DispatchBookEvent(event *bookModel.BookEvent, options dispatcher.Options) (*dispatcher.Report, *appError.Error) {
+	webhookInfrastructure, err := micro.repository()
+	if err != nil {
+		return nil, err
+	}
+	return dispatcher.NewDispatcher(webhookInfrastructure, nil, options).Dispatch(event)
+}
+
+// RetryWebhookDeliveries sends again up to limit deliveries that are due.
+func (micro *MicroAWSWebhookDynamoDB) RetryWebhookDeliveries(options dispatcher.Options, limit int) (*dispatcher.Report, *appError.Error) {
+	webhookInfrastructure, err := micro.repository()
+	if err != nil {
+		return nil, err
+	}
+	return dispatcher.NewDispatcher(webhookInfrastructure, nil, options).RetryDue(limit)
+}
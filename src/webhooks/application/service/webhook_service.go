@@ -0,0 +1,13 @@
+package service
+
+import (
+	"main/src/webhooks/domain/model"
+	appError "main/utils/error"
+)
+
+type WebhookService interface {
+	CreateSubscription(*model.Subscription) (*model.Subscription, *appError.Error)
+	GetSubscriptions() ([]model.Subscription, *appError.Error)
+	DeleteSubscription(string) *appError.Error
+	GetDeliveries(string, model.DeliveryStatus, int) ([]model.Delivery, *appError.Error)
+}
@@ -0,0 +1,104 @@
+package service
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"main/src/webhooks/domain/model"
+	"main/src/webhooks/domain/repository"
+	appError "main/utils/error"
+	"main/utils/lib"
+)
+
+const (
+	DefaultDeliveries = 50
+	MaxDeliveries     = 100
+	// secretBytes is the size of a generated secret, hex encoded.
+	secretBytes = 32
+)
+
+type WebhookServiceDynamoDB struct {
+	repo repository.WebhookRepository
+	// allowHTTP accepts endpoints without TLS, for local mode only.
+	allowHTTP bool
+}
+
+func NewWebhookServiceDynamoDB(repo repository.WebhookRepository, allowHTTP bool) WebhookService {
+	return &WebhookServiceDynamoDB{
+		repo:      repo,
+		allowHTTP: allowHTTP,
+	}
+}
+
+// CreateSubscription registers an active subscription. Without a secret one
+// is generated; the response is the only time it is shown. Endpoints must
+// use https outside local mode; those whose name resolves to a private
+// address are refused when the dispatcher dials them.
+func (service *WebhookServiceDynamoDB) CreateSubscription(subscription *model.Subscription) (*model.Subscription, *appError.Error) {
+	subscription.ID = uuid.NewString()
+	subscription.Active = true
+	subscription.CreatedAt = time.Now().UTC().Truncate(time.Second)
+	if subscription.Secret == "" {
+		secret := make([]byte, secretBytes)
+		if _, err := rand.Read(secret); err != nil {
+			return nil, appError.NewUnexpectedError(err.Error())
+		}
+		subscription.Secret = hex.EncodeToString(secret)
+	}
+	if err := subscription.Validate(); err != nil {
+		return nil, err
+	}
+	if !service.allowHTTP && !strings.HasPrefix(strings.ToLower(subscription.URL), "https://") {
+		return nil, appError.NewValidationError("URL must be an https URL.")
+	}
+	if err := service.repo.CreateSubscription(subscription); err != nil {
+		return nil, err
+	}
+	return subscription, nil
+}
+
+// GetSubscriptions lists the subscriptions without their secrets.
+func (service *WebhookServiceDynamoDB) GetSubscriptions() ([]model.Subscription, *appError.Error) {
+	subscriptions, err := service.repo.GetSubscriptions()
+	if err != nil {
+		return nil, err
+	}
+	for i := range subscriptions {
+		subscriptions[i].Secret = ""
+	}
+	return subscriptions, nil
+}
+
+// DeleteSubscription stops new deliveries to the subscription; pending
+// retries are dead-lettered when they come due.
+func (service *WebhookServiceDynamoDB) DeleteSubscription(id string) *appError.Error {
+	if err := lib.ValidateUUID(id); err != nil {
+		return err
+	}
+	return service.repo.DeleteSubscription(id)
+}
+
+// GetDeliveries returns the delivery log of a subscription, newest first.
+// Filtering by DeliveryDead lists its dead letters.
+func (service *WebhookServiceDynamoDB) GetDeliveries(subscriptionID string, status model.DeliveryStatus, limit int) ([]model.Delivery, *appError.Error) {
+	if err := lib.ValidateUUID(subscriptionID); err != nil {
+		return nil, err
+	}
+	if status != "" && !status.IsValid() {
+		return nil, appError.NewValidationError("Unknown delivery status: " + string(status))
+	}
+	if limit == 0 {
+		limit = DefaultDeliveries
+	}
+	if limit < 1 || limit > MaxDeliveries {
+		return nil, appError.NewValidationError(fmt.Sprintf("limit must be a number between 1 and %d.", MaxDeliveries))
+	}
+	if _, err := service.repo.GetSubscriptionByID(subscriptionID); err != nil {
+		return nil, err
+	}
+	return service.repo.GetDeliveriesBySubscription(subscriptionID, status, limit)
+}
@@ -0,0 +1,123 @@
+package service_test
+
+import (
+	"testing"
+
+	"main/src/webhooks/application/service"
+	"main/src/webhooks/domain/model"
+	appError "main/utils/error"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	repoMock "main/mocks"
+)
+
+type WebhookServiceDynamoDBSuite struct {
+	suite.Suite
+	webhookRepository *repoMock.WebhookRepository
+	webhookService    service.WebhookService
+}
+
+const (
+	MethodCreateSubscription          = "CreateSubscription"
+	MethodGetSubscriptions            = "GetSubscriptions"
+	MethodGetSubscriptionByID         = "GetSubscriptionByID"
+	MethodDeleteSubscription          = "DeleteSubscription"
+	MethodGetDeliveriesBySubscription = "GetDeliveriesBySubscription"
+)
+
+func (suite *WebhookServiceDynamoDBSuite) SetupTest() {
+	suite.webhookRepository = new(repoMock.WebhookRepository)
+	suite.webhookService = service.NewWebhookServiceDynamoDB(suite.webhookRepository, false)
+}
+
+func (suite *WebhookServiceDynamoDBSuite) TestCreateSubscriptionGeneratesSecret() {
+	suite.webhookRepository.On(MethodCreateSubscription, mock.Anything).Return(nil)
+	created, err := suite.webhookService.CreateSubscription(&model.Subscription{
+		ID:         "ignored",
+		URL:        "https://partner.example/hooks",
+		EventTypes: []string{model.AllEvents},
+		Active:     false,
+	})
+	suite.Nil(err)
+	suite.Nil(uuid.Validate(created.ID))
+	suite.True(created.Active)
+	suite.Len(created.Secret, 64)
+	suite.False(created.CreatedAt.IsZero())
+	suite.webhookRepository.AssertExpectations(suite.T())
+}
+
+func (suite *WebhookServiceDynamoDBSuite) TestCreateSubscriptionInvalid() {
+	_, err := suite.webhookService.CreateSubscription(&model.Subscription{
+		URL:        "https://partner.example/hooks",
+		EventTypes: []string{model.AllEvents},
+		Secret:     "short",
+	})
+	suite.NotNil(err)
+	suite.webhookRepository.AssertNotCalled(suite.T(), MethodCreateSubscription, mock.Anything)
+}
+
+func (suite *WebhookServiceDynamoDBSuite) TestCreateSubscriptionRequiresHTTPS() {
+	subscription := model.Subscription{URL: "http://partner.example/hooks", EventTypes: []string{model.AllEvents}}
+	_, err := suite.webhookService.CreateSubscription(&subscription)
+	suite.NotNil(err)
+	suite.webhookRepository.AssertNotCalled(suite.T(), MethodCreateSubscription, mock.Anything)
+
+	suite.webhookRepository.On(MethodCreateSubscription, mock.Anything).Return(nil)
+	local := service.NewWebhookServiceDynamoDB(suite.webhookRepository, true)
+	_, err = local.CreateSubscription(&subscription)
+	suite.Nil(err)
+}
+
+func (suite *WebhookServiceDynamoDBSuite) TestGetSubscriptionsHidesSecrets() {
+	suite.webhookRepository.On(MethodGetSubscriptions).Return([]model.Subscription{
+		{ID: uuid.NewString(), Secret: "0123456789abcdef"},
+		{ID: uuid.NewString(), Secret: "fedcba9876543210"},
+	}, nil)
+	subscriptions, err := suite.webhookService.GetSubscriptions()
+	suite.Nil(err)
+	suite.Len(subscriptions, 2)
+	for _, subscription := range subscriptions {
+		suite.Empty(subscription.Secret)
+	}
+}
+
+func (suite *WebhookServiceDynamoDBSuite) TestDeleteSubscriptionInvalidID() {
+	err := suite.webhookService.DeleteSubscription("invalid")
+	suite.NotNil(err)
+	suite.webhookRepository.AssertNotCalled(suite.T(), MethodDeleteSubscription, mock.Anything)
+}
+
+func (suite *WebhookServiceDynamoDBSuite) TestGetDeliveries() {
+	id := uuid.NewString()
+	dead := []model.Delivery{{ID: "1", SubscriptionID: id, Status: model.DeliveryDead}}
+	suite.webhookRepository.On(MethodGetSubscriptionByID, id).Return(&model.Subscription{ID: id}, nil)
+	suite.webhookRepository.On(MethodGetDeliveriesBySubscription, id, model.DeliveryDead, service.DefaultDeliveries).Return(dead, nil)
+
+	deliveries, err := suite.webhookService.GetDeliveries(id, model.DeliveryDead, 0)
+	suite.Nil(err)
+	suite.Equal(dead, deliveries)
+	suite.webhookRepository.AssertExpectations(suite.T())
+}
+
+func (suite *WebhookServiceDynamoDBSuite) TestGetDeliveriesInvalidFilters() {
+	id := uuid.NewString()
+	_, err := suite.webhookService.GetDeliveries(id, "lost", 10)
+	suite.NotNil(err)
+	_, err = suite.webhookService.GetDeliveries(id, "", service.MaxDeliveries+1)
+	suite.NotNil(err)
+	suite.webhookRepository.AssertNotCalled(suite.T(), MethodGetDeliveriesBySubscription, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *WebhookServiceDynamoDBSuite) TestGetDeliveriesUnknownSubscription() {
+	id := uuid.NewString()
+	suite.webhookRepository.On(MethodGetSubscriptionByID, id).Return(nil, appError.NewNotFoundError("Webhook "+id+" not found."))
+	_, err := suite.webhookService.GetDeliveries(id, "", 10)
+	suite.Equal(404, err.Code)
+}
+
+func TestWebhookServiceDynamoDBSuite(t *testing.T) {
+	suite.Run(t, new(WebhookServiceDynamoDBSuite))
+}
@@ -0,0 +1,63 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type DeliveryStatus string
+
+const (
+	DeliveryPending   DeliveryStatus = "pending"
+	DeliveryRetrying  DeliveryStatus = "retrying"
+	DeliveryDelivered DeliveryStatus = "delivered"
+	// DeliveryDead deliveries ran out of attempts and are kept for inspection.
+	DeliveryDead DeliveryStatus = "dead"
+)
+
+func (s DeliveryStatus) IsValid() bool {
+	switch s {
+	case DeliveryPending, DeliveryRetrying, DeliveryDelivered, DeliveryDead:
+		return true
+	}
+	return false
+}
+
+// Delivery is one event sent to one subscription, with the log of its
+// attempts. The payload is kept so every attempt sends the same body.
+type Delivery struct {
+	ID             string         `json:"ID" dynamodbav:"ID"`
+	SubscriptionID string         `json:"subscription_id" dynamodbav:"subscription_id"`
+	EventID        string         `json:"event_id" dynamodbav:"event_id"`
+	EventType      string         `json:"event_type" dynamodbav:"event_type"`
+	URL            string         `json:"url" dynamodbav:"url"`
+	Payload        string         `json:"payload" dynamodbav:"payload"`
+	Status         DeliveryStatus `json:"status" dynamodbav:"status"`
+	Attempts       []Attempt      `json:"attempts" dynamodbav:"attempts"`
+	NextAttemptAt  *time.Time     `json:"next_attempt_at,omitempty" dynamodbav:"next_attempt_at,omitempty"`
+	// Reason tells why a dead delivery was given up.
+	Reason    string    `json:"reason,omitempty" dynamodbav:"reason,omitempty"`
+	CreatedAt time.Time `json:"created_at" dynamodbav:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" dynamodbav:"updated_at"`
+}
+
+// Attempt records one request of a delivery. StatusCode is zero when no
+// response arrived.
+type Attempt struct {
+	At         time.Time `json:"at" dynamodbav:"at"`
+	StatusCode int       `json:"status_code,omitempty" dynamodbav:"status_code,omitempty"`
+	DurationMs int64     `json:"duration_ms" dynamodbav:"duration_ms"`
+	Error      string    `json:"error,omitempty" dynamodbav:"error,omitempty"`
+}
+
+// DeliveryID is derived from the subscription and the event, so an event
+// relayed twice maps to the same delivery and is only sent once.
+func DeliveryID(subscriptionID, eventID string) string {
+	return uuid.NewSHA1(uuid.NameSpaceURL, []byte(subscriptionID+"/"+eventID)).String()
+}
+
+// Succeeded reports whether a response code acknowledges a delivery.
+func Succeeded(statusCode int) bool {
+	return statusCode >= 200 && statusCode < 300
+}
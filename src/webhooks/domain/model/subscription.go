@@ -0,0 +1,107 @@
+package model
+
+import (
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	bookModel "main/src/books/domain/model"
+	appError "main/utils/error"
+	"main/utils/lib"
+)
+
+const (
+	// AllEvents subscribes to every book event type.
+	AllEvents = "*"
+
+	MinSecretCharacters = 16
+	MaxURLCharacters    = 2048
+)
+
+var EventTypes = []bookModel.BookEventType{
+	bookModel.BookCreated,
+	bookModel.BookUpdated,
+	bookModel.BookDeleted,
+	bookModel.BookImageChanged,
+}
+
+// Subscription is a partner endpoint receiving the book events it asked for.
+// The secret signs every delivery; it is only shown when the subscription is
+// created.
+type Subscription struct {
+	ID         string    `json:"ID,omitempty" dynamodbav:"ID,omitempty"`
+	URL        string    `json:"url,omitempty" dynamodbav:"url,omitempty"`
+	EventTypes []string  `json:"event_types,omitempty" dynamodbav:"event_types,omitempty"`
+	Secret     string    `json:"secret,omitempty" dynamodbav:"secret,omitempty"`
+	Active     bool      `json:"active" dynamodbav:"active"`
+	CreatedAt  time.Time `json:"created_at" dynamodbav:"created_at"`
+}
+
+func (s *Subscription) Validate() *appError.Error {
+	if err := lib.ValidateUUID(s.ID); err != nil {
+		return err
+	}
+	if len(s.URL) > MaxURLCharacters {
+		return appError.NewValidationError("URL cannot exceed 2048 characters.")
+	}
+	endpoint, err := url.Parse(s.URL)
+	if err != nil || (endpoint.Scheme != "https" && endpoint.Scheme != "http") || endpoint.Host == "" {
+		return appError.NewValidationError("URL must be an absolute http or https URL.")
+	}
+	host := strings.ToLower(endpoint.Hostname())
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return appError.NewValidationError("URL must not point to this host.")
+	}
+	if ip := net.ParseIP(host); ip != nil && !PublicAddress(ip) {
+		return appError.NewValidationError("URL must not point to a loopback, link-local or private address.")
+	}
+	if len(s.EventTypes) == 0 {
+		return appError.NewValidationError("At least one event type is required.")
+	}
+	for _, eventType := range s.EventTypes {
+		if !isEventType(eventType) {
+			return appError.NewValidationError("Unknown event type: " + eventType)
+		}
+	}
+	if len(s.Secret) < MinSecretCharacters {
+		return appError.NewValidationError("Secret must have at least 16 characters.")
+	}
+	return nil
+}
+
+// sharedAddressSpace is the carrier-grade NAT range, private in practice.
+var sharedAddressSpace = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+// PublicAddress reports whether deliveries may be sent to ip: not a
+// loopback, link-local, private, unspecified or multicast address, which
+// would let subscribers reach the metadata service or hosts of the VPC.
+func PublicAddress(ip net.IP) bool {
+	return !ip.IsLoopback() && !ip.IsLinkLocalUnicast() && !ip.IsPrivate() &&
+		!ip.IsUnspecified() && !ip.IsMulticast() && !sharedAddressSpace.Contains(ip)
+}
+
+func isEventType(eventType string) bool {
+	if eventType == AllEvents {
+		return true
+	}
+	for _, known := range EventTypes {
+		if eventType == string(known) {
+			return true
+		}
+	}
+	return false
+}
+
+// Matches reports whether the subscription wants events of eventType.
+func (s *Subscription) Matches(eventType bookModel.BookEventType) bool {
+	if !s.Active {
+		return false
+	}
+	for _, wanted := range s.EventTypes {
+		if wanted == AllEvents || wanted == string(eventType) {
+			return true
+		}
+	}
+	return false
+}
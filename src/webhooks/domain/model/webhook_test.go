@@ -0,0 +1,86 @@
+package model_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	bookModel "main/src/books/domain/model"
+	"main/src/webhooks/domain/model"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type WebhookModelSuite struct {
+	suite.Suite
+}
+
+const (
+	subscriptionID = "123e4567-e89b-12d3-a456-426614174000"
+	secret         = "0123456789abcdef"
+)
+
+func (s *WebhookModelSuite) TestValidate() {
+	created := []string{string(bookModel.BookCreated)}
+	var tests = []struct {
+		name         string
+		subscription model.Subscription
+		expected     bool // true if no error is expected, false otherwise
+	}{
+		{"valid", model.Subscription{ID: subscriptionID, URL: "https://partner.example/hooks", EventTypes: created, Secret: secret}, true},
+		{"all_events", model.Subscription{ID: subscriptionID, URL: "http://partner.example", EventTypes: []string{model.AllEvents}, Secret: secret}, true},
+		{"invalid_id", model.Subscription{ID: "invalid", URL: "https://partner.example", EventTypes: created, Secret: secret}, false},
+		{"relative_url", model.Subscription{ID: subscriptionID, URL: "/hooks", EventTypes: created, Secret: secret}, false},
+		{"ftp_url", model.Subscription{ID: subscriptionID, URL: "ftp://partner.example", EventTypes: created, Secret: secret}, false},
+		{"url_too_long", model.Subscription{ID: subscriptionID, URL: "https://partner.example/" + strings.Repeat("a", 2048), EventTypes: created, Secret: secret}, false},
+		{"no_events", model.Subscription{ID: subscriptionID, URL: "https://partner.example", Secret: secret}, false},
+		{"unknown_event", model.Subscription{ID: subscriptionID, URL: "https://partner.example", EventTypes: []string{"BookBurned"}, Secret: secret}, false},
+		{"short_secret", model.Subscription{ID: subscriptionID, URL: "https://partner.example", EventTypes: created, Secret: "short"}, false},
+		{"localhost", model.Subscription{ID: subscriptionID, URL: "https://localhost:8080/hooks", EventTypes: created, Secret: secret}, false},
+		{"loopback", model.Subscription{ID: subscriptionID, URL: "https://127.0.0.1/hooks", EventTypes: created, Secret: secret}, false},
+		{"metadata_service", model.Subscription{ID: subscriptionID, URL: "http://169.254.169.254/latest/meta-data", EventTypes: created, Secret: secret}, false},
+		{"private", model.Subscription{ID: subscriptionID, URL: "https://10.0.12.7/hooks", EventTypes: created, Secret: secret}, false},
+		{"ipv6_loopback", model.Subscription{ID: subscriptionID, URL: "https://[::1]/hooks", EventTypes: created, Secret: secret}, false},
+		{"public_ip", model.Subscription{ID: subscriptionID, URL: "https://203.0.113.10/hooks", EventTypes: created, Secret: secret}, true},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			err := tt.subscription.Validate()
+			if tt.expected {
+				s.Nil(err)
+			} else {
+				s.NotNil(err)
+			}
+		})
+	}
+}
+
+func (s *WebhookModelSuite) TestMatches() {
+	subscription := model.Subscription{EventTypes: []string{string(bookModel.BookDeleted)}, Active: true}
+	s.True(subscription.Matches(bookModel.BookDeleted))
+	s.False(subscription.Matches(bookModel.BookCreated))
+
+	subscription.EventTypes = []string{model.AllEvents}
+	s.True(subscription.Matches(bookModel.BookImageChanged))
+
+	subscription.Active = false
+	s.False(subscription.Matches(bookModel.BookImageChanged), "Inactive subscriptions receive nothing")
+}
+
+func (s *WebhookModelSuite) TestDeliveryID() {
+	s.Equal(model.DeliveryID(subscriptionID, "event-1"), model.DeliveryID(subscriptionID, "event-1"))
+	s.NotEqual(model.DeliveryID(subscriptionID, "event-1"), model.DeliveryID(subscriptionID, "event-2"))
+}
+
+func (s *WebhookModelSuite) TestSucceeded() {
+	s.True(model.Succeeded(http.StatusOK))
+	s.True(model.Succeeded(http.StatusNoContent))
+	s.False(model.Succeeded(http.StatusMovedPermanently))
+	s.False(model.Succeeded(http.StatusGone))
+	s.False(model.Succeeded(0))
+}
+
+func TestWebhookModelSuite(t *testing.T) {
+	suite.Run(t, new(WebhookModelSuite))
+}
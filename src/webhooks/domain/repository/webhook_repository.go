@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"time"
+
+	"main/src/webhooks/domain/model"
+	appError "main/utils/error"
+)
+
+type WebhookRepository interface {
+	CreateSubscription(*model.Subscription) *appError.Error
+	GetSubscriptions() ([]model.Subscription, *appError.Error)
+	GetSubscriptionByID(string) (*model.Subscription, *appError.Error)
+	DeleteSubscription(string) *appError.Error
+	CreateDelivery(*model.Delivery) *appError.Error
+	SaveDelivery(*model.Delivery) *appError.Error
+	GetDueDeliveries(time.Time, int) ([]model.Delivery, *appError.Error)
+	GetDeliveriesBySubscription(string, model.DeliveryStatus, int) ([]model.Delivery, *appError.Error)
+}
@@ -0,0 +1,255 @@
+package adapter
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"main/src/webhooks/domain/model"
+	appError "main/utils/error"
+)
+
+// Indexes of the deliveries table. Deliveries waiting for an attempt share
+// the due partition, ordered by their next attempt; the attribute is dropped
+// once they are delivered or dead, so the sparse index only holds the
+// backlog.
+const (
+	SubscriptionIndex = "subscription_id-created_at-index"
+	DueIndex          = "due-next_attempt_at-index"
+	dueAttribute      = "due"
+	duePartition      = "retry"
+)
+
+type WebhookDynamoDBRepository struct {
+	ctx                context.Context
+	client             *dynamodb.Client
+	subscriptionsTable string
+	deliveriesTable    string
+}
+
+func NewWebhookDynamoDBRepository(ctx context.Context, client *dynamodb.Client, subscriptionsTable, deliveriesTable string) *WebhookDynamoDBRepository {
+	return &WebhookDynamoDBRepository{
+		ctx:                ctx,
+		client:             client,
+		subscriptionsTable: subscriptionsTable,
+		deliveriesTable:    deliveriesTable,
+	}
+}
+
+func idKey(id string) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		"ID": &types.AttributeValueMemberS{Value: id},
+	}
+}
+
+func (r *WebhookDynamoDBRepository) CreateSubscription(subscription *model.Subscription) *appError.Error {
+	av, err := attributevalue.MarshalMap(subscription)
+	if err != nil {
+		log.Printf("Error marshaling subscription: %v, ID: %s", err, subscription.ID)
+		return appError.NewUnexpectedError(err.Error())
+	}
+	_, err = r.client.PutItem(r.ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(r.subscriptionsTable),
+		Item:      av,
+	})
+	if err != nil {
+		log.Printf("Error putting subscription in DynamoDB: %v, table: %s", err, r.subscriptionsTable)
+		return appError.NewUnexpectedError(err.Error())
+	}
+	log.Printf("Created webhook subscription, ID: %s, url: %s", subscription.ID, subscription.URL)
+	return nil
+}
+
+// GetSubscriptions scans the whole table; partners number in the tens.
+func (r *WebhookDynamoDBRepository) GetSubscriptions() ([]model.Subscription, *appError.Error) {
+	subscriptions := []model.Subscription{}
+	paginator := dynamodb.NewScanPaginator(r.client, &dynamodb.ScanInput{
+		TableName: aws.String(r.subscriptionsTable),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(r.ctx)
+		if err != nil {
+			log.Printf("Error scanning subscriptions: %v, table: %s", err, r.subscriptionsTable)
+			return nil, appError.NewUnexpectedError(err.Error())
+		}
+		var pageSubscriptions []model.Subscription
+		if err := attributevalue.UnmarshalListOfMaps(page.Items, &pageSubscriptions); err != nil {
+			log.Printf("Error unmarshaling subscriptions from DynamoDB: %v", err)
+			return nil, appError.NewUnexpectedError(err.Error())
+		}
+		subscriptions = append(subscriptions, pageSubscriptions...)
+	}
+	return subscriptions, nil
+}
+
+func (r *WebhookDynamoDBRepository) GetSubscriptionByID(id string) (*model.Subscription, *appError.Error) {
+	result, err := r.client.GetItem(r.ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(r.subscriptionsTable),
+		Key:       idKey(id),
+	})
+	if err != nil {
+		log.Printf("Error getting subscription from DynamoDB: %v, table: %s", err, r.subscriptionsTable)
+		return nil, appError.NewUnexpectedError(err.Error())
+	}
+	if result.Item == nil {
+		return nil, appError.NewNotFoundError("Webhook " + id + " not found.")
+	}
+	var subscription model.Subscription
+	if err := attributevalue.UnmarshalMap(result.Item, &subscription); err != nil {
+		log.Printf("Error unmarshaling subscription from DynamoDB: %v, ID: %s", err, id)
+		return nil, appError.NewUnexpectedError(err.Error())
+	}
+	return &subscription, nil
+}
+
+func (r *WebhookDynamoDBRepository) DeleteSubscription(id string) *appError.Error {
+	expr, err := expression.NewBuilder().WithCondition(expression.AttributeExists(expression.Name("ID"))).Build()
+	if err != nil {
+		log.Printf("Error building expression for delete: %v, ID: %s", err, id)
+		return appError.NewUnexpectedError(err.Error())
+	}
+	_, err = r.client.DeleteItem(r.ctx, &dynamodb.DeleteItemInput{
+		TableName:                aws.String(r.subscriptionsTable),
+		Key:                      idKey(id),
+		ConditionExpression:      expr.Condition(),
+		ExpressionAttributeNames: expr.Names(),
+	})
+	if err != nil {
+		var conditional *types.ConditionalCheckFailedException
+		if errors.As(err, &conditional) {
+			return appError.NewNotFoundError("Webhook " + id + " not found.")
+		}
+		log.Printf("Error deleting subscription from DynamoDB: %v, table: %s", err, r.subscriptionsTable)
+		return appError.NewUnexpectedError(err.Error())
+	}
+	log.Printf("Deleted webhook subscription, ID: %s", id)
+	return nil
+}
+
+// marshalDelivery adds the due index key to deliveries waiting for an attempt.
+func marshalDelivery(delivery *model.Delivery) (map[string]types.AttributeValue, error) {
+	av, err := attributevalue.MarshalMap(delivery)
+	if err != nil {
+		return nil, err
+	}
+	if delivery.NextAttemptAt != nil && (delivery.Status == model.DeliveryPending || delivery.Status == model.DeliveryRetrying) {
+		av[dueAttribute] = &types.AttributeValueMemberS{Value: duePartition}
+	}
+	return av, nil
+}
+
+// CreateDelivery stores a new delivery, failing with a conflict when the
+// event was already dispatched to the subscription.
+func (r *WebhookDynamoDBRepository) CreateDelivery(delivery *model.Delivery) *appError.Error {
+	av, err := marshalDelivery(delivery)
+	if err != nil {
+		log.Printf("Error marshaling delivery: %v, ID: %s", err, delivery.ID)
+		return appError.NewUnexpectedError(err.Error())
+	}
+	expr, err := expression.NewBuilder().WithCondition(expression.AttributeNotExists(expression.Name("ID"))).Build()
+	if err != nil {
+		log.Printf("Error building expression for delivery: %v, ID: %s", err, delivery.ID)
+		return appError.NewUnexpectedError(err.Error())
+	}
+	_, err = r.client.PutItem(r.ctx, &dynamodb.PutItemInput{
+		TableName:                aws.String(r.deliveriesTable),
+		Item:                     av,
+		ConditionExpression:      expr.Condition(),
+		ExpressionAttributeNames: expr.Names(),
+	})
+	if err != nil {
+		var conditional *types.ConditionalCheckFailedException
+		if errors.As(err, &conditional) {
+			return appError.NewConflictError("Delivery " + delivery.ID + " already exists.")
+		}
+		log.Printf("Error putting delivery in DynamoDB: %v, table: %s", err, r.deliveriesTable)
+		return appError.NewUnexpectedError(err.Error())
+	}
+	return nil
+}
+
+func (r *WebhookDynamoDBRepository) SaveDelivery(delivery *model.Delivery) *appError.Error {
+	av, err := marshalDelivery(delivery)
+	if err != nil {
+		log.Printf("Error marshaling delivery: %v, ID: %s", err, delivery.ID)
+		return appError.NewUnexpectedError(err.Error())
+	}
+	_, err = r.client.PutItem(r.ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(r.deliveriesTable),
+		Item:      av,
+	})
+	if err != nil {
+		log.Printf("Error putting delivery in DynamoDB: %v, table: %s", err, r.deliveriesTable)
+		return appError.NewUnexpectedError(err.Error())
+	}
+	log.Printf("Saved delivery %s, status: %s, attempts: %d", delivery.ID, delivery.Status, len(delivery.Attempts))
+	return nil
+}
+
+// GetDueDeliveries returns up to limit deliveries whose next attempt is at or
+// before now, oldest first.
+func (r *WebhookDynamoDBRepository) GetDueDeliveries(now time.Time, limit int) ([]model.Delivery, *appError.Error) {
+	keyCond := expression.Key(dueAttribute).Equal(expression.Value(duePartition)).
+		And(expression.Key("next_attempt_at").LessThanEqual(expression.Value(now)))
+	expr, err := expression.NewBuilder().WithKeyCondition(keyCond).Build()
+	if err != nil {
+		log.Printf("Error building expression for due deliveries query: %v", err)
+		return nil, appError.NewUnexpectedError(err.Error())
+	}
+	return r.queryDeliveries(&dynamodb.QueryInput{
+		TableName:                 aws.String(r.deliveriesTable),
+		IndexName:                 aws.String(DueIndex),
+		KeyConditionExpression:    expr.KeyCondition(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		ScanIndexForward:          aws.Bool(true),
+		Limit:                     aws.Int32(int32(limit)),
+	})
+}
+
+// GetDeliveriesBySubscription returns the latest deliveries of a
+// subscription, newest first, optionally only those with status. The limit
+// applies before the status filter, so a filtered page may come back short.
+func (r *WebhookDynamoDBRepository) GetDeliveriesBySubscription(subscriptionID string, status model.DeliveryStatus, limit int) ([]model.Delivery, *appError.Error) {
+	builder := expression.NewBuilder().
+		WithKeyCondition(expression.Key("subscription_id").Equal(expression.Value(subscriptionID)))
+	if status != "" {
+		builder = builder.WithFilter(expression.Name("status").Equal(expression.Value(status)))
+	}
+	expr, err := builder.Build()
+	if err != nil {
+		log.Printf("Error building expression for deliveries query: %v", err)
+		return nil, appError.NewUnexpectedError(err.Error())
+	}
+	return r.queryDeliveries(&dynamodb.QueryInput{
+		TableName:                 aws.String(r.deliveriesTable),
+		IndexName:                 aws.String(SubscriptionIndex),
+		KeyConditionExpression:    expr.KeyCondition(),
+		FilterExpression:          expr.Filter(),
+		ExpressionAttributeNames:  expr.Names(),
+		ExpressionAttributeValues: expr.Values(),
+		ScanIndexForward:          aws.Bool(false),
+		Limit:                     aws.Int32(int32(limit)),
+	})
+}
+
+func (r *WebhookDynamoDBRepository) queryDeliveries(input *dynamodb.QueryInput) ([]model.Delivery, *appError.Error) {
+	result, err := r.client.Query(r.ctx, input)
+	if err != nil {
+		log.Printf("Error querying deliveries: %v, table: %s, index: %s", err, r.deliveriesTable, aws.ToString(input.IndexName))
+		return nil, appError.NewUnexpectedError(err.Error())
+	}
+	deliveries := []model.Delivery{}
+	if err := attributevalue.UnmarshalListOfMaps(result.Items, &deliveries); err != nil {
+		log.Printf("Error unmarshaling deliveries from DynamoDB: %v", err)
+		return nil, appError.NewUnexpectedError(err.Error())
+	}
+	log.Printf("Retrieved %d deliveries from index %s", len(deliveries), aws.ToString(input.IndexName))
+	return deliveries, nil
+}
@@ -0,0 +1,32 @@
+package configuration
+
+import (
+	"log"
+	"os"
+)
+
+func GetDynamoDBWebhookTable() string {
+	tableName := os.Getenv("WEBHOOKS_TABLE")
+	if tableName == "" {
+		log.Printf("Local DynamoDB Database")
+		return "Test_Webhook_Table"
+	}
+	log.Printf("AWS DynamoDB Database: %s", tableName)
+	return tableName
+}
+
+func GetDynamoDBDeliveryTable() string {
+	tableName := os.Getenv("DELIVERIES_TABLE")
+	if tableName == "" {
+		log.Printf("Local DynamoDB Database")
+		return "Test_Delivery_Table"
+	}
+	log.Printf("AWS DynamoDB Database: %s", tableName)
+	return tableName
+}
+
+// IsLocal tells whether the webhooks run locally, without a table
+// configured, where endpoints may use plain http.
+func IsLocal() bool {
+	return os.Getenv("WEBHOOKS_TABLE") == ""
+}
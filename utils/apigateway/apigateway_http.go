@@ -0,0 +1,99 @@
+package apigateway
+
+import (
+	"context"
+	"encoding/base64"
+	"io"
+	"log"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+// LambdaHandler is the signature of the API Gateway Lambda handlers.
+type LambdaHandler func(context.Context, events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error)
+
+var resourceParameter = regexp.MustCompile(`\{(\w+)\}`)
+
+// HTTPHandler serves a Lambda handler over net/http, for running the API
+// without API Gateway. resource is the API Gateway resource of the route,
+// such as /books/{bookId}; it must also be the path of the ServeMux pattern
+// the handler is registered with, so that its parameters can be read.
+// Multipart bodies are passed base64 encoded, as API Gateway does for the
+// binary media types.
+func HTTPHandler(resource string, handler LambdaHandler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		request, err := apiGatewayRequest(resource, r)
+		if err != nil {
+			log.Printf("Error reading request body: %v", err)
+			http.Error(w, `{"error": "Error reading request body"}`, http.StatusBadRequest)
+			return
+		}
+
+		response, err := handler(r.Context(), request)
+		if err != nil {
+			log.Printf("Error handling %s %s: %v", r.Method, r.URL.Path, err)
+			http.Error(w, `{"error": "Internal server error"}`, http.StatusInternalServerError)
+			return
+		}
+		writeAPIGatewayResponse(w, response)
+	}
+}
+
+func apiGatewayRequest(resource string, r *http.Request) (events.APIGatewayProxyRequest, error) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return events.APIGatewayProxyRequest{}, err
+	}
+
+	request := events.APIGatewayProxyRequest{
+		Resource:                        resource,
+		Path:                            r.URL.Path,
+		HTTPMethod:                      r.Method,
+		Headers:                         map[string]string{"Host": r.Host},
+		MultiValueHeaders:               map[string][]string{"Host": {r.Host}},
+		QueryStringParameters:           map[string]string{},
+		MultiValueQueryStringParameters: map[string][]string{},
+		PathParameters:                  map[string]string{},
+		Body:                            string(body),
+	}
+	for key, values := range r.Header {
+		request.Headers[key] = values[0]
+		request.MultiValueHeaders[key] = values
+	}
+	for key, values := range r.URL.Query() {
+		request.QueryStringParameters[key] = values[0]
+		request.MultiValueQueryStringParameters[key] = values
+	}
+	for _, match := range resourceParameter.FindAllStringSubmatch(resource, -1) {
+		request.PathParameters[match[1]] = r.PathValue(match[1])
+	}
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/") {
+		request.Body = base64.StdEncoding.EncodeToString(body)
+		request.IsBase64Encoded = true
+	}
+	return request, nil
+}
+
+func writeAPIGatewayResponse(w http.ResponseWriter, response events.APIGatewayProxyResponse) {
+	for key, value := range response.Headers {
+		w.Header().Set(key, value)
+	}
+	for key, values := range response.MultiValueHeaders {
+		w.Header()[http.CanonicalHeaderKey(key)] = values
+	}
+	body := []byte(response.Body)
+	if response.IsBase64Encoded {
+		decoded, err := base64.StdEncoding.DecodeString(response.Body)
+		if err != nil {
+			log.Printf("Error decoding base64 response: %v", err)
+			http.Error(w, `{"error": "Error decoding response"}`, http.StatusInternalServerError)
+			return
+		}
+		body = decoded
+	}
+	w.WriteHeader(response.StatusCode)
+	w.Write(body)
+}
@@ -2,6 +2,7 @@ package apigateway
 
 import (
 	"encoding/json"
+	"strings"
 
 	appError "main/utils/error"
 
@@ -23,3 +24,44 @@ func ParseAPIGatewayRequestParameters(request events.APIGatewayProxyRequest, par
 	}
 	return param, nil
 }
+
+// ParseAPIGatewayRequestHeader looks up a header ignoring case, since clients
+// and API Gateway do not agree on header capitalization.
+func ParseAPIGatewayRequestHeader(request events.APIGatewayProxyRequest, header string) (string, *appError.Error) {
+	for key, value := range request.Headers {
+		if strings.EqualFold(key, header) && value != "" {
+			return value, nil
+		}
+	}
+	return "", appError.NewBadRequestError("header " + header + " is required")
+}
+
+// ParseAPIGatewayRequestIdentity is the caller authenticated by the API
+// Gateway authorizer: the subject of the Cognito claims, or the principal of
+// a Lambda authorizer. Headers are set by the client and cannot identify it.
+func ParseAPIGatewayRequestIdentity(request events.APIGatewayProxyRequest) (string, *appError.Error) {
+	authorizer := request.RequestContext.Authorizer
+	if claims, ok := authorizer["claims"].(map[string]interface{}); ok {
+		if subject, ok := claims["sub"].(string); ok && subject != "" {
+			return subject, nil
+		}
+	}
+	if principal, ok := authorizer["principalId"].(string); ok && principal != "" {
+		return principal, nil
+	}
+	return "", appError.NewUnauthorizedError("The request is not authenticated.")
+}
+
+// APIGatewayBaseURL rebuilds the public address of the API from the Host
+// header and stage, for responses that must carry absolute links. It is empty
+// when the request has no Host header.
+func APIGatewayBaseURL(request events.APIGatewayProxyRequest) string {
+	host, errHost := ParseAPIGatewayRequestHeader(request, "Host")
+	if errHost != nil {
+		return ""
+	}
+	if request.RequestContext.Stage == "" {
+		return "https://" + host
+	}
+	return "https://" + host + "/" + request.RequestContext.Stage
+}
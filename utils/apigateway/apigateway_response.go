@@ -44,3 +44,19 @@ func APIGatewayDataResponse(statusCode int, data interface{}) (events.APIGateway
 func APIGatewayError(statusCode int, err string) (events.APIGatewayProxyResponse, error) {
 	return apiGatewayResponse(statusCode, map[string]string{"error": err}, HeadersJSON)
 }
+
+// APIGatewayContentResponse returns a body that is already serialized, for
+// responses negotiated through the Accept header.
+func APIGatewayContentResponse(statusCode int, contentType string, body []byte) (events.APIGatewayProxyResponse, error) {
+	headers := make(map[string]string, len(HeadersJSON)+1)
+	for key, value := range HeadersJSON {
+		headers[key] = value
+	}
+	headers["Content-Type"] = contentType
+	headers["Vary"] = "Accept"
+	return events.APIGatewayProxyResponse{
+		Body:       string(body),
+		StatusCode: statusCode,
+		Headers:    headers,
+	}, nil
+}
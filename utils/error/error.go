@@ -45,3 +45,31 @@ func NewValidationError(message string) *Error {
 		Message: message,
 	}
 }
+
+func NewNotFoundError(message string) *Error {
+	return &Error{
+		Code:    http.StatusNotFound, // 404
+		Message: message,
+	}
+}
+
+func NewConflictError(message string) *Error {
+	return &Error{
+		Code:    http.StatusConflict, // 409
+		Message: message,
+	}
+}
+
+func NewForbiddenError(message string) *Error {
+	return &Error{
+		Code:    http.StatusForbidden, // 403
+		Message: message,
+	}
+}
+
+func NewUnauthorizedError(message string) *Error {
+	return &Error{
+		Code:    http.StatusUnauthorized, // 401
+		Message: message,
+	}
+}
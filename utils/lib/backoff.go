@@ -0,0 +1,32 @@
+package lib
+
+import (
+	"context"
+	"math/rand"
+	"time"
+)
+
+// Backoff returns the delay before retry number attempt (starting at 0) using
+// exponential backoff with full jitter, capped at max.
+func Backoff(attempt int, base, max time.Duration) time.Duration {
+	delay := max
+	if attempt < 32 {
+		if exp := base << uint(attempt); exp > 0 && exp < max {
+			delay = exp
+		}
+	}
+	return time.Duration(rand.Int63n(int64(delay) + 1))
+}
+
+// Sleep waits for delay, or less when ctx is done first, in which case it
+// returns the reason ctx was done.
+func Sleep(ctx context.Context, delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}